@@ -11,8 +11,24 @@ func main() {
 	// 1. 初始化应用（所有依赖注入在 bootstrap 里完成）
 	app := bootstrap.NewApp()
 
-	// 2. 设置路由（传入 Controllers）
-	r := router.SetupRouter(app.UserController, app.TransactionController)
+	// 2. 设置路由（传入中间件和 Controllers）
+	r := router.SetupRouter(
+		app.Idempotency,
+		app.UserController,
+		app.TransactionController,
+		app.PortfolioController,
+		app.LotController,
+		app.ReportController,
+		app.FXController,
+		app.AccountController,
+		app.JournalController,
+		app.FeeController,
+		app.MarketController,
+		app.InstrumentController,
+		app.PriceController,
+		app.StreamController,
+		app.PerformanceController,
+	)
 
 	// 3. 启动服务器
 	log.Println("====================================")
@@ -26,8 +42,62 @@ func main() {
 	log.Println("   GET  /api/v1/user/profile     - 获取个人信息")
 	log.Println("   PUT  /api/v1/user/profile     - 更新个人信息")
 	log.Println("   POST /api/v1/user/password    - 修改密码")
+	log.Println("   --- 券商账户模块 ---")
+	log.Println("   POST /api/v1/accounts/create     - 创建账户")
+	log.Println("   GET  /api/v1/accounts/list       - 查询账户列表")
+	log.Println("   GET  /api/v1/accounts/:id        - 查询单个账户")
+	log.Println("   PUT  /api/v1/accounts/:id        - 更新账户")
+	log.Println("   DEL  /api/v1/accounts/:id        - 删除账户")
+	log.Println("   GET  /api/v1/accounts/:id/cash   - 现金流水及余额")
+	log.Println("   --- 手续费费率表模块 ---")
+	log.Println("   POST /api/v1/fee-schedules/create - 创建费率表")
+	log.Println("   GET  /api/v1/fee-schedules/list   - 查询费率表列表")
+	log.Println("   GET  /api/v1/fee-schedules/:id    - 查询单个费率表")
+	log.Println("   PUT  /api/v1/fee-schedules/:id    - 更新费率表")
+	log.Println("   DEL  /api/v1/fee-schedules/:id    - 删除费率表")
+	log.Println("   --- 证券主数据模块 ---")
+	log.Println("   POST /api/v1/instruments/create - 创建证券")
+	log.Println("   GET  /api/v1/instruments/search - 搜索证券")
+	log.Println("   GET  /api/v1/instruments/:id    - 查询单个证券")
+	log.Println("   PUT  /api/v1/instruments/:id    - 更新证券")
+	log.Println("   DEL  /api/v1/instruments/:id    - 删除证券")
 	log.Println("   --- 交易模块 ---")
-	log.Println("   POST /api/v1/transactions     - 创建交易")
+	log.Println("   POST /api/v1/transactions/create - 创建交易")
+	log.Println("   GET  /api/v1/transactions/list   - 查询交易列表")
+	log.Println("   POST /api/v1/transactions/import - 批量导入交易（CSV / IBKR Flex / OFX）")
+	log.Println("   GET  /api/v1/transactions/export - 导出交易（CSV / JSONL / XLSX）")
+	log.Println("   GET  /api/v1/transactions/:id    - 查询单条交易")
+	log.Println("   PUT  /api/v1/transactions/:id    - 更新交易")
+	log.Println("   DEL  /api/v1/transactions/:id    - 删除交易")
+	log.Println("   --- 持仓模块 ---")
+	log.Println("   GET  /api/v1/portfolio/holdings  - 持仓汇总")
+	log.Println("   GET  /api/v1/portfolio/history   - 估值历史（资产曲线）")
+	log.Println("   POST /api/v1/portfolio/history/rebuild - 重新生成估值快照")
+	log.Println("   --- 业绩分析模块 ---")
+	log.Println("   GET  /api/v1/performance/returns - 收益率（TWR / XIRR）")
+	log.Println("   GET  /api/v1/performance/risk    - 风险指标（波动率、最大回撤、夏普、Beta）")
+	log.Println("   --- 税务批次模块 ---")
+	log.Println("   GET  /api/v1/lots/list           - 查询批次")
+	log.Println("   GET  /api/v1/lots/realized       - 已实现盈亏明细")
+	log.Println("   POST /api/v1/lots/rebuild        - 重建全部批次")
+	log.Println("   --- 复式记账模块 ---")
+	log.Println("   GET  /api/v1/journal/entries       - 查询分录")
+	log.Println("   GET  /api/v1/journal/trial-balance - 试算平衡表")
+	log.Println("   POST /api/v1/journal/rebuild       - 重新过账全部交易")
+	log.Println("   --- 报表模块 ---")
+	log.Println("   GET  /api/v1/reports/pnl         - 盈亏报表")
+	log.Println("   --- 汇率模块 ---")
+	log.Println("   POST /api/v1/fx/import           - 批量导入汇率（CSV）")
+	log.Println("   GET  /api/v1/fx/rates            - 查询汇率序列")
+	log.Println("   GET  /api/v1/fx/rate             - 按日期查询汇率")
+	log.Println("   --- 交易日历与行情模块 ---")
+	log.Println("   GET  /api/v1/market/calendar     - 交易日历（NYSE / HKEX / SSE / SZSE）")
+	log.Println("   GET  /api/v1/market/quotes       - 批量查询行情")
+	log.Println("   GET  /api/v1/market/stream       - 实时行情推送（SSE，默认订阅当前持仓）")
+	log.Println("   POST /api/v1/market/stream/ticket - 签发推送票据（一次性，供 EventSource 使用）")
+	log.Println("   --- 历史行情模块 ---")
+	log.Println("   POST /api/v1/prices/import       - 批量导入日线行情（CSV）")
+	log.Println("   GET  /api/v1/prices/bars         - 查询日线行情")
 	log.Println("====================================")
 
 	if err := r.Run(":8080"); err != nil {
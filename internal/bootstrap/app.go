@@ -1,16 +1,59 @@
 package bootstrap
 
 import (
+	"fmt"
 	"log"
 
+	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 
+	"github.com/florentyang/smartfin-go/internal/cache"
+	cacheImpl "github.com/florentyang/smartfin-go/internal/cache/impl"
 	"github.com/florentyang/smartfin-go/internal/config"
 	"github.com/florentyang/smartfin-go/internal/controller"
+	"github.com/florentyang/smartfin-go/internal/dao"
+	accountRepoImpl "github.com/florentyang/smartfin-go/internal/dao/account/impl"
+	feeRepoImpl "github.com/florentyang/smartfin-go/internal/dao/fee/impl"
+	fxRepoImpl "github.com/florentyang/smartfin-go/internal/dao/fxrate/impl"
+	idempotencyRepoImpl "github.com/florentyang/smartfin-go/internal/dao/idempotency/impl"
+	instrumentRepoImpl "github.com/florentyang/smartfin-go/internal/dao/instrument/impl"
+	journalRepoImpl "github.com/florentyang/smartfin-go/internal/dao/journal/impl"
+	lotRepoImpl "github.com/florentyang/smartfin-go/internal/dao/lot/impl"
+	priceBarRepoImpl "github.com/florentyang/smartfin-go/internal/dao/pricebar/impl"
+	snapshotRepoImpl "github.com/florentyang/smartfin-go/internal/dao/snapshot/impl"
 	txRepoImpl "github.com/florentyang/smartfin-go/internal/dao/transaction/impl"
 	userRepoImpl "github.com/florentyang/smartfin-go/internal/dao/user/impl"
+	accountDomainImpl "github.com/florentyang/smartfin-go/internal/domain/account/impl"
+	feeDomain "github.com/florentyang/smartfin-go/internal/domain/fee"
+	feeDomainImpl "github.com/florentyang/smartfin-go/internal/domain/fee/impl"
+	fxDomain "github.com/florentyang/smartfin-go/internal/domain/fx"
+	fxDomainImpl "github.com/florentyang/smartfin-go/internal/domain/fx/impl"
+	idempotencyDomainImpl "github.com/florentyang/smartfin-go/internal/domain/idempotency/impl"
+	instrumentDomain "github.com/florentyang/smartfin-go/internal/domain/instrument"
+	instrumentDomainImpl "github.com/florentyang/smartfin-go/internal/domain/instrument/impl"
+	journalDomain "github.com/florentyang/smartfin-go/internal/domain/journal"
+	journalDomainImpl "github.com/florentyang/smartfin-go/internal/domain/journal/impl"
+	lotDomain "github.com/florentyang/smartfin-go/internal/domain/lot"
+	lotDomainImpl "github.com/florentyang/smartfin-go/internal/domain/lot/impl"
+	marketDomain "github.com/florentyang/smartfin-go/internal/domain/market"
+	marketDomainImpl "github.com/florentyang/smartfin-go/internal/domain/market/impl"
+	performanceDomainImpl "github.com/florentyang/smartfin-go/internal/domain/performance/impl"
+	portfolioDomain "github.com/florentyang/smartfin-go/internal/domain/portfolio"
+	portfolioDomainImpl "github.com/florentyang/smartfin-go/internal/domain/portfolio/impl"
+	priceDomain "github.com/florentyang/smartfin-go/internal/domain/price"
+	priceDomainImpl "github.com/florentyang/smartfin-go/internal/domain/price/impl"
+	quoteDomain "github.com/florentyang/smartfin-go/internal/domain/quote"
+	quoteDomainImpl "github.com/florentyang/smartfin-go/internal/domain/quote/impl"
+	reportDomainImpl "github.com/florentyang/smartfin-go/internal/domain/report/impl"
+	streamDomainImpl "github.com/florentyang/smartfin-go/internal/domain/stream/impl"
 	txDomainImpl "github.com/florentyang/smartfin-go/internal/domain/transaction/impl"
 	userDomainImpl "github.com/florentyang/smartfin-go/internal/domain/user/impl"
+	"github.com/florentyang/smartfin-go/internal/importer"
+	importerImpl "github.com/florentyang/smartfin-go/internal/importer/impl"
+	"github.com/florentyang/smartfin-go/internal/job"
+	"github.com/florentyang/smartfin-go/internal/middleware"
+	"github.com/florentyang/smartfin-go/internal/quote"
+	quoteImpl "github.com/florentyang/smartfin-go/internal/quote/impl"
 	"github.com/florentyang/smartfin-go/internal/service"
 )
 
@@ -18,9 +61,38 @@ import (
 type App struct {
 	DB *gorm.DB
 
+	// Middlewares（给 Router 用）
+	Idempotency gin.HandlerFunc
+
 	// Controllers（给 Router 用）
 	UserController        controller.UserController
 	TransactionController controller.TransactionController
+	PortfolioController   controller.PortfolioController
+	LotController         controller.LotController
+	ReportController      controller.ReportController
+	FXController          controller.FXController
+	AccountController     controller.AccountController
+	JournalController     controller.JournalController
+	FeeController         controller.FeeController
+	MarketController      controller.MarketController
+	InstrumentController  controller.InstrumentController
+	PriceController       controller.PriceController
+	StreamController      controller.StreamController
+	PerformanceController controller.PerformanceController
+
+	// Domains（跨模块共享）
+	lotDomain        lotDomain.Domain
+	fxDomain         fxDomain.Domain
+	feeDomain        feeDomain.Domain
+	marketDomain     marketDomain.Domain
+	instrumentDomain instrumentDomain.Domain
+	journalDomain    journalDomain.Domain
+	priceDomain      priceDomain.Domain
+	quoteDomain      quoteDomain.Domain
+	portfolioDomain  portfolioDomain.Domain
+
+	// 后台任务
+	snapshotQueue *job.SnapshotQueue
 }
 
 // NewApp 创建并初始化应用程序
@@ -31,13 +103,41 @@ func NewApp() *App {
 	// ==================== 1. 基础设施层 ====================
 	app.initDatabase()
 
+	app.initIdempotency()
+
 	// ==================== 2. 业务层初始化 ====================
 	app.initUserModule()
 
+	app.initAccountModule()
+
+	app.initMarketModule() // 交易模块依赖交易日历 Domain，需先初始化
+
+	app.initInstrumentModule() // 交易模块依赖证券主数据 Domain，需先初始化
+
+	app.initFeeModule() // 交易模块依赖费率表 Domain，需先初始化
+
+	app.initFXModule() // 持仓、报表依赖汇率 Domain，需先初始化
+
+	app.initPriceModule() // 持仓、报表依赖历史行情 Domain 估值，需先初始化
+
+	app.initLotModule() // 交易模块依赖批次 Domain，需先初始化
+
+	app.initJournalModule() // 交易模块依赖分录 Domain，需先初始化
+
+	app.initPortfolioModule() // 交易模块依赖快照补齐队列，行情推送、业绩分析依赖持仓 Domain，需先初始化
+
 	app.initTransactionModule()
+
+	app.initPerformanceModule()
+
+	app.initStreamModule()
+
+	app.initReportModule()
 	// TODO: 以后加其他模块
 	// app.initAssetModule()
-	// app.initTransactionModule()
+
+	// ==================== 3. 后台任务 ====================
+	app.initJobs()
 
 	return app
 }
@@ -51,6 +151,15 @@ func (app *App) initDatabase() {
 	app.DB = db
 }
 
+// initIdempotency 初始化幂等中间件
+// 幂等键存数据库，对所有写接口生效
+func (app *App) initIdempotency() {
+	idempotencyRepo := idempotencyRepoImpl.NewIdempotencyRepo(app.DB)
+	idempotencyDomain := idempotencyDomainImpl.NewIdempotencyDomain(idempotencyRepo)
+
+	app.Idempotency = middleware.Idempotency(idempotencyDomain, config.DefaultIdempotencyConfig())
+}
+
 // initUserModule 初始化用户模块（依赖注入链）
 func (app *App) initUserModule() {
 	// DAO → Domain → Service → Controller
@@ -62,11 +171,229 @@ func (app *App) initUserModule() {
 	app.UserController = userController
 }
 
+// initAccountModule 初始化券商账户模块
+// 删除账户前需检查是否仍有交易，复用交易 DAO
+func (app *App) initAccountModule() {
+	accountRepo := accountRepoImpl.NewAccountRepo(app.DB)
+	txRepo := txRepoImpl.NewTransactionRepo(app.DB)
+	userRepo := userRepoImpl.NewUserRepo(app.DB)
+	transactor := dao.NewTransactor(app.DB)
+	accountDomain := accountDomainImpl.NewAccountDomain(accountRepo, txRepo, userRepo, transactor)
+	accountService := service.NewAccountService(accountDomain)
+	accountController := controller.NewAccountController(accountService)
+
+	app.AccountController = accountController
+}
+
+// initMarketModule 初始化交易日历和行情模块
+// 启动时加载休市日数据、行情源和行情缓存，数据文件有误或缓存连接失败时直接退出
+func (app *App) initMarketModule() {
+	marketDomain, err := marketDomainImpl.NewMarketDomain(config.DefaultMarketConfig())
+	if err != nil {
+		log.Fatalf("交易日历初始化失败: %v", err)
+	}
+	app.marketDomain = marketDomain
+
+	quoteConfig := config.DefaultQuoteConfig()
+	provider, err := newQuoteProvider(quoteConfig)
+	if err != nil {
+		log.Fatalf("行情源初始化失败: %v", err)
+	}
+	if quoteConfig.CacheTTL > 0 {
+		quoteCache, err := newCache(config.DefaultCacheConfig())
+		if err != nil {
+			log.Fatalf("行情缓存初始化失败: %v", err)
+		}
+		provider = quoteImpl.NewCachedProvider(provider, quoteCache, app.marketDomain, quoteConfig)
+	}
+	app.quoteDomain = quoteDomainImpl.NewQuoteDomain(provider, quoteConfig)
+
+	marketService := service.NewMarketService(app.marketDomain, app.quoteDomain)
+	marketController := controller.NewMarketController(marketService)
+
+	app.MarketController = marketController
+}
+
+// initInstrumentModule 初始化证券主数据模块
+func (app *App) initInstrumentModule() {
+	instrumentRepo := instrumentRepoImpl.NewInstrumentRepo(app.DB)
+	app.instrumentDomain = instrumentDomainImpl.NewInstrumentDomain(instrumentRepo)
+	instrumentService := service.NewInstrumentService(app.instrumentDomain)
+	instrumentController := controller.NewInstrumentController(instrumentService)
+
+	app.InstrumentController = instrumentController
+}
+
+// newQuoteProvider 按配置创建行情源：接入新的行情源只需在这里增加分支
+func newQuoteProvider(cfg *config.QuoteConfig) (quote.Provider, error) {
+	switch cfg.Provider {
+	case quote.ProviderStatic:
+		return quoteImpl.NewStaticProvider(), nil
+	case quote.ProviderFile:
+		provider, err := quoteImpl.NewFileProvider(cfg.File)
+		if err != nil {
+			return nil, err
+		}
+		return provider, nil
+	default:
+		return nil, fmt.Errorf("不支持的行情源: %s", cfg.Provider)
+	}
+}
+
+// newCache 按配置创建缓存：接入新的缓存后端只需在这里增加分支
+func newCache(cfg *config.CacheConfig) (cache.Cache, error) {
+	switch cfg.Backend {
+	case cache.BackendMemory:
+		return cacheImpl.NewMemoryCache(cfg.MaxEntries), nil
+	case cache.BackendRedis:
+		redisCache, err := cacheImpl.NewRedisCache(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return redisCache, nil
+	default:
+		return nil, fmt.Errorf("不支持的缓存后端: %s", cfg.Backend)
+	}
+}
+
+// initFeeModule 初始化手续费费率表模块
+// 匹配费率表时需要读取交易账户的券商，复用账户 DAO
+func (app *App) initFeeModule() {
+	feeRepo := feeRepoImpl.NewFeeRepo(app.DB)
+	accountRepo := accountRepoImpl.NewAccountRepo(app.DB)
+	app.feeDomain = feeDomainImpl.NewFeeDomain(feeRepo, accountRepo)
+	feeService := service.NewFeeService(app.feeDomain)
+	feeController := controller.NewFeeController(feeService)
+
+	app.FeeController = feeController
+}
+
+// initFXModule 初始化汇率模块
+func (app *App) initFXModule() {
+	fxRepo := fxRepoImpl.NewFXRateRepo(app.DB)
+	app.fxDomain = fxDomainImpl.NewFXDomain(fxRepo)
+	fxService := service.NewFXService(app.fxDomain)
+	fxController := controller.NewFXController(fxService)
+
+	app.FXController = fxController
+}
+
+// initPriceModule 初始化历史行情模块
+// 行情按证券主数据关联，依赖证券 Domain 解析股票代码
+func (app *App) initPriceModule() {
+	priceBarRepo := priceBarRepoImpl.NewPriceBarRepo(app.DB)
+	instrumentRepo := instrumentRepoImpl.NewInstrumentRepo(app.DB)
+	app.priceDomain = priceDomainImpl.NewPriceDomain(priceBarRepo, instrumentRepo, app.instrumentDomain)
+	priceService := service.NewPriceService(app.priceDomain)
+	priceController := controller.NewPriceController(priceService)
+
+	app.PriceController = priceController
+}
+
+// initLotModule 初始化税务批次模块
+func (app *App) initLotModule() {
+	lotRepo := lotRepoImpl.NewLotRepo(app.DB)
+	txRepo := txRepoImpl.NewTransactionRepo(app.DB)
+	transactor := dao.NewTransactor(app.DB)
+	userRepo := userRepoImpl.NewUserRepo(app.DB)
+	accountRepo := accountRepoImpl.NewAccountRepo(app.DB)
+	app.lotDomain = lotDomainImpl.NewLotDomain(lotRepo, txRepo, userRepo, accountRepo, transactor)
+	lotService := service.NewLotService(app.lotDomain)
+	lotController := controller.NewLotController(lotService)
+
+	app.LotController = lotController
+}
+
+// initJournalModule 初始化复式记账模块
+// 卖出、转出的结转成本来自批次回放，依赖批次 Domain
+func (app *App) initJournalModule() {
+	journalRepo := journalRepoImpl.NewJournalRepo(app.DB)
+	txRepo := txRepoImpl.NewTransactionRepo(app.DB)
+	transactor := dao.NewTransactor(app.DB)
+	app.journalDomain = journalDomainImpl.NewJournalDomain(journalRepo, txRepo, app.lotDomain, transactor)
+	journalService := service.NewJournalService(app.journalDomain)
+	journalController := controller.NewJournalController(journalService)
+
+	app.JournalController = journalController
+}
+
+// initTransactionModule 初始化交易模块
 func (app *App) initTransactionModule() {
 	txRepo := txRepoImpl.NewTransactionRepo(app.DB)
-	txDomain := txDomainImpl.NewTransactionDomain(txRepo)
-	txService := service.NewTransactionService(txDomain)
+	userRepo := userRepoImpl.NewUserRepo(app.DB)
+	accountRepo := accountRepoImpl.NewAccountRepo(app.DB)
+	snapshotRepo := snapshotRepoImpl.NewSnapshotRepo(app.DB)
+	transactor := dao.NewTransactor(app.DB)
+	txDomain := txDomainImpl.NewTransactionDomain(txRepo, userRepo, accountRepo, snapshotRepo, app.instrumentDomain, app.feeDomain, app.marketDomain, app.lotDomain, app.journalDomain, app.snapshotQueue, transactor)
+	// 对账单导入器：新增格式只需在这里注册
+	importers := importer.NewRegistry(
+		importerImpl.NewCSVImporter(),
+		importerImpl.NewIBKRFlexImporter(),
+		importerImpl.NewOFXImporter(),
+	)
+	txService := service.NewTransactionService(txDomain, importers)
 	txController := controller.NewTransactionController(txService)
 
 	app.TransactionController = txController
 }
+
+// initPortfolioModule 初始化持仓模块
+// 持仓由交易流水推导，复用交易 DAO；估值历史来自每日快照，交易写入后由快照补齐队列异步重新生成
+func (app *App) initPortfolioModule() {
+	txRepo := txRepoImpl.NewTransactionRepo(app.DB)
+	userRepo := userRepoImpl.NewUserRepo(app.DB)
+	snapshotRepo := snapshotRepoImpl.NewSnapshotRepo(app.DB)
+	transactor := dao.NewTransactor(app.DB)
+	app.portfolioDomain = portfolioDomainImpl.NewPortfolioDomain(txRepo, userRepo, snapshotRepo, app.fxDomain, app.priceDomain, transactor)
+	app.snapshotQueue = job.NewSnapshotQueue(app.portfolioDomain)
+	portfolioService := service.NewPortfolioService(app.portfolioDomain)
+	portfolioController := controller.NewPortfolioController(portfolioService)
+
+	app.PortfolioController = portfolioController
+}
+
+// initPerformanceModule 初始化业绩分析模块
+// 收益率、风险指标基于持仓 Domain 的每日估值快照；单只股票的买卖现金流来自交易 DAO，基准行情来自历史行情 Domain
+func (app *App) initPerformanceModule() {
+	txRepo := txRepoImpl.NewTransactionRepo(app.DB)
+	performanceDomain := performanceDomainImpl.NewPerformanceDomain(app.portfolioDomain, txRepo, app.fxDomain, app.priceDomain, config.DefaultPerformanceConfig())
+	performanceService := service.NewPerformanceService(performanceDomain)
+	performanceController := controller.NewPerformanceController(performanceService)
+
+	app.PerformanceController = performanceController
+}
+
+// initStreamModule 初始化实时行情推送模块
+// Hub 复用行情 Domain（含缓存），所有连接共享轮询，随进程运行
+func (app *App) initStreamModule() {
+	streamConfig := config.DefaultStreamConfig()
+	streamDomain := streamDomainImpl.NewStreamDomain(app.quoteDomain, streamConfig, config.DefaultQuoteConfig().MaxSymbols)
+	streamService := service.NewStreamService(streamDomain, app.portfolioDomain)
+	streamController := controller.NewStreamController(streamService, streamConfig)
+
+	app.StreamController = streamController
+}
+
+// initReportModule 初始化报表模块
+func (app *App) initReportModule() {
+	txRepo := txRepoImpl.NewTransactionRepo(app.DB)
+	lotRepo := lotRepoImpl.NewLotRepo(app.DB)
+	userRepo := userRepoImpl.NewUserRepo(app.DB)
+	reportDomain := reportDomainImpl.NewReportDomain(txRepo, lotRepo, userRepo, app.lotDomain, app.fxDomain, app.priceDomain)
+	reportService := service.NewReportService(reportDomain)
+	reportController := controller.NewReportController(reportService)
+
+	app.ReportController = reportController
+}
+
+// initJobs 注册并启动后台定时任务
+func (app *App) initJobs() {
+	snapshotConfig := config.DefaultSnapshotConfig()
+
+	scheduler := job.NewScheduler()
+	if err := scheduler.Daily("估值快照", snapshotConfig.RunAt, job.NewSnapshotJob(app.portfolioDomain)); err != nil {
+		log.Fatalf("定时任务初始化失败: %v", err)
+	}
+	scheduler.Start(snapshotConfig.RunOnStartup)
+	app.snapshotQueue.Start()
+}
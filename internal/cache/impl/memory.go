@@ -0,0 +1,104 @@
+package impl
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/florentyang/smartfin-go/internal/cache"
+)
+
+// ==================== 进程内 LRU 缓存 ====================
+// 双向链表 + 哈希表：链表头为最近使用的条目，超过容量时淘汰链表尾；
+// 过期的条目在读取时删除，或在淘汰时随 LRU 顺序被挤出
+
+// MemoryCache 进程内 LRU + TTL 缓存（并发安全）
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	ll         *list.List               // 按最近使用排序的条目
+	items      map[string]*list.Element // 键 → 链表节点
+	now        func() time.Time
+}
+
+// memoryEntry 缓存条目
+type memoryEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time // 零值表示不过期
+}
+
+// NewMemoryCache 创建进程内缓存，maxEntries 为最多保存的条目数（<= 0 时不限制）
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+		now:        time.Now,
+	}
+}
+
+// Get 读取缓存（返回副本，调用方修改不影响缓存）
+func (c *MemoryCache) Get(_ context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, cache.ErrCacheMiss
+	}
+	entry := elem.Value.(*memoryEntry)
+	if !entry.expiresAt.IsZero() && !c.now().Before(entry.expiresAt) {
+		c.removeElement(elem)
+		return nil, cache.ErrCacheMiss
+	}
+	c.ll.MoveToFront(elem)
+	return append([]byte(nil), entry.value...), nil
+}
+
+// Set 写入缓存，超过容量时淘汰最久未使用的条目
+func (c *MemoryCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	entry := &memoryEntry{key: key, value: append([]byte(nil), value...)}
+	if ttl > 0 {
+		entry.expiresAt = c.now().Add(ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		elem.Value = entry
+		c.ll.MoveToFront(elem)
+		return nil
+	}
+	c.items[key] = c.ll.PushFront(entry)
+	if c.maxEntries > 0 && c.ll.Len() > c.maxEntries {
+		c.removeElement(c.ll.Back())
+	}
+	return nil
+}
+
+// Delete 删除缓存
+func (c *MemoryCache) Delete(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		c.removeElement(elem)
+	}
+	return nil
+}
+
+// Len 当前条目数（含尚未清理的过期条目）
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// removeElement 删除链表节点及其索引（调用方持有锁）
+func (c *MemoryCache) removeElement(elem *list.Element) {
+	c.ll.Remove(elem)
+	delete(c.items, elem.Value.(*memoryEntry).key)
+}
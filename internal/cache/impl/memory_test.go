@@ -0,0 +1,253 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/florentyang/smartfin-go/internal/cache"
+)
+
+// ==================== 测试辅助 ====================
+
+// cacheOp 对缓存的一步操作
+type cacheOp struct {
+	op    string        // set / get / delete / advance
+	key   string        // 键
+	value string        // set 的值；get 的期望值（空表示期望未命中）
+	ttl   time.Duration // set 的有效期；advance 的前进时长
+}
+
+// runOps 依次执行操作并检查 get 的结果，最后检查条目数
+func runOps(t *testing.T, c *MemoryCache, ops []cacheOp, wantLen int) {
+	t.Helper()
+	ctx := context.Background()
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	for i, o := range ops {
+		switch o.op {
+		case "set":
+			if err := c.Set(ctx, o.key, []byte(o.value), o.ttl); err != nil {
+				t.Fatalf("ops[%d] Set(%s) error = %v", i, o.key, err)
+			}
+		case "get":
+			got, err := c.Get(ctx, o.key)
+			if o.value == "" {
+				if !errors.Is(err, cache.ErrCacheMiss) {
+					t.Errorf("ops[%d] Get(%s) = %q, %v, want miss", i, o.key, got, err)
+				}
+				continue
+			}
+			if err != nil || string(got) != o.value {
+				t.Errorf("ops[%d] Get(%s) = %q, %v, want %q", i, o.key, got, err, o.value)
+			}
+		case "delete":
+			if err := c.Delete(ctx, o.key); err != nil {
+				t.Fatalf("ops[%d] Delete(%s) error = %v", i, o.key, err)
+			}
+		case "advance":
+			now = now.Add(o.ttl)
+		}
+	}
+	if got := c.Len(); got != wantLen {
+		t.Errorf("Len() = %d, want %d", got, wantLen)
+	}
+}
+
+// ==================== 测试用例 ====================
+
+func TestMemoryCacheLRU(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxEntries int
+		ops        []cacheOp
+		wantLen    int
+	}{
+		{
+			name:       "超过容量时淘汰最早写入的条目",
+			maxEntries: 2,
+			ops: []cacheOp{
+				{op: "set", key: "a", value: "1"},
+				{op: "set", key: "b", value: "2"},
+				{op: "set", key: "c", value: "3"},
+				{op: "get", key: "a"},
+				{op: "get", key: "b", value: "2"},
+				{op: "get", key: "c", value: "3"},
+			},
+			wantLen: 2,
+		},
+		{
+			name:       "读取会刷新最近使用顺序",
+			maxEntries: 2,
+			ops: []cacheOp{
+				{op: "set", key: "a", value: "1"},
+				{op: "set", key: "b", value: "2"},
+				{op: "get", key: "a", value: "1"},
+				{op: "set", key: "c", value: "3"},
+				{op: "get", key: "b"},
+				{op: "get", key: "a", value: "1"},
+				{op: "get", key: "c", value: "3"},
+			},
+			wantLen: 2,
+		},
+		{
+			name:       "覆盖写入刷新顺序且不增加条目",
+			maxEntries: 2,
+			ops: []cacheOp{
+				{op: "set", key: "a", value: "1"},
+				{op: "set", key: "b", value: "2"},
+				{op: "set", key: "a", value: "1b"},
+				{op: "set", key: "c", value: "3"},
+				{op: "get", key: "b"},
+				{op: "get", key: "a", value: "1b"},
+			},
+			wantLen: 2,
+		},
+		{
+			name:       "删除后腾出容量",
+			maxEntries: 2,
+			ops: []cacheOp{
+				{op: "set", key: "a", value: "1"},
+				{op: "set", key: "b", value: "2"},
+				{op: "delete", key: "a"},
+				{op: "delete", key: "missing"},
+				{op: "set", key: "c", value: "3"},
+				{op: "get", key: "a"},
+				{op: "get", key: "b", value: "2"},
+				{op: "get", key: "c", value: "3"},
+			},
+			wantLen: 2,
+		},
+		{
+			name:       "容量为 0 时不限制",
+			maxEntries: 0,
+			ops: []cacheOp{
+				{op: "set", key: "a", value: "1"},
+				{op: "set", key: "b", value: "2"},
+				{op: "set", key: "c", value: "3"},
+				{op: "get", key: "a", value: "1"},
+			},
+			wantLen: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runOps(t, NewMemoryCache(tt.maxEntries), tt.ops, tt.wantLen)
+		})
+	}
+}
+
+func TestMemoryCacheTTL(t *testing.T) {
+	tests := []struct {
+		name    string
+		ops     []cacheOp
+		wantLen int
+	}{
+		{
+			name: "有效期内命中",
+			ops: []cacheOp{
+				{op: "set", key: "a", value: "1", ttl: 10 * time.Second},
+				{op: "advance", ttl: 9 * time.Second},
+				{op: "get", key: "a", value: "1"},
+			},
+			wantLen: 1,
+		},
+		{
+			// 到达过期时间即未命中，并删除条目
+			name: "到期后未命中并删除",
+			ops: []cacheOp{
+				{op: "set", key: "a", value: "1", ttl: 10 * time.Second},
+				{op: "advance", ttl: 10 * time.Second},
+				{op: "get", key: "a"},
+			},
+			wantLen: 0,
+		},
+		{
+			name: "有效期为 0 时不过期",
+			ops: []cacheOp{
+				{op: "set", key: "a", value: "1"},
+				{op: "advance", ttl: 365 * 24 * time.Hour},
+				{op: "get", key: "a", value: "1"},
+			},
+			wantLen: 1,
+		},
+		{
+			name: "覆盖写入重新计算有效期",
+			ops: []cacheOp{
+				{op: "set", key: "a", value: "1", ttl: 10 * time.Second},
+				{op: "advance", ttl: 8 * time.Second},
+				{op: "set", key: "a", value: "2", ttl: 10 * time.Second},
+				{op: "advance", ttl: 8 * time.Second},
+				{op: "get", key: "a", value: "2"},
+			},
+			wantLen: 1,
+		},
+		{
+			name: "覆盖为不过期",
+			ops: []cacheOp{
+				{op: "set", key: "a", value: "1", ttl: 10 * time.Second},
+				{op: "set", key: "a", value: "2"},
+				{op: "advance", ttl: time.Hour},
+				{op: "get", key: "a", value: "2"},
+			},
+			wantLen: 1,
+		},
+		{
+			// 过期条目未被读取时仍占用容量，按 LRU 顺序被挤出
+			name: "未读取的过期条目计入条目数",
+			ops: []cacheOp{
+				{op: "set", key: "a", value: "1", ttl: time.Second},
+				{op: "set", key: "b", value: "2"},
+				{op: "advance", ttl: time.Minute},
+				{op: "get", key: "b", value: "2"},
+			},
+			wantLen: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runOps(t, NewMemoryCache(0), tt.ops, tt.wantLen)
+		})
+	}
+}
+
+func TestMemoryCacheExpiredEvictedFirst(t *testing.T) {
+	// 过期条目在链表尾时，超过容量优先被挤出
+	runOps(t, NewMemoryCache(2), []cacheOp{
+		{op: "set", key: "a", value: "1", ttl: time.Second},
+		{op: "set", key: "b", value: "2"},
+		{op: "advance", ttl: time.Minute},
+		{op: "set", key: "c", value: "3"},
+		{op: "get", key: "a"},
+		{op: "get", key: "b", value: "2"},
+		{op: "get", key: "c", value: "3"},
+	}, 2)
+}
+
+func TestMemoryCacheCopiesValues(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache(0)
+
+	// 写入后修改原切片不影响缓存
+	value := []byte("abc")
+	if err := c.Set(ctx, "k", value, 0); err != nil {
+		t.Fatal(err)
+	}
+	value[0] = 'x'
+
+	// 读取结果的修改不影响缓存
+	got, err := c.Get(ctx, "k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got[1] = 'y'
+
+	got, err = c.Get(ctx, "k")
+	if err != nil || string(got) != "abc" {
+		t.Errorf("Get() = %q, %v, want %q", got, err, "abc")
+	}
+}
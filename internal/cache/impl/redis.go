@@ -0,0 +1,70 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/florentyang/smartfin-go/internal/cache"
+	"github.com/florentyang/smartfin-go/internal/config"
+)
+
+// ==================== Redis 缓存 ====================
+// 多个服务实例共享同一份缓存；过期由 Redis 负责（SET ... PX）
+
+// RedisCache Redis 缓存
+type RedisCache struct {
+	client *redis.Client
+	prefix string // 键前缀，多个应用共用一个 Redis 时避免冲突
+}
+
+// NewRedisCache 连接 Redis 并创建缓存，连接失败时返回错误
+func NewRedisCache(cfg *config.CacheConfig) (*RedisCache, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.RedisAddr,
+		Password: cfg.RedisPassword,
+		DB:       cfg.RedisDB,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("连接 Redis 失败: %w", err)
+	}
+
+	return &RedisCache{client: client, prefix: cfg.KeyPrefix}, nil
+}
+
+// Get 读取缓存
+func (c *RedisCache) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := c.client.Get(ctx, c.prefix+key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, cache.ErrCacheMiss
+		}
+		return nil, err
+	}
+	return value, nil
+}
+
+// Set 写入缓存
+func (c *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if ttl < 0 {
+		ttl = 0
+	}
+	return c.client.Set(ctx, c.prefix+key, value, ttl).Err()
+}
+
+// Delete 删除缓存
+func (c *RedisCache) Delete(ctx context.Context, key string) error {
+	return c.client.Del(ctx, c.prefix+key).Err()
+}
+
+// Close 关闭 Redis 连接
+func (c *RedisCache) Close() error {
+	return c.client.Close()
+}
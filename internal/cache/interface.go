@@ -0,0 +1,34 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ==================== 错误定义 ====================
+
+var (
+	// ErrCacheMiss 缓存中没有该键（或已过期）
+	ErrCacheMiss = errors.New("缓存未命中")
+)
+
+// 缓存后端名称
+const (
+	BackendMemory = "memory" // 进程内 LRU 缓存（默认，单实例部署）
+	BackendRedis  = "redis"  // Redis 缓存（多实例共享）
+)
+
+// ==================== 接口定义 ====================
+// 缓存只存字节，序列化由使用方负责；每个后端实现一个 Cache，bootstrap 按配置选择
+
+type Cache interface {
+	// Get 读取缓存，不存在或已过期时返回 ErrCacheMiss
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Set 写入缓存，ttl 后过期（ttl <= 0 表示不过期）
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// Delete 删除缓存，不存在时不报错
+	Delete(ctx context.Context, key string) error
+}
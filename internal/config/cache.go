@@ -0,0 +1,34 @@
+package config
+
+// CacheConfig 缓存配置
+type CacheConfig struct {
+	// Backend 缓存后端：memory（进程内 LRU，默认）/ redis（多实例共享，见 docker-compose 中的 redis 服务）
+	Backend string
+
+	// MaxEntries 进程内缓存最多保存的条目数，超过时淘汰最久未使用的条目
+	MaxEntries int
+
+	// RedisAddr Redis 地址（Backend 为 redis 时使用）
+	RedisAddr string
+
+	// RedisPassword Redis 密码
+	RedisPassword string
+
+	// RedisDB Redis 数据库编号
+	RedisDB int
+
+	// KeyPrefix 缓存键前缀（Redis 由多个应用共用时避免冲突）
+	KeyPrefix string
+}
+
+// DefaultCacheConfig 默认配置（进程内缓存）
+func DefaultCacheConfig() *CacheConfig {
+	return &CacheConfig{
+		Backend:       "memory",
+		MaxEntries:    10000,
+		RedisAddr:     "localhost:6379",
+		RedisPassword: "",
+		RedisDB:       0,
+		KeyPrefix:     "smartfin:",
+	}
+}
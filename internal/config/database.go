@@ -53,6 +53,17 @@ func InitDB(cfg *DatabaseConfig) (*gorm.DB, error) {
 	if err := db.AutoMigrate(
 		&entity.User{},
 		&entity.Transaction{}, // ← 新增 Transaction 表
+		&entity.TaxLot{},
+		&entity.LotAssignment{},
+		&entity.IdempotencyKey{},
+		&entity.FXRate{},
+		&entity.Account{},
+		&entity.JournalLine{},
+		&entity.FeeSchedule{},
+		&entity.Instrument{},
+		&entity.PriceBar{},
+		&entity.PortfolioSnapshot{},
+		&entity.SnapshotHolding{},
 	); err != nil {
 		return nil, fmt.Errorf("数据库迁移失败: %w", err)
 	}
@@ -0,0 +1,14 @@
+package config
+
+// IdempotencyConfig 幂等中间件配置
+type IdempotencyConfig struct {
+	// MaxBodyBytes 带幂等键的请求体上限（需要整体读入内存计算摘要，超过时拒绝），需覆盖批量导入的对账单文件
+	MaxBodyBytes int64
+}
+
+// DefaultIdempotencyConfig 默认配置
+func DefaultIdempotencyConfig() *IdempotencyConfig {
+	return &IdempotencyConfig{
+		MaxBodyBytes: 32 << 20, // 32 MB
+	}
+}
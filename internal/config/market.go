@@ -0,0 +1,16 @@
+package config
+
+// MarketConfig 交易日历配置
+type MarketConfig struct {
+	// HolidayDir 休市日数据目录（可选）
+	// 目录下的 nyse.csv、hkex.csv、sse.csv、szse.csv 覆盖内置的同名数据文件，
+	// 交易所公布新一年的休市安排后无需重新编译；为空时只使用内置数据
+	HolidayDir string
+}
+
+// DefaultMarketConfig 默认配置（只使用内置数据）
+func DefaultMarketConfig() *MarketConfig {
+	return &MarketConfig{
+		HolidayDir: "",
+	}
+}
@@ -0,0 +1,16 @@
+package config
+
+import "github.com/shopspring/decimal"
+
+// PerformanceConfig 业绩分析配置
+type PerformanceConfig struct {
+	// RiskFreeRate 年化无风险利率（计算夏普、索提诺比率），请求未指定时使用；0.02 表示 2%
+	RiskFreeRate decimal.Decimal
+}
+
+// DefaultPerformanceConfig 默认配置
+func DefaultPerformanceConfig() *PerformanceConfig {
+	return &PerformanceConfig{
+		RiskFreeRate: decimal.New(2, -2),
+	}
+}
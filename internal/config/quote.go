@@ -0,0 +1,40 @@
+package config
+
+import "time"
+
+// QuoteConfig 行情配置
+type QuoteConfig struct {
+	// Provider 行情源：static（进程内行情，默认，离线可用）/ file（CSV 文件行情）
+	Provider string
+
+	// File 文件行情源的 CSV 路径（Provider 为 file 时必填）
+	File string
+
+	// Workers 批量查询时同时请求行情源的最大并发数
+	Workers int
+
+	// Timeout 单只股票的查询超时
+	Timeout time.Duration
+
+	// MaxSymbols 单次最多查询的股票数
+	MaxSymbols int
+
+	// CacheTTL 行情缓存的有效期，期内直接返回缓存（<= 0 表示不缓存）
+	CacheTTL time.Duration
+
+	// StaleTTL 缓存过期后继续保留的时长：休市期间先返回过期行情，再在后台刷新（<= 0 表示不返回过期行情）
+	StaleTTL time.Duration
+}
+
+// DefaultQuoteConfig 默认配置（进程内行情源）
+func DefaultQuoteConfig() *QuoteConfig {
+	return &QuoteConfig{
+		Provider:   "static",
+		File:       "",
+		Workers:    8,
+		Timeout:    3 * time.Second,
+		MaxSymbols: 100,
+		CacheTTL:   5 * time.Second,
+		StaleTTL:   72 * time.Hour,
+	}
+}
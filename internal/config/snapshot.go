@@ -0,0 +1,19 @@
+package config
+
+// SnapshotConfig 每日估值快照任务配置
+type SnapshotConfig struct {
+	// RunAt 每天运行的时间（本地时区，HH:MM），生成截止到前一天的快照；
+	// 默认 06:00：美股已收盘（北京时间）、A 股和港股尚未开盘，导入前一天的日线行情后运行
+	RunAt string
+
+	// RunOnStartup 启动时立即运行一次（补齐停机期间缺失的天数）
+	RunOnStartup bool
+}
+
+// DefaultSnapshotConfig 默认配置
+func DefaultSnapshotConfig() *SnapshotConfig {
+	return &SnapshotConfig{
+		RunAt:        "06:00",
+		RunOnStartup: true,
+	}
+}
@@ -0,0 +1,28 @@
+package config
+
+import "time"
+
+// StreamConfig 行情推送配置
+type StreamConfig struct {
+	// Interval 推送 Hub 轮询行情源的间隔（只轮询有人订阅的股票，行情有变化才推送）
+	Interval time.Duration
+
+	// MaxSymbols 单个连接最多订阅的股票数
+	MaxSymbols int
+
+	// SlowClientTimeout 客户端积压行情的最长时间，超过后断开该连接（积压期间同一只股票只保留最新一条）
+	SlowClientTimeout time.Duration
+
+	// Heartbeat 没有行情时发送心跳的间隔（防止代理断开空闲连接）
+	Heartbeat time.Duration
+}
+
+// DefaultStreamConfig 默认配置
+func DefaultStreamConfig() *StreamConfig {
+	return &StreamConfig{
+		Interval:          2 * time.Second,
+		MaxSymbols:        100,
+		SlowClientTimeout: 30 * time.Second,
+		Heartbeat:         15 * time.Second,
+	}
+}
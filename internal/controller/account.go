@@ -0,0 +1,223 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	accountDomain "github.com/florentyang/smartfin-go/internal/domain/account"
+	"github.com/florentyang/smartfin-go/internal/dto"
+	"github.com/florentyang/smartfin-go/internal/service"
+	"github.com/florentyang/smartfin-go/pkg/response"
+)
+
+// ==================== 接口定义 ====================
+
+type AccountController interface {
+	Create(c *gin.Context) // 创建账户
+	List(c *gin.Context)   // 查询账户列表
+	Get(c *gin.Context)    // 查询单个账户
+	Update(c *gin.Context) // 更新账户
+	Delete(c *gin.Context) // 删除账户
+	Cash(c *gin.Context)   // 账户现金流水
+}
+
+// ==================== 结构体 ====================
+
+type accountController struct {
+	accountService service.AccountService
+}
+
+// ==================== 构造函数 ====================
+
+func NewAccountController(accountService service.AccountService) AccountController {
+	return &accountController{accountService: accountService}
+}
+
+// ==================== 接口实现 ====================
+
+// Create 创建账户
+// POST /api/v1/accounts/create
+// 请求体：{ name, broker, number, notes, margin }
+func (ctrl *accountController) Create(c *gin.Context) {
+	// 1. 从 JWT 中间件获取用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "请先登录")
+		return
+	}
+
+	// 2. 绑定请求参数（JSON → DTO）
+	var req dto.CreateAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 3. 调用 Service 层创建
+	account, err := ctrl.accountService.Create(userID.(uint), &req)
+	if err != nil {
+		failAccount(c, err)
+		return
+	}
+
+	// 4. 返回创建的账户
+	response.Success(c, account)
+}
+
+// List 查询账户列表
+// GET /api/v1/accounts/list
+func (ctrl *accountController) List(c *gin.Context) {
+	// 1. 从 JWT 中间件获取用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "请先登录")
+		return
+	}
+
+	// 2. 调用 Service 层查询
+	list, err := ctrl.accountService.List(userID.(uint))
+	if err != nil {
+		failAccount(c, err)
+		return
+	}
+
+	// 3. 返回账户列表
+	response.Success(c, list)
+}
+
+// Get 查询单个账户
+// GET /api/v1/accounts/:id
+func (ctrl *accountController) Get(c *gin.Context) {
+	// 1. 从 JWT 中间件获取用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "请先登录")
+		return
+	}
+
+	// 2. 解析路径参数中的账户ID
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	// 3. 调用 Service 层查询
+	account, err := ctrl.accountService.Get(userID.(uint), id)
+	if err != nil {
+		failAccount(c, err)
+		return
+	}
+
+	// 4. 返回账户
+	response.Success(c, account)
+}
+
+// Update 更新账户
+// PUT /api/v1/accounts/:id
+// 请求体：{ name, broker, number, notes, margin }
+func (ctrl *accountController) Update(c *gin.Context) {
+	// 1. 从 JWT 中间件获取用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "请先登录")
+		return
+	}
+
+	// 2. 解析路径参数中的账户ID
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	// 3. 绑定请求参数（JSON → DTO）
+	var req dto.UpdateAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 4. 调用 Service 层更新
+	account, err := ctrl.accountService.Update(userID.(uint), id, &req)
+	if err != nil {
+		failAccount(c, err)
+		return
+	}
+
+	// 5. 返回更新后的账户
+	response.Success(c, account)
+}
+
+// Delete 删除账户
+// DELETE /api/v1/accounts/:id
+// 账户下还有交易时不能删除
+func (ctrl *accountController) Delete(c *gin.Context) {
+	// 1. 从 JWT 中间件获取用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "请先登录")
+		return
+	}
+
+	// 2. 解析路径参数中的账户ID
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	// 3. 调用 Service 层删除
+	if err := ctrl.accountService.Delete(userID.(uint), id); err != nil {
+		failAccount(c, err)
+		return
+	}
+
+	// 4. 返回成功响应
+	response.Success(c, "删除成功")
+}
+
+// Cash 账户现金流水及余额变化
+// GET /api/v1/accounts/:id/cash
+// Query 参数：currency, start_date, end_date
+func (ctrl *accountController) Cash(c *gin.Context) {
+	// 1. 从 JWT 中间件获取用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "请先登录")
+		return
+	}
+
+	// 2. 解析路径参数中的账户ID
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	// 3. 绑定 Query 参数（URL → DTO）
+	var req dto.AccountCashRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 4. 调用 Service 层回放现金流水
+	cash, err := ctrl.accountService.Cash(userID.(uint), id, &req)
+	if err != nil {
+		failAccount(c, err)
+		return
+	}
+
+	// 5. 返回现金流水
+	response.Success(c, cash)
+}
+
+// ==================== 私有辅助函数 ====================
+
+// failAccount 根据账户模块的错误类型返回不同响应
+func failAccount(c *gin.Context, err error) {
+	if errors.Is(err, accountDomain.ErrAccountNotFound) {
+		response.NotFound(c, err.Error())
+		return
+	}
+	response.Fail(c, http.StatusBadRequest, err.Error())
+}
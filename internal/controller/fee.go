@@ -0,0 +1,188 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	feeDomain "github.com/florentyang/smartfin-go/internal/domain/fee"
+	"github.com/florentyang/smartfin-go/internal/dto"
+	"github.com/florentyang/smartfin-go/internal/service"
+	"github.com/florentyang/smartfin-go/pkg/response"
+)
+
+// ==================== 接口定义 ====================
+
+type FeeController interface {
+	Create(c *gin.Context) // 创建费率表
+	List(c *gin.Context)   // 查询费率表列表
+	Get(c *gin.Context)    // 查询单个费率表
+	Update(c *gin.Context) // 更新费率表
+	Delete(c *gin.Context) // 删除费率表
+}
+
+// ==================== 结构体 ====================
+
+type feeController struct {
+	feeService service.FeeService
+}
+
+// ==================== 构造函数 ====================
+
+func NewFeeController(feeService service.FeeService) FeeController {
+	return &feeController{feeService: feeService}
+}
+
+// ==================== 接口实现 ====================
+
+// Create 创建费率表
+// POST /api/v1/fee-schedules/create
+// 请求体：{ name, account_id, broker, currency, commission_rate, per_share_fee, min_commission, stamp_duty_rate, transfer_fee_rate, levy_rate }
+func (ctrl *feeController) Create(c *gin.Context) {
+	// 1. 从 JWT 中间件获取用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "请先登录")
+		return
+	}
+
+	// 2. 绑定请求参数（JSON → DTO）
+	var req dto.FeeScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 3. 调用 Service 层创建
+	schedule, err := ctrl.feeService.Create(userID.(uint), &req)
+	if err != nil {
+		failFee(c, err)
+		return
+	}
+
+	// 4. 返回创建的费率表
+	response.Success(c, schedule)
+}
+
+// List 查询费率表列表
+// GET /api/v1/fee-schedules/list
+func (ctrl *feeController) List(c *gin.Context) {
+	// 1. 从 JWT 中间件获取用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "请先登录")
+		return
+	}
+
+	// 2. 调用 Service 层查询
+	list, err := ctrl.feeService.List(userID.(uint))
+	if err != nil {
+		failFee(c, err)
+		return
+	}
+
+	// 3. 返回费率表列表
+	response.Success(c, list)
+}
+
+// Get 查询单个费率表
+// GET /api/v1/fee-schedules/:id
+func (ctrl *feeController) Get(c *gin.Context) {
+	// 1. 从 JWT 中间件获取用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "请先登录")
+		return
+	}
+
+	// 2. 解析路径参数中的费率表ID
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	// 3. 调用 Service 层查询
+	schedule, err := ctrl.feeService.Get(userID.(uint), id)
+	if err != nil {
+		failFee(c, err)
+		return
+	}
+
+	// 4. 返回费率表
+	response.Success(c, schedule)
+}
+
+// Update 更新费率表
+// PUT /api/v1/fee-schedules/:id
+// 请求体：{ name, account_id, broker, currency, commission_rate, per_share_fee, min_commission, stamp_duty_rate, transfer_fee_rate, levy_rate }
+func (ctrl *feeController) Update(c *gin.Context) {
+	// 1. 从 JWT 中间件获取用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "请先登录")
+		return
+	}
+
+	// 2. 解析路径参数中的费率表ID
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	// 3. 绑定请求参数（JSON → DTO）
+	var req dto.FeeScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 4. 调用 Service 层更新
+	schedule, err := ctrl.feeService.Update(userID.(uint), id, &req)
+	if err != nil {
+		failFee(c, err)
+		return
+	}
+
+	// 5. 返回更新后的费率表
+	response.Success(c, schedule)
+}
+
+// Delete 删除费率表
+// DELETE /api/v1/fee-schedules/:id
+// 已按该费率表计算的交易保留手续费和明细
+func (ctrl *feeController) Delete(c *gin.Context) {
+	// 1. 从 JWT 中间件获取用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "请先登录")
+		return
+	}
+
+	// 2. 解析路径参数中的费率表ID
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	// 3. 调用 Service 层删除
+	if err := ctrl.feeService.Delete(userID.(uint), id); err != nil {
+		failFee(c, err)
+		return
+	}
+
+	// 4. 返回成功响应
+	response.Success(c, "删除成功")
+}
+
+// ==================== 私有辅助函数 ====================
+
+// failFee 根据费率表模块的错误类型返回不同响应
+// 适用账户不存在按参数错误处理，费率表本身不存在才返回 404
+func failFee(c *gin.Context, err error) {
+	if errors.Is(err, feeDomain.ErrScheduleNotFound) {
+		response.NotFound(c, err.Error())
+		return
+	}
+	response.Fail(c, http.StatusBadRequest, err.Error())
+}
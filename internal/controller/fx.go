@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	fxDomain "github.com/florentyang/smartfin-go/internal/domain/fx"
+	"github.com/florentyang/smartfin-go/internal/dto"
+	"github.com/florentyang/smartfin-go/internal/service"
+	"github.com/florentyang/smartfin-go/pkg/response"
+)
+
+// ==================== 接口定义 ====================
+
+type FXController interface {
+	Import(c *gin.Context) // 批量导入汇率
+	List(c *gin.Context)   // 查询汇率序列
+	Rate(c *gin.Context)   // 按日期查询汇率
+}
+
+// ==================== 结构体 ====================
+
+type fxController struct {
+	fxService service.FXService
+}
+
+// ==================== 构造函数 ====================
+
+func NewFXController(fxService service.FXService) FXController {
+	return &fxController{fxService: fxService}
+}
+
+// ==================== 接口实现 ====================
+
+// Import 批量导入汇率
+// POST /api/v1/fx/import
+// multipart 表单：file（CSV，表头 date,base,quote,rate）
+func (ctrl *fxController) Import(c *gin.Context) {
+	// 1. 读取上传文件
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		response.BadRequest(c, "参数错误: 请上传汇率文件（file 字段）")
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		response.BadRequest(c, "文件读取失败: "+err.Error())
+		return
+	}
+	defer file.Close()
+
+	// 2. 调用 Service 层导入
+	result, err := ctrl.fxService.Import(file)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// 3. 存在错误行时整体未写入，返回错误报告
+	if len(result.Errors) > 0 {
+		response.FailWithData(c, http.StatusBadRequest, "导入失败：存在错误行，未写入任何数据", result)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// List 查询汇率序列
+// GET /api/v1/fx/rates
+// Query 参数：base, quote, start_date, end_date
+func (ctrl *fxController) List(c *gin.Context) {
+	// 1. 绑定 Query 参数（URL → DTO）
+	var req dto.ListFXRatesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 2. 调用 Service 层查询
+	result, err := ctrl.fxService.List(&req)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// Rate 按日期查询汇率
+// GET /api/v1/fx/rate
+// Query 参数：from, to, date
+func (ctrl *fxController) Rate(c *gin.Context) {
+	// 1. 绑定 Query 参数（URL → DTO）
+	var req dto.FXRateRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 2. 调用 Service 层查询
+	result, err := ctrl.fxService.Rate(&req)
+	if err != nil {
+		if errors.Is(err, fxDomain.ErrRateNotFound) {
+			response.NotFound(c, err.Error())
+			return
+		}
+		response.Fail(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.Success(c, result)
+}
@@ -0,0 +1,160 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	instrumentDomain "github.com/florentyang/smartfin-go/internal/domain/instrument"
+	"github.com/florentyang/smartfin-go/internal/dto"
+	"github.com/florentyang/smartfin-go/internal/service"
+	"github.com/florentyang/smartfin-go/pkg/response"
+)
+
+// ==================== 接口定义 ====================
+
+type InstrumentController interface {
+	Create(c *gin.Context) // 创建证券
+	Search(c *gin.Context) // 搜索证券
+	Get(c *gin.Context)    // 查询单个证券
+	Update(c *gin.Context) // 更新证券
+	Delete(c *gin.Context) // 删除证券
+}
+
+// ==================== 结构体 ====================
+
+type instrumentController struct {
+	instrumentService service.InstrumentService
+}
+
+// ==================== 构造函数 ====================
+
+func NewInstrumentController(instrumentService service.InstrumentService) InstrumentController {
+	return &instrumentController{instrumentService: instrumentService}
+}
+
+// ==================== 接口实现 ====================
+
+// Create 创建证券
+// POST /api/v1/instruments/create
+// 请求体：{ symbol, exchange, currency, asset_class, lot_size, name, isin }
+func (ctrl *instrumentController) Create(c *gin.Context) {
+	// 1. 绑定请求参数（JSON → DTO）
+	var req dto.InstrumentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 2. 调用 Service 层创建
+	instrument, err := ctrl.instrumentService.Create(&req)
+	if err != nil {
+		failInstrument(c, err)
+		return
+	}
+
+	// 3. 返回创建的证券
+	response.Success(c, instrument)
+}
+
+// Search 搜索证券
+// GET /api/v1/instruments/search
+// Query 参数：q, exchange, asset_class, page, page_size
+func (ctrl *instrumentController) Search(c *gin.Context) {
+	// 1. 绑定 Query 参数（URL → DTO）
+	var req dto.SearchInstrumentRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 2. 调用 Service 层搜索
+	result, err := ctrl.instrumentService.Search(&req)
+	if err != nil {
+		failInstrument(c, err)
+		return
+	}
+
+	// 3. 返回分页结果
+	response.Success(c, result)
+}
+
+// Get 查询单个证券
+// GET /api/v1/instruments/:id
+func (ctrl *instrumentController) Get(c *gin.Context) {
+	// 1. 解析路径参数中的证券ID
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	// 2. 调用 Service 层查询
+	instrument, err := ctrl.instrumentService.Get(id)
+	if err != nil {
+		failInstrument(c, err)
+		return
+	}
+
+	// 3. 返回证券
+	response.Success(c, instrument)
+}
+
+// Update 更新证券
+// PUT /api/v1/instruments/:id
+// 请求体：{ symbol, exchange, currency, asset_class, lot_size, name, isin }
+func (ctrl *instrumentController) Update(c *gin.Context) {
+	// 1. 解析路径参数中的证券ID
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	// 2. 绑定请求参数（JSON → DTO）
+	var req dto.InstrumentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 3. 调用 Service 层更新
+	instrument, err := ctrl.instrumentService.Update(id, &req)
+	if err != nil {
+		failInstrument(c, err)
+		return
+	}
+
+	// 4. 返回更新后的证券
+	response.Success(c, instrument)
+}
+
+// Delete 删除证券
+// DELETE /api/v1/instruments/:id
+// 交易按股票代码文本关联，删除证券不影响已有交易
+func (ctrl *instrumentController) Delete(c *gin.Context) {
+	// 1. 解析路径参数中的证券ID
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	// 2. 调用 Service 层删除
+	if err := ctrl.instrumentService.Delete(id); err != nil {
+		failInstrument(c, err)
+		return
+	}
+
+	// 3. 返回成功响应
+	response.Success(c, "删除成功")
+}
+
+// ==================== 私有辅助函数 ====================
+
+// failInstrument 根据证券模块的错误类型返回不同响应
+func failInstrument(c *gin.Context, err error) {
+	if errors.Is(err, instrumentDomain.ErrInstrumentNotFound) {
+		response.NotFound(c, err.Error())
+		return
+	}
+	response.Fail(c, http.StatusBadRequest, err.Error())
+}
@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/florentyang/smartfin-go/internal/dto"
+	"github.com/florentyang/smartfin-go/internal/service"
+	"github.com/florentyang/smartfin-go/pkg/response"
+)
+
+// ==================== 接口定义 ====================
+
+type JournalController interface {
+	List(c *gin.Context)         // 查询分录
+	TrialBalance(c *gin.Context) // 试算平衡表
+	Rebuild(c *gin.Context)      // 重新过账全部交易
+}
+
+// ==================== 结构体 ====================
+
+type journalController struct {
+	journalService service.JournalService
+}
+
+// ==================== 构造函数 ====================
+
+func NewJournalController(journalService service.JournalService) JournalController {
+	return &journalController{journalService: journalService}
+}
+
+// ==================== 接口实现 ====================
+
+// List 分页查询分录
+// GET /api/v1/journal/entries
+// Query 参数：page, page_size, account_id, transaction_id, ledger, start_date, end_date
+func (ctrl *journalController) List(c *gin.Context) {
+	// 1. 从 JWT 中间件获取用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "请先登录")
+		return
+	}
+
+	// 2. 绑定 Query 参数（URL → DTO）
+	var req dto.ListJournalRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 3. 调用 Service 层查询
+	result, err := ctrl.journalService.List(userID.(uint), &req)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// 4. 返回分页结果
+	response.Success(c, result)
+}
+
+// TrialBalance 试算平衡表
+// GET /api/v1/journal/trial-balance
+// Query 参数：account_id, end_date
+func (ctrl *journalController) TrialBalance(c *gin.Context) {
+	// 1. 从 JWT 中间件获取用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "请先登录")
+		return
+	}
+
+	// 2. 绑定 Query 参数（URL → DTO）
+	var req dto.TrialBalanceRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 3. 调用 Service 层汇总
+	result, err := ctrl.journalService.TrialBalance(userID.(uint), &req)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// 4. 返回试算平衡表
+	response.Success(c, result)
+}
+
+// Rebuild 按交易流水重新过账全部交易
+// POST /api/v1/journal/rebuild
+// 用于本功能上线前已存在的交易：分录平时在交易增删改时自动过账
+func (ctrl *journalController) Rebuild(c *gin.Context) {
+	// 1. 从 JWT 中间件获取用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "请先登录")
+		return
+	}
+
+	// 2. 调用 Service 层过账
+	result, err := ctrl.journalService.Rebuild(userID.(uint))
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// 3. 返回过账结果
+	response.Success(c, result)
+}
@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/florentyang/smartfin-go/internal/dto"
+	"github.com/florentyang/smartfin-go/internal/service"
+	"github.com/florentyang/smartfin-go/pkg/response"
+)
+
+// ==================== 接口定义 ====================
+
+type LotController interface {
+	List(c *gin.Context)     // 查询批次
+	Realized(c *gin.Context) // 查询已实现盈亏明细
+	Rebuild(c *gin.Context)  // 重建全部批次
+}
+
+// ==================== 结构体 ====================
+
+type lotController struct {
+	lotService service.LotService
+}
+
+// ==================== 构造函数 ====================
+
+func NewLotController(lotService service.LotService) LotController {
+	return &lotController{lotService: lotService}
+}
+
+// ==================== 接口实现 ====================
+
+// List 查询批次
+// GET /api/v1/lots/list
+// Query 参数：account_id, symbol, open_only
+func (ctrl *lotController) List(c *gin.Context) {
+	// 1. 从 JWT 中间件获取用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "请先登录")
+		return
+	}
+
+	// 2. 绑定 Query 参数（URL → DTO）
+	var req dto.ListLotsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 3. 调用 Service 层查询
+	result, err := ctrl.lotService.List(userID.(uint), &req)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// 4. 返回批次列表
+	response.Success(c, result)
+}
+
+// Realized 查询已实现盈亏明细（按批次）
+// GET /api/v1/lots/realized
+// Query 参数：account_id, symbol, sell_id, start_date, end_date
+func (ctrl *lotController) Realized(c *gin.Context) {
+	// 1. 从 JWT 中间件获取用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "请先登录")
+		return
+	}
+
+	// 2. 绑定 Query 参数（URL → DTO）
+	var req dto.RealizedLotsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 3. 调用 Service 层查询
+	result, err := ctrl.lotService.Realized(userID.(uint), &req)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// 4. 返回已实现盈亏明细
+	response.Success(c, result)
+}
+
+// Rebuild 按交易流水重建全部批次
+// POST /api/v1/lots/rebuild
+// 用于本功能上线前已存在的交易：批次平时在交易增删改时自动重建
+func (ctrl *lotController) Rebuild(c *gin.Context) {
+	// 1. 从 JWT 中间件获取用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "请先登录")
+		return
+	}
+
+	// 2. 调用 Service 层重建
+	result, err := ctrl.lotService.Rebuild(userID.(uint))
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// 3. 返回重建结果
+	response.Success(c, result)
+}
@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/florentyang/smartfin-go/internal/dto"
+	"github.com/florentyang/smartfin-go/internal/service"
+	"github.com/florentyang/smartfin-go/pkg/errcode"
+	"github.com/florentyang/smartfin-go/pkg/response"
+)
+
+// ==================== 接口定义 ====================
+
+type MarketController interface {
+	Calendar(c *gin.Context) // 交易日历
+	Quotes(c *gin.Context)   // 批量查询行情
+}
+
+// ==================== 结构体 ====================
+
+type marketController struct {
+	marketService service.MarketService
+}
+
+// ==================== 构造函数 ====================
+
+func NewMarketController(marketService service.MarketService) MarketController {
+	return &marketController{marketService: marketService}
+}
+
+// ==================== 接口实现 ====================
+
+// Calendar 交易日历：每天是否开市、休市节日、交易时段
+// GET /api/v1/market/calendar
+// Query 参数：exchange, start_date, end_date
+func (ctrl *marketController) Calendar(c *gin.Context) {
+	// 1. 绑定 Query 参数（URL → DTO）
+	var req dto.MarketCalendarRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 2. 调用 Service 层查询
+	result, err := ctrl.marketService.Calendar(&req)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// 3. 返回交易日历
+	response.Success(c, result)
+}
+
+// Quotes 批量查询最新行情
+// GET /api/v1/market/quotes
+// Query 参数：symbols（逗号分隔）
+// 部分股票失败时仍返回成功，失败的股票列在 errors 中；全部失败时返回 5002 并附带错误明细
+func (ctrl *marketController) Quotes(c *gin.Context) {
+	// 1. 绑定 Query 参数（URL → DTO）
+	var req dto.MarketQuotesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 2. 调用 Service 层查询（客户端断开时取消）
+	result, err := ctrl.marketService.Quotes(c.Request.Context(), &req)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// 3. 全部失败
+	if len(result.Quotes) == 0 {
+		response.FailWithData(c, errcode.QuoteFetchError, errcode.GetMsg(errcode.QuoteFetchError), result)
+		return
+	}
+
+	response.Success(c, result)
+}
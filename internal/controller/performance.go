@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	fxDomain "github.com/florentyang/smartfin-go/internal/domain/fx"
+	instrumentDomain "github.com/florentyang/smartfin-go/internal/domain/instrument"
+	performanceDomain "github.com/florentyang/smartfin-go/internal/domain/performance"
+	portfolioDomain "github.com/florentyang/smartfin-go/internal/domain/portfolio"
+	priceDomain "github.com/florentyang/smartfin-go/internal/domain/price"
+	"github.com/florentyang/smartfin-go/internal/dto"
+	"github.com/florentyang/smartfin-go/internal/service"
+	"github.com/florentyang/smartfin-go/pkg/response"
+)
+
+// ==================== 接口定义 ====================
+
+type PerformanceController interface {
+	Returns(c *gin.Context) // 收益率（TWR / XIRR）
+	Risk(c *gin.Context)    // 风险指标（波动率、最大回撤、夏普、Beta）
+}
+
+// ==================== 结构体 ====================
+
+type performanceController struct {
+	performanceService service.PerformanceService
+}
+
+// ==================== 构造函数 ====================
+
+func NewPerformanceController(performanceService service.PerformanceService) PerformanceController {
+	return &performanceController{performanceService: performanceService}
+}
+
+// ==================== 接口实现 ====================
+
+// Returns 收益率
+// GET /api/v1/performance/returns
+// Query 参数：account_id, symbol, windows
+// 基于每日估值快照计算，截止到最新快照（昨天）
+func (ctrl *performanceController) Returns(c *gin.Context) {
+	// 1. 从 JWT 中间件获取用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "请先登录")
+		return
+	}
+
+	// 2. 绑定 Query 参数（URL → DTO）
+	var req dto.ReturnsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 3. 调用 Service 层计算
+	result, err := ctrl.performanceService.Returns(userID.(uint), &req)
+	if err != nil {
+		if errors.Is(err, performanceDomain.ErrNoHistory) {
+			response.NotFound(c, err.Error())
+			return
+		}
+		// 区间无效、股票代码无效、缺少汇率按参数错误返回
+		if errors.Is(err, performanceDomain.ErrInvalidWindow) || errors.Is(err, instrumentDomain.ErrInvalidSymbol) || errors.Is(err, fxDomain.ErrRateNotFound) {
+			response.Fail(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		response.Fail(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// 4. 返回收益率
+	response.Success(c, result)
+}
+
+// Risk 风险指标
+// GET /api/v1/performance/risk
+// Query 参数：account_id, symbol, start_date, end_date, benchmark, risk_free_rate
+// 日收益率来自每日估值快照，基准取历史行情库的收盘价
+func (ctrl *performanceController) Risk(c *gin.Context) {
+	// 1. 从 JWT 中间件获取用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "请先登录")
+		return
+	}
+
+	// 2. 绑定 Query 参数（URL → DTO）
+	var req dto.RiskRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 3. 调用 Service 层计算
+	result, err := ctrl.performanceService.Risk(userID.(uint), &req)
+	if err != nil {
+		if errors.Is(err, performanceDomain.ErrNoHistory) {
+			response.NotFound(c, err.Error())
+			return
+		}
+		// 日期格式错误、日期范围无效、无风险利率无效、股票代码无效、基准没有行情、缺少汇率按参数错误返回
+		var parseErr *time.ParseError
+		if errors.As(err, &parseErr) ||
+			errors.Is(err, portfolioDomain.ErrInvalidDateRange) ||
+			errors.Is(err, performanceDomain.ErrInvalidRiskFreeRate) ||
+			errors.Is(err, instrumentDomain.ErrInvalidSymbol) ||
+			errors.Is(err, priceDomain.ErrInstrumentNotFound) ||
+			errors.Is(err, performanceDomain.ErrNoBenchmarkPrices) ||
+			errors.Is(err, fxDomain.ErrRateNotFound) {
+			response.Fail(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		response.Fail(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// 4. 返回风险指标
+	response.Success(c, result)
+}
@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	fxDomain "github.com/florentyang/smartfin-go/internal/domain/fx"
+	portfolioDomain "github.com/florentyang/smartfin-go/internal/domain/portfolio"
+	"github.com/florentyang/smartfin-go/internal/dto"
+	"github.com/florentyang/smartfin-go/internal/service"
+	"github.com/florentyang/smartfin-go/pkg/response"
+)
+
+// ==================== 接口定义 ====================
+
+type PortfolioController interface {
+	Holdings(c *gin.Context)       // 持仓汇总
+	History(c *gin.Context)        // 估值历史（资产曲线）
+	RebuildHistory(c *gin.Context) // 重新生成估值快照
+}
+
+// ==================== 结构体 ====================
+
+type portfolioController struct {
+	portfolioService service.PortfolioService
+}
+
+// ==================== 构造函数 ====================
+
+func NewPortfolioController(portfolioService service.PortfolioService) PortfolioController {
+	return &portfolioController{portfolioService: portfolioService}
+}
+
+// ==================== 接口实现 ====================
+
+// Holdings 持仓汇总
+// GET /api/v1/portfolio/holdings
+// Query 参数：account_id, include_closed, currency
+func (ctrl *portfolioController) Holdings(c *gin.Context) {
+	// 1. 从 JWT 中间件获取用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "请先登录")
+		return
+	}
+
+	// 2. 绑定 Query 参数（URL → DTO）
+	var req dto.HoldingsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 3. 调用 Service 层计算持仓
+	result, err := ctrl.portfolioService.Holdings(userID.(uint), &req)
+	if err != nil {
+		// 缺少汇率需要用户补录，按参数错误返回
+		if errors.Is(err, fxDomain.ErrRateNotFound) {
+			response.Fail(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		response.Fail(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// 4. 返回持仓汇总
+	response.Success(c, result)
+}
+
+// History 估值历史（资产曲线）
+// GET /api/v1/portfolio/history
+// Query 参数：account_id, start_date, end_date, include_holdings
+// 数据来自每日估值快照（后台任务生成），交易变更后缺失的天数由快照补齐队列异步重新生成，查询只读
+func (ctrl *portfolioController) History(c *gin.Context) {
+	// 1. 从 JWT 中间件获取用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "请先登录")
+		return
+	}
+
+	// 2. 绑定 Query 参数（URL → DTO）
+	var req dto.PortfolioHistoryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 3. 调用 Service 层查询
+	result, err := ctrl.portfolioService.History(userID.(uint), &req)
+	if err != nil {
+		// 日期格式错误、日期范围无效、缺少汇率按参数错误返回
+		var parseErr *time.ParseError
+		if errors.As(err, &parseErr) || errors.Is(err, portfolioDomain.ErrInvalidDateRange) || errors.Is(err, fxDomain.ErrRateNotFound) {
+			response.Fail(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		response.Fail(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// 4. 返回估值历史
+	response.Success(c, result)
+}
+
+// RebuildHistory 删除全部估值快照并重新生成（截止到昨天）
+// POST /api/v1/portfolio/history/rebuild
+// 交易变更会自动使快照失效；补导或修正历史行情、汇率后调用此接口
+func (ctrl *portfolioController) RebuildHistory(c *gin.Context) {
+	// 1. 从 JWT 中间件获取用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "请先登录")
+		return
+	}
+
+	// 2. 调用 Service 层重新生成
+	result, err := ctrl.portfolioService.RebuildHistory(userID.(uint))
+	if err != nil {
+		if errors.Is(err, fxDomain.ErrRateNotFound) {
+			response.Fail(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		response.Fail(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// 3. 返回生成的天数
+	response.Success(c, result)
+}
@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	priceDomain "github.com/florentyang/smartfin-go/internal/domain/price"
+	"github.com/florentyang/smartfin-go/internal/dto"
+	"github.com/florentyang/smartfin-go/internal/service"
+	"github.com/florentyang/smartfin-go/pkg/response"
+)
+
+// ==================== 接口定义 ====================
+
+type PriceController interface {
+	Import(c *gin.Context) // 批量导入日线行情
+	List(c *gin.Context)   // 查询日线行情
+}
+
+// ==================== 结构体 ====================
+
+type priceController struct {
+	priceService service.PriceService
+}
+
+// ==================== 构造函数 ====================
+
+func NewPriceController(priceService service.PriceService) PriceController {
+	return &priceController{priceService: priceService}
+}
+
+// ==================== 接口实现 ====================
+
+// Import 批量导入日线行情
+// POST /api/v1/prices/import
+// multipart 表单：file（CSV，表头 date,symbol,close，可选 open,high,low,volume）
+func (ctrl *priceController) Import(c *gin.Context) {
+	// 1. 读取上传文件
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		response.BadRequest(c, "参数错误: 请上传行情文件（file 字段）")
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		response.BadRequest(c, "文件读取失败: "+err.Error())
+		return
+	}
+	defer file.Close()
+
+	// 2. 调用 Service 层导入
+	result, err := ctrl.priceService.Import(file)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// 3. 存在错误行时整体未写入，返回错误报告
+	if len(result.Errors) > 0 {
+		response.FailWithData(c, http.StatusBadRequest, "导入失败：存在错误行，未写入任何数据", result)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// List 查询日线行情
+// GET /api/v1/prices/bars
+// Query 参数：symbol, start_date, end_date
+func (ctrl *priceController) List(c *gin.Context) {
+	// 1. 绑定 Query 参数（URL → DTO）
+	var req dto.ListPriceBarsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 2. 调用 Service 层查询
+	result, err := ctrl.priceService.List(&req)
+	if err != nil {
+		if errors.Is(err, priceDomain.ErrInstrumentNotFound) {
+			response.NotFound(c, err.Error())
+			return
+		}
+		response.Fail(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.Success(c, result)
+}
@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/florentyang/smartfin-go/internal/dto"
+	"github.com/florentyang/smartfin-go/internal/service"
+	"github.com/florentyang/smartfin-go/pkg/response"
+)
+
+// ==================== 接口定义 ====================
+
+type ReportController interface {
+	PnL(c *gin.Context) // 盈亏报表
+}
+
+// ==================== 结构体 ====================
+
+type reportController struct {
+	reportService service.ReportService
+}
+
+// ==================== 构造函数 ====================
+
+func NewReportController(reportService service.ReportService) ReportController {
+	return &reportController{reportService: reportService}
+}
+
+// ==================== 接口实现 ====================
+
+// PnL 盈亏报表（已实现 + 未实现）
+// GET /api/v1/reports/pnl
+// Query 参数：account_id, start_date, end_date, group_by, currency
+func (ctrl *reportController) PnL(c *gin.Context) {
+	// 1. 从 JWT 中间件获取用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "请先登录")
+		return
+	}
+
+	// 2. 绑定 Query 参数（URL → DTO）
+	var req dto.PnLReportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 3. 调用 Service 层计算
+	result, err := ctrl.reportService.PnL(userID.(uint), &req)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// 4. 返回报表
+	response.Success(c, result)
+}
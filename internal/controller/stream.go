@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/florentyang/smartfin-go/internal/config"
+	streamDomain "github.com/florentyang/smartfin-go/internal/domain/stream"
+	"github.com/florentyang/smartfin-go/internal/dto"
+	"github.com/florentyang/smartfin-go/internal/service"
+	"github.com/florentyang/smartfin-go/pkg/response"
+)
+
+// ==================== 接口定义 ====================
+
+type StreamController interface {
+	Quotes(c *gin.Context) // 实时行情推送（SSE）
+	Ticket(c *gin.Context) // 签发推送票据
+}
+
+// ==================== 结构体 ====================
+
+type streamController struct {
+	streamService service.StreamService
+	heartbeat     time.Duration // 心跳间隔（防止代理因空闲断开连接）
+	writeTimeout  time.Duration // 单次写入的超时时间（客户端不读时不会永久阻塞）
+}
+
+// ==================== 构造函数 ====================
+
+func NewStreamController(streamService service.StreamService, cfg *config.StreamConfig) StreamController {
+	ctrl := &streamController{
+		streamService: streamService,
+		heartbeat:     cfg.Heartbeat,
+		writeTimeout:  cfg.SlowClientTimeout,
+	}
+	defaults := config.DefaultStreamConfig()
+	if ctrl.heartbeat <= 0 {
+		ctrl.heartbeat = defaults.Heartbeat
+	}
+	if ctrl.writeTimeout <= 0 {
+		ctrl.writeTimeout = defaults.SlowClientTimeout
+	}
+	return ctrl
+}
+
+// ==================== 接口实现 ====================
+
+// Quotes 实时行情推送（Server-Sent Events）
+// GET /api/v1/market/stream
+// Query 参数：symbols（逗号分隔，不传时订阅当前持仓）、ticket（EventSource 无法设置请求头时使用，由 Ticket 接口签发）
+// 事件：subscribed（订阅的股票）→ quote（行情有变化时推送，格式同批量查询）→ error（客户端过慢被断开）
+// 订阅失败时在建立推送前返回普通 JSON 错误
+func (ctrl *streamController) Quotes(c *gin.Context) {
+	// 1. 从 Context 获取 userID（由 StreamJWTAuth 中间件设置）
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "请先登录")
+		return
+	}
+
+	// 2. 绑定 Query 参数（URL → DTO）
+	var req dto.MarketStreamRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 3. 调用 Service 层订阅
+	stream, err := ctrl.streamService.Subscribe(userID.(uint), &req)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	defer stream.Close()
+
+	// 4. 设置 SSE 响应头
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // 关闭 Nginx 缓冲
+	c.Status(http.StatusOK)
+
+	rc := http.NewResponseController(c.Writer)
+	if err := ctrl.send(c, rc, "subscribed", stream.Subscribed()); err != nil {
+		return
+	}
+
+	// 5. 推送循环：客户端断开、写入失败或被 Hub 断开时结束
+	heartbeat := time.NewTicker(ctrl.heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-stream.Dropped():
+			ctrl.send(c, rc, "error", gin.H{"message": streamDomain.ErrSlowConsumer.Error()})
+			return
+		case <-stream.Ready():
+			for _, q := range stream.Next() {
+				if err := ctrl.send(c, rc, "quote", q); err != nil {
+					return
+				}
+			}
+		case <-heartbeat.C:
+			if err := ctrl.write(c, rc, ": ping\n\n"); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Ticket 签发推送票据
+// POST /api/v1/market/stream/ticket
+// 票据 60 秒内有效、只能使用一次、只能用于推送接口
+func (ctrl *streamController) Ticket(c *gin.Context) {
+	// 1. 从 Context 获取用户信息（由 JWTAuth 中间件设置）
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "请先登录")
+		return
+	}
+	username := c.GetString("username")
+
+	// 2. 调用 Service 层签发
+	resp, err := ctrl.streamService.Ticket(userID.(uint), username)
+	if err != nil {
+		response.Fail(c, http.StatusInternalServerError, "签发票据失败")
+		return
+	}
+
+	// 3. 返回成功响应
+	response.Success(c, resp)
+}
+
+// ==================== 私有辅助函数 ====================
+
+// send 写入一条 SSE 事件（data 为 JSON）
+func (ctrl *streamController) send(c *gin.Context, rc *http.ResponseController, event string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return ctrl.write(c, rc, fmt.Sprintf("event: %s\ndata: %s\n\n", event, payload))
+}
+
+// write 写入并立即刷新；每次写入前设置写超时，客户端长时间不读时写入失败、连接结束
+func (ctrl *streamController) write(c *gin.Context, rc *http.ResponseController, msg string) error {
+	// 不支持写超时的 ResponseWriter（如测试环境）忽略即可
+	_ = rc.SetWriteDeadline(time.Now().Add(ctrl.writeTimeout))
+	if _, err := c.Writer.WriteString(msg); err != nil {
+		return err
+	}
+	return rc.Flush()
+}
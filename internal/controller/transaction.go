@@ -1,12 +1,19 @@
 package controller
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
+	marketDomain "github.com/florentyang/smartfin-go/internal/domain/market"
+	txDomain "github.com/florentyang/smartfin-go/internal/domain/transaction"
 	"github.com/florentyang/smartfin-go/internal/dto"
 	"github.com/florentyang/smartfin-go/internal/service"
+	"github.com/florentyang/smartfin-go/pkg/errcode"
 	"github.com/florentyang/smartfin-go/pkg/response"
 )
 
@@ -14,7 +21,12 @@ import (
 
 type TransactionController interface {
 	Create(c *gin.Context) // 创建交易
+	Get(c *gin.Context)    // 查询单条交易
+	Update(c *gin.Context) // 更新交易
+	Delete(c *gin.Context) // 删除交易
+	Import(c *gin.Context) // 批量导入交易（CSV / IBKR Flex XML / OFX）
 	List(c *gin.Context)   // 查询交易列表
+	Export(c *gin.Context) // 导出交易（CSV / JSON Lines / XLSX）
 }
 
 // ==================== 结构体 ====================
@@ -52,7 +64,7 @@ func (ctrl *transactionController) Create(c *gin.Context) {
 	// 3. 调用 Service 层处理业务
 	tx, err := ctrl.txService.Create(userID.(uint), &req)
 	if err != nil {
-		response.Fail(c, http.StatusBadRequest, err.Error())
+		failTransaction(c, err)
 		return
 	}
 
@@ -60,9 +72,144 @@ func (ctrl *transactionController) Create(c *gin.Context) {
 	response.Success(c, tx)
 }
 
+// Get 查询单条交易记录
+// GET /api/v1/transactions/:id
+func (ctrl *transactionController) Get(c *gin.Context) {
+	// 1. 从 JWT 中间件获取用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "请先登录")
+		return
+	}
+
+	// 2. 解析路径参数中的交易ID
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	// 3. 调用 Service 层查询
+	tx, err := ctrl.txService.Get(userID.(uint), id)
+	if err != nil {
+		failTransaction(c, err)
+		return
+	}
+
+	// 4. 返回交易记录
+	response.Success(c, tx)
+}
+
+// Update 更新交易记录
+// PUT /api/v1/transactions/:id
+// 请求体：{ symbol, name, type, quantity, price, fee, trade_time, notes }
+func (ctrl *transactionController) Update(c *gin.Context) {
+	// 1. 从 JWT 中间件获取用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "请先登录")
+		return
+	}
+
+	// 2. 解析路径参数中的交易ID
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	// 3. 绑定请求参数（JSON → DTO）
+	var req dto.UpdateTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 4. 调用 Service 层更新
+	tx, err := ctrl.txService.Update(userID.(uint), id, &req)
+	if err != nil {
+		failTransaction(c, err)
+		return
+	}
+
+	// 5. 返回更新后的交易记录
+	response.Success(c, tx)
+}
+
+// Delete 删除交易记录
+// DELETE /api/v1/transactions/:id
+func (ctrl *transactionController) Delete(c *gin.Context) {
+	// 1. 从 JWT 中间件获取用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "请先登录")
+		return
+	}
+
+	// 2. 解析路径参数中的交易ID
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	// 3. 调用 Service 层删除
+	if err := ctrl.txService.Delete(userID.(uint), id); err != nil {
+		failTransaction(c, err)
+		return
+	}
+
+	// 4. 返回成功响应
+	response.Success(c, "删除成功")
+}
+
+// Import 批量导入交易（CSV / IBKR Flex XML / OFX）
+// POST /api/v1/transactions/import?format=ibkr_flex&timezone=America/New_York&account_id=1&dry_run=true
+// multipart 表单：file（对账单文件）
+func (ctrl *transactionController) Import(c *gin.Context) {
+	// 1. 从 JWT 中间件获取用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "请先登录")
+		return
+	}
+
+	// 2. 绑定 Query 参数
+	var req dto.ImportTransactionRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 3. 读取上传文件
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		response.BadRequest(c, "参数错误: 请上传对账单文件（file 字段）")
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		response.BadRequest(c, "文件读取失败: "+err.Error())
+		return
+	}
+	defer file.Close()
+
+	// 4. 调用 Service 层导入
+	result, err := ctrl.txService.Import(userID.(uint), &req, file)
+	if err != nil {
+		response.Fail(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// 5. 存在错误行时整体未写入，返回错误报告
+	if len(result.Errors) > 0 {
+		response.FailWithData(c, http.StatusBadRequest, "导入失败：存在错误行，未写入任何数据", result)
+		return
+	}
+
+	response.Success(c, result)
+}
+
 // List 查询交易列表
 // GET /api/v1/transactions
-// Query 参数：page, page_size, symbol, type, start_date, end_date
+// Query 参数：page, page_size, account_id, symbol, type, start_date, end_date
 func (ctrl *transactionController) List(c *gin.Context) {
 	// 1. 从 JWT 中间件获取用户ID
 	userID, exists := c.Get("userID")
@@ -88,3 +235,94 @@ func (ctrl *transactionController) List(c *gin.Context) {
 	// 4. 返回分页数据
 	response.Success(c, result)
 }
+
+// exportContentTypes 导出格式 → Content-Type
+var exportContentTypes = map[string]string{
+	service.ExportFormatCSV:   "text/csv; charset=utf-8",
+	service.ExportFormatJSONL: "application/x-ndjson; charset=utf-8",
+	service.ExportFormatXLSX:  "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// Export 导出交易
+// GET /api/v1/transactions/export?format=csv|jsonl|xlsx
+// Query 参数：format, account_id, symbol, type, start_date, end_date（筛选条件与 List 一致）
+// 以附件形式流式返回文件，不经过统一 JSON 响应
+func (ctrl *transactionController) Export(c *gin.Context) {
+	// 1. 从 JWT 中间件获取用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "请先登录")
+		return
+	}
+
+	// 2. 绑定 Query 参数
+	var req dto.ExportTransactionRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+	if req.Format == "" {
+		req.Format = service.ExportFormatCSV
+	}
+
+	// 3. 设置下载响应头（响应头在第一次写出数据时才发送）
+	filename := fmt.Sprintf("transactions_%s.%s", time.Now().Format("20060102150405"), req.Format)
+	c.Header("Content-Type", exportContentTypes[req.Format])
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	// 4. 调用 Service 层边查询边写出
+	if err := ctrl.txService.Export(userID.(uint), &req, c.Writer); err != nil {
+		// 还没写出数据：撤销下载响应头，返回 JSON 错误
+		if !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Type")
+			c.Writer.Header().Del("Content-Disposition")
+			failExport(c, err)
+			return
+		}
+		// 已经开始写出：状态码已发送无法修改，文件末尾已追加错误标记，记录错误
+		_ = c.Error(err)
+		c.Abort()
+	}
+}
+
+// ==================== 私有辅助函数 ====================
+
+// failExport 导出失败（尚未写出数据）时的响应
+// 参数错误按交易模块的错误类型返回；读取交易失败属于内部错误，不向客户端暴露数据库错误详情
+func failExport(c *gin.Context, err error) {
+	var parseErr *time.ParseError
+	if errors.As(err, &parseErr) || errors.Is(err, service.ErrInvalidExportFormat) {
+		failTransaction(c, err)
+		return
+	}
+	_ = c.Error(err)
+	response.Fail(c, http.StatusInternalServerError, "导出失败，请稍后重试")
+}
+
+// parseIDParam 解析路径参数 :id
+// 解析失败时直接写入 400 响应并返回 false
+func parseIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		response.BadRequest(c, "参数错误: id 无效")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// failTransaction 根据交易模块的错误类型返回不同响应
+func failTransaction(c *gin.Context, err error) {
+	if errors.Is(err, txDomain.ErrTransactionNotFound) {
+		response.NotFound(c, err.Error())
+		return
+	}
+	if errors.Is(err, txDomain.ErrInsufficientPosition) || errors.Is(err, txDomain.ErrInsufficientCash) {
+		response.Fail(c, errcode.InsufficientBalance, err.Error())
+		return
+	}
+	if errors.Is(err, marketDomain.ErrMarketClosed) {
+		response.Fail(c, errcode.MarketClosed, err.Error())
+		return
+	}
+	response.Fail(c, http.StatusBadRequest, err.Error())
+}
@@ -0,0 +1,90 @@
+package impl
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+
+	accountRepo "github.com/florentyang/smartfin-go/internal/dao/account"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== Repository 结构体 ====================
+
+type repository struct {
+	db *gorm.DB
+}
+
+// ==================== 构造函数 ====================
+
+// NewAccountRepo 创建 DAO 实例
+func NewAccountRepo(db *gorm.DB) accountRepo.Repo {
+	return &repository{db: db}
+}
+
+// ==================== 接口实现 ====================
+
+// WithTx 返回绑定到指定数据库事务的 Repo
+func (r *repository) WithTx(tx *gorm.DB) accountRepo.Repo {
+	return &repository{db: tx}
+}
+
+// Create 创建账户
+func (r *repository) Create(account *entity.Account) error {
+	return r.db.Create(account).Error
+}
+
+// GetByID 按 ID 查找账户
+func (r *repository) GetByID(id uint) (*entity.Account, error) {
+	var account entity.Account
+	err := r.db.First(&account, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, accountRepo.ErrAccountNotFound
+		}
+		return nil, err
+	}
+	return &account, nil
+}
+
+// Update 更新账户
+func (r *repository) Update(account *entity.Account) error {
+	return r.db.Save(account).Error
+}
+
+// Delete 删除账户
+func (r *repository) Delete(id uint) error {
+	result := r.db.Delete(&entity.Account{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return accountRepo.ErrAccountNotFound
+	}
+	return nil
+}
+
+// FindByUserID 查询用户的全部账户
+func (r *repository) FindByUserID(userID uint) ([]*entity.Account, error) {
+	var accounts []*entity.Account
+	err := r.db.Where("user_id = ?", userID).
+		Order("id ASC").
+		Find(&accounts).Error
+	if err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// ExistsByName 检查用户下是否已有同名账户
+func (r *repository) ExistsByName(userID uint, name string, excludeID uint) (bool, error) {
+	var count int64
+	query := r.db.Model(&entity.Account{}).Where("user_id = ? AND name = ?", userID, name)
+	if excludeID != 0 {
+		query = query.Where("id <> ?", excludeID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,42 @@
+package account
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 错误定义 ====================
+// DAO 层的错误，供上层判断使用
+
+var (
+	ErrAccountNotFound = errors.New("账户不存在")
+)
+
+// ==================== 接口定义 ====================
+// Domain 层会依赖这个接口
+
+type Repo interface {
+	// WithTx 返回绑定到指定数据库事务的 Repo
+	WithTx(tx *gorm.DB) Repo
+
+	// Create 创建账户
+	Create(account *entity.Account) error
+
+	// GetByID 按 ID 查找账户
+	GetByID(id uint) (*entity.Account, error)
+
+	// Update 更新账户
+	Update(account *entity.Account) error
+
+	// Delete 删除账户
+	Delete(id uint) error
+
+	// FindByUserID 查询用户的全部账户（按创建顺序）
+	FindByUserID(userID uint) ([]*entity.Account, error)
+
+	// ExistsByName 检查用户下是否已有同名账户（excludeID 为修改时排除的账户自身，可为 0）
+	ExistsByName(userID uint, name string, excludeID uint) (bool, error)
+}
@@ -0,0 +1,77 @@
+package impl
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+
+	feeRepo "github.com/florentyang/smartfin-go/internal/dao/fee"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== Repository 结构体 ====================
+
+type repository struct {
+	db *gorm.DB
+}
+
+// ==================== 构造函数 ====================
+
+// NewFeeRepo 创建 DAO 实例
+func NewFeeRepo(db *gorm.DB) feeRepo.Repo {
+	return &repository{db: db}
+}
+
+// ==================== 接口实现 ====================
+
+// WithTx 返回绑定到指定数据库事务的 Repo
+func (r *repository) WithTx(tx *gorm.DB) feeRepo.Repo {
+	return &repository{db: tx}
+}
+
+// Create 创建费率表
+func (r *repository) Create(schedule *entity.FeeSchedule) error {
+	return r.db.Create(schedule).Error
+}
+
+// GetByID 按 ID 查找费率表
+func (r *repository) GetByID(id uint) (*entity.FeeSchedule, error) {
+	var schedule entity.FeeSchedule
+	err := r.db.First(&schedule, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, feeRepo.ErrScheduleNotFound
+		}
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// Update 更新费率表
+func (r *repository) Update(schedule *entity.FeeSchedule) error {
+	return r.db.Save(schedule).Error
+}
+
+// Delete 删除费率表
+func (r *repository) Delete(id uint) error {
+	result := r.db.Delete(&entity.FeeSchedule{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return feeRepo.ErrScheduleNotFound
+	}
+	return nil
+}
+
+// FindByUserID 查询用户的全部费率表
+func (r *repository) FindByUserID(userID uint) ([]*entity.FeeSchedule, error) {
+	var schedules []*entity.FeeSchedule
+	err := r.db.Where("user_id = ?", userID).
+		Order("id ASC").
+		Find(&schedules).Error
+	if err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
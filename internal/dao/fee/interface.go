@@ -0,0 +1,39 @@
+package fee
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 错误定义 ====================
+// DAO 层的错误，供上层判断使用
+
+var (
+	ErrScheduleNotFound = errors.New("费率表不存在")
+)
+
+// ==================== 接口定义 ====================
+// Domain 层会依赖这个接口
+
+type Repo interface {
+	// WithTx 返回绑定到指定数据库事务的 Repo
+	WithTx(tx *gorm.DB) Repo
+
+	// Create 创建费率表
+	Create(schedule *entity.FeeSchedule) error
+
+	// GetByID 按 ID 查找费率表
+	GetByID(id uint) (*entity.FeeSchedule, error)
+
+	// Update 更新费率表
+	Update(schedule *entity.FeeSchedule) error
+
+	// Delete 删除费率表
+	Delete(id uint) error
+
+	// FindByUserID 查询用户的全部费率表（按创建顺序）
+	FindByUserID(userID uint) ([]*entity.FeeSchedule, error)
+}
@@ -0,0 +1,78 @@
+package impl
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	fxRepo "github.com/florentyang/smartfin-go/internal/dao/fxrate"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// upsertBatchSize 批量写入时每批的行数
+const upsertBatchSize = 500
+
+// ==================== Repository 结构体 ====================
+
+type repository struct {
+	db *gorm.DB
+}
+
+// ==================== 构造函数 ====================
+
+// NewFXRateRepo 创建 DAO 实例
+func NewFXRateRepo(db *gorm.DB) fxRepo.Repo {
+	return &repository{db: db}
+}
+
+// ==================== 接口实现 ====================
+
+// Upsert 批量写入汇率，货币对 + 日期冲突时更新汇率
+func (r *repository) Upsert(rates []*entity.FXRate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "base"}, {Name: "quote"}, {Name: "rate_date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "updated_at"}),
+	}).CreateInBatches(rates, upsertBatchSize).Error
+}
+
+// FindRates 按筛选条件查询汇率
+func (r *repository) FindRates(filter *fxRepo.RateFilter) ([]*entity.FXRate, error) {
+	var rates []*entity.FXRate
+
+	query := r.db.Model(&entity.FXRate{}).
+		Where("base = ? AND quote = ?", filter.Base, filter.Quote)
+
+	// 按日期范围筛选
+	if filter.StartDate != nil {
+		query = query.Where("rate_date >= ?", filter.StartDate)
+	}
+	if filter.EndDate != nil {
+		query = query.Where("rate_date < ?", filter.EndDate)
+	}
+
+	if err := query.Order("rate_date ASC").Find(&rates).Error; err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+// FindOnOrBefore 查询指定日期当天或之前最近的一条汇率
+func (r *repository) FindOnOrBefore(base, quote string, date time.Time) (*entity.FXRate, error) {
+	var rate entity.FXRate
+	err := r.db.
+		Where("base = ? AND quote = ? AND rate_date <= ?", base, quote, date).
+		Order("rate_date DESC").
+		First(&rate).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fxRepo.ErrRateNotFound
+		}
+		return nil, err
+	}
+	return &rate, nil
+}
@@ -0,0 +1,38 @@
+package fxrate
+
+import (
+	"errors"
+	"time"
+
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 错误定义 ====================
+
+var ErrRateNotFound = errors.New("汇率不存在")
+
+// ==================== 查询条件结构体 ====================
+
+// RateFilter 查询汇率的筛选条件
+type RateFilter struct {
+	Base      string     // 基础货币（必须）
+	Quote     string     // 计价货币（必须）
+	StartDate *time.Time // 开始日期（可选）
+	EndDate   *time.Time // 结束日期（可选，不含）
+}
+
+// ==================== 接口定义 ====================
+// Domain 层会依赖这个接口
+
+type Repo interface {
+	// Upsert 批量写入汇率
+	// 同一货币对同一天已存在时覆盖汇率（重复导入同一份文件结果不变）
+	Upsert(rates []*entity.FXRate) error
+
+	// FindRates 按筛选条件查询汇率（按日期正序）
+	FindRates(filter *RateFilter) ([]*entity.FXRate, error)
+
+	// FindOnOrBefore 查询指定日期当天或之前最近的一条汇率
+	// 不存在时返回 ErrRateNotFound
+	FindOnOrBefore(base, quote string, date time.Time) (*entity.FXRate, error)
+}
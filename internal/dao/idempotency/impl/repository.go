@@ -0,0 +1,68 @@
+package impl
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	idempotencyRepo "github.com/florentyang/smartfin-go/internal/dao/idempotency"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== Repository 结构体 ====================
+
+type repository struct {
+	db *gorm.DB
+}
+
+// ==================== 构造函数 ====================
+
+// NewIdempotencyRepo 创建 DAO 实例
+func NewIdempotencyRepo(db *gorm.DB) idempotencyRepo.Repo {
+	return &repository{db: db}
+}
+
+// ==================== 接口实现 ====================
+
+// Create 创建幂等键记录
+// 使用 INSERT ... ON DUPLICATE KEY 忽略冲突，通过影响行数判断键是否已被占用
+func (r *repository) Create(record *entity.IdempotencyKey) error {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return idempotencyRepo.ErrKeyExists
+	}
+	return nil
+}
+
+// GetByKey 按用户和键查找记录
+func (r *repository) GetByKey(userID uint, key string) (*entity.IdempotencyKey, error) {
+	var record entity.IdempotencyKey
+	err := r.db.Where("user_id = ? AND `key` = ?", userID, key).First(&record).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, idempotencyRepo.ErrKeyNotFound
+		}
+		return nil, err
+	}
+	return &record, nil
+}
+
+// SaveResponse 保存首次请求的响应
+func (r *repository) SaveResponse(id uint, statusCode int, contentType string, body []byte) error {
+	return r.db.Model(&entity.IdempotencyKey{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status_code":  statusCode,
+			"content_type": contentType,
+			"response":     body,
+		}).Error
+}
+
+// Delete 删除记录
+func (r *repository) Delete(id uint) error {
+	return r.db.Delete(&entity.IdempotencyKey{}, id).Error
+}
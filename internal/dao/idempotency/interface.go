@@ -0,0 +1,32 @@
+package idempotency
+
+import (
+	"errors"
+
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 错误定义 ====================
+
+var (
+	ErrKeyNotFound = errors.New("幂等键不存在")
+	ErrKeyExists   = errors.New("幂等键已存在")
+)
+
+// ==================== 接口定义 ====================
+// Domain 层会依赖这个接口
+
+type Repo interface {
+	// Create 创建幂等键记录
+	// 同一用户的同一个键已存在时返回 ErrKeyExists（依赖唯一索引，并发安全）
+	Create(record *entity.IdempotencyKey) error
+
+	// GetByKey 按用户和键查找记录
+	GetByKey(userID uint, key string) (*entity.IdempotencyKey, error)
+
+	// SaveResponse 保存首次请求的响应
+	SaveResponse(id uint, statusCode int, contentType string, body []byte) error
+
+	// Delete 删除记录
+	Delete(id uint) error
+}
@@ -0,0 +1,157 @@
+package impl
+
+import (
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+
+	instrumentRepo "github.com/florentyang/smartfin-go/internal/dao/instrument"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// likeEscaper 转义 LIKE 通配符，关键字中的 % 和 _ 按普通字符匹配
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// ==================== Repository 结构体 ====================
+
+type repository struct {
+	db *gorm.DB
+}
+
+// ==================== 构造函数 ====================
+
+// NewInstrumentRepo 创建 DAO 实例
+func NewInstrumentRepo(db *gorm.DB) instrumentRepo.Repo {
+	return &repository{db: db}
+}
+
+// ==================== 接口实现 ====================
+
+// WithTx 返回绑定到指定数据库事务的 Repo
+func (r *repository) WithTx(tx *gorm.DB) instrumentRepo.Repo {
+	return &repository{db: tx}
+}
+
+// Create 创建证券
+func (r *repository) Create(instrument *entity.Instrument) error {
+	return r.db.Create(instrument).Error
+}
+
+// GetByID 按 ID 查找证券
+func (r *repository) GetByID(id uint) (*entity.Instrument, error) {
+	var instrument entity.Instrument
+	err := r.db.First(&instrument, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, instrumentRepo.ErrInstrumentNotFound
+		}
+		return nil, err
+	}
+	return &instrument, nil
+}
+
+// GetBySymbol 按规范化的股票代码查找证券
+func (r *repository) GetBySymbol(symbol string) (*entity.Instrument, error) {
+	var instrument entity.Instrument
+	err := r.db.Where("symbol = ?", symbol).First(&instrument).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, instrumentRepo.ErrInstrumentNotFound
+		}
+		return nil, err
+	}
+	return &instrument, nil
+}
+
+// FindBySymbols 按规范化的股票代码批量查找证券
+func (r *repository) FindBySymbols(symbols []string) ([]*entity.Instrument, error) {
+	var list []*entity.Instrument
+	if len(symbols) == 0 {
+		return list, nil
+	}
+	if err := r.db.Where("symbol IN ?", symbols).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// GetByISIN 按 ISIN 查找证券
+func (r *repository) GetByISIN(isin string) (*entity.Instrument, error) {
+	var instrument entity.Instrument
+	err := r.db.Where("isin = ?", isin).Order("id ASC").First(&instrument).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, instrumentRepo.ErrInstrumentNotFound
+		}
+		return nil, err
+	}
+	return &instrument, nil
+}
+
+// ExistsBySymbol 检查股票代码是否已存在
+func (r *repository) ExistsBySymbol(symbol string, excludeID uint) (bool, error) {
+	var count int64
+	query := r.db.Model(&entity.Instrument{}).Where("symbol = ?", symbol)
+	if excludeID != 0 {
+		query = query.Where("id <> ?", excludeID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Update 更新证券
+func (r *repository) Update(instrument *entity.Instrument) error {
+	return r.db.Save(instrument).Error
+}
+
+// Delete 删除证券
+func (r *repository) Delete(id uint) error {
+	result := r.db.Delete(&entity.Instrument{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return instrumentRepo.ErrInstrumentNotFound
+	}
+	return nil
+}
+
+// Search 按条件分页搜索证券
+func (r *repository) Search(filter *instrumentRepo.SearchFilter) ([]*entity.Instrument, int64, error) {
+	var list []*entity.Instrument
+	var total int64
+
+	// ===== 构建查询条件 =====
+	query := r.db.Model(&entity.Instrument{})
+	if filter.Query != "" {
+		keyword := likeEscaper.Replace(filter.Query)
+		query = query.Where("symbol LIKE ? OR name LIKE ? OR isin = ?",
+			keyword+"%", "%"+keyword+"%", filter.Query)
+	}
+	if filter.Exchange != "" {
+		query = query.Where("exchange = ?", filter.Exchange)
+	}
+	if filter.AssetClass != "" {
+		query = query.Where("asset_class = ?", filter.AssetClass)
+	}
+
+	// ===== 先查询总数（分页前） =====
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// ===== 分页 + 排序 + 查询数据 =====
+	err := query.
+		Order("symbol ASC").
+		Limit(filter.PageSize).
+		Offset((filter.Page - 1) * filter.PageSize).
+		Find(&list).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return list, total, nil
+}
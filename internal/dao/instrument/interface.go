@@ -0,0 +1,62 @@
+package instrument
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 错误定义 ====================
+// DAO 层的错误，供上层判断使用
+
+var (
+	ErrInstrumentNotFound = errors.New("证券不存在")
+)
+
+// ==================== 查询条件 ====================
+
+// SearchFilter 搜索证券的条件
+type SearchFilter struct {
+	Query      string // 关键字（可选）：代码前缀、名称包含或 ISIN 完全匹配
+	Exchange   string // 交易所（可选）
+	AssetClass string // 资产类别（可选）
+	Page       int    // 页码
+	PageSize   int    // 每页条数
+}
+
+// ==================== 接口定义 ====================
+// Domain 层会依赖这个接口
+
+type Repo interface {
+	// WithTx 返回绑定到指定数据库事务的 Repo
+	WithTx(tx *gorm.DB) Repo
+
+	// Create 创建证券
+	Create(instrument *entity.Instrument) error
+
+	// GetByID 按 ID 查找证券
+	GetByID(id uint) (*entity.Instrument, error)
+
+	// GetBySymbol 按规范化的股票代码查找证券
+	GetBySymbol(symbol string) (*entity.Instrument, error)
+
+	// FindBySymbols 按规范化的股票代码批量查找证券（主数据中没有的代码不返回）
+	FindBySymbols(symbols []string) ([]*entity.Instrument, error)
+
+	// GetByISIN 按 ISIN 查找证券（有多条时取最早创建的）
+	GetByISIN(isin string) (*entity.Instrument, error)
+
+	// ExistsBySymbol 检查股票代码是否已存在（excludeID 为修改时排除的证券自身，可为 0）
+	ExistsBySymbol(symbol string, excludeID uint) (bool, error)
+
+	// Update 更新证券
+	Update(instrument *entity.Instrument) error
+
+	// Delete 删除证券
+	Delete(id uint) error
+
+	// Search 按条件分页搜索证券（按代码排序）
+	Search(filter *SearchFilter) ([]*entity.Instrument, int64, error)
+}
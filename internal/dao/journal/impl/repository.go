@@ -0,0 +1,126 @@
+package impl
+
+import (
+	"gorm.io/gorm"
+
+	journalRepo "github.com/florentyang/smartfin-go/internal/dao/journal"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== Repository 结构体 ====================
+
+type repository struct {
+	db *gorm.DB
+}
+
+// ==================== 构造函数 ====================
+
+// NewJournalRepo 创建 DAO 实例
+func NewJournalRepo(db *gorm.DB) journalRepo.Repo {
+	return &repository{db: db}
+}
+
+// ==================== 接口实现 ====================
+
+// WithTx 返回绑定到指定数据库事务的 Repo
+func (r *repository) WithTx(tx *gorm.DB) journalRepo.Repo {
+	return &repository{db: tx}
+}
+
+// ReplaceSymbol 替换用户某只股票的全部分录行
+func (r *repository) ReplaceSymbol(userID uint, symbol string, lines []*entity.JournalLine) error {
+	// 1. 删除旧的分录行
+	if err := r.db.Where("user_id = ? AND symbol = ?", userID, symbol).
+		Delete(&entity.JournalLine{}).Error; err != nil {
+		return err
+	}
+
+	// 2. 写入新的分录行
+	if len(lines) > 0 {
+		if err := r.db.Create(lines).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FindLines 按筛选条件分页查询分录行
+func (r *repository) FindLines(filter *journalRepo.LineFilter) ([]*entity.JournalLine, int64, error) {
+	var lines []*entity.JournalLine
+	var total int64
+
+	query := r.db.Model(&entity.JournalLine{}).Where("user_id = ?", filter.UserID)
+
+	// 按账户筛选
+	if filter.AccountID != nil {
+		query = query.Where("account_id = ?", *filter.AccountID)
+	}
+
+	// 按来源交易筛选
+	if filter.TransactionID != 0 {
+		query = query.Where("transaction_id = ?", filter.TransactionID)
+	}
+
+	// 按会计科目筛选
+	if filter.Ledger != "" {
+		query = query.Where("ledger = ?", filter.Ledger)
+	}
+
+	// 按记账时间范围筛选
+	if filter.StartTime != nil {
+		query = query.Where("posted_at >= ?", filter.StartTime)
+	}
+	if filter.EndTime != nil {
+		query = query.Where("posted_at < ?", filter.EndTime)
+	}
+
+	// 先查询总数（分页前）
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 分页 + 排序：同一笔交易的分录行排在一起
+	offset := (filter.Page - 1) * filter.PageSize
+	err := query.
+		Order("posted_at ASC").
+		Order("transaction_id ASC").
+		Order("id ASC").
+		Limit(filter.PageSize).
+		Offset(offset).
+		Find(&lines).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return lines, total, nil
+}
+
+// SumByLedger 按会计科目 + 币种汇总借贷发生额
+func (r *repository) SumByLedger(filter *journalRepo.BalanceFilter) ([]*journalRepo.LedgerTotal, error) {
+	var totals []*journalRepo.LedgerTotal
+
+	query := r.db.Model(&entity.JournalLine{}).Where("user_id = ?", filter.UserID)
+
+	// 按账户筛选
+	if filter.AccountID != nil {
+		query = query.Where("account_id = ?", *filter.AccountID)
+	}
+
+	// 按截止时间筛选
+	if filter.EndTime != nil {
+		query = query.Where("posted_at < ?", filter.EndTime)
+	}
+
+	err := query.
+		Select("ledger, currency, SUM(debit) AS debit, SUM(credit) AS credit").
+		Group("ledger, currency").
+		Order("currency ASC").
+		Order("ledger ASC").
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
@@ -0,0 +1,60 @@
+package journal
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 查询条件结构体 ====================
+
+// LineFilter 查询分录行的筛选条件
+type LineFilter struct {
+	UserID        uint       // 用户ID（必须）
+	AccountID     *uint      // 账户ID（可选，nil 表示全部账户）
+	TransactionID uint       // 来源交易ID（可选）
+	Ledger        string     // 会计科目（可选）
+	StartTime     *time.Time // 记账时间起（可选）
+	EndTime       *time.Time // 记账时间止（可选，不含）
+	Page          int        // 页码
+	PageSize      int        // 每页条数
+}
+
+// BalanceFilter 试算平衡的筛选条件
+type BalanceFilter struct {
+	UserID    uint       // 用户ID（必须）
+	AccountID *uint      // 账户ID（可选，nil 表示全部账户）
+	EndTime   *time.Time // 截止时间（可选，不含）
+}
+
+// ==================== 查询结果结构体 ====================
+
+// LedgerTotal 按会计科目 + 币种汇总的借贷发生额
+type LedgerTotal struct {
+	Ledger   string
+	Currency string
+	Debit    decimal.Decimal
+	Credit   decimal.Decimal
+}
+
+// ==================== 接口定义 ====================
+// Domain 层会依赖这个接口
+
+type Repo interface {
+	// WithTx 返回绑定到指定数据库事务的 Repo
+	WithTx(tx *gorm.DB) Repo
+
+	// ReplaceSymbol 替换用户某只股票（symbol 为空表示没有股票代码的现金交易）的全部分录行
+	// 先删除旧数据再写入新数据，应在事务中调用
+	ReplaceSymbol(userID uint, symbol string, lines []*entity.JournalLine) error
+
+	// FindLines 按筛选条件分页查询分录行（按记账时间、交易ID正序）
+	// 返回：分录行、总条数、错误
+	FindLines(filter *LineFilter) ([]*entity.JournalLine, int64, error)
+
+	// SumByLedger 按会计科目 + 币种汇总借贷发生额
+	SumByLedger(filter *BalanceFilter) ([]*LedgerTotal, error)
+}
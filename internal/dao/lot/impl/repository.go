@@ -0,0 +1,127 @@
+package impl
+
+import (
+	"gorm.io/gorm"
+
+	lotRepo "github.com/florentyang/smartfin-go/internal/dao/lot"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== Repository 结构体 ====================
+
+type repository struct {
+	db *gorm.DB
+}
+
+// ==================== 构造函数 ====================
+
+// NewLotRepo 创建 DAO 实例
+func NewLotRepo(db *gorm.DB) lotRepo.Repo {
+	return &repository{db: db}
+}
+
+// ==================== 接口实现 ====================
+
+// WithTx 返回绑定到指定数据库事务的 Repo
+func (r *repository) WithTx(tx *gorm.DB) lotRepo.Repo {
+	return &repository{db: tx}
+}
+
+// ReplaceSymbol 替换用户某只股票的全部批次和分配记录
+func (r *repository) ReplaceSymbol(userID uint, symbol string, lots []*entity.TaxLot, assignments []*entity.LotAssignment) error {
+	// 1. 删除旧的分配记录和批次
+	if err := r.db.Where("user_id = ? AND symbol = ?", userID, symbol).
+		Delete(&entity.LotAssignment{}).Error; err != nil {
+		return err
+	}
+	if err := r.db.Where("user_id = ? AND symbol = ?", userID, symbol).
+		Delete(&entity.TaxLot{}).Error; err != nil {
+		return err
+	}
+
+	// 2. 写入新的批次和分配记录
+	if len(lots) > 0 {
+		if err := r.db.Create(lots).Error; err != nil {
+			return err
+		}
+	}
+	if len(assignments) > 0 {
+		if err := r.db.Create(assignments).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FindLots 按筛选条件查询批次
+func (r *repository) FindLots(filter *lotRepo.LotFilter) ([]*entity.TaxLot, error) {
+	var lots []*entity.TaxLot
+
+	query := r.db.Model(&entity.TaxLot{}).Where("user_id = ?", filter.UserID)
+
+	// 按账户筛选
+	if filter.AccountID != nil {
+		query = query.Where("account_id = ?", *filter.AccountID)
+	}
+
+	// 按股票代码筛选
+	if filter.Symbol != "" {
+		query = query.Where("symbol = ?", filter.Symbol)
+	}
+
+	// 只查询未平仓批次
+	if filter.OpenOnly {
+		query = query.Where("remaining_quantity > 0")
+	}
+
+	err := query.
+		Order("open_time ASC").
+		Order("id ASC").
+		Find(&lots).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return lots, nil
+}
+
+// FindAssignments 按筛选条件查询分配记录
+func (r *repository) FindAssignments(filter *lotRepo.AssignmentFilter) ([]*entity.LotAssignment, error) {
+	var assignments []*entity.LotAssignment
+
+	query := r.db.Model(&entity.LotAssignment{}).Where("user_id = ?", filter.UserID)
+
+	// 按账户筛选
+	if filter.AccountID != nil {
+		query = query.Where("account_id = ?", *filter.AccountID)
+	}
+
+	// 按股票代码筛选
+	if filter.Symbol != "" {
+		query = query.Where("symbol = ?", filter.Symbol)
+	}
+
+	// 按卖出交易筛选
+	if filter.SellTransactionID != 0 {
+		query = query.Where("sell_transaction_id = ?", filter.SellTransactionID)
+	}
+
+	// 按卖出时间范围筛选
+	if filter.StartTime != nil {
+		query = query.Where("close_time >= ?", filter.StartTime)
+	}
+	if filter.EndTime != nil {
+		query = query.Where("close_time < ?", filter.EndTime)
+	}
+
+	err := query.
+		Order("close_time ASC").
+		Order("id ASC").
+		Find(&assignments).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return assignments, nil
+}
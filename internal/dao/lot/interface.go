@@ -0,0 +1,47 @@
+package lot
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 查询条件结构体 ====================
+
+// LotFilter 查询批次的筛选条件
+type LotFilter struct {
+	UserID    uint   // 用户ID（必须）
+	AccountID *uint  // 账户ID（可选，nil 表示全部账户）
+	Symbol    string // 股票代码（可选）
+	OpenOnly  bool   // 只查询未平仓批次（剩余数量 > 0）
+}
+
+// AssignmentFilter 查询批次分配记录的筛选条件
+type AssignmentFilter struct {
+	UserID            uint       // 用户ID（必须）
+	AccountID         *uint      // 账户ID（可选，nil 表示全部账户）
+	Symbol            string     // 股票代码（可选）
+	SellTransactionID uint       // 卖出交易ID（可选）
+	StartTime         *time.Time // 卖出时间起（可选）
+	EndTime           *time.Time // 卖出时间止（可选，不含）
+}
+
+// ==================== 接口定义 ====================
+// Domain 层会依赖这个接口
+
+type Repo interface {
+	// WithTx 返回绑定到指定数据库事务的 Repo
+	WithTx(tx *gorm.DB) Repo
+
+	// ReplaceSymbol 替换用户某只股票的全部批次和分配记录
+	// 先删除旧数据再写入新数据，应在事务中调用
+	ReplaceSymbol(userID uint, symbol string, lots []*entity.TaxLot, assignments []*entity.LotAssignment) error
+
+	// FindLots 按筛选条件查询批次（按开仓时间正序）
+	FindLots(filter *LotFilter) ([]*entity.TaxLot, error)
+
+	// FindAssignments 按筛选条件查询分配记录（按卖出时间正序）
+	FindAssignments(filter *AssignmentFilter) ([]*entity.LotAssignment, error)
+}
@@ -0,0 +1,119 @@
+package impl
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	priceBarRepo "github.com/florentyang/smartfin-go/internal/dao/pricebar"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// upsertBatchSize 批量写入时每批的行数
+const upsertBatchSize = 500
+
+// ==================== Repository 结构体 ====================
+
+type repository struct {
+	db *gorm.DB
+}
+
+// ==================== 构造函数 ====================
+
+// NewPriceBarRepo 创建 DAO 实例
+func NewPriceBarRepo(db *gorm.DB) priceBarRepo.Repo {
+	return &repository{db: db}
+}
+
+// ==================== 接口实现 ====================
+
+// Upsert 批量写入日线行情，证券 + 日期冲突时更新 OHLCV
+func (r *repository) Upsert(bars []*entity.PriceBar) error {
+	if len(bars) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "instrument_id"}, {Name: "bar_date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"open", "high", "low", "close", "volume", "updated_at"}),
+	}).CreateInBatches(bars, upsertBatchSize).Error
+}
+
+// FindBars 按筛选条件查询日线行情
+func (r *repository) FindBars(filter *priceBarRepo.BarFilter) ([]*entity.PriceBar, error) {
+	var bars []*entity.PriceBar
+
+	query := r.db.Model(&entity.PriceBar{}).Where("instrument_id = ?", filter.InstrumentID)
+
+	// 按日期范围筛选
+	if filter.StartDate != nil {
+		query = query.Where("bar_date >= ?", filter.StartDate)
+	}
+	if filter.EndDate != nil {
+		query = query.Where("bar_date < ?", filter.EndDate)
+	}
+
+	if err := query.Order("bar_date ASC").Find(&bars).Error; err != nil {
+		return nil, err
+	}
+	return bars, nil
+}
+
+// FindOnOrBefore 查询某只证券在指定日期当天或之前最近的一条行情
+// 走 (instrument_id, bar_date) 联合索引，倒序取第一条
+func (r *repository) FindOnOrBefore(instrumentID uint, date time.Time) (*entity.PriceBar, error) {
+	var bar entity.PriceBar
+	err := r.db.
+		Where("instrument_id = ? AND bar_date <= ?", instrumentID, date).
+		Order("bar_date DESC").
+		First(&bar).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, priceBarRepo.ErrBarNotFound
+		}
+		return nil, err
+	}
+	return &bar, nil
+}
+
+// FindLatestOnOrBefore 批量查询多只证券在指定日期当天或之前最近的一条行情
+// 子查询按证券分组取最大日期（联合索引上完成），再关联回原表取整行
+func (r *repository) FindLatestOnOrBefore(instrumentIDs []uint, date time.Time) ([]*entity.PriceBar, error) {
+	var bars []*entity.PriceBar
+	if len(instrumentIDs) == 0 {
+		return bars, nil
+	}
+
+	latest := r.db.Model(&entity.PriceBar{}).
+		Select("instrument_id, MAX(bar_date) AS bar_date").
+		Where("instrument_id IN ? AND bar_date <= ?", instrumentIDs, date).
+		Group("instrument_id")
+
+	err := r.db.Table("price_bars AS b").
+		Select("b.*").
+		Joins("JOIN (?) AS latest ON b.instrument_id = latest.instrument_id AND b.bar_date = latest.bar_date", latest).
+		Find(&bars).Error
+	if err != nil {
+		return nil, err
+	}
+	return bars, nil
+}
+
+// FindBarsIn 批量查询多只证券在 [startDate, endDate) 内的日线行情
+// 走 (instrument_id, bar_date) 联合索引
+func (r *repository) FindBarsIn(instrumentIDs []uint, startDate, endDate time.Time) ([]*entity.PriceBar, error) {
+	var bars []*entity.PriceBar
+	if len(instrumentIDs) == 0 {
+		return bars, nil
+	}
+
+	err := r.db.
+		Where("instrument_id IN ? AND bar_date >= ? AND bar_date < ?", instrumentIDs, startDate, endDate).
+		Order("instrument_id ASC, bar_date ASC").
+		Find(&bars).Error
+	if err != nil {
+		return nil, err
+	}
+	return bars, nil
+}
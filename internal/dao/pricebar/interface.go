@@ -0,0 +1,44 @@
+package pricebar
+
+import (
+	"errors"
+	"time"
+
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 错误定义 ====================
+
+var ErrBarNotFound = errors.New("行情不存在")
+
+// ==================== 查询条件结构体 ====================
+
+// BarFilter 查询日线行情的筛选条件
+type BarFilter struct {
+	InstrumentID uint       // 证券ID（必须）
+	StartDate    *time.Time // 开始日期（可选）
+	EndDate      *time.Time // 结束日期（可选，不含）
+}
+
+// ==================== 接口定义 ====================
+// Domain 层会依赖这个接口
+
+type Repo interface {
+	// Upsert 批量写入日线行情
+	// 同一证券同一天已存在时覆盖（重复导入同一份文件结果不变）
+	Upsert(bars []*entity.PriceBar) error
+
+	// FindBars 按筛选条件查询日线行情（按日期正序）
+	FindBars(filter *BarFilter) ([]*entity.PriceBar, error)
+
+	// FindOnOrBefore 查询某只证券在指定日期当天或之前最近的一条行情
+	// 不存在时返回 ErrBarNotFound
+	FindOnOrBefore(instrumentID uint, date time.Time) (*entity.PriceBar, error)
+
+	// FindLatestOnOrBefore 批量查询多只证券在指定日期当天或之前最近的一条行情
+	// 一次查询完成，没有行情的证券不返回
+	FindLatestOnOrBefore(instrumentIDs []uint, date time.Time) ([]*entity.PriceBar, error)
+
+	// FindBarsIn 批量查询多只证券在 [startDate, endDate) 内的日线行情（按证券、日期正序）
+	FindBarsIn(instrumentIDs []uint, startDate, endDate time.Time) ([]*entity.PriceBar, error)
+}
@@ -0,0 +1,112 @@
+package impl
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	snapshotRepo "github.com/florentyang/smartfin-go/internal/dao/snapshot"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// createBatchSize 批量写入时每批的快照数
+const createBatchSize = 200
+
+// ==================== Repository 结构体 ====================
+
+type repository struct {
+	db *gorm.DB
+}
+
+// ==================== 构造函数 ====================
+
+// NewSnapshotRepo 创建 DAO 实例
+func NewSnapshotRepo(db *gorm.DB) snapshotRepo.Repo {
+	return &repository{db: db}
+}
+
+// ==================== 接口实现 ====================
+
+// WithTx 返回绑定到指定数据库事务的 Repo
+func (r *repository) WithTx(tx *gorm.DB) snapshotRepo.Repo {
+	return &repository{db: tx}
+}
+
+// Create 批量写入快照，持仓明细由 GORM 关联一并写入
+func (r *repository) Create(snapshots []*entity.PortfolioSnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+	return r.db.CreateInBatches(snapshots, createBatchSize).Error
+}
+
+// FindLatest 查询用户最近一天的任意一条快照
+// 走 (user_id, snapshot_date, account_id) 联合索引，倒序取第一条
+func (r *repository) FindLatest(userID uint) (*entity.PortfolioSnapshot, error) {
+	var snapshot entity.PortfolioSnapshot
+	err := r.db.
+		Where("user_id = ?", userID).
+		Order("snapshot_date DESC").
+		First(&snapshot).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, snapshotRepo.ErrSnapshotNotFound
+		}
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// FindSnapshots 按筛选条件查询快照
+func (r *repository) FindSnapshots(filter *snapshotRepo.SnapshotFilter) ([]*entity.PortfolioSnapshot, error) {
+	var snapshots []*entity.PortfolioSnapshot
+
+	query := r.db.Model(&entity.PortfolioSnapshot{}).Where("user_id = ?", filter.UserID)
+
+	// 按账户筛选
+	if filter.AccountID != nil {
+		query = query.Where("account_id = ?", *filter.AccountID)
+	}
+
+	// 按日期范围筛选
+	if filter.StartDate != nil {
+		query = query.Where("snapshot_date >= ?", filter.StartDate)
+	}
+	if filter.EndDate != nil {
+		query = query.Where("snapshot_date < ?", filter.EndDate)
+	}
+
+	// 按需加载持仓明细
+	if filter.IncludeHoldings {
+		query = query.Preload("Holdings", func(db *gorm.DB) *gorm.DB {
+			return db.Order("symbol ASC")
+		})
+	}
+
+	if err := query.Order("snapshot_date ASC").Order("account_id ASC").Find(&snapshots).Error; err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+// DeleteFrom 删除用户指定日期当天及之后的全部快照
+func (r *repository) DeleteFrom(userID uint, date time.Time) error {
+	return r.delete("user_id = ? AND snapshot_date >= ?", userID, date)
+}
+
+// DeleteAll 删除用户的全部快照
+func (r *repository) DeleteAll(userID uint) error {
+	return r.delete("user_id = ?", userID)
+}
+
+// ==================== 私有辅助函数 ====================
+
+// delete 删除满足条件的快照：先删持仓明细（按快照ID子查询），再删快照
+func (r *repository) delete(query string, args ...interface{}) error {
+	ids := r.db.Model(&entity.PortfolioSnapshot{}).Select("id").Where(query, args...)
+	if err := r.db.Where("snapshot_id IN (?)", ids).Delete(&entity.SnapshotHolding{}).Error; err != nil {
+		return err
+	}
+	return r.db.Where(query, args...).Delete(&entity.PortfolioSnapshot{}).Error
+}
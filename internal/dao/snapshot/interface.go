@@ -0,0 +1,50 @@
+package snapshot
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 错误定义 ====================
+
+var ErrSnapshotNotFound = errors.New("快照不存在")
+
+// ==================== 查询条件结构体 ====================
+
+// SnapshotFilter 查询估值快照的筛选条件
+type SnapshotFilter struct {
+	UserID          uint       // 用户ID（必须）
+	AccountID       *uint      // 账户ID（可选，nil 表示全部账户）
+	StartDate       *time.Time // 开始日期（可选）
+	EndDate         *time.Time // 结束日期（可选，不含）
+	IncludeHoldings bool       // 是否加载持仓明细
+}
+
+// ==================== 接口定义 ====================
+// Domain 层会依赖这个接口
+
+type Repo interface {
+	// WithTx 返回绑定到指定数据库事务的 Repo
+	WithTx(tx *gorm.DB) Repo
+
+	// Create 批量写入快照（连同持仓明细）
+	Create(snapshots []*entity.PortfolioSnapshot) error
+
+	// FindLatest 查询用户最近一天的任意一条快照
+	// 不存在时返回 ErrSnapshotNotFound
+	FindLatest(userID uint) (*entity.PortfolioSnapshot, error)
+
+	// FindSnapshots 按筛选条件查询快照（按日期、账户正序）
+	FindSnapshots(filter *SnapshotFilter) ([]*entity.PortfolioSnapshot, error)
+
+	// DeleteFrom 删除用户指定日期当天及之后的全部快照（连同持仓明细）
+	// 交易变更后调用，应与交易写入在同一事务中
+	DeleteFrom(userID uint, date time.Time) error
+
+	// DeleteAll 删除用户的全部快照（连同持仓明细）
+	DeleteAll(userID uint) error
+}
@@ -1,6 +1,8 @@
 package impl
 
 import (
+	"errors"
+
 	"gorm.io/gorm"
 
 	txRepo "github.com/florentyang/smartfin-go/internal/dao/transaction"
@@ -22,22 +24,205 @@ func NewTransactionRepo(db *gorm.DB) txRepo.Repo {
 
 // ==================== 接口实现 ====================
 
+// WithTx 返回绑定到指定数据库事务的 Repo
+func (r *repository) WithTx(tx *gorm.DB) txRepo.Repo {
+	return &repository{db: tx}
+}
+
 // Create 创建交易记录
 func (r *repository) Create(tx *entity.Transaction) error {
 	return r.db.Create(tx).Error
 }
 
+// GetByID 按 ID 查找交易记录
+func (r *repository) GetByID(id uint) (*entity.Transaction, error) {
+	var tx entity.Transaction
+	err := r.db.First(&tx, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, txRepo.ErrTransactionNotFound
+		}
+		return nil, err
+	}
+	return &tx, nil
+}
+
+// Update 更新交易记录
+func (r *repository) Update(tx *entity.Transaction) error {
+	return r.db.Save(tx).Error
+}
+
+// Delete 删除交易记录
+func (r *repository) Delete(id uint) error {
+	result := r.db.Delete(&entity.Transaction{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return txRepo.ErrTransactionNotFound
+	}
+	return nil
+}
+
+// ExistsBrokerTrade 判断券商成交是否已导入
+func (r *repository) ExistsBrokerTrade(userID uint, source, brokerTradeID string) (bool, error) {
+	var count int64
+	err := r.db.Model(&entity.Transaction{}).
+		Where("user_id = ? AND source = ? AND broker_trade_id = ?", userID, source, brokerTradeID).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ExistsByAccount 判断账户下是否有交易
+func (r *repository) ExistsByAccount(userID, accountID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&entity.Transaction{}).
+		Where("user_id = ? AND account_id = ?", userID, accountID).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// FindCurrencies 查询用户某只股票指定类型的交易使用过的币种
+func (r *repository) FindCurrencies(filter *txRepo.CurrencyFilter) ([]string, error) {
+	var currencies []string
+
+	query := r.db.Model(&entity.Transaction{}).
+		Where("user_id = ? AND symbol = ? AND type IN ?", filter.UserID, filter.Symbol, filter.Types)
+
+	// 修改交易时排除自身
+	if filter.ExcludeID != 0 {
+		query = query.Where("id <> ?", filter.ExcludeID)
+	}
+
+	if err := query.Distinct().Pluck("currency", &currencies).Error; err != nil {
+		return nil, err
+	}
+	return currencies, nil
+}
+
 // FindByUserID 根据用户ID和筛选条件查询交易列表
 // 支持：分页、按股票代码筛选、按交易类型筛选、按日期范围筛选
 func (r *repository) FindByUserID(filter *txRepo.ListFilter) ([]*entity.Transaction, int64, error) {
 	var txList []*entity.Transaction
 	var total int64
 
+	// ===== 构建查询（用户ID + 筛选条件） =====
+	query := r.listQuery(filter)
+
+	// ===== 先查询总数（分页前） =====
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// ===== 分页 + 排序 + 查询数据 =====
+	offset := (filter.Page - 1) * filter.PageSize
+
+	err := query.
+		Order("trade_time DESC"). // 默认按交易时间倒序
+		Limit(filter.PageSize).
+		Offset(offset).
+		Find(&txList).Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return txList, total, nil
+}
+
+// Stream 按筛选条件逐行读取交易（忽略分页参数）
+// 使用数据库游标逐行扫描，内存占用与结果集大小无关
+func (r *repository) Stream(filter *txRepo.ListFilter, fn func(tx *entity.Transaction) error) error {
+	rows, err := r.listQuery(filter).
+		Order("trade_time ASC").
+		Order("id ASC").
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tx entity.Transaction
+		if err := r.db.ScanRows(rows, &tx); err != nil {
+			return err
+		}
+		if err := fn(&tx); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// FindLedger 按交易时间正序查询用户的全部交易（不分页）
+func (r *repository) FindLedger(filter *txRepo.LedgerFilter) ([]*entity.Transaction, error) {
+	var txList []*entity.Transaction
+
+	query := r.db.Model(&entity.Transaction{}).Where("user_id = ?", filter.UserID)
+
+	// 按账户筛选
+	if filter.AccountID != nil {
+		query = query.Where("account_id = ?", *filter.AccountID)
+	}
+
+	// 按股票代码筛选
+	if filter.Symbol != "" {
+		query = query.Where("symbol = ?", filter.Symbol)
+	}
+	if filter.NoSymbol {
+		query = query.Where("symbol = ?", "")
+	}
+
+	// 按截止时间筛选
+	if filter.EndTime != nil {
+		query = query.Where("trade_time < ?", filter.EndTime)
+	}
+
+	err := query.
+		Order("trade_time ASC").
+		Order("id ASC").
+		Find(&txList).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return txList, nil
+}
+
+// FindUserIDs 查询有交易记录的全部用户ID
+func (r *repository) FindUserIDs() ([]uint, error) {
+	var userIDs []uint
+	err := r.db.Model(&entity.Transaction{}).
+		Distinct().
+		Order("user_id ASC").
+		Pluck("user_id", &userIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	return userIDs, nil
+}
+
+// ==================== 私有辅助函数 ====================
+
+// listQuery 构建交易列表的查询条件（不含分页和排序）
+// FindByUserID 和 Stream 共用，保证列表与导出的筛选口径一致
+func (r *repository) listQuery(filter *txRepo.ListFilter) *gorm.DB {
 	// ===== 构建基础查询（必须按用户ID筛选） =====
 	query := r.db.Model(&entity.Transaction{}).Where("user_id = ?", filter.UserID)
 
 	// ===== 动态添加筛选条件 =====
 
+	// 按账户筛选
+	if filter.AccountID != nil {
+		query = query.Where("account_id = ?", *filter.AccountID)
+	}
+
 	// 按股票代码筛选
 	if filter.Symbol != "" {
 		query = query.Where("symbol = ?", filter.Symbol)
@@ -58,23 +243,5 @@ func (r *repository) FindByUserID(filter *txRepo.ListFilter) ([]*entity.Transact
 		query = query.Where("trade_time < ?", filter.EndTime)
 	}
 
-	// ===== 先查询总数（分页前） =====
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, err
-	}
-
-	// ===== 分页 + 排序 + 查询数据 =====
-	offset := (filter.Page - 1) * filter.PageSize
-
-	err := query.
-		Order("trade_time DESC"). // 默认按交易时间倒序
-		Limit(filter.PageSize).
-		Offset(offset).
-		Find(&txList).Error
-
-	if err != nil {
-		return nil, 0, err
-	}
-
-	return txList, total, nil
+	return query
 }
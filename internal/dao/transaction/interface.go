@@ -4,6 +4,8 @@ import (
 	"errors"
 	"time"
 
+	"gorm.io/gorm"
+
 	"github.com/florentyang/smartfin-go/internal/entity"
 )
 
@@ -18,6 +20,7 @@ var (
 // ListFilter 查询交易列表的筛选条件
 type ListFilter struct {
 	UserID    uint       // 用户ID（必须）
+	AccountID *uint      // 账户ID（可选，nil 表示全部账户，0 表示未指定账户的交易）
 	Symbol    string     // 股票代码（可选）
 	Type      string     // 交易类型（可选）
 	StartTime *time.Time // 开始时间（可选）
@@ -26,14 +29,65 @@ type ListFilter struct {
 	PageSize  int        // 每页条数
 }
 
+// LedgerFilter 查询交易流水（不分页）的筛选条件
+// 用于持仓汇总等需要按时间顺序回放全部交易的场景
+type LedgerFilter struct {
+	UserID    uint       // 用户ID（必须）
+	AccountID *uint      // 账户ID（可选，nil 表示全部账户）
+	Symbol    string     // 股票代码（可选）
+	NoSymbol  bool       // 只查询没有股票代码的交易（可选，与 Symbol 互斥）
+	EndTime   *time.Time // 截止时间（可选，不含）
+}
+
+// CurrencyFilter 查询股票已用币种的筛选条件
+type CurrencyFilter struct {
+	UserID    uint     // 用户ID（必须）
+	Symbol    string   // 股票代码（必须）
+	Types     []string // 交易类型（必须）
+	ExcludeID uint     // 排除的交易ID（修改交易时排除自身，可选）
+}
+
 // ==================== 接口定义 ====================
 // Domain 层会依赖这个接口
 
 type Repo interface {
+	// WithTx 返回绑定到指定数据库事务的 Repo
+	WithTx(tx *gorm.DB) Repo
+
 	// Create 创建交易记录
 	Create(tx *entity.Transaction) error
 
+	// GetByID 按 ID 查找交易记录
+	GetByID(id uint) (*entity.Transaction, error)
+
+	// Update 更新交易记录
+	Update(tx *entity.Transaction) error
+
+	// Delete 删除交易记录
+	Delete(id uint) error
+
+	// ExistsBrokerTrade 判断券商成交是否已导入（按用户 + 来源 + 券商成交编号）
+	ExistsBrokerTrade(userID uint, source, brokerTradeID string) (bool, error)
+
+	// ExistsByAccount 判断账户下是否有交易（删除账户前校验）
+	ExistsByAccount(userID, accountID uint) (bool, error)
+
+	// FindCurrencies 查询用户某只股票指定类型的交易使用过的币种（去重）
+	FindCurrencies(filter *CurrencyFilter) ([]string, error)
+
 	// FindByUserID 根据用户ID和筛选条件查询交易列表
 	// 返回：交易列表、总条数、错误
 	FindByUserID(filter *ListFilter) ([]*entity.Transaction, int64, error)
+
+	// Stream 按筛选条件逐行读取交易（忽略分页参数），按交易时间正序回调 fn
+	// fn 返回错误时停止读取并返回该错误
+	Stream(filter *ListFilter, fn func(tx *entity.Transaction) error) error
+
+	// FindLedger 按交易时间正序查询用户的全部交易（不分页）
+	// 同一时间的交易按 ID 正序，保证回放顺序稳定
+	FindLedger(filter *LedgerFilter) ([]*entity.Transaction, error)
+
+	// FindUserIDs 查询有交易记录的全部用户ID（按 ID 正序）
+	// 用于后台任务逐个用户处理
+	FindUserIDs() ([]uint, error)
 }
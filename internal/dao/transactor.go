@@ -0,0 +1,31 @@
+package dao
+
+import (
+	"gorm.io/gorm"
+)
+
+// ==================== 接口定义 ====================
+// Domain 层通过这个接口开启数据库事务，
+// 在 fn 中用 tx 调用各 Repo 的 WithTx，保证多表写入的原子性
+
+type Transactor interface {
+	// Transaction 在同一个数据库事务中执行 fn
+	// fn 返回错误时整体回滚，否则提交
+	Transaction(fn func(tx *gorm.DB) error) error
+}
+
+// ==================== 接口实现 ====================
+
+type transactor struct {
+	db *gorm.DB
+}
+
+// NewTransactor 创建事务管理器
+func NewTransactor(db *gorm.DB) Transactor {
+	return &transactor{db: db}
+}
+
+// Transaction 在同一个数据库事务中执行 fn
+func (t *transactor) Transaction(fn func(tx *gorm.DB) error) error {
+	return t.db.Transaction(fn)
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	userRepo "github.com/florentyang/smartfin-go/internal/dao/user"
 	"github.com/florentyang/smartfin-go/internal/entity"
@@ -27,6 +28,11 @@ func NewUserRepo(db *gorm.DB) userRepo.Repo {
 
 // ==================== 接口实现 ====================
 
+// WithTx 返回绑定到指定数据库事务的 Repo
+func (r *repository) WithTx(tx *gorm.DB) userRepo.Repo {
+	return &repository{db: tx}
+}
+
 // Create 创建用户
 func (r *repository) Create(user *entity.User) error {
 	return r.db.Create(user).Error
@@ -45,6 +51,19 @@ func (r *repository) GetByID(id uint) (*entity.User, error) {
 	return &user, nil
 }
 
+// GetByIDForUpdate 按 ID 查找用户并加行锁
+func (r *repository) GetByIDForUpdate(id uint) (*entity.User, error) {
+	var user entity.User
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, userRepo.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
 // GetByUsername 按用户名查找用户
 func (r *repository) GetByUsername(username string) (*entity.User, error) {
 	var user entity.User
@@ -3,6 +3,8 @@ package user
 import (
 	"errors"
 
+	"gorm.io/gorm"
+
 	"github.com/florentyang/smartfin-go/internal/entity"
 )
 
@@ -17,12 +19,19 @@ var (
 // Domain 层会依赖这个接口，而不是具体实现
 
 type Repo interface {
+	// WithTx 返回绑定到指定数据库事务的 Repo
+	WithTx(tx *gorm.DB) Repo
+
 	// Create 创建用户
 	Create(user *entity.User) error
 
 	// GetByID 按 ID 查找用户
 	GetByID(id uint) (*entity.User, error)
 
+	// GetByIDForUpdate 按 ID 查找用户并加行锁（SELECT ... FOR UPDATE）
+	// 必须在事务中使用，用于串行化同一用户的账本写入
+	GetByIDForUpdate(id uint) (*entity.User, error)
+
 	// GetByUsername 按用户名查找用户
 	GetByUsername(username string) (*entity.User, error)
 
@@ -0,0 +1,281 @@
+package impl
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/florentyang/smartfin-go/internal/dao"
+	accountRepo "github.com/florentyang/smartfin-go/internal/dao/account"
+	txRepo "github.com/florentyang/smartfin-go/internal/dao/transaction"
+	userRepo "github.com/florentyang/smartfin-go/internal/dao/user"
+	accountDomain "github.com/florentyang/smartfin-go/internal/domain/account"
+	fxDomain "github.com/florentyang/smartfin-go/internal/domain/fx"
+	lotDomain "github.com/florentyang/smartfin-go/internal/domain/lot"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== UseCase 结构体 ====================
+
+type usecase struct {
+	accountRepo accountRepo.Repo // 账户 DAO
+	txRepo      txRepo.Repo      // 交易 DAO（删除前检查账户下是否有交易，推导现金流水）
+	userRepo    userRepo.Repo    // 用户 DAO（加锁，与交易写入串行化）
+	transactor  dao.Transactor   // 事务管理器
+}
+
+// ==================== 构造函数 ====================
+
+// NewAccountDomain 创建 Domain 实例
+func NewAccountDomain(
+	accountRepo accountRepo.Repo,
+	txRepo txRepo.Repo,
+	userRepo userRepo.Repo,
+	transactor dao.Transactor,
+) accountDomain.Domain {
+	return &usecase{
+		accountRepo: accountRepo,
+		txRepo:      txRepo,
+		userRepo:    userRepo,
+		transactor:  transactor,
+	}
+}
+
+// ==================== 业务方法实现 ====================
+
+// Create 创建账户
+func (u *usecase) Create(input *accountDomain.CreateInput) (*entity.Account, error) {
+	// 1. 校验名称（同一用户下唯一）
+	name, err := u.checkName(input.UserID, input.Name, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 成本计算方法（空表示使用用户的默认方法）
+	if input.CostBasisMethod != "" && !lotDomain.IsValidMethod(input.CostBasisMethod) {
+		return nil, accountDomain.ErrInvalidMethod
+	}
+
+	// 3. 卖空开关：未指定时取用户的设置
+	allowShortSelling := false
+	if input.AllowShortSelling != nil {
+		allowShortSelling = *input.AllowShortSelling
+	} else {
+		user, err := u.userRepo.GetByID(input.UserID)
+		if err != nil {
+			return nil, err
+		}
+		allowShortSelling = user.AllowShortSelling
+	}
+
+	// 4. 组装实体并写入
+	account := &entity.Account{
+		UserID:            input.UserID,
+		Name:              name,
+		Broker:            strings.TrimSpace(input.Broker),
+		Number:            strings.TrimSpace(input.Number),
+		Notes:             input.Notes,
+		Margin:            input.Margin,
+		AllowShortSelling: allowShortSelling,
+		CostBasisMethod:   input.CostBasisMethod,
+	}
+	if err := u.accountRepo.Create(account); err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// Get 查询单个账户
+// 账户不属于当前用户时按"不存在"处理，避免泄露他人数据
+func (u *usecase) Get(userID, id uint) (*entity.Account, error) {
+	account, err := u.accountRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, accountRepo.ErrAccountNotFound) {
+			return nil, accountDomain.ErrAccountNotFound
+		}
+		return nil, err
+	}
+
+	// 归属校验：只能访问自己的账户
+	if account.UserID != userID {
+		return nil, accountDomain.ErrAccountNotFound
+	}
+
+	return account, nil
+}
+
+// List 查询用户的全部账户
+func (u *usecase) List(userID uint) ([]*entity.Account, error) {
+	return u.accountRepo.FindByUserID(userID)
+}
+
+// Update 更新账户
+func (u *usecase) Update(input *accountDomain.UpdateInput) (*entity.Account, error) {
+	// 1. 查询并校验归属
+	account, err := u.Get(input.UserID, input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 校验名称（排除自身）和成本计算方法
+	name, err := u.checkName(input.UserID, input.Name, account.ID)
+	if err != nil {
+		return nil, err
+	}
+	if input.CostBasisMethod != "" && !lotDomain.IsValidMethod(input.CostBasisMethod) {
+		return nil, accountDomain.ErrInvalidMethod
+	}
+
+	// 3. 覆盖可编辑字段并保存
+	account.Name = name
+	account.Broker = strings.TrimSpace(input.Broker)
+	account.Number = strings.TrimSpace(input.Number)
+	account.Notes = input.Notes
+	account.Margin = input.Margin
+	account.AllowShortSelling = input.AllowShortSelling
+	account.CostBasisMethod = input.CostBasisMethod
+	if err := u.accountRepo.Update(account); err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// Cash 账户现金流水及余额变化
+// 按时间顺序回放账户的全部交易，开始时间之前的只累计到期初余额
+func (u *usecase) Cash(input *accountDomain.CashInput) (*accountDomain.CashOutput, error) {
+	// 1. 查询并校验归属
+	account, err := u.Get(input.UserID, input.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 币种筛选（可选）
+	currency := ""
+	if input.Currency != "" {
+		currency, err = fxDomain.NormalizeCurrency(input.Currency)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 3. 加载账户截止到结束时间的交易流水（按交易时间排序）
+	ledger, err := u.txRepo.FindLedger(&txRepo.LedgerFilter{
+		UserID:    input.UserID,
+		AccountID: &account.ID,
+		EndTime:   input.EndTime,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. 回放：每个币种单独累计余额
+	output := &accountDomain.CashOutput{Account: account}
+	balances := make(map[string]*accountDomain.CashBalance)
+	for _, tx := range ledger {
+		if currency != "" && tx.Currency != currency {
+			continue
+		}
+		amount := entity.CashFlow(tx)
+		if amount.IsZero() {
+			continue
+		}
+
+		balance, ok := balances[tx.Currency]
+		if !ok {
+			balance = &accountDomain.CashBalance{Currency: tx.Currency}
+			balances[tx.Currency] = balance
+		}
+		balance.Closing = balance.Closing.Add(amount)
+
+		// 开始时间之前的交易只计入期初余额
+		if input.StartTime != nil && tx.TradeTime.Before(*input.StartTime) {
+			balance.Opening = balance.Closing
+			continue
+		}
+		if amount.IsPositive() {
+			balance.Inflow = balance.Inflow.Add(amount)
+		} else {
+			balance.Outflow = balance.Outflow.Sub(amount)
+		}
+		output.Entries = append(output.Entries, &accountDomain.CashEntry{
+			Transaction: tx,
+			Amount:      amount,
+			Balance:     balance.Closing,
+		})
+	}
+
+	// 5. 余额按币种排序，保证输出稳定
+	for _, balance := range balances {
+		output.Balances = append(output.Balances, balance)
+	}
+	sort.Slice(output.Balances, func(i, j int) bool {
+		return output.Balances[i].Currency < output.Balances[j].Currency
+	})
+
+	return output, nil
+}
+
+// Delete 删除账户
+// 锁定用户行后再检查交易，防止与并发写入的交易交错
+func (u *usecase) Delete(userID, id uint) error {
+	return u.transactor.Transaction(func(db *gorm.DB) error {
+		accounts := u.accountRepo.WithTx(db)
+
+		// 1. 锁定用户行：与交易写入串行化
+		if _, err := u.userRepo.WithTx(db).GetByIDForUpdate(userID); err != nil {
+			return err
+		}
+
+		// 2. 查询并校验归属
+		account, err := accounts.GetByID(id)
+		if err != nil {
+			if errors.Is(err, accountRepo.ErrAccountNotFound) {
+				return accountDomain.ErrAccountNotFound
+			}
+			return err
+		}
+		if account.UserID != userID {
+			return accountDomain.ErrAccountNotFound
+		}
+
+		// 3. 账户下还有交易时不能删除
+		inUse, err := u.txRepo.WithTx(db).ExistsByAccount(userID, account.ID)
+		if err != nil {
+			return err
+		}
+		if inUse {
+			return accountDomain.ErrAccountInUse
+		}
+
+		// 4. 删除
+		if err := accounts.Delete(account.ID); err != nil {
+			if errors.Is(err, accountRepo.ErrAccountNotFound) {
+				return accountDomain.ErrAccountNotFound
+			}
+			return err
+		}
+		return nil
+	})
+}
+
+// ==================== 私有辅助函数 ====================
+
+// checkName 校验账户名称：去掉首尾空格后不能为空，且同一用户下唯一
+func (u *usecase) checkName(userID uint, name string, excludeID uint) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", accountDomain.ErrNameRequired
+	}
+	exists, err := u.accountRepo.ExistsByName(userID, name, excludeID)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		return "", accountDomain.ErrNameExists
+	}
+	return name, nil
+}
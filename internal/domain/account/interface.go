@@ -0,0 +1,110 @@
+package account
+
+import (
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 错误定义 ====================
+// 领域层的业务错误（中文方便调试）
+
+var (
+	ErrAccountNotFound = errors.New("账户不存在")
+	ErrNameRequired    = errors.New("账户名称不能为空")
+	ErrNameExists      = errors.New("账户名称已存在")
+	ErrAccountInUse    = errors.New("账户下还有交易记录，不能删除")
+	ErrInvalidMethod   = errors.New("成本计算方法无效，必须是 FIFO、LIFO 或 HIFO")
+)
+
+// ==================== Domain 输入结构体 ====================
+
+// CreateInput 创建账户的输入参数
+type CreateInput struct {
+	UserID uint   // 用户ID（必须）
+	Name   string // 账户名称（必须，同一用户下唯一）
+	Broker string // 券商（可选）
+	Number string // 券商账号（可选）
+	Notes  string // 备注（可选）
+	Margin bool   // 是否融资账户（可选，默认 false：交易不能使现金余额为负）
+
+	AllowShortSelling *bool  // 是否允许卖空（可选，nil 时取用户的卖空设置）
+	CostBasisMethod   string // 默认成本计算方法（可选，空表示使用用户的默认方法）
+}
+
+// UpdateInput 更新账户的输入参数
+// PUT 语义：整体替换可编辑字段
+type UpdateInput struct {
+	ID     uint // 账户ID
+	UserID uint // 当前登录用户ID（用于归属校验）
+	Name   string
+	Broker string
+	Number string
+	Notes  string
+	Margin bool
+
+	AllowShortSelling bool   // 是否允许卖空
+	CostBasisMethod   string // 默认成本计算方法（空表示使用用户的默认方法）
+}
+
+// CashInput 查询账户现金流水的输入参数
+type CashInput struct {
+	UserID    uint       // 用户ID（必须）
+	AccountID uint       // 账户ID（必须）
+	Currency  string     // 只看某个币种（可选，不传为全部币种）
+	StartTime *time.Time // 开始时间（可选，之前的流水只计入期初余额）
+	EndTime   *time.Time // 结束时间（可选，不含）
+}
+
+// ==================== Domain 输出结构体 ====================
+
+// CashBalance 单一币种的现金余额汇总
+type CashBalance struct {
+	Currency string
+	Opening  decimal.Decimal // 期初余额（开始时间之前的累计）
+	Inflow   decimal.Decimal // 期间流入
+	Outflow  decimal.Decimal // 期间流出（正数）
+	Closing  decimal.Decimal // 期末余额 = 期初 + 流入 - 流出
+}
+
+// CashEntry 现金流水的一行：一笔交易及其之后的余额
+type CashEntry struct {
+	Transaction *entity.Transaction
+	Amount      decimal.Decimal // 现金变动（正数流入，负数流出）
+	Balance     decimal.Decimal // 该笔交易之后同币种的余额
+}
+
+// CashOutput 账户现金流水
+type CashOutput struct {
+	Account  *entity.Account
+	Balances []*CashBalance // 按币种排序
+	Entries  []*CashEntry   // 期间内的流水，按交易时间排序
+}
+
+// ==================== Domain 接口定义 ====================
+// Service 层会依赖这个接口
+
+type Domain interface {
+	// Create 创建账户
+	Create(input *CreateInput) (*entity.Account, error)
+
+	// Get 查询单个账户（只能查询属于当前用户的账户）
+	Get(userID, id uint) (*entity.Account, error)
+
+	// List 查询用户的全部账户
+	List(userID uint) ([]*entity.Account, error)
+
+	// Update 更新账户
+	Update(input *UpdateInput) (*entity.Account, error)
+
+	// Cash 账户现金流水及余额变化
+	// 现金由交易流水推导：买入流出、卖出流入，出入金、分红、利息、费用按金额增减，各币种分别结算
+	Cash(input *CashInput) (*CashOutput, error)
+
+	// Delete 删除账户
+	// 账户下还有交易时不能删除（需先删除或转移交易），避免交易失去归属
+	Delete(userID, id uint) error
+}
@@ -0,0 +1,63 @@
+package impl
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 手续费计算 ====================
+// 各项费用按成交额（A = 数量 × 单价）和数量（Q）计算，分别四舍五入到分：
+// - 佣金：A × 佣金费率 + Q × 每股佣金，不足最低佣金按最低佣金收取
+// - 印花税：A × 印花税率，仅卖出（A 股）
+// - 过户费：A × 过户费率，买卖双边（A 股）
+// - 交易征费：A × 征费费率，买卖双边（港股印花税、证监会及会财局征费、交易费合计）
+
+// calculate 按费率表计算一笔买卖交易的手续费明细
+func calculate(schedule *entity.FeeSchedule, tx *entity.Transaction) entity.FeeBreakdown {
+	commission := tx.Amount.Mul(schedule.CommissionRate).
+		Add(tx.Quantity.Mul(schedule.PerShareFee))
+	if commission.LessThan(schedule.MinCommission) {
+		commission = schedule.MinCommission
+	}
+
+	stampDuty := decimal.Zero
+	if tx.Type == entity.TransactionTypeSell {
+		stampDuty = tx.Amount.Mul(schedule.StampDutyRate)
+	}
+
+	return entity.FeeBreakdown{
+		Commission:  commission.Round(2),
+		StampDuty:   stampDuty.Round(2),
+		TransferFee: tx.Amount.Mul(schedule.TransferFeeRate).Round(2),
+		Levy:        tx.Amount.Mul(schedule.LevyRate).Round(2),
+	}
+}
+
+// specificity 费率表对一笔交易的匹配程度
+// 费率表上的每个限定条件（账户、券商、币种）都必须与交易一致，否则不匹配（返回 -1）
+// 限定条件越多越具体：账户 4 分、券商 2 分、币种 1 分
+func specificity(schedule *entity.FeeSchedule, tx *entity.Transaction, broker string) int {
+	score := 0
+	if schedule.AccountID != 0 {
+		if schedule.AccountID != tx.AccountID {
+			return -1
+		}
+		score += 4
+	}
+	if schedule.Broker != "" {
+		if broker == "" || !strings.EqualFold(schedule.Broker, broker) {
+			return -1
+		}
+		score += 2
+	}
+	if schedule.Currency != "" {
+		if schedule.Currency != tx.Currency {
+			return -1
+		}
+		score++
+	}
+	return score
+}
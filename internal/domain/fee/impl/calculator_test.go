@@ -0,0 +1,206 @@
+package impl
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 测试费率表 ====================
+
+// aShare A 股：佣金万分之 2.5、最低 5 元，印花税千分之 0.5（仅卖出），过户费十万分之 1
+func aShare() *entity.FeeSchedule {
+	return &entity.FeeSchedule{
+		CommissionRate:  decimal.RequireFromString("0.00025"),
+		MinCommission:   decimal.RequireFromString("5"),
+		StampDutyRate:   decimal.RequireFromString("0.0005"),
+		TransferFeeRate: decimal.RequireFromString("0.00001"),
+	}
+}
+
+// usPerShare 美股按股收费：每股 0.005 美元、最低 1 美元
+func usPerShare() *entity.FeeSchedule {
+	return &entity.FeeSchedule{
+		PerShareFee:   decimal.RequireFromString("0.005"),
+		MinCommission: decimal.RequireFromString("1"),
+	}
+}
+
+// hkShare 港股：佣金万分之 3、最低 3 港元；印花税 0.1%、证监会征费 0.0027%、
+// 会财局征费 0.00015%、交易费 0.00565% 双边收取，合计计入交易征费
+func hkShare() *entity.FeeSchedule {
+	return &entity.FeeSchedule{
+		CommissionRate: decimal.RequireFromString("0.0003"),
+		MinCommission:  decimal.RequireFromString("3"),
+		LevyRate:       decimal.RequireFromString("0.0010857"),
+	}
+}
+
+// feeTx 构造一笔买卖交易，成交金额 = 数量 × 单价
+func feeTx(txType, quantity, price string) *entity.Transaction {
+	q := decimal.RequireFromString(quantity)
+	p := decimal.RequireFromString(price)
+	return &entity.Transaction{Type: txType, Quantity: q, Price: p, Amount: q.Mul(p)}
+}
+
+// ==================== 测试用例 ====================
+
+func TestCalculate(t *testing.T) {
+	const (
+		buy  = entity.TransactionTypeBuy
+		sell = entity.TransactionTypeSell
+	)
+
+	tests := []struct {
+		name        string
+		schedule    *entity.FeeSchedule
+		tx          *entity.Transaction
+		commission  string
+		stampDuty   string
+		transferFee string
+		levy        string
+		total       string
+	}{
+		// ---------- A 股 ----------
+		{
+			name:     "A 股买入不足最低佣金，不收印花税",
+			schedule: aShare(), tx: feeTx(buy, "1000", "10"),
+			commission: "5", stampDuty: "0", transferFee: "0.1", levy: "0", total: "5.1",
+		},
+		{
+			name:     "A 股卖出收印花税",
+			schedule: aShare(), tx: feeTx(sell, "1000", "10"),
+			commission: "5", stampDuty: "5", transferFee: "0.1", levy: "0", total: "10.1",
+		},
+		{
+			// 佣金 30.85，过户费 1.234 → 1.23
+			name:     "A 股买入超过最低佣金",
+			schedule: aShare(), tx: feeTx(buy, "10000", "12.34"),
+			commission: "30.85", stampDuty: "0", transferFee: "1.23", levy: "0", total: "32.08",
+		},
+		{
+			name:     "A 股卖出超过最低佣金",
+			schedule: aShare(), tx: feeTx(sell, "10000", "12.34"),
+			commission: "30.85", stampDuty: "61.7", transferFee: "1.23", levy: "0", total: "93.78",
+		},
+		{
+			// 成交额 1005：印花税 0.5025 → 0.50，过户费 0.01005 → 0.01
+			name:     "A 股各项费用分别四舍五入到分",
+			schedule: aShare(), tx: feeTx(sell, "100", "10.05"),
+			commission: "5", stampDuty: "0.5", transferFee: "0.01", levy: "0", total: "5.51",
+		},
+		{
+			// 佣金恰好等于最低佣金：20000 × 0.00025 = 5
+			name:     "佣金恰好等于最低佣金",
+			schedule: aShare(), tx: feeTx(buy, "2000", "10"),
+			commission: "5", stampDuty: "0", transferFee: "0.2", levy: "0", total: "5.2",
+		},
+
+		// ---------- 按股收费 ----------
+		{
+			name:     "按股收费不足最低佣金",
+			schedule: usPerShare(), tx: feeTx(buy, "100", "150"),
+			commission: "1", stampDuty: "0", transferFee: "0", levy: "0", total: "1",
+		},
+		{
+			name:     "按股收费与成交额无关",
+			schedule: usPerShare(), tx: feeTx(sell, "1000", "3"),
+			commission: "5", stampDuty: "0", transferFee: "0", levy: "0", total: "5",
+		},
+		{
+			// 333 × 0.005 = 1.665，四舍五入为 1.67
+			name:     "按股收费四舍五入到分",
+			schedule: usPerShare(), tx: feeTx(buy, "333", "20"),
+			commission: "1.67", stampDuty: "0", transferFee: "0", levy: "0", total: "1.67",
+		},
+		{
+			// 佣金 = 成交额 × 费率 + 数量 × 每股佣金：3 + 5
+			name: "费率与按股佣金叠加",
+			schedule: &entity.FeeSchedule{
+				CommissionRate: decimal.RequireFromString("0.001"),
+				PerShareFee:    decimal.RequireFromString("0.005"),
+			},
+			tx:         feeTx(buy, "1000", "3"),
+			commission: "8", stampDuty: "0", transferFee: "0", levy: "0", total: "8",
+		},
+
+		// ---------- 港股 ----------
+		{
+			// 成交额 700000：佣金 210，征费 759.99
+			name:     "港股买入收取征费",
+			schedule: hkShare(), tx: feeTx(buy, "2000", "350"),
+			commission: "210", stampDuty: "0", transferFee: "0", levy: "759.99", total: "969.99",
+		},
+		{
+			name:     "港股卖出征费与买入相同",
+			schedule: hkShare(), tx: feeTx(sell, "2000", "350"),
+			commission: "210", stampDuty: "0", transferFee: "0", levy: "759.99", total: "969.99",
+		},
+		{
+			// 成交额 5000：佣金 1.5 → 最低 3，征费 5.4285 → 5.43
+			name:     "港股小额成交",
+			schedule: hkShare(), tx: feeTx(buy, "100", "50"),
+			commission: "3", stampDuty: "0", transferFee: "0", levy: "5.43", total: "8.43",
+		},
+
+		// ---------- 其他 ----------
+		{
+			name:     "空费率表不收费",
+			schedule: &entity.FeeSchedule{}, tx: feeTx(sell, "1000", "10"),
+			commission: "0", stampDuty: "0", transferFee: "0", levy: "0", total: "0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := calculate(tt.schedule, tt.tx)
+			for _, c := range []struct {
+				field string
+				got   decimal.Decimal
+				want  string
+			}{
+				{"commission", got.Commission, tt.commission},
+				{"stamp_duty", got.StampDuty, tt.stampDuty},
+				{"transfer_fee", got.TransferFee, tt.transferFee},
+				{"levy", got.Levy, tt.levy},
+				{"total", got.Total(), tt.total},
+			} {
+				if !c.got.Equal(decimal.RequireFromString(c.want)) {
+					t.Errorf("%s = %s, want %s", c.field, c.got, c.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSpecificity(t *testing.T) {
+	tx := &entity.Transaction{AccountID: 7, Currency: "HKD"}
+
+	tests := []struct {
+		name     string
+		schedule *entity.FeeSchedule
+		broker   string
+		want     int
+	}{
+		{name: "不限条件", schedule: &entity.FeeSchedule{}, broker: "Futu", want: 0},
+		{name: "币种匹配", schedule: &entity.FeeSchedule{Currency: "HKD"}, broker: "Futu", want: 1},
+		{name: "券商匹配（不区分大小写）", schedule: &entity.FeeSchedule{Broker: "futu"}, broker: "Futu", want: 2},
+		{name: "账户匹配", schedule: &entity.FeeSchedule{AccountID: 7}, broker: "Futu", want: 4},
+		{name: "全部匹配", schedule: &entity.FeeSchedule{AccountID: 7, Broker: "Futu", Currency: "HKD"}, broker: "Futu", want: 7},
+		{name: "券商和币种匹配（低于只限定账户）", schedule: &entity.FeeSchedule{Broker: "Futu", Currency: "HKD"}, broker: "Futu", want: 3},
+		{name: "币种不匹配", schedule: &entity.FeeSchedule{Currency: "USD"}, broker: "Futu", want: -1},
+		{name: "券商不匹配", schedule: &entity.FeeSchedule{Broker: "IBKR"}, broker: "Futu", want: -1},
+		{name: "未指定账户时不匹配限定券商的费率表", schedule: &entity.FeeSchedule{Broker: "Futu"}, broker: "", want: -1},
+		{name: "账户不匹配", schedule: &entity.FeeSchedule{AccountID: 8, Currency: "HKD"}, broker: "Futu", want: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := specificity(tt.schedule, tx, tt.broker); got != tt.want {
+				t.Errorf("specificity() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
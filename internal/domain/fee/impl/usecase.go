@@ -0,0 +1,219 @@
+package impl
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	accountRepo "github.com/florentyang/smartfin-go/internal/dao/account"
+	feeRepo "github.com/florentyang/smartfin-go/internal/dao/fee"
+	accountDomain "github.com/florentyang/smartfin-go/internal/domain/account"
+	feeDomain "github.com/florentyang/smartfin-go/internal/domain/fee"
+	fxDomain "github.com/florentyang/smartfin-go/internal/domain/fx"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== UseCase 结构体 ====================
+
+type usecase struct {
+	feeRepo     feeRepo.Repo     // 费率表 DAO
+	accountRepo accountRepo.Repo // 账户 DAO（校验适用账户的归属，读取交易账户的券商）
+}
+
+// ==================== 构造函数 ====================
+
+// NewFeeDomain 创建 Domain 实例
+func NewFeeDomain(feeRepo feeRepo.Repo, accountRepo accountRepo.Repo) feeDomain.Domain {
+	return &usecase{
+		feeRepo:     feeRepo,
+		accountRepo: accountRepo,
+	}
+}
+
+// ==================== 业务方法实现 ====================
+
+// WithTx 返回绑定到指定数据库事务的 Domain
+func (u *usecase) WithTx(tx *gorm.DB) feeDomain.Domain {
+	return &usecase{
+		feeRepo:     u.feeRepo.WithTx(tx),
+		accountRepo: u.accountRepo.WithTx(tx),
+	}
+}
+
+// Create 创建费率表
+func (u *usecase) Create(input *feeDomain.CreateInput) (*entity.FeeSchedule, error) {
+	schedule := &entity.FeeSchedule{UserID: input.UserID}
+	if err := u.apply(schedule, &input.ScheduleInput); err != nil {
+		return nil, err
+	}
+	if err := u.feeRepo.Create(schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// Get 查询单个费率表
+// 费率表不属于当前用户时按"不存在"处理，避免泄露他人数据
+func (u *usecase) Get(userID, id uint) (*entity.FeeSchedule, error) {
+	schedule, err := u.feeRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, feeRepo.ErrScheduleNotFound) {
+			return nil, feeDomain.ErrScheduleNotFound
+		}
+		return nil, err
+	}
+
+	// 归属校验：只能访问自己的费率表
+	if schedule.UserID != userID {
+		return nil, feeDomain.ErrScheduleNotFound
+	}
+
+	return schedule, nil
+}
+
+// List 查询用户的全部费率表
+func (u *usecase) List(userID uint) ([]*entity.FeeSchedule, error) {
+	return u.feeRepo.FindByUserID(userID)
+}
+
+// Update 更新费率表
+// 只影响之后新建或修改的交易，已计算的手续费不会重算
+func (u *usecase) Update(input *feeDomain.UpdateInput) (*entity.FeeSchedule, error) {
+	// 1. 查询并校验归属
+	schedule, err := u.Get(input.UserID, input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 校验并覆盖可编辑字段
+	if err := u.apply(schedule, &input.ScheduleInput); err != nil {
+		return nil, err
+	}
+
+	// 3. 保存
+	if err := u.feeRepo.Update(schedule); err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+// Delete 删除费率表
+func (u *usecase) Delete(userID, id uint) error {
+	// 1. 查询并校验归属
+	schedule, err := u.Get(userID, id)
+	if err != nil {
+		return err
+	}
+
+	// 2. 删除
+	if err := u.feeRepo.Delete(schedule.ID); err != nil {
+		if errors.Is(err, feeRepo.ErrScheduleNotFound) {
+			return feeDomain.ErrScheduleNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// Quote 为一笔买卖交易匹配费率表并计算手续费
+func (u *usecase) Quote(tx *entity.Transaction) (*feeDomain.Quote, error) {
+	// 1. 查询用户的全部费率表
+	schedules, err := u.feeRepo.FindByUserID(tx.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if len(schedules) == 0 {
+		return nil, nil
+	}
+
+	// 2. 交易账户的券商（未指定账户时只能匹配不限券商的费率表）
+	broker := ""
+	if tx.AccountID != 0 {
+		account, err := u.accountRepo.GetByID(tx.AccountID)
+		if err != nil {
+			if errors.Is(err, accountRepo.ErrAccountNotFound) {
+				return nil, accountDomain.ErrAccountNotFound
+			}
+			return nil, err
+		}
+		broker = account.Broker
+	}
+
+	// 3. 选出最具体的费率表（同样具体时取最早创建的，费率表已按 ID 排序）
+	var best *entity.FeeSchedule
+	bestScore := -1
+	for _, schedule := range schedules {
+		if score := specificity(schedule, tx, broker); score > bestScore {
+			best, bestScore = schedule, score
+		}
+	}
+	if best == nil {
+		return nil, nil
+	}
+
+	// 4. 计算手续费明细
+	return &feeDomain.Quote{
+		Schedule:  best,
+		Breakdown: calculate(best, tx),
+	}, nil
+}
+
+// ==================== 私有辅助函数 ====================
+
+// apply 校验费率表的可编辑字段并写入实体
+func (u *usecase) apply(schedule *entity.FeeSchedule, input *feeDomain.ScheduleInput) error {
+	// 1. 名称不能为空
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return feeDomain.ErrNameRequired
+	}
+
+	// 2. 费率和最低佣金不能为负数
+	for _, rate := range []decimal.Decimal{
+		input.CommissionRate, input.PerShareFee, input.MinCommission,
+		input.StampDutyRate, input.TransferFeeRate, input.LevyRate,
+	} {
+		if rate.IsNegative() {
+			return feeDomain.ErrInvalidRate
+		}
+	}
+
+	// 3. 适用账户必须属于当前用户
+	if input.AccountID != 0 {
+		account, err := u.accountRepo.GetByID(input.AccountID)
+		if err != nil {
+			if errors.Is(err, accountRepo.ErrAccountNotFound) {
+				return accountDomain.ErrAccountNotFound
+			}
+			return err
+		}
+		if account.UserID != schedule.UserID {
+			return accountDomain.ErrAccountNotFound
+		}
+	}
+
+	// 4. 适用币种（可选）
+	currency := ""
+	if input.Currency != "" {
+		code, err := fxDomain.NormalizeCurrency(input.Currency)
+		if err != nil {
+			return err
+		}
+		currency = code
+	}
+
+	schedule.Name = name
+	schedule.AccountID = input.AccountID
+	schedule.Broker = strings.TrimSpace(input.Broker)
+	schedule.Currency = currency
+	schedule.CommissionRate = input.CommissionRate
+	schedule.PerShareFee = input.PerShareFee
+	schedule.MinCommission = input.MinCommission
+	schedule.StampDutyRate = input.StampDutyRate
+	schedule.TransferFeeRate = input.TransferFeeRate
+	schedule.LevyRate = input.LevyRate
+	return nil
+}
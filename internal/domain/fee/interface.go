@@ -0,0 +1,84 @@
+package fee
+
+import (
+	"errors"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 错误定义 ====================
+// 领域层的业务错误（中文方便调试）
+
+var (
+	ErrScheduleNotFound = errors.New("费率表不存在")
+	ErrNameRequired     = errors.New("费率表名称不能为空")
+	ErrInvalidRate      = errors.New("费率和最低佣金不能为负数")
+)
+
+// ==================== Domain 输入结构体 ====================
+
+// ScheduleInput 费率表的可编辑字段（创建和更新共用）
+type ScheduleInput struct {
+	Name            string          // 名称（必须）
+	AccountID       uint            // 适用账户（可选，0 表示不限）
+	Broker          string          // 适用券商（可选，空表示不限）
+	Currency        string          // 适用交易币种（可选，空表示不限）
+	CommissionRate  decimal.Decimal // 佣金费率（按成交额）
+	PerShareFee     decimal.Decimal // 每股佣金
+	MinCommission   decimal.Decimal // 最低佣金
+	StampDutyRate   decimal.Decimal // 印花税率（仅卖出）
+	TransferFeeRate decimal.Decimal // 过户费率（买卖双边）
+	LevyRate        decimal.Decimal // 交易征费等双边费率合计
+}
+
+// CreateInput 创建费率表的输入参数
+type CreateInput struct {
+	UserID uint // 用户ID（必须）
+	ScheduleInput
+}
+
+// UpdateInput 更新费率表的输入参数
+// PUT 语义：整体替换可编辑字段
+type UpdateInput struct {
+	ID     uint // 费率表ID
+	UserID uint // 当前登录用户ID（用于归属校验）
+	ScheduleInput
+}
+
+// ==================== Domain 输出结构体 ====================
+
+// Quote 按费率表计算的一笔交易的手续费
+type Quote struct {
+	Schedule  *entity.FeeSchedule // 匹配到的费率表
+	Breakdown entity.FeeBreakdown // 手续费明细
+}
+
+// ==================== Domain 接口定义 ====================
+// Service 层和交易 Domain 会依赖这个接口
+
+type Domain interface {
+	// WithTx 返回绑定到指定数据库事务的 Domain
+	WithTx(tx *gorm.DB) Domain
+
+	// Create 创建费率表
+	Create(input *CreateInput) (*entity.FeeSchedule, error)
+
+	// Get 查询单个费率表（只能查询属于当前用户的费率表）
+	Get(userID, id uint) (*entity.FeeSchedule, error)
+
+	// List 查询用户的全部费率表
+	List(userID uint) ([]*entity.FeeSchedule, error)
+
+	// Update 更新费率表
+	Update(input *UpdateInput) (*entity.FeeSchedule, error)
+
+	// Delete 删除费率表（已按该费率表计算的交易保留手续费和明细）
+	Delete(userID, id uint) error
+
+	// Quote 为一笔买卖交易匹配费率表并计算手续费
+	// 账户、券商、币种均限定的费率表最具体，优先匹配；没有匹配的费率表时返回 nil
+	Quote(tx *entity.Transaction) (*Quote, error)
+}
@@ -0,0 +1,99 @@
+package impl
+
+import (
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	fxRepo "github.com/florentyang/smartfin-go/internal/dao/fxrate"
+	fxDomain "github.com/florentyang/smartfin-go/internal/domain/fx"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 汇率换算器 ====================
+// 第一次换算某个币种时，一次性加载该货币对的正向和反向汇率序列，
+// 之后按日期二分查找，避免报表中逐笔交易查询数据库
+
+// pairSeries 一个货币对的汇率序列（按日期正序）
+type pairSeries struct {
+	direct  []*entity.FXRate // from → to
+	inverse []*entity.FXRate // to → from
+}
+
+type converter struct {
+	fxRepo fxRepo.Repo
+	to     string
+	series map[string]*pairSeries // 按源货币缓存
+}
+
+// Currency 目标货币
+func (c *converter) Currency() string {
+	return c.to
+}
+
+// Rate 查询 from → 目标货币在 at 当天或之前最近的汇率
+func (c *converter) Rate(from string, at time.Time) (decimal.Decimal, error) {
+	// 1. 同币种：汇率为 1（未指定币种的历史数据按默认币种处理）
+	if from == "" {
+		from = entity.DefaultCurrency
+	}
+	if from == c.to {
+		return decimal.NewFromInt(1), nil
+	}
+
+	// 2. 加载并缓存货币对的汇率序列
+	s, err := c.load(from)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	// 3. 取日期较近的一条
+	date := fxDomain.DateOf(at)
+	rate, _, _, ok := pickRate(onOrBefore(s.direct, date), onOrBefore(s.inverse, date))
+	if !ok {
+		return decimal.Zero, rateNotFound(from, c.to, date)
+	}
+	return rate, nil
+}
+
+// Convert 将 from 货币的金额换算为目标货币
+func (c *converter) Convert(amount decimal.Decimal, from string, at time.Time) (decimal.Decimal, error) {
+	if amount.IsZero() {
+		return decimal.Zero, nil
+	}
+	rate, err := c.Rate(from, at)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return amount.Mul(rate).Round(4), nil
+}
+
+// load 加载 from → 目标货币的正向和反向汇率序列
+func (c *converter) load(from string) (*pairSeries, error) {
+	if s, ok := c.series[from]; ok {
+		return s, nil
+	}
+	direct, err := c.fxRepo.FindRates(&fxRepo.RateFilter{Base: from, Quote: c.to})
+	if err != nil {
+		return nil, err
+	}
+	inverse, err := c.fxRepo.FindRates(&fxRepo.RateFilter{Base: c.to, Quote: from})
+	if err != nil {
+		return nil, err
+	}
+	s := &pairSeries{direct: direct, inverse: inverse}
+	c.series[from] = s
+	return s, nil
+}
+
+// onOrBefore 在按日期正序的序列中查找 date 当天或之前最近的一条
+func onOrBefore(rates []*entity.FXRate, date time.Time) *entity.FXRate {
+	i := sort.Search(len(rates), func(i int) bool {
+		return fxDomain.DateOf(rates[i].RateDate).After(date)
+	})
+	if i == 0 {
+		return nil
+	}
+	return rates[i-1]
+}
@@ -0,0 +1,185 @@
+package impl
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	fxRepo "github.com/florentyang/smartfin-go/internal/dao/fxrate"
+	fxDomain "github.com/florentyang/smartfin-go/internal/domain/fx"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== UseCase 结构体 ====================
+
+type usecase struct {
+	fxRepo fxRepo.Repo // 依赖汇率 DAO 层接口
+}
+
+// ==================== 构造函数 ====================
+
+// NewFXDomain 创建 Domain 实例
+func NewFXDomain(repo fxRepo.Repo) fxDomain.Domain {
+	return &usecase{
+		fxRepo: repo,
+	}
+}
+
+// ==================== 业务方法实现 ====================
+
+// Import 批量导入汇率
+func (u *usecase) Import(input *fxDomain.ImportInput) (*fxDomain.ImportOutput, error) {
+	output := &fxDomain.ImportOutput{
+		Total: len(input.Rates),
+	}
+
+	// 1. 逐行校验并规范化
+	for i, rate := range input.Rates {
+		if err := normalizeRate(rate); err != nil {
+			output.Errors = append(output.Errors, &fxDomain.ImportRowError{Index: i, Err: err})
+		}
+	}
+	if len(output.Errors) > 0 || input.DryRun {
+		return output, nil
+	}
+
+	// 2. 全部通过才写入
+	if err := u.fxRepo.Upsert(input.Rates); err != nil {
+		return nil, err
+	}
+	output.Committed = true
+	return output, nil
+}
+
+// List 查询某个货币对的汇率序列
+func (u *usecase) List(input *fxDomain.ListInput) ([]*entity.FXRate, error) {
+	base, quote, err := normalizePair(input.Base, input.Quote)
+	if err != nil {
+		return nil, err
+	}
+	filter := &fxRepo.RateFilter{Base: base, Quote: quote}
+	// 日期统一按日历日期比较（与 date 列一致）
+	if input.StartDate != nil {
+		start := fxDomain.DateOf(*input.StartDate)
+		filter.StartDate = &start
+	}
+	if input.EndDate != nil {
+		end := fxDomain.DateOf(*input.EndDate)
+		filter.EndDate = &end
+	}
+	return u.fxRepo.FindRates(filter)
+}
+
+// Rate 查询 from → to 在指定日期当天或之前最近的汇率
+func (u *usecase) Rate(from, to string, date time.Time) (*fxDomain.RateOutput, error) {
+	// 1. 校验货币代码
+	var err error
+	if from, err = fxDomain.NormalizeCurrency(from); err != nil {
+		return nil, err
+	}
+	if to, err = fxDomain.NormalizeCurrency(to); err != nil {
+		return nil, err
+	}
+	date = fxDomain.DateOf(date)
+	output := &fxDomain.RateOutput{From: from, To: to, Date: date}
+
+	// 2. 同币种：汇率为 1
+	if from == to {
+		output.RateDate = date
+		output.Rate = decimal.NewFromInt(1)
+		return output, nil
+	}
+
+	// 3. 分别查询正向和反向货币对，取日期较近的一条
+	direct, err := u.findOnOrBefore(from, to, date)
+	if err != nil {
+		return nil, err
+	}
+	inverse, err := u.findOnOrBefore(to, from, date)
+	if err != nil {
+		return nil, err
+	}
+	rate, rateDate, inverted, ok := pickRate(direct, inverse)
+	if !ok {
+		return nil, rateNotFound(from, to, date)
+	}
+
+	output.RateDate = rateDate
+	output.Rate = rate.Round(8)
+	output.Inverted = inverted
+	return output, nil
+}
+
+// NewConverter 创建换算到 to 货币的 Converter
+func (u *usecase) NewConverter(to string) fxDomain.Converter {
+	return &converter{
+		fxRepo: u.fxRepo,
+		to:     to,
+		series: make(map[string]*pairSeries),
+	}
+}
+
+// ==================== 私有辅助函数 ====================
+
+// findOnOrBefore 查询汇率，不存在时返回 nil
+func (u *usecase) findOnOrBefore(base, quote string, date time.Time) (*entity.FXRate, error) {
+	rate, err := u.fxRepo.FindOnOrBefore(base, quote, date)
+	if errors.Is(err, fxRepo.ErrRateNotFound) {
+		return nil, nil
+	}
+	return rate, err
+}
+
+// normalizeRate 校验并规范化一条汇率
+func normalizeRate(rate *entity.FXRate) error {
+	base, quote, err := normalizePair(rate.Base, rate.Quote)
+	if err != nil {
+		return err
+	}
+	if rate.RateDate.IsZero() {
+		return fxDomain.ErrInvalidDate
+	}
+	if !rate.Rate.IsPositive() {
+		return fxDomain.ErrInvalidRate
+	}
+	rate.Base = base
+	rate.Quote = quote
+	rate.RateDate = fxDomain.DateOf(rate.RateDate)
+	return nil
+}
+
+// normalizePair 校验并规范化货币对
+func normalizePair(base, quote string) (string, string, error) {
+	base, err := fxDomain.NormalizeCurrency(base)
+	if err != nil {
+		return "", "", err
+	}
+	quote, err = fxDomain.NormalizeCurrency(quote)
+	if err != nil {
+		return "", "", err
+	}
+	if base == quote {
+		return "", "", fxDomain.ErrSameCurrency
+	}
+	return base, quote, nil
+}
+
+// pickRate 在正向和反向汇率中取日期较近的一条（同一天优先正向）
+// 反向汇率取倒数：1 / (1 To = x From)
+func pickRate(direct, inverse *entity.FXRate) (rate decimal.Decimal, rateDate time.Time, inverted, ok bool) {
+	switch {
+	case direct != nil && (inverse == nil || !direct.RateDate.Before(inverse.RateDate)):
+		return direct.Rate, direct.RateDate, false, true
+	case inverse != nil:
+		return decimal.NewFromInt(1).Div(inverse.Rate), inverse.RateDate, true, true
+	default:
+		return decimal.Zero, time.Time{}, false, false
+	}
+}
+
+// rateNotFound 缺少汇率的错误（带上货币对和日期，便于用户补录）
+func rateNotFound(from, to string, date time.Time) error {
+	return fmt.Errorf("%w: %s/%s %s", fxDomain.ErrRateNotFound, from, to, date.Format("2006-01-02"))
+}
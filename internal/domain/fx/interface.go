@@ -0,0 +1,131 @@
+package fx
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 错误定义 ====================
+// 领域层的业务错误（中文方便调试）
+
+var (
+	ErrInvalidCurrency = errors.New("货币代码无效，必须是 3 位字母的 ISO 4217 代码，如 USD")
+	ErrSameCurrency    = errors.New("货币对的两种货币不能相同")
+	ErrInvalidRate     = errors.New("汇率必须大于 0")
+	ErrInvalidDate     = errors.New("汇率日期不能为空")
+	ErrRateNotFound    = errors.New("缺少汇率")
+)
+
+// 报表币种：持仓、盈亏报表的金额按哪种币种列示
+const (
+	ReportCurrencyBase  = "base"  // 换算为用户的基准货币（默认）：成本按交易日汇率，市值按估值日汇率
+	ReportCurrencyTrade = "trade" // 保持交易币种，不做换算
+)
+
+// BaseCurrencyOf 用户的基准货币（未设置时为默认币种）
+func BaseCurrencyOf(user *entity.User) string {
+	if user.BaseCurrency == "" {
+		return entity.DefaultCurrency
+	}
+	return user.BaseCurrency
+}
+
+// NormalizeCurrency 校验并规范化货币代码（转为大写），如 "usd" → "USD"
+func NormalizeCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 3 {
+		return "", ErrInvalidCurrency
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return "", ErrInvalidCurrency
+		}
+	}
+	return code, nil
+}
+
+// DateOf 取时间所在的日历日期（汇率按日期生效）
+// 返回本地时区的零点，与数据库连接的 loc=Local 一致，写入 date 列时日期不会偏移
+func DateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// ==================== Domain 输入结构体 ====================
+
+// ImportInput 批量导入汇率的输入参数
+type ImportInput struct {
+	Rates  []*entity.FXRate // 按文件顺序的待导入汇率
+	DryRun bool             // 试运行：只校验不写入
+}
+
+// ListInput 查询汇率的输入参数
+type ListInput struct {
+	Base      string     // 基础货币（必须）
+	Quote     string     // 计价货币（必须）
+	StartDate *time.Time // 开始日期（可选）
+	EndDate   *time.Time // 结束日期（可选，不含）
+}
+
+// ==================== Domain 输出结构体 ====================
+
+// ImportRowError 单行导入错误
+type ImportRowError struct {
+	Index int   // 行下标（对应 ImportInput.Rates）
+	Err   error // 业务错误
+}
+
+// ImportOutput 批量导入汇率的输出结果
+type ImportOutput struct {
+	Total     int               // 总行数
+	Committed bool              // 是否已写入（存在错误行或试运行时不写入）
+	Errors    []*ImportRowError // 错误行
+}
+
+// RateOutput 汇率查询结果
+type RateOutput struct {
+	From     string          // 源货币
+	To       string          // 目标货币
+	Date     time.Time       // 查询日期
+	RateDate time.Time       // 实际采用的汇率日期（当天或之前最近一天）
+	Rate     decimal.Decimal // 1 From = Rate To
+	Inverted bool            // 是否由反向货币对取倒数得到
+}
+
+// ==================== Converter 接口 ====================
+
+// Converter 把多种货币的金额换算为同一目标货币
+// 按货币对缓存汇率序列，适合报表中大量按日期换算的场景；非并发安全，每次请求单独创建
+type Converter interface {
+	// Currency 目标货币
+	Currency() string
+
+	// Rate 查询 from → 目标货币在 at 当天或之前最近的汇率（同币种为 1）
+	Rate(from string, at time.Time) (decimal.Decimal, error)
+
+	// Convert 将 from 货币的金额按 at 当天的汇率换算为目标货币（保留 4 位小数）
+	Convert(amount decimal.Decimal, from string, at time.Time) (decimal.Decimal, error)
+}
+
+// ==================== Domain 接口定义 ====================
+// Service 层和其他 Domain 会依赖这个接口
+
+type Domain interface {
+	// Import 批量导入汇率
+	// 逐行校验，全部通过才写入；同一货币对同一天已存在时覆盖
+	Import(input *ImportInput) (*ImportOutput, error)
+
+	// List 查询某个货币对的汇率序列（按日期正序）
+	List(input *ListInput) ([]*entity.FXRate, error)
+
+	// Rate 查询 from → to 在指定日期当天或之前最近的汇率
+	// 只录入了反向货币对时自动取倒数
+	Rate(from, to string, date time.Time) (*RateOutput, error)
+
+	// NewConverter 创建换算到 to 货币的 Converter
+	NewConverter(to string) Converter
+}
@@ -0,0 +1,120 @@
+package impl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	idempotencyRepo "github.com/florentyang/smartfin-go/internal/dao/idempotency"
+	idempotencyDomain "github.com/florentyang/smartfin-go/internal/domain/idempotency"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+const (
+	// maxKeyLength 幂等键最大长度（与数据库字段一致）
+	maxKeyLength = 255
+
+	// keyTTL 幂等键有效期：过期后同一个键视为新请求
+	keyTTL = 24 * time.Hour
+
+	// pendingTimeout 处理中状态的超时时间：超时视为首次请求已中断（如进程崩溃），允许重新占用
+	pendingTimeout = 5 * time.Minute
+)
+
+// ==================== UseCase 结构体 ====================
+
+type usecase struct {
+	repo idempotencyRepo.Repo // 依赖 DAO 层接口
+}
+
+// ==================== 构造函数 ====================
+
+// NewIdempotencyDomain 创建 Domain 实例
+func NewIdempotencyDomain(repo idempotencyRepo.Repo) idempotencyDomain.Domain {
+	return &usecase{repo: repo}
+}
+
+// ==================== 业务方法实现 ====================
+
+// Begin 占用幂等键
+func (u *usecase) Begin(input *idempotencyDomain.BeginInput) (*idempotencyDomain.BeginOutput, error) {
+	// 1. 校验键
+	if input.Key == "" || len(input.Key) > maxKeyLength {
+		return nil, idempotencyDomain.ErrInvalidKey
+	}
+
+	// 2. 计算请求摘要：同一个键必须对应同一个请求
+	hash := requestHash(input.Method, input.Path, input.Body)
+
+	// 3. 查询已有记录
+	existing, err := u.repo.GetByKey(input.UserID, input.Key)
+	if err != nil && !errors.Is(err, idempotencyRepo.ErrKeyNotFound) {
+		return nil, err
+	}
+	if existing != nil {
+		if u.expired(existing) {
+			// 已过期或处理中途中断：删除后按新请求处理
+			if err := u.repo.Delete(existing.ID); err != nil {
+				return nil, err
+			}
+		} else {
+			if existing.RequestHash != hash {
+				return nil, idempotencyDomain.ErrKeyReused
+			}
+			if !existing.Completed() {
+				return nil, idempotencyDomain.ErrInProgress
+			}
+			return &idempotencyDomain.BeginOutput{Record: existing, Replay: true}, nil
+		}
+	}
+
+	// 4. 占用新键（唯一索引保证并发请求只有一个能占用成功）
+	record := &entity.IdempotencyKey{
+		UserID:      input.UserID,
+		Key:         input.Key,
+		Method:      input.Method,
+		Path:        input.Path,
+		RequestHash: hash,
+	}
+	if err := u.repo.Create(record); err != nil {
+		if errors.Is(err, idempotencyRepo.ErrKeyExists) {
+			// 并发的同键请求抢先占用
+			return nil, idempotencyDomain.ErrInProgress
+		}
+		return nil, err
+	}
+	return &idempotencyDomain.BeginOutput{Record: record}, nil
+}
+
+// Complete 保存首次请求的响应
+func (u *usecase) Complete(input *idempotencyDomain.CompleteInput) error {
+	return u.repo.SaveResponse(input.ID, input.StatusCode, input.ContentType, input.Body)
+}
+
+// Abandon 放弃幂等键
+func (u *usecase) Abandon(id uint) error {
+	return u.repo.Delete(id)
+}
+
+// ==================== 私有辅助函数 ====================
+
+// expired 记录是否已失效：超过有效期，或处理中状态超时
+func (u *usecase) expired(record *entity.IdempotencyKey) bool {
+	age := time.Since(record.CreatedAt)
+	if age > keyTTL {
+		return true
+	}
+	return !record.Completed() && age > pendingTimeout
+}
+
+// requestHash 计算请求摘要：SHA-256(方法 + 路径 + 请求体)
+func requestHash(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{'\n'})
+	h.Write([]byte(path))
+	h.Write([]byte{'\n'})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,57 @@
+package idempotency
+
+import (
+	"errors"
+
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 错误定义 ====================
+
+var (
+	ErrInvalidKey = errors.New("Idempotency-Key 长度必须在 1~255 个字符之间")
+	ErrKeyReused  = errors.New("Idempotency-Key 已用于内容不同的请求")
+	ErrInProgress = errors.New("相同 Idempotency-Key 的请求正在处理中，请稍后重试")
+)
+
+// ==================== Domain 输入输出结构体 ====================
+
+// BeginInput 开始一次幂等请求的输入参数
+type BeginInput struct {
+	UserID uint   // 当前登录用户ID
+	Key    string // Idempotency-Key 请求头
+	Method string // 请求方法
+	Path   string // 请求路径
+	Body   []byte // 请求体
+}
+
+// BeginOutput 开始一次幂等请求的结果
+type BeginOutput struct {
+	Record *entity.IdempotencyKey // 幂等键记录
+	Replay bool                   // true：首次请求已完成，直接回放 Record 中的响应
+}
+
+// CompleteInput 保存首次响应的输入参数
+type CompleteInput struct {
+	ID          uint   // 幂等键记录ID
+	StatusCode  int    // HTTP 状态码
+	ContentType string // Content-Type
+	Body        []byte // 响应体
+}
+
+// ==================== Domain 接口定义 ====================
+// 幂等中间件会依赖这个接口
+
+type Domain interface {
+	// Begin 占用幂等键
+	// - 新键：记录请求摘要并标记为处理中
+	// - 已完成且请求相同：返回 Replay = true
+	// - 请求内容不同：ErrKeyReused；首次请求仍在处理：ErrInProgress
+	Begin(input *BeginInput) (*BeginOutput, error)
+
+	// Complete 保存首次请求的响应，之后的重试直接回放
+	Complete(input *CompleteInput) error
+
+	// Abandon 放弃幂等键（首次请求服务端出错时调用，允许客户端用同一个键重试）
+	Abandon(id uint) error
+}
@@ -0,0 +1,251 @@
+package impl
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	instrumentRepo "github.com/florentyang/smartfin-go/internal/dao/instrument"
+	fxDomain "github.com/florentyang/smartfin-go/internal/domain/fx"
+	instrumentDomain "github.com/florentyang/smartfin-go/internal/domain/instrument"
+	marketDomain "github.com/florentyang/smartfin-go/internal/domain/market"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// maxExchangeLen 交易所代码的最大长度（与 instruments.exchange 列一致）
+const maxExchangeLen = 10
+
+// exchangeCurrencies 交易所的默认交易币种（未填写币种时使用）
+var exchangeCurrencies = map[string]string{
+	marketDomain.ExchangeNYSE: "USD",
+	marketDomain.ExchangeHKEX: "HKD",
+	marketDomain.ExchangeSSE:  "CNY",
+	marketDomain.ExchangeSZSE: "CNY",
+	marketDomain.ExchangeBSE:  "CNY",
+}
+
+// ==================== UseCase 结构体 ====================
+
+type usecase struct {
+	instrumentRepo instrumentRepo.Repo // 依赖 DAO 层接口
+}
+
+// ==================== 构造函数 ====================
+
+// NewInstrumentDomain 创建 Domain 实例
+func NewInstrumentDomain(repo instrumentRepo.Repo) instrumentDomain.Domain {
+	return &usecase{
+		instrumentRepo: repo,
+	}
+}
+
+// ==================== 业务方法实现 ====================
+
+// WithTx 返回绑定到指定数据库事务的 Domain
+func (u *usecase) WithTx(tx *gorm.DB) instrumentDomain.Domain {
+	return &usecase{
+		instrumentRepo: u.instrumentRepo.WithTx(tx),
+	}
+}
+
+// Create 创建证券
+func (u *usecase) Create(input *instrumentDomain.InstrumentInput) (*entity.Instrument, error) {
+	instrument := &entity.Instrument{}
+	if err := u.apply(instrument, input); err != nil {
+		return nil, err
+	}
+	if err := u.instrumentRepo.Create(instrument); err != nil {
+		return nil, err
+	}
+	return instrument, nil
+}
+
+// Get 查询单个证券
+func (u *usecase) Get(id uint) (*entity.Instrument, error) {
+	instrument, err := u.instrumentRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, instrumentRepo.ErrInstrumentNotFound) {
+			return nil, instrumentDomain.ErrInstrumentNotFound
+		}
+		return nil, err
+	}
+	return instrument, nil
+}
+
+// Search 按条件分页搜索证券
+// 关键字转为大写后匹配代码和 ISIN，名称按原样匹配（MySQL 默认排序规则不区分大小写）
+func (u *usecase) Search(input *instrumentDomain.SearchInput) (*instrumentDomain.SearchOutput, error) {
+	list, total, err := u.instrumentRepo.Search(&instrumentRepo.SearchFilter{
+		Query:      strings.ToUpper(strings.TrimSpace(input.Query)),
+		Exchange:   strings.ToUpper(strings.TrimSpace(input.Exchange)),
+		AssetClass: strings.ToUpper(strings.TrimSpace(input.AssetClass)),
+		Page:       input.Page,
+		PageSize:   input.PageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentDomain.SearchOutput{
+		List:  list,
+		Total: total,
+	}, nil
+}
+
+// Update 更新证券
+func (u *usecase) Update(input *instrumentDomain.UpdateInput) (*entity.Instrument, error) {
+	// 1. 查询证券
+	instrument, err := u.Get(input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 校验并覆盖可编辑字段
+	if err := u.apply(instrument, &input.InstrumentInput); err != nil {
+		return nil, err
+	}
+
+	// 3. 保存
+	if err := u.instrumentRepo.Update(instrument); err != nil {
+		return nil, err
+	}
+
+	return instrument, nil
+}
+
+// Delete 删除证券
+func (u *usecase) Delete(id uint) error {
+	if err := u.instrumentRepo.Delete(id); err != nil {
+		if errors.Is(err, instrumentRepo.ErrInstrumentNotFound) {
+			return instrumentDomain.ErrInstrumentNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// Resolve 规范化交易的股票代码并查找主数据
+func (u *usecase) Resolve(symbol, currency string) (*instrumentDomain.Resolution, error) {
+	// 1. 规范化股票代码
+	normalized, err := instrumentDomain.NormalizeSymbol(symbol, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 按代码查找主数据
+	instrument, err := u.instrumentRepo.GetBySymbol(normalized)
+	if err == nil {
+		return &instrumentDomain.Resolution{Symbol: instrument.Symbol, Instrument: instrument}, nil
+	}
+	if !errors.Is(err, instrumentRepo.ErrInstrumentNotFound) {
+		return nil, err
+	}
+
+	// 3. 传入的是 ISIN 时按 ISIN 查找，使用主数据的代码
+	if instrumentDomain.IsValidISIN(normalized) {
+		instrument, err = u.instrumentRepo.GetByISIN(normalized)
+		if err == nil {
+			return &instrumentDomain.Resolution{Symbol: instrument.Symbol, Instrument: instrument}, nil
+		}
+		if !errors.Is(err, instrumentRepo.ErrInstrumentNotFound) {
+			return nil, err
+		}
+	}
+
+	// 4. 主数据中没有：使用规范化的代码
+	return &instrumentDomain.Resolution{Symbol: normalized}, nil
+}
+
+// ==================== 私有辅助函数 ====================
+
+// apply 校验证券的可编辑字段并写入实体
+func (u *usecase) apply(instrument *entity.Instrument, input *instrumentDomain.InstrumentInput) error {
+	// 1. 币种（填写时先规范化，用于推断不带交易所标识的代码）
+	currency := ""
+	if input.Currency != "" {
+		code, err := fxDomain.NormalizeCurrency(input.Currency)
+		if err != nil {
+			return err
+		}
+		currency = code
+	}
+
+	// 2. 规范化股票代码
+	symbol, err := instrumentDomain.NormalizeSymbol(input.Symbol, currency)
+	if err != nil {
+		return err
+	}
+
+	// 3. 交易所：能从代码识别时以识别结果为准，填写的交易所必须一致
+	exchange := strings.ToUpper(strings.TrimSpace(input.Exchange))
+	if parsed, ok := marketDomain.ParseSymbol(symbol, currency); ok {
+		if exchange != "" && exchange != parsed.Exchange {
+			return instrumentDomain.ErrExchangeMismatch
+		}
+		exchange = parsed.Exchange
+	}
+	if len(exchange) > maxExchangeLen || strings.Trim(exchange, "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789") != "" {
+		return instrumentDomain.ErrInvalidExchange
+	}
+
+	// 4. 未填写币种时按交易所推断
+	if currency == "" {
+		currency = exchangeCurrencies[exchange]
+		if currency == "" {
+			return instrumentDomain.ErrCurrencyRequired
+		}
+	}
+
+	// 5. 资产类别（默认 STOCK）
+	assetClass := strings.ToUpper(strings.TrimSpace(input.AssetClass))
+	if assetClass == "" {
+		assetClass = entity.AssetClassStock
+	}
+	if !entity.IsValidAssetClass(assetClass) {
+		return instrumentDomain.ErrInvalidAssetClass
+	}
+
+	// 6. 每手股数（未填写时 A 股为 100，其他为 1）
+	lotSize := input.LotSize
+	if lotSize.IsNegative() {
+		return instrumentDomain.ErrInvalidLotSize
+	}
+	if lotSize.IsZero() {
+		lotSize = decimal.NewFromInt(1)
+		switch exchange {
+		case marketDomain.ExchangeSSE, marketDomain.ExchangeSZSE, marketDomain.ExchangeBSE:
+			lotSize = decimal.NewFromInt(100)
+		}
+	}
+
+	// 7. 名称不能为空
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return instrumentDomain.ErrNameRequired
+	}
+
+	// 8. ISIN（可选）
+	isin := strings.ToUpper(strings.TrimSpace(input.ISIN))
+	if isin != "" && !instrumentDomain.IsValidISIN(isin) {
+		return instrumentDomain.ErrInvalidISIN
+	}
+
+	// 9. 股票代码唯一（修改时排除自身）
+	exists, err := u.instrumentRepo.ExistsBySymbol(symbol, instrument.ID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return instrumentDomain.ErrSymbolExists
+	}
+
+	instrument.Symbol = symbol
+	instrument.Exchange = exchange
+	instrument.Currency = currency
+	instrument.AssetClass = assetClass
+	instrument.LotSize = lotSize
+	instrument.Name = name
+	instrument.ISIN = isin
+	return nil
+}
@@ -0,0 +1,177 @@
+package instrument
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	marketDomain "github.com/florentyang/smartfin-go/internal/domain/market"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 错误定义 ====================
+// 领域层的业务错误（中文方便调试）
+
+var (
+	ErrInstrumentNotFound = errors.New("证券不存在")
+	ErrInvalidSymbol      = errors.New("股票代码无效：只能包含字母、数字、点和横线，最长 20 个字符")
+	ErrSymbolExists       = errors.New("股票代码已存在")
+	ErrNameRequired       = errors.New("证券名称不能为空")
+	ErrInvalidExchange    = errors.New("交易所代码无效：只能包含字母和数字，最长 10 个字符")
+	ErrExchangeMismatch   = errors.New("交易所与股票代码的交易所后缀不一致")
+	ErrCurrencyRequired   = errors.New("无法从交易所推断币种，请填写币种")
+	ErrInvalidAssetClass  = errors.New("资产类别无效，可选 STOCK、ETF、FUND、BOND、OPTION、FUTURE、CRYPTO、OTHER")
+	ErrInvalidLotSize     = errors.New("每手股数必须大于 0")
+	ErrInvalidISIN        = errors.New("ISIN 无效：必须是 2 位国家代码 + 9 位字母数字 + 1 位校验码")
+)
+
+// maxSymbolLen 股票代码的最大长度（与 transactions.symbol 列一致）
+const maxSymbolLen = 20
+
+// ==================== Domain 输入结构体 ====================
+
+// InstrumentInput 证券的可编辑字段（创建和更新共用）
+type InstrumentInput struct {
+	Symbol     string          // 股票代码（必须，保存前规范化）
+	Exchange   string          // 交易所（可选，能从股票代码识别时自动填写）
+	Currency   string          // 交易币种（可选，能从交易所推断时自动填写）
+	AssetClass string          // 资产类别（可选，默认 STOCK）
+	LotSize    decimal.Decimal // 每手股数（可选，A 股默认 100，其他默认 1）
+	Name       string          // 证券名称（必须）
+	ISIN       string          // 国际证券识别码（可选）
+}
+
+// UpdateInput 更新证券的输入参数
+// PUT 语义：整体替换可编辑字段
+type UpdateInput struct {
+	ID uint // 证券ID
+	InstrumentInput
+}
+
+// SearchInput 搜索证券的输入参数
+type SearchInput struct {
+	Query      string // 关键字（可选）：代码前缀、名称包含或 ISIN
+	Exchange   string // 交易所（可选）
+	AssetClass string // 资产类别（可选）
+	Page       int    // 页码
+	PageSize   int    // 每页条数
+}
+
+// ==================== Domain 输出结构体 ====================
+
+// SearchOutput 搜索证券的输出结果
+type SearchOutput struct {
+	List  []*entity.Instrument // 证券列表
+	Total int64                // 总条数
+}
+
+// Resolution 股票代码的解析结果
+type Resolution struct {
+	Symbol     string             // 规范化的股票代码（主数据中有该证券时为主数据的代码）
+	Instrument *entity.Instrument // 主数据中的证券（没有时为 nil）
+}
+
+// ==================== Domain 接口定义 ====================
+// Service 层和交易 Domain 会依赖这个接口
+
+type Domain interface {
+	// WithTx 返回绑定到指定数据库事务的 Domain
+	WithTx(tx *gorm.DB) Domain
+
+	// Create 创建证券
+	Create(input *InstrumentInput) (*entity.Instrument, error)
+
+	// Get 查询单个证券
+	Get(id uint) (*entity.Instrument, error)
+
+	// Search 按关键字、交易所、资产类别分页搜索证券
+	Search(input *SearchInput) (*SearchOutput, error)
+
+	// Update 更新证券（已有交易的股票代码和名称不会随之修改）
+	Update(input *UpdateInput) (*entity.Instrument, error)
+
+	// Delete 删除证券（交易按代码文本关联，不受影响）
+	Delete(id uint) error
+
+	// Resolve 规范化交易的股票代码并查找主数据
+	// 先按规范化的代码查找，找不到且传入的是 ISIN 时再按 ISIN 查找；主数据中没有的代码照常使用
+	Resolve(symbol, currency string) (*Resolution, error)
+}
+
+// ==================== 工具函数 ====================
+
+// NormalizeSymbol 校验并规范化股票代码
+// 转为大写；能识别交易所的代码统一为一种写法，同一只股票的不同写法归为同一个持仓：
+// - 美股不带后缀：aapl、AAPL.US → AAPL
+// - A 股带交易所后缀：600519、SH600519、600519.SS → 600519.SH（不带后缀时需要人民币计价）
+// - 港股 4 位代码带后缀：700.HK、00700.HK → 0700.HK
+// - 代码中间的空格视为点：BRK B（IBKR 的写法）→ BRK.B
+// currency 为交易币种，用于推断不带交易所标识的代码所在市场
+func NormalizeSymbol(symbol, currency string) (string, error) {
+	s := strings.ToUpper(strings.Join(strings.Fields(symbol), "."))
+	if s == "" || len(s) > maxSymbolLen || strings.Trim(s, "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789.-") != "" {
+		return "", ErrInvalidSymbol
+	}
+
+	parsed, ok := marketDomain.ParseSymbol(s, currency)
+	if !ok {
+		return s, nil
+	}
+	switch parsed.Exchange {
+	case marketDomain.ExchangeSSE:
+		return parsed.Code + ".SH", nil
+	case marketDomain.ExchangeSZSE:
+		return parsed.Code + ".SZ", nil
+	case marketDomain.ExchangeBSE:
+		return parsed.Code + ".BJ", nil
+	case marketDomain.ExchangeHKEX:
+		code := strings.TrimLeft(parsed.Code, "0")
+		if len(code) < 4 {
+			code = strings.Repeat("0", 4-len(code)) + code
+		}
+		return code + ".HK", nil
+	default:
+		return parsed.Code, nil
+	}
+}
+
+// IsValidISIN 校验 ISIN（ISO 6166）：2 位字母国家代码 + 9 位字母数字 + 1 位 Luhn 校验码
+func IsValidISIN(isin string) bool {
+	if len(isin) != 12 {
+		return false
+	}
+	for i := 0; i < 2; i++ {
+		if isin[i] < 'A' || isin[i] > 'Z' {
+			return false
+		}
+	}
+
+	// 字母展开为两位数字（A=10 … Z=35），再对数字串做 Luhn 校验
+	var digits []int
+	for i := 0; i < 12; i++ {
+		c := isin[i]
+		switch {
+		case c >= '0' && c <= '9':
+			digits = append(digits, int(c-'0'))
+		case c >= 'A' && c <= 'Z' && i < 11:
+			v := int(c-'A') + 10
+			digits = append(digits, v/10, v%10)
+		default:
+			return false
+		}
+	}
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := digits[i]
+		if (len(digits)-1-i)%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
@@ -0,0 +1,139 @@
+package impl
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+
+	journalDomain "github.com/florentyang/smartfin-go/internal/domain/journal"
+	lotDomain "github.com/florentyang/smartfin-go/internal/domain/lot"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 过账规则 ====================
+// 每笔交易按类型生成一组分录（A = Amount，F = Fee，金额均为交易币种）：
+// - BUY：         借 证券 A+F；贷 现金 A+F（手续费计入成本）
+// - SELL：        借 现金 A-F；贷 证券 结转成本；贷 已实现损益 批次盈亏（亏损记借方）
+//                 超出批次的卖空部分没有成本，按净收入贷记证券（形成负持仓成本）
+// - DIVIDEND / INTEREST：借 现金 A-F；借 费用 F（预扣税）；贷 收入 A
+// - DEPOSIT：     借 现金 A-F；借 费用 F；贷 资本 A
+// - WITHDRAWAL：  借 资本 A；借 费用 F；贷 现金 A+F
+// - FEE：         借 费用 A+F；贷 现金 A+F
+// - TRANSFER_IN： 借 证券 A+F；贷 资本 A；贷 现金 F
+// - TRANSFER_OUT：借 资本 结转成本；借 费用 F；贷 证券 结转成本；贷 现金 F
+// - SPLIT：       不产生分录（数量变化，成本不变）
+// 现金科目的净发生额与 entity.CashFlow 一致。
+
+// entryBuilder 逐行构建一笔交易的分录
+type entryBuilder struct {
+	tx    *entity.Transaction
+	lines []*entity.JournalLine
+}
+
+// debit 记借方；金额为负时改记贷方，为 0 时不生成分录行
+func (b *entryBuilder) debit(ledger string, amount decimal.Decimal) {
+	if amount.IsNegative() {
+		b.credit(ledger, amount.Neg())
+		return
+	}
+	b.add(ledger, amount, decimal.Zero)
+}
+
+// credit 记贷方；金额为负时改记借方，为 0 时不生成分录行
+func (b *entryBuilder) credit(ledger string, amount decimal.Decimal) {
+	if amount.IsNegative() {
+		b.debit(ledger, amount.Neg())
+		return
+	}
+	b.add(ledger, decimal.Zero, amount)
+}
+
+func (b *entryBuilder) add(ledger string, debit, credit decimal.Decimal) {
+	if debit.IsZero() && credit.IsZero() {
+		return
+	}
+	b.lines = append(b.lines, &entity.JournalLine{
+		UserID:        b.tx.UserID,
+		Symbol:        b.tx.Symbol,
+		AccountID:     b.tx.AccountID,
+		TransactionID: b.tx.ID,
+		Ledger:        ledger,
+		Currency:      b.tx.Currency,
+		Debit:         debit,
+		Credit:        credit,
+		PostedAt:      b.tx.TradeTime,
+	})
+}
+
+// postTransaction 按过账规则生成一笔交易的分录，并校验借贷平衡
+// relief 为卖出、转出从批次中结转的汇总（其他类型为 nil）
+func postTransaction(tx *entity.Transaction, relief *lotDomain.Relief) ([]*entity.JournalLine, error) {
+	if relief == nil {
+		relief = &lotDomain.Relief{}
+	}
+
+	b := &entryBuilder{tx: tx}
+	switch tx.Type {
+	case entity.TransactionTypeBuy:
+		cost := tx.Amount.Add(tx.Fee)
+		b.debit(entity.LedgerSecurities, cost)
+		b.credit(entity.LedgerCash, cost)
+
+	case entity.TransactionTypeSell:
+		net := tx.Amount.Sub(tx.Fee)
+		uncovered := net.Sub(relief.Proceeds) // 卖空部分的净收入
+		b.debit(entity.LedgerCash, net)
+		b.credit(entity.LedgerSecurities, relief.CostBasis.Add(uncovered))
+		b.credit(entity.LedgerRealizedGain, relief.RealizedGain)
+
+	case entity.TransactionTypeDividend, entity.TransactionTypeInterest:
+		b.debit(entity.LedgerCash, tx.Amount.Sub(tx.Fee))
+		b.debit(entity.LedgerFees, tx.Fee)
+		b.credit(entity.LedgerIncome, tx.Amount)
+
+	case entity.TransactionTypeDeposit:
+		b.debit(entity.LedgerCash, tx.Amount.Sub(tx.Fee))
+		b.debit(entity.LedgerFees, tx.Fee)
+		b.credit(entity.LedgerCapital, tx.Amount)
+
+	case entity.TransactionTypeWithdrawal:
+		b.debit(entity.LedgerCapital, tx.Amount)
+		b.debit(entity.LedgerFees, tx.Fee)
+		b.credit(entity.LedgerCash, tx.Amount.Add(tx.Fee))
+
+	case entity.TransactionTypeFee:
+		b.debit(entity.LedgerFees, tx.Amount.Add(tx.Fee))
+		b.credit(entity.LedgerCash, tx.Amount.Add(tx.Fee))
+
+	case entity.TransactionTypeTransferIn:
+		b.debit(entity.LedgerSecurities, tx.Amount.Add(tx.Fee))
+		b.credit(entity.LedgerCapital, tx.Amount)
+		b.credit(entity.LedgerCash, tx.Fee)
+
+	case entity.TransactionTypeTransferOut:
+		b.debit(entity.LedgerCapital, relief.CostBasis)
+		b.debit(entity.LedgerFees, tx.Fee)
+		b.credit(entity.LedgerSecurities, relief.CostBasis)
+		b.credit(entity.LedgerCash, tx.Fee)
+	}
+
+	if err := checkBalanced(tx, b.lines); err != nil {
+		return nil, err
+	}
+	return b.lines, nil
+}
+
+// checkBalanced 借贷平衡校验：一笔交易的借方合计必须等于贷方合计
+// 一笔交易只有一个币种，因此不需要按币种拆分
+func checkBalanced(tx *entity.Transaction, lines []*entity.JournalLine) error {
+	debit, credit := decimal.Zero, decimal.Zero
+	for _, line := range lines {
+		debit = debit.Add(line.Debit)
+		credit = credit.Add(line.Credit)
+	}
+	if !debit.Equal(credit) {
+		return fmt.Errorf("%w：交易 %d 借方合计 %s，贷方合计 %s",
+			journalDomain.ErrUnbalanced, tx.ID, debit.String(), credit.String())
+	}
+	return nil
+}
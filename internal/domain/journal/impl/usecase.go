@@ -0,0 +1,192 @@
+package impl
+
+import (
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"github.com/florentyang/smartfin-go/internal/dao"
+	journalRepo "github.com/florentyang/smartfin-go/internal/dao/journal"
+	txRepo "github.com/florentyang/smartfin-go/internal/dao/transaction"
+	journalDomain "github.com/florentyang/smartfin-go/internal/domain/journal"
+	lotDomain "github.com/florentyang/smartfin-go/internal/domain/lot"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== UseCase 结构体 ====================
+
+type usecase struct {
+	journalRepo journalRepo.Repo // 分录 DAO
+	txRepo      txRepo.Repo      // 交易 DAO（读取交易流水）
+	lotDomain   lotDomain.Domain // 批次 Domain（卖出、转出的结转成本）
+	transactor  dao.Transactor   // 事务管理器
+}
+
+// ==================== 构造函数 ====================
+
+// NewJournalDomain 创建 Domain 实例
+func NewJournalDomain(
+	journalRepo journalRepo.Repo,
+	txRepo txRepo.Repo,
+	lotDomain lotDomain.Domain,
+	transactor dao.Transactor,
+) journalDomain.Domain {
+	return &usecase{
+		journalRepo: journalRepo,
+		txRepo:      txRepo,
+		lotDomain:   lotDomain,
+		transactor:  transactor,
+	}
+}
+
+// ==================== 业务方法实现 ====================
+
+// WithTx 返回绑定到指定数据库事务的 Domain
+func (u *usecase) WithTx(tx *gorm.DB) journalDomain.Domain {
+	return &usecase{
+		journalRepo: u.journalRepo.WithTx(tx),
+		txRepo:      u.txRepo.WithTx(tx),
+		lotDomain:   u.lotDomain.WithTx(tx),
+		transactor:  u.transactor,
+	}
+}
+
+// Post 重新过账用户某只股票的全部交易
+func (u *usecase) Post(userID uint, symbol string) error {
+	// 1. 查询该股票的全部交易（没有股票代码时只查现金交易）
+	ledger, err := u.txRepo.FindLedger(&txRepo.LedgerFilter{
+		UserID:   userID,
+		Symbol:   symbol,
+		NoSymbol: symbol == "",
+	})
+	if err != nil {
+		return err
+	}
+
+	// 2. 回放批次，得到每笔卖出、转出的结转成本（现金交易不涉及批次）
+	var reliefs map[uint]*lotDomain.Relief
+	if symbol != "" {
+		reliefs, err = u.lotDomain.Reliefs(userID, symbol)
+		if err != nil {
+			return err
+		}
+	}
+
+	// 3. 逐笔过账，任意一笔借贷不平衡则整体拒绝
+	var lines []*entity.JournalLine
+	for _, tx := range ledger {
+		posted, err := postTransaction(tx, reliefs[tx.ID])
+		if err != nil {
+			return err
+		}
+		lines = append(lines, posted...)
+	}
+
+	// 4. 整体替换
+	return u.journalRepo.ReplaceSymbol(userID, symbol, lines)
+}
+
+// PostAll 重新过账用户的全部交易
+// 在一个事务中完成，任意一只股票过账失败则整体回滚
+func (u *usecase) PostAll(userID uint) (int, error) {
+	var count int
+	err := u.transactor.Transaction(func(db *gorm.DB) error {
+		w := u.WithTx(db)
+
+		// 1. 从交易流水中收集股票代码（含没有股票代码的现金交易）
+		ledger, err := u.txRepo.WithTx(db).FindLedger(&txRepo.LedgerFilter{UserID: userID})
+		if err != nil {
+			return err
+		}
+		seen := make(map[string]bool)
+		for _, tx := range ledger {
+			if seen[tx.Symbol] {
+				continue
+			}
+			seen[tx.Symbol] = true
+
+			// 2. 逐只股票过账
+			if err := w.Post(userID, tx.Symbol); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Entries 分页查询分录
+func (u *usecase) Entries(input *journalDomain.EntriesInput) (*journalDomain.EntriesOutput, error) {
+	lines, total, err := u.journalRepo.FindLines(&journalRepo.LineFilter{
+		UserID:        input.UserID,
+		AccountID:     input.AccountID,
+		TransactionID: input.TransactionID,
+		Ledger:        input.Ledger,
+		StartTime:     input.StartTime,
+		EndTime:       input.EndTime,
+		Page:          input.Page,
+		PageSize:      input.PageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &journalDomain.EntriesOutput{
+		Lines: lines,
+		Total: total,
+	}, nil
+}
+
+// TrialBalance 试算平衡表
+// 按科目 + 币种汇总借贷发生额，并按币种检查借贷合计是否相等
+func (u *usecase) TrialBalance(input *journalDomain.TrialBalanceInput) (*journalDomain.TrialBalanceOutput, error) {
+	// 1. 数据库汇总（已按币种、科目排序）
+	totals, err := u.journalRepo.SumByLedger(&journalRepo.BalanceFilter{
+		UserID:    input.UserID,
+		AccountID: input.AccountID,
+		EndTime:   input.EndTime,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 逐行计算余额，并累计各币种合计
+	output := &journalDomain.TrialBalanceOutput{Balanced: true}
+	byCurrency := make(map[string]*journalDomain.TrialBalanceTotal)
+	for _, t := range totals {
+		output.Rows = append(output.Rows, &journalDomain.TrialBalanceRow{
+			Ledger:   t.Ledger,
+			Currency: t.Currency,
+			Debit:    t.Debit,
+			Credit:   t.Credit,
+			Balance:  t.Debit.Sub(t.Credit),
+		})
+
+		total, ok := byCurrency[t.Currency]
+		if !ok {
+			total = &journalDomain.TrialBalanceTotal{
+				Currency: t.Currency,
+				Debit:    decimal.Zero,
+				Credit:   decimal.Zero,
+			}
+			byCurrency[t.Currency] = total
+			output.Totals = append(output.Totals, total)
+		}
+		total.Debit = total.Debit.Add(t.Debit)
+		total.Credit = total.Credit.Add(t.Credit)
+	}
+
+	// 3. 按币种检查借贷平衡
+	for _, total := range output.Totals {
+		total.Balanced = total.Debit.Equal(total.Credit)
+		if !total.Balanced {
+			output.Balanced = false
+		}
+	}
+
+	return output, nil
+}
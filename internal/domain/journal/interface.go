@@ -0,0 +1,96 @@
+package journal
+
+import (
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 错误定义 ====================
+// 领域层的业务错误（中文方便调试）
+
+var (
+	// ErrUnbalanced 一笔交易的分录借贷不相等，拒绝过账（整个数据库事务回滚）
+	ErrUnbalanced = errors.New("分录借贷不平衡")
+)
+
+// ==================== Domain 输入结构体 ====================
+
+// EntriesInput 查询分录的输入参数
+type EntriesInput struct {
+	UserID        uint       // 用户ID（必须）
+	AccountID     *uint      // 账户ID（可选，nil 表示全部账户）
+	TransactionID uint       // 来源交易ID（可选）
+	Ledger        string     // 会计科目（可选）
+	StartTime     *time.Time // 记账时间起（可选）
+	EndTime       *time.Time // 记账时间止（可选，不含）
+	Page          int        // 页码
+	PageSize      int        // 每页条数
+}
+
+// TrialBalanceInput 试算平衡的输入参数
+type TrialBalanceInput struct {
+	UserID    uint       // 用户ID（必须）
+	AccountID *uint      // 账户ID（可选，nil 表示全部账户）
+	EndTime   *time.Time // 截止时间（可选，不含；不传则包含全部分录）
+}
+
+// ==================== Domain 输出结构体 ====================
+
+// EntriesOutput 查询分录的输出结果
+type EntriesOutput struct {
+	Lines []*entity.JournalLine // 分录行
+	Total int64                 // 总条数
+}
+
+// TrialBalanceRow 试算平衡表的一行：一个会计科目在一个币种下的发生额与余额
+type TrialBalanceRow struct {
+	Ledger   string
+	Currency string
+	Debit    decimal.Decimal // 借方发生额
+	Credit   decimal.Decimal // 贷方发生额
+	Balance  decimal.Decimal // 余额 = 借方 - 贷方（资产、费用为正，收入、权益为负）
+}
+
+// TrialBalanceTotal 单一币种的借贷合计
+type TrialBalanceTotal struct {
+	Currency string
+	Debit    decimal.Decimal
+	Credit   decimal.Decimal
+	Balanced bool // 借方合计 = 贷方合计
+}
+
+// TrialBalanceOutput 试算平衡表
+// 不同币种的金额不能相加，借贷平衡按币种分别检查
+type TrialBalanceOutput struct {
+	Rows     []*TrialBalanceRow   // 按币种、科目排序
+	Totals   []*TrialBalanceTotal // 各币种借贷合计
+	Balanced bool                 // 全部币种均平衡
+}
+
+// ==================== Domain 接口定义 ====================
+// Service 层和交易 Domain 会依赖这个接口
+
+type Domain interface {
+	// WithTx 返回绑定到指定数据库事务的 Domain
+	// 交易 Domain 在写入交易的同一事务中重新过账
+	WithTx(tx *gorm.DB) Domain
+
+	// Post 重新过账用户某只股票的全部交易（symbol 为空表示没有股票代码的现金交易）
+	// 卖出、转出的结转成本依赖批次回放，补录或修改历史交易后其后的分录也会变化，因此按股票整体过账；
+	// 任意一笔交易的分录借贷不平衡时返回 ErrUnbalanced
+	Post(userID uint, symbol string) error
+
+	// PostAll 重新过账用户的全部交易（用于历史数据初始化），返回过账的股票数量（含现金交易分组）
+	PostAll(userID uint) (int, error)
+
+	// Entries 分页查询分录
+	Entries(input *EntriesInput) (*EntriesOutput, error)
+
+	// TrialBalance 试算平衡表
+	TrialBalance(input *TrialBalanceInput) (*TrialBalanceOutput, error)
+}
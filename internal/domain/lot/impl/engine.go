@@ -0,0 +1,266 @@
+package impl
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/shopspring/decimal"
+
+	lotDomain "github.com/florentyang/smartfin-go/internal/domain/lot"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 批次回放引擎 ====================
+// 按交易时间顺序回放单只股票的交易流水：
+// - BUY：开立一个批次，成本 = 成交金额 + 手续费
+// - TRANSFER_IN：开立一个批次，成本 = 转入成本 + 手续费
+// - SELL：按该笔卖出记录的成本计算方法消耗未平仓批次，生成分配记录；
+//   未记录方法的历史卖出按所属账户的默认方法（见 lotDomain.DefaultMethod）
+//   卖出手续费按数量分摊到各个分配记录，已实现盈亏 = 分摊后的净收入 - 结转成本
+// - TRANSFER_OUT：按同样的方法消耗批次，成本随股票转出，不生成分配记录（不产生已实现盈亏）
+// - SPLIT：未平仓批次的数量按比例缩放，成本不变，单位成本相应调整
+// - 现金类交易（分红、利息等）不影响批次
+// 批次按账户隔离：卖出、转出只消耗同一账户的批次，拆股只调整同一账户的批次
+// 超出未平仓批次的卖出部分（卖空）不生成分配记录
+
+// methodResolver 按账户ID返回默认成本计算方法（交易未记录方法时使用）
+type methodResolver func(accountID uint) string
+
+// replay 回放交易流水，返回批次、分配记录，以及每笔卖出/转出的结转汇总
+func replay(userID uint, symbol string, ledger []*entity.Transaction, defaults methodResolver) ([]*entity.TaxLot, []*entity.LotAssignment, map[uint]*lotDomain.Relief, error) {
+	r := newReplayer(userID, symbol, defaults)
+	for _, tx := range ledger {
+		if err := r.apply(tx); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	return r.lots, r.assignments, r.reliefs, nil
+}
+
+// replayer 单只股票的回放状态，逐笔 apply 交易
+// 需要在多个时点取未平仓批次时（按月估值），一次回放中途调用 openLots 即可
+type replayer struct {
+	userID      uint
+	symbol      string
+	defaults    methodResolver // 为 nil 时未记录方法的卖出按 FIFO
+	lots        []*entity.TaxLot
+	assignments []*entity.LotAssignment
+	lotByID     map[uint]*entity.TaxLot
+	reliefs     map[uint]*lotDomain.Relief
+}
+
+// newReplayer 创建空的回放状态
+func newReplayer(userID uint, symbol string, defaults methodResolver) *replayer {
+	return &replayer{
+		userID:   userID,
+		symbol:   symbol,
+		defaults: defaults,
+		lotByID:  make(map[uint]*entity.TaxLot),
+		reliefs:  make(map[uint]*lotDomain.Relief),
+	}
+}
+
+// method 卖出、转出采用的成本计算方法：交易记录的方法优先，否则取所属账户的默认方法
+func (r *replayer) method(tx *entity.Transaction) string {
+	if tx.CostBasisMethod != "" {
+		return tx.CostBasisMethod
+	}
+	if r.defaults != nil {
+		return r.defaults(tx.AccountID)
+	}
+	return entity.CostBasisFIFO
+}
+
+// apply 回放一笔交易
+func (r *replayer) apply(tx *entity.Transaction) error {
+	switch tx.Type {
+	case entity.TransactionTypeBuy, entity.TransactionTypeTransferIn:
+		lot := openLot(r.userID, r.symbol, tx)
+		r.lots = append(r.lots, lot)
+		r.lotByID[lot.ID] = lot
+
+	case entity.TransactionTypeSell, entity.TransactionTypeTransferOut:
+		// 1. 确定消耗顺序
+		method := r.method(tx)
+		candidates, err := sellCandidates(tx, method, r.lots, r.lotByID)
+		if err != nil {
+			return err
+		}
+
+		// 2. 逐个批次消耗
+		sold, err := consumeLots(r.userID, r.symbol, tx, method, candidates)
+		if err != nil {
+			return err
+		}
+
+		// 3. 只有卖出产生已实现盈亏；转出只结转成本
+		relief := &lotDomain.Relief{}
+		for _, a := range sold {
+			relief.CostBasis = relief.CostBasis.Add(a.CostBasis)
+		}
+		if tx.Type == entity.TransactionTypeSell {
+			r.assignments = append(r.assignments, sold...)
+			for _, a := range sold {
+				relief.Proceeds = relief.Proceeds.Add(a.Proceeds)
+				relief.RealizedGain = relief.RealizedGain.Add(a.RealizedGain)
+			}
+		}
+		r.reliefs[tx.ID] = relief
+
+	case entity.TransactionTypeSplit:
+		splitLots(r.lots, tx.AccountID, tx.Ratio)
+	}
+	return nil
+}
+
+// openLots 当前的未平仓批次（副本，之后的回放不会修改）
+func (r *replayer) openLots() []*entity.TaxLot {
+	var open []*entity.TaxLot
+	for _, lot := range r.lots {
+		if lot.RemainingQuantity.IsPositive() {
+			copied := *lot
+			open = append(open, &copied)
+		}
+	}
+	return open
+}
+
+// openLot 由一笔 BUY / TRANSFER_IN 开立批次
+// 两者的 Amount 分别是成交金额和转入成本
+func openLot(userID uint, symbol string, tx *entity.Transaction) *entity.TaxLot {
+	costBasis := tx.Amount.Add(tx.Fee)
+	return &entity.TaxLot{
+		ID:                tx.ID,
+		UserID:            userID,
+		Symbol:            symbol,
+		AccountID:         tx.AccountID,
+		OpenTime:          tx.TradeTime,
+		Currency:          tx.Currency,
+		Quantity:          tx.Quantity,
+		CostBasis:         costBasis,
+		UnitCost:          costBasis.Div(tx.Quantity).Round(4),
+		RemainingQuantity: tx.Quantity,
+		RemainingCost:     costBasis,
+	}
+}
+
+// splitLots 拆股/合股：账户内未平仓批次的数量按比例缩放，总成本不变
+// 数量保留 4 位小数，与数据库精度一致
+func splitLots(lots []*entity.TaxLot, accountID uint, ratio decimal.Decimal) {
+	for _, lot := range lots {
+		if lot.AccountID != accountID || !lot.RemainingQuantity.IsPositive() {
+			continue
+		}
+		lot.Quantity = lot.Quantity.Mul(ratio).Round(4)
+		lot.RemainingQuantity = lot.RemainingQuantity.Mul(ratio).Round(4)
+		lot.UnitCost = lot.CostBasis.Div(lot.Quantity).Round(4)
+	}
+}
+
+// sellCandidates 按成本计算方法返回批次消耗顺序（卖出、转出共用）
+func sellCandidates(tx *entity.Transaction, method string, lots []*entity.TaxLot, lotByID map[uint]*entity.TaxLot) ([]*entity.TaxLot, error) {
+	// 指定批次：按用户给出的顺序
+	if method == entity.CostBasisSpecific {
+		ids, err := lotDomain.DecodeLotIDs(tx.LotIDs)
+		if err != nil {
+			return nil, err
+		}
+		candidates := make([]*entity.TaxLot, 0, len(ids))
+		for _, id := range ids {
+			lot, ok := lotByID[id]
+			if !ok {
+				// 批次不存在、不属于该股票或开仓晚于卖出时间
+				return nil, fmt.Errorf("%w：批次 %d 在卖出交易 %d 之前不存在", lotDomain.ErrInvalidLotSelection, id, tx.ID)
+			}
+			if lot.AccountID != tx.AccountID {
+				return nil, fmt.Errorf("%w：批次 %d 不属于卖出交易 %d 的账户", lotDomain.ErrInvalidLotSelection, id, tx.ID)
+			}
+			candidates = append(candidates, lot)
+		}
+		return candidates, nil
+	}
+
+	// 其他方法：从同一账户的未平仓批次中排序
+	candidates := make([]*entity.TaxLot, 0, len(lots))
+	for _, lot := range lots {
+		if lot.AccountID == tx.AccountID && lot.RemainingQuantity.IsPositive() {
+			candidates = append(candidates, lot)
+		}
+	}
+
+	switch method {
+	case entity.CostBasisLIFO:
+		// 后进先出：开仓时间倒序
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return candidates[i].OpenTime.After(candidates[j].OpenTime)
+		})
+	case entity.CostBasisHIFO:
+		// 最高成本优先：单位成本倒序，相同成本先进先出
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return candidates[i].UnitCost.GreaterThan(candidates[j].UnitCost)
+		})
+	default:
+		// FIFO：已按开仓时间正序
+	}
+
+	return candidates, nil
+}
+
+// consumeLots 按顺序消耗批次，生成分配记录
+func consumeLots(userID uint, symbol string, tx *entity.Transaction, method string, candidates []*entity.TaxLot) ([]*entity.LotAssignment, error) {
+	netProceeds := tx.Amount.Sub(tx.Fee) // 卖出净收入
+	need := tx.Quantity                  // 待消耗数量
+	remainProceeds := netProceeds        // 待分摊收入
+
+	var assignments []*entity.LotAssignment
+	for _, lot := range candidates {
+		if need.IsZero() {
+			break
+		}
+		if !lot.RemainingQuantity.IsPositive() {
+			continue
+		}
+
+		qty := decimal.Min(need, lot.RemainingQuantity)
+
+		// 结转成本：批次用完时取剩余成本，避免尾差
+		cost := lot.RemainingCost
+		if qty.LessThan(lot.RemainingQuantity) {
+			cost = lot.RemainingCost.Mul(qty).Div(lot.RemainingQuantity).Round(4)
+		}
+
+		// 分摊收入：最后一次分摊取剩余收入，避免尾差
+		proceeds := remainProceeds
+		if qty.LessThan(need) {
+			proceeds = netProceeds.Mul(qty).Div(tx.Quantity).Round(4)
+		}
+
+		lot.RemainingQuantity = lot.RemainingQuantity.Sub(qty)
+		lot.RemainingCost = lot.RemainingCost.Sub(cost)
+		need = need.Sub(qty)
+		remainProceeds = remainProceeds.Sub(proceeds)
+
+		assignments = append(assignments, &entity.LotAssignment{
+			UserID:            userID,
+			Symbol:            symbol,
+			AccountID:         tx.AccountID,
+			SellTransactionID: tx.ID,
+			LotID:             lot.ID,
+			Method:            method,
+			Currency:          tx.Currency,
+			Quantity:          qty,
+			CostBasis:         cost,
+			Proceeds:          proceeds,
+			RealizedGain:      proceeds.Sub(cost),
+			OpenTime:          lot.OpenTime,
+			CloseTime:         tx.TradeTime,
+		})
+	}
+
+	// 指定批次必须覆盖全部卖出数量
+	if method == entity.CostBasisSpecific && need.IsPositive() {
+		return nil, fmt.Errorf("%w：卖出交易 %d 指定批次的剩余数量不足", lotDomain.ErrInvalidLotSelection, tx.ID)
+	}
+
+	return assignments, nil
+}
@@ -0,0 +1,432 @@
+package impl
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	lotDomain "github.com/florentyang/smartfin-go/internal/domain/lot"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 测试数据构造 ====================
+
+// day 2024 年 1 月的某一天（同一天内按交易ID排序，回放只看流水顺序）
+func day(d int) time.Time {
+	return time.Date(2024, 1, d, 10, 0, 0, 0, time.UTC)
+}
+
+// trade 构造一笔买卖/转托管交易
+func trade(id, accountID uint, txType string, d int, quantity, amount, fee string) *entity.Transaction {
+	return &entity.Transaction{
+		ID:        id,
+		AccountID: accountID,
+		Symbol:    "AAPL",
+		Type:      txType,
+		Quantity:  decimal.RequireFromString(quantity),
+		Amount:    decimal.RequireFromString(amount),
+		Fee:       decimal.RequireFromString(fee),
+		Currency:  "USD",
+		TradeTime: day(d),
+	}
+}
+
+// sell 构造一笔卖出，method 为空表示未记录方法
+func sell(id, accountID uint, d int, quantity, amount, fee, method string, lotIDs ...uint) *entity.Transaction {
+	tx := trade(id, accountID, entity.TransactionTypeSell, d, quantity, amount, fee)
+	tx.CostBasisMethod = method
+	tx.LotIDs = lotDomain.EncodeLotIDs(lotIDs)
+	return tx
+}
+
+// split 构造一笔拆股
+func split(id, accountID uint, d int, ratio string) *entity.Transaction {
+	tx := trade(id, accountID, entity.TransactionTypeSplit, d, "0", "0", "0")
+	tx.Ratio = decimal.RequireFromString(ratio)
+	return tx
+}
+
+// threeBuys 账户 1 的三个批次：单位成本 101（含手续费）、120、110
+func threeBuys() []*entity.Transaction {
+	return []*entity.Transaction{
+		trade(1, 1, entity.TransactionTypeBuy, 1, "10", "1000", "10"),
+		trade(2, 1, entity.TransactionTypeBuy, 2, "10", "1200", "0"),
+		trade(3, 1, entity.TransactionTypeBuy, 3, "10", "1100", "0"),
+	}
+}
+
+// ==================== 断言 ====================
+
+// wantLot 期望的批次状态
+type wantLot struct {
+	id            uint
+	quantity      string
+	unitCost      string
+	remainingQty  string
+	remainingCost string
+}
+
+// wantAssignment 期望的分配记录
+type wantAssignment struct {
+	sellID   uint
+	lotID    uint
+	method   string
+	quantity string
+	cost     string
+	proceeds string
+	gain     string
+}
+
+// wantRelief 期望的结转汇总
+type wantRelief struct {
+	cost     string
+	proceeds string
+	gain     string
+}
+
+// assertDecimal 比较 decimal 与期望的字符串
+func assertDecimal(t *testing.T, field string, got decimal.Decimal, want string) {
+	t.Helper()
+	if !got.Equal(decimal.RequireFromString(want)) {
+		t.Errorf("%s = %s, want %s", field, got, want)
+	}
+}
+
+// assertLots 逐个比较回放后的批次（含已平仓批次，按开仓顺序）
+func assertLots(t *testing.T, got []*entity.TaxLot, want []wantLot) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("len(lots) = %d, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		lot := got[i]
+		if lot.ID != w.id {
+			t.Errorf("lots[%d].ID = %d, want %d", i, lot.ID, w.id)
+		}
+		assertDecimal(t, "quantity", lot.Quantity, w.quantity)
+		assertDecimal(t, "unit_cost", lot.UnitCost, w.unitCost)
+		assertDecimal(t, "remaining_quantity", lot.RemainingQuantity, w.remainingQty)
+		assertDecimal(t, "remaining_cost", lot.RemainingCost, w.remainingCost)
+	}
+}
+
+// assertAssignments 逐个比较分配记录
+func assertAssignments(t *testing.T, got []*entity.LotAssignment, want []wantAssignment) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("len(assignments) = %d, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		a := got[i]
+		if a.SellTransactionID != w.sellID || a.LotID != w.lotID || a.Method != w.method {
+			t.Errorf("assignments[%d] sell/lot/method = %d/%d/%s, want %d/%d/%s",
+				i, a.SellTransactionID, a.LotID, a.Method, w.sellID, w.lotID, w.method)
+		}
+		assertDecimal(t, "quantity", a.Quantity, w.quantity)
+		assertDecimal(t, "cost_basis", a.CostBasis, w.cost)
+		assertDecimal(t, "proceeds", a.Proceeds, w.proceeds)
+		assertDecimal(t, "realized_gain", a.RealizedGain, w.gain)
+	}
+}
+
+// ==================== 测试用例 ====================
+
+func TestReplay(t *testing.T) {
+	lifo := func(uint) string { return entity.CostBasisLIFO }
+
+	tests := []struct {
+		name            string
+		ledger          []*entity.Transaction
+		defaults        methodResolver
+		wantLots        []wantLot
+		wantAssignments []wantAssignment
+		wantReliefs     map[uint]wantRelief
+	}{
+		{
+			// 净收入 1935 按数量分摊：10/15 → 1290，最后一笔取剩余 645
+			name:   "FIFO 先消耗最早的批次，手续费按数量分摊",
+			ledger: append(threeBuys(), sell(4, 1, 4, "15", "1950", "15", entity.CostBasisFIFO)),
+			wantLots: []wantLot{
+				{1, "10", "101", "0", "0"},
+				{2, "10", "120", "5", "600"},
+				{3, "10", "110", "10", "1100"},
+			},
+			wantAssignments: []wantAssignment{
+				{4, 1, entity.CostBasisFIFO, "10", "1010", "1290", "280"},
+				{4, 2, entity.CostBasisFIFO, "5", "600", "645", "45"},
+			},
+			wantReliefs: map[uint]wantRelief{4: {"1610", "1935", "325"}},
+		},
+		{
+			name:   "LIFO 先消耗最近的批次",
+			ledger: append(threeBuys(), sell(4, 1, 4, "15", "1950", "15", entity.CostBasisLIFO)),
+			wantLots: []wantLot{
+				{1, "10", "101", "10", "1010"},
+				{2, "10", "120", "5", "600"},
+				{3, "10", "110", "0", "0"},
+			},
+			wantAssignments: []wantAssignment{
+				{4, 3, entity.CostBasisLIFO, "10", "1100", "1290", "190"},
+				{4, 2, entity.CostBasisLIFO, "5", "600", "645", "45"},
+			},
+		},
+		{
+			name:   "HIFO 先消耗单位成本最高的批次",
+			ledger: append(threeBuys(), sell(4, 1, 4, "15", "1950", "15", entity.CostBasisHIFO)),
+			wantLots: []wantLot{
+				{1, "10", "101", "10", "1010"},
+				{2, "10", "120", "0", "0"},
+				{3, "10", "110", "5", "550"},
+			},
+			wantAssignments: []wantAssignment{
+				{4, 2, entity.CostBasisHIFO, "10", "1200", "1290", "90"},
+				{4, 3, entity.CostBasisHIFO, "5", "550", "645", "95"},
+			},
+		},
+		{
+			name:   "SPECIFIC 按指定的批次顺序消耗",
+			ledger: append(threeBuys(), sell(4, 1, 4, "15", "1950", "15", entity.CostBasisSpecific, 3, 1)),
+			wantLots: []wantLot{
+				{1, "10", "101", "5", "505"},
+				{2, "10", "120", "10", "1200"},
+				{3, "10", "110", "0", "0"},
+			},
+			wantAssignments: []wantAssignment{
+				{4, 3, entity.CostBasisSpecific, "10", "1100", "1290", "190"},
+				{4, 1, entity.CostBasisSpecific, "5", "505", "645", "140"},
+			},
+		},
+		{
+			name:     "未记录方法的卖出按账户默认方法",
+			ledger:   append(threeBuys(), sell(4, 1, 4, "10", "1300", "0", "")),
+			defaults: lifo,
+			wantLots: []wantLot{
+				{1, "10", "101", "10", "1010"},
+				{2, "10", "120", "10", "1200"},
+				{3, "10", "110", "0", "0"},
+			},
+			wantAssignments: []wantAssignment{
+				{4, 3, entity.CostBasisLIFO, "10", "1100", "1300", "200"},
+			},
+		},
+		{
+			name:   "未记录方法且没有默认方法时按 FIFO",
+			ledger: append(threeBuys(), sell(4, 1, 4, "10", "1300", "0", "")),
+			wantLots: []wantLot{
+				{1, "10", "101", "0", "0"},
+				{2, "10", "120", "10", "1200"},
+				{3, "10", "110", "10", "1100"},
+			},
+			wantAssignments: []wantAssignment{
+				{4, 1, entity.CostBasisFIFO, "10", "1010", "1300", "290"},
+			},
+		},
+		{
+			// 部分消耗按比例四舍五入，批次用完时取剩余成本，不留尾差
+			name: "部分卖出的成本四舍五入，最后一次取剩余成本",
+			ledger: []*entity.Transaction{
+				trade(1, 1, entity.TransactionTypeBuy, 1, "3", "100", "0"),
+				sell(2, 1, 2, "1", "50", "0", entity.CostBasisFIFO),
+				sell(3, 1, 3, "2", "100", "0", entity.CostBasisFIFO),
+			},
+			wantLots: []wantLot{
+				{1, "3", "33.3333", "0", "0"},
+			},
+			wantAssignments: []wantAssignment{
+				{2, 1, entity.CostBasisFIFO, "1", "33.3333", "50", "16.6667"},
+				{3, 1, entity.CostBasisFIFO, "2", "66.6667", "100", "33.3333"},
+			},
+		},
+		{
+			// 拆股只调整同一账户的批次：数量翻倍，总成本不变
+			name: "拆股按比例缩放数量，成本不变",
+			ledger: []*entity.Transaction{
+				trade(1, 1, entity.TransactionTypeBuy, 1, "10", "1000", "10"),
+				trade(2, 2, entity.TransactionTypeBuy, 1, "10", "500", "0"),
+				split(3, 1, 2, "2"),
+				sell(4, 1, 3, "5", "300", "0", entity.CostBasisFIFO),
+			},
+			wantLots: []wantLot{
+				{1, "20", "50.5", "15", "757.5"},
+				{2, "10", "50", "10", "500"},
+			},
+			wantAssignments: []wantAssignment{
+				{4, 1, entity.CostBasisFIFO, "5", "252.5", "300", "47.5"},
+			},
+		},
+		{
+			name: "合股按比例缩小数量",
+			ledger: []*entity.Transaction{
+				trade(1, 1, entity.TransactionTypeBuy, 1, "10", "1000", "0"),
+				split(2, 1, 2, "0.5"),
+			},
+			wantLots: []wantLot{
+				{1, "5", "200", "5", "1000"},
+			},
+		},
+		{
+			// 转出结转 1010 + 600，不生成分配记录；转入方以结转成本 + 手续费开立新批次
+			name: "转出结转成本，转入以结转成本开立批次",
+			ledger: []*entity.Transaction{
+				trade(1, 1, entity.TransactionTypeBuy, 1, "10", "1000", "10"),
+				trade(2, 1, entity.TransactionTypeBuy, 2, "10", "1200", "0"),
+				trade(3, 1, entity.TransactionTypeTransferOut, 3, "15", "0", "0"),
+				trade(4, 2, entity.TransactionTypeTransferIn, 4, "15", "1610", "5"),
+				sell(5, 2, 5, "15", "1800", "0", entity.CostBasisFIFO),
+			},
+			wantLots: []wantLot{
+				{1, "10", "101", "0", "0"},
+				{2, "10", "120", "5", "600"},
+				{4, "15", "107.6667", "0", "0"},
+			},
+			wantAssignments: []wantAssignment{
+				{5, 4, entity.CostBasisFIFO, "15", "1615", "1800", "185"},
+			},
+			wantReliefs: map[uint]wantRelief{
+				3: {"1610", "0", "0"},
+				5: {"1615", "1800", "185"},
+			},
+		},
+		{
+			name: "转出按账户默认方法消耗批次",
+			ledger: []*entity.Transaction{
+				trade(1, 1, entity.TransactionTypeBuy, 1, "10", "1000", "0"),
+				trade(2, 1, entity.TransactionTypeBuy, 2, "10", "1200", "0"),
+				trade(3, 1, entity.TransactionTypeTransferOut, 3, "10", "0", "0"),
+			},
+			defaults: lifo,
+			wantLots: []wantLot{
+				{1, "10", "100", "10", "1000"},
+				{2, "10", "120", "0", "0"},
+			},
+			wantReliefs: map[uint]wantRelief{3: {"1200", "0", "0"}},
+		},
+		{
+			// 卖出 15 股只有 10 股批次：净收入按 10/15 分摊，其余为卖空部分
+			name: "超出批次的卖空部分不生成分配记录",
+			ledger: []*entity.Transaction{
+				trade(1, 1, entity.TransactionTypeBuy, 1, "10", "1000", "10"),
+				sell(2, 1, 2, "15", "1500", "0", entity.CostBasisFIFO),
+			},
+			wantLots: []wantLot{
+				{1, "10", "101", "0", "0"},
+			},
+			wantAssignments: []wantAssignment{
+				{2, 1, entity.CostBasisFIFO, "10", "1010", "1000", "-10"},
+			},
+			wantReliefs: map[uint]wantRelief{2: {"1010", "1000", "-10"}},
+		},
+		{
+			name: "其他账户的批次不参与卖出",
+			ledger: []*entity.Transaction{
+				trade(1, 1, entity.TransactionTypeBuy, 1, "10", "1000", "0"),
+				sell(2, 2, 2, "5", "600", "0", entity.CostBasisFIFO),
+			},
+			wantLots: []wantLot{
+				{1, "10", "100", "10", "1000"},
+			},
+			wantReliefs: map[uint]wantRelief{2: {"0", "0", "0"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lots, assignments, reliefs, err := replay(1, "AAPL", tt.ledger, tt.defaults)
+			if err != nil {
+				t.Fatalf("replay() error = %v", err)
+			}
+			assertLots(t, lots, tt.wantLots)
+			assertAssignments(t, assignments, tt.wantAssignments)
+			for id, want := range tt.wantReliefs {
+				relief, ok := reliefs[id]
+				if !ok {
+					t.Fatalf("缺少交易 %d 的结转汇总", id)
+				}
+				assertDecimal(t, "relief.cost_basis", relief.CostBasis, want.cost)
+				assertDecimal(t, "relief.proceeds", relief.Proceeds, want.proceeds)
+				assertDecimal(t, "relief.realized_gain", relief.RealizedGain, want.gain)
+			}
+		})
+	}
+}
+
+func TestReplayInvalidLotSelection(t *testing.T) {
+	tests := []struct {
+		name   string
+		ledger []*entity.Transaction
+	}{
+		{
+			name:   "批次不存在",
+			ledger: append(threeBuys(), sell(4, 1, 4, "5", "600", "0", entity.CostBasisSpecific, 9)),
+		},
+		{
+			name: "批次开仓晚于卖出",
+			ledger: []*entity.Transaction{
+				trade(1, 1, entity.TransactionTypeBuy, 1, "10", "1000", "0"),
+				sell(2, 1, 2, "5", "600", "0", entity.CostBasisSpecific, 3),
+				trade(3, 1, entity.TransactionTypeBuy, 3, "10", "1000", "0"),
+			},
+		},
+		{
+			name: "批次属于其他账户",
+			ledger: []*entity.Transaction{
+				trade(1, 2, entity.TransactionTypeBuy, 1, "10", "1000", "0"),
+				sell(2, 1, 2, "5", "600", "0", entity.CostBasisSpecific, 1),
+			},
+		},
+		{
+			name:   "指定批次的剩余数量不足",
+			ledger: append(threeBuys(), sell(4, 1, 4, "15", "1950", "0", entity.CostBasisSpecific, 1)),
+		},
+		{
+			name:   "指定批次已被之前的卖出用完",
+			ledger: append(threeBuys(), sell(4, 1, 4, "10", "1300", "0", entity.CostBasisFIFO), sell(5, 1, 5, "5", "600", "0", entity.CostBasisSpecific, 1)),
+		},
+		{
+			name: "批次ID格式错误",
+			ledger: func() []*entity.Transaction {
+				tx := sell(4, 1, 4, "5", "600", "0", entity.CostBasisSpecific)
+				tx.LotIDs = "1,x"
+				return append(threeBuys(), tx)
+			}(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, _, err := replay(1, "AAPL", tt.ledger, nil)
+			if !errors.Is(err, lotDomain.ErrInvalidLotSelection) {
+				t.Fatalf("replay() error = %v, want %v", err, lotDomain.ErrInvalidLotSelection)
+			}
+		})
+	}
+}
+
+func TestReplayerOpenLotsIsSnapshot(t *testing.T) {
+	r := newReplayer(1, "AAPL", nil)
+	ledger := []*entity.Transaction{
+		trade(1, 1, entity.TransactionTypeBuy, 1, "10", "1000", "0"),
+		trade(2, 1, entity.TransactionTypeBuy, 2, "10", "1200", "0"),
+		sell(3, 1, 3, "10", "1300", "0", entity.CostBasisFIFO),
+	}
+	for _, tx := range ledger[:2] {
+		if err := r.apply(tx); err != nil {
+			t.Fatalf("apply() error = %v", err)
+		}
+	}
+	open := r.openLots()
+
+	// 之后的回放不影响已取出的副本
+	if err := r.apply(ledger[2]); err != nil {
+		t.Fatalf("apply() error = %v", err)
+	}
+	if len(open) != 2 {
+		t.Fatalf("len(open) = %d, want 2", len(open))
+	}
+	assertDecimal(t, "remaining_quantity", open[0].RemainingQuantity, "10")
+	if after := r.openLots(); len(after) != 1 || after[0].ID != 2 {
+		t.Errorf("openLots() after sell = %v, want only lot 2", after)
+	}
+}
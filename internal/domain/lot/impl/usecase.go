@@ -0,0 +1,265 @@
+package impl
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"github.com/florentyang/smartfin-go/internal/dao"
+	accountRepo "github.com/florentyang/smartfin-go/internal/dao/account"
+	lotRepo "github.com/florentyang/smartfin-go/internal/dao/lot"
+	txRepo "github.com/florentyang/smartfin-go/internal/dao/transaction"
+	userRepo "github.com/florentyang/smartfin-go/internal/dao/user"
+	lotDomain "github.com/florentyang/smartfin-go/internal/domain/lot"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== UseCase 结构体 ====================
+
+type usecase struct {
+	lotRepo     lotRepo.Repo     // 批次 DAO
+	txRepo      txRepo.Repo      // 交易 DAO（读取交易流水）
+	userRepo    userRepo.Repo    // 用户 DAO（默认成本计算方法）
+	accountRepo accountRepo.Repo // 账户 DAO（账户的成本计算方法，优先于用户设置）
+	transactor  dao.Transactor   // 事务管理器
+}
+
+// ==================== 构造函数 ====================
+
+// NewLotDomain 创建 Domain 实例
+func NewLotDomain(lotRepo lotRepo.Repo, txRepo txRepo.Repo, userRepo userRepo.Repo, accountRepo accountRepo.Repo, transactor dao.Transactor) lotDomain.Domain {
+	return &usecase{
+		lotRepo:     lotRepo,
+		txRepo:      txRepo,
+		userRepo:    userRepo,
+		accountRepo: accountRepo,
+		transactor:  transactor,
+	}
+}
+
+// ==================== 业务方法实现 ====================
+
+// WithTx 返回绑定到指定数据库事务的 Domain
+func (u *usecase) WithTx(tx *gorm.DB) lotDomain.Domain {
+	return &usecase{
+		lotRepo:     u.lotRepo.WithTx(tx),
+		txRepo:      u.txRepo.WithTx(tx),
+		userRepo:    u.userRepo.WithTx(tx),
+		accountRepo: u.accountRepo.WithTx(tx),
+		transactor:  u.transactor,
+	}
+}
+
+// Rebuild 重建用户某只股票的批次和分配记录
+// 每次交易增删改后整体回放，保证补录历史交易后结果仍然正确；
+// 卖出交易记录了当时的成本计算方法，所以回放结果是稳定的（未记录方法的历史卖出按账户的默认方法）
+func (u *usecase) Rebuild(userID uint, symbol string) error {
+	// 1. 查询该股票的全部交易（按交易时间正序）
+	ledger, err := u.txRepo.FindLedger(&txRepo.LedgerFilter{
+		UserID: userID,
+		Symbol: symbol,
+	})
+	if err != nil {
+		return err
+	}
+	defaults, err := u.defaultMethods(userID)
+	if err != nil {
+		return err
+	}
+
+	// 2. 回放生成批次和分配记录
+	lots, assignments, _, err := replay(userID, symbol, ledger, defaults)
+	if err != nil {
+		return err
+	}
+
+	// 3. 整体替换
+	return u.lotRepo.ReplaceSymbol(userID, symbol, lots, assignments)
+}
+
+// RebuildAll 重建用户全部股票的批次
+// 在一个事务中完成，任意一只股票回放失败则整体回滚
+func (u *usecase) RebuildAll(userID uint) (int, error) {
+	var count int
+	err := u.transactor.Transaction(func(db *gorm.DB) error {
+		w := u.WithTx(db)
+
+		// 1. 从交易流水中收集股票代码
+		ledger, err := u.txRepo.WithTx(db).FindLedger(&txRepo.LedgerFilter{UserID: userID})
+		if err != nil {
+			return err
+		}
+		seen := make(map[string]bool)
+		for _, tx := range ledger {
+			// 没有股票代码的现金交易不涉及批次
+			if tx.Symbol == "" || seen[tx.Symbol] {
+				continue
+			}
+			seen[tx.Symbol] = true
+
+			// 2. 逐只股票重建
+			if err := w.Rebuild(userID, tx.Symbol); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Reliefs 回放某只股票的交易流水，返回每笔卖出、转出的结转汇总
+// 与 Rebuild 使用同一套回放逻辑，但不读写批次表
+func (u *usecase) Reliefs(userID uint, symbol string) (map[uint]*lotDomain.Relief, error) {
+	ledger, err := u.txRepo.FindLedger(&txRepo.LedgerFilter{
+		UserID: userID,
+		Symbol: symbol,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defaults, err := u.defaultMethods(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, _, reliefs, err := replay(userID, symbol, ledger, defaults)
+	if err != nil {
+		return nil, err
+	}
+	return reliefs, nil
+}
+
+// OpenLotsAt 回放截至 asOf 的交易流水，返回当时的未平仓批次
+// 批次按账户隔离，只回放指定账户的交易即可得到该账户的批次
+func (u *usecase) OpenLotsAt(userID uint, accountID *uint, asOf time.Time) ([]*entity.TaxLot, error) {
+	series, err := u.OpenLotsSeries(userID, accountID, []time.Time{asOf})
+	if err != nil {
+		return nil, err
+	}
+	return series[0], nil
+}
+
+// OpenLotsSeries 一次回放，返回每个时点的未平仓批次
+func (u *usecase) OpenLotsSeries(userID uint, accountID *uint, times []time.Time) ([][]*entity.TaxLot, error) {
+	series := make([][]*entity.TaxLot, len(times))
+	if len(times) == 0 {
+		return series, nil
+	}
+
+	// 1. 查询截至最后一个时点的全部交易
+	last := times[len(times)-1]
+	ledger, err := u.txRepo.FindLedger(&txRepo.LedgerFilter{
+		UserID:    userID,
+		AccountID: accountID,
+		EndTime:   &last,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defaults, err := u.defaultMethods(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 按股票代码分组（保持时间顺序）
+	var symbols []string
+	bySymbol := make(map[string][]*entity.Transaction)
+	for _, tx := range ledger {
+		if _, ok := bySymbol[tx.Symbol]; !ok {
+			symbols = append(symbols, tx.Symbol)
+		}
+		bySymbol[tx.Symbol] = append(bySymbol[tx.Symbol], tx)
+	}
+
+	// 3. 逐只股票回放，每到一个时点收集当时的未平仓批次
+	for _, symbol := range symbols {
+		r := newReplayer(userID, symbol, defaults)
+		txs := bySymbol[symbol]
+		i := 0
+		for k, at := range times {
+			for ; i < len(txs) && txs[i].TradeTime.Before(at); i++ {
+				if err := r.apply(txs[i]); err != nil {
+					return nil, err
+				}
+			}
+			series[k] = append(series[k], r.openLots()...)
+		}
+	}
+
+	return series, nil
+}
+
+// ListLots 查询批次
+func (u *usecase) ListLots(input *lotDomain.ListLotsInput) ([]*entity.TaxLot, error) {
+	return u.lotRepo.FindLots(&lotRepo.LotFilter{
+		UserID:    input.UserID,
+		AccountID: input.AccountID,
+		Symbol:    input.Symbol,
+		OpenOnly:  input.OpenOnly,
+	})
+}
+
+// Realized 查询已实现盈亏明细
+func (u *usecase) Realized(input *lotDomain.RealizedInput) (*lotDomain.RealizedOutput, error) {
+	// 1. 查询分配记录
+	assignments, err := u.lotRepo.FindAssignments(&lotRepo.AssignmentFilter{
+		UserID:            input.UserID,
+		AccountID:         input.AccountID,
+		Symbol:            input.Symbol,
+		SellTransactionID: input.SellTransactionID,
+		StartTime:         input.StartTime,
+		EndTime:           input.EndTime,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 汇总
+	output := &lotDomain.RealizedOutput{
+		Assignments:       assignments,
+		TotalProceeds:     decimal.Zero,
+		TotalCostBasis:    decimal.Zero,
+		TotalRealizedGain: decimal.Zero,
+	}
+	for _, a := range assignments {
+		output.TotalProceeds = output.TotalProceeds.Add(a.Proceeds)
+		output.TotalCostBasis = output.TotalCostBasis.Add(a.CostBasis)
+		output.TotalRealizedGain = output.TotalRealizedGain.Add(a.RealizedGain)
+	}
+
+	return output, nil
+}
+
+// ==================== 私有辅助函数 ====================
+
+// defaultMethods 用户各账户的默认成本计算方法（账户设置优先于用户设置，见 lotDomain.DefaultMethod）
+func (u *usecase) defaultMethods(userID uint) (methodResolver, error) {
+	user, err := u.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := u.accountRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	methods := make(map[uint]string, len(accounts))
+	for _, account := range accounts {
+		methods[account.ID] = lotDomain.DefaultMethod(user, account)
+	}
+	fallback := lotDomain.DefaultMethod(user, nil)
+	return func(accountID uint) string {
+		if method, ok := methods[accountID]; ok {
+			return method
+		}
+		return fallback
+	}, nil
+}
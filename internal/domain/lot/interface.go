@@ -0,0 +1,143 @@
+package lot
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 错误定义 ====================
+// 领域层的业务错误（中文方便调试）
+
+var (
+	ErrInvalidMethod       = errors.New("成本计算方法无效，必须是 FIFO、LIFO 或 HIFO")
+	ErrInvalidLotSelection = errors.New("指定的批次无效")
+)
+
+// ==================== Domain 输入结构体 ====================
+
+// ListLotsInput 查询批次的输入参数
+type ListLotsInput struct {
+	UserID    uint   // 用户ID（必须）
+	AccountID *uint  // 账户ID（可选，nil 表示全部账户）
+	Symbol    string // 股票代码（可选）
+	OpenOnly  bool   // 只查询未平仓批次
+}
+
+// RealizedInput 查询已实现盈亏明细的输入参数
+type RealizedInput struct {
+	UserID            uint       // 用户ID（必须）
+	AccountID         *uint      // 账户ID（可选，nil 表示全部账户）
+	Symbol            string     // 股票代码（可选）
+	SellTransactionID uint       // 卖出交易ID（可选）
+	StartTime         *time.Time // 卖出时间起（可选）
+	EndTime           *time.Time // 卖出时间止（可选，不含）
+}
+
+// ==================== Domain 输出结构体 ====================
+
+// RealizedOutput 已实现盈亏明细的输出结果
+type RealizedOutput struct {
+	Assignments       []*entity.LotAssignment // 批次分配明细
+	TotalProceeds     decimal.Decimal         // 卖出净收入合计
+	TotalCostBasis    decimal.Decimal         // 结转成本合计
+	TotalRealizedGain decimal.Decimal         // 已实现盈亏合计
+}
+
+// Relief 一笔卖出/转出从批次中结转的汇总（复式记账过账用）
+// 超出未平仓批次的部分（卖空）不计入
+type Relief struct {
+	CostBasis    decimal.Decimal // 结转成本合计
+	Proceeds     decimal.Decimal // 分摊到批次的卖出净收入（仅 SELL）
+	RealizedGain decimal.Decimal // 已实现盈亏（仅 SELL）
+}
+
+// ==================== Domain 接口定义 ====================
+// Service 层和交易 Domain 会依赖这个接口
+
+type Domain interface {
+	// WithTx 返回绑定到指定数据库事务的 Domain
+	// 交易 Domain 在写入交易的同一事务中重建批次
+	WithTx(tx *gorm.DB) Domain
+
+	// Rebuild 重建用户某只股票的批次和分配记录
+	// 核心业务逻辑：按时间顺序回放交易流水，BUY 开立批次，SELL 按成本计算方法消耗批次
+	Rebuild(userID uint, symbol string) error
+
+	// RebuildAll 重建用户全部股票的批次（用于历史数据初始化），返回重建的股票数量
+	RebuildAll(userID uint) (int, error)
+
+	// Reliefs 回放某只股票的交易流水，返回每笔卖出、转出（按交易ID）的结转汇总
+	// 不读写批次表，用于复式记账过账
+	Reliefs(userID uint, symbol string) (map[uint]*Relief, error)
+
+	// OpenLotsAt 回放截至 asOf（不含）的交易流水，返回当时的未平仓批次
+	// accountID 为 nil 时返回全部账户的批次；不读写批次表，用于按历史时点估值
+	OpenLotsAt(userID uint, accountID *uint, asOf time.Time) ([]*entity.TaxLot, error)
+
+	// OpenLotsSeries 一次回放截至最后一个时点（不含）的交易流水，返回每个时点的未平仓批次
+	// times 须按时间正序，结果与逐个调用 OpenLotsAt 相同；用于按月等多个历史时点估值
+	OpenLotsSeries(userID uint, accountID *uint, times []time.Time) ([][]*entity.TaxLot, error)
+
+	// ListLots 查询批次
+	ListLots(input *ListLotsInput) ([]*entity.TaxLot, error)
+
+	// Realized 查询已实现盈亏明细
+	Realized(input *RealizedInput) (*RealizedOutput, error)
+}
+
+// ==================== 工具函数 ====================
+
+// IsValidMethod 校验用户可选的默认成本计算方法（SPECIFIC 只能通过指定批次触发）
+func IsValidMethod(method string) bool {
+	switch method {
+	case entity.CostBasisFIFO, entity.CostBasisLIFO, entity.CostBasisHIFO:
+		return true
+	default:
+		return false
+	}
+}
+
+// DefaultMethod 卖出、转出未指定批次时使用的成本计算方法
+// 账户设置优先，其次用户设置，都未设置（或无效）时为 FIFO；account 为 nil 表示未指定账户（account_id=0）
+func DefaultMethod(user *entity.User, account *entity.Account) string {
+	if account != nil && IsValidMethod(account.CostBasisMethod) {
+		return account.CostBasisMethod
+	}
+	if user != nil && IsValidMethod(user.CostBasisMethod) {
+		return user.CostBasisMethod
+	}
+	return entity.CostBasisFIFO
+}
+
+// EncodeLotIDs 将批次ID列表编码为逗号分隔的字符串（存入 Transaction.LotIDs）
+func EncodeLotIDs(ids []uint) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.FormatUint(uint64(id), 10)
+	}
+	return strings.Join(parts, ",")
+}
+
+// DecodeLotIDs 解析 Transaction.LotIDs
+func DecodeLotIDs(s string) ([]uint, error) {
+	if s == "" {
+		return nil, nil
+	}
+	parts := strings.Split(s, ",")
+	ids := make([]uint, len(parts))
+	for i, part := range parts {
+		id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 64)
+		if err != nil {
+			return nil, ErrInvalidLotSelection
+		}
+		ids[i] = uint(id)
+	}
+	return ids, nil
+}
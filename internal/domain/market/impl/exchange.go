@@ -0,0 +1,119 @@
+package impl
+
+import (
+	"fmt"
+	"time"
+	_ "time/tzdata" // 内置时区数据库，不依赖运行环境的 /usr/share/zoneinfo
+
+	marketDomain "github.com/florentyang/smartfin-go/internal/domain/market"
+)
+
+// ==================== 交易所定义 ====================
+// 交易时段为交易所当地时间，包含开盘、收盘集合竞价（集合竞价的成交时间落在时段内）：
+// - NYSE：09:30-16:00（不含盘前盘后）
+// - HKEX：09:00-12:00、13:00-16:10（含开市前时段和收市竞价）
+// - SSE / SZSE：09:15-11:30、13:00-15:00（含开盘集合竞价）
+
+// clock 一天中的时刻（距零点的分钟数）
+type clock int
+
+func hm(hour, minute int) clock {
+	return clock(hour*60 + minute)
+}
+
+func (c clock) String() string {
+	return fmt.Sprintf("%02d:%02d", int(c)/60, int(c)%60)
+}
+
+// sessionSpec 常规交易时段
+type sessionSpec struct {
+	open  clock
+	close clock
+}
+
+// holiday 休市日或提前收市日
+type holiday struct {
+	name       string
+	earlyClose bool  // 是否提前收市（false 表示全天休市）
+	closeAt    clock // 提前收市时间
+}
+
+// exchange 一个交易所的时区、交易时段和休市日
+type exchange struct {
+	code     string
+	name     string
+	loc      *time.Location
+	sessions []sessionSpec
+	holidays map[string]*holiday // 日期（2006-01-02）→ 休市安排
+}
+
+// exchangeSpecs 支持的交易所（顺序即 Exchanges 的返回顺序）
+var exchangeSpecs = []struct {
+	code     string
+	name     string
+	timezone string
+	file     string
+	sessions []sessionSpec
+}{
+	{marketDomain.ExchangeNYSE, "纽约证券交易所", "America/New_York", "nyse.csv",
+		[]sessionSpec{{hm(9, 30), hm(16, 0)}}},
+	{marketDomain.ExchangeHKEX, "香港交易所", "Asia/Hong_Kong", "hkex.csv",
+		[]sessionSpec{{hm(9, 0), hm(12, 0)}, {hm(13, 0), hm(16, 10)}}},
+	{marketDomain.ExchangeSSE, "上海证券交易所", "Asia/Shanghai", "sse.csv",
+		[]sessionSpec{{hm(9, 15), hm(11, 30)}, {hm(13, 0), hm(15, 0)}}},
+	{marketDomain.ExchangeSZSE, "深圳证券交易所", "Asia/Shanghai", "szse.csv",
+		[]sessionSpec{{hm(9, 15), hm(11, 30)}, {hm(13, 0), hm(15, 0)}}},
+}
+
+// info 交易所信息
+func (e *exchange) info() *marketDomain.ExchangeInfo {
+	sessions := make([]string, len(e.sessions))
+	for i, s := range e.sessions {
+		sessions[i] = s.open.String() + "-" + s.close.String()
+	}
+	return &marketDomain.ExchangeInfo{
+		Code:     e.code,
+		Name:     e.name,
+		Timezone: e.loc.String(),
+		Sessions: sessions,
+	}
+}
+
+// day 某个当地日期的开市情况
+// 周末休市；休市日全天休市；提前收市日截断收市时间之后的时段
+func (e *exchange) day(year int, month time.Month, dayOfMonth int) *marketDomain.TradingDay {
+	date := time.Date(year, month, dayOfMonth, 0, 0, 0, 0, e.loc)
+	result := &marketDomain.TradingDay{Date: date}
+
+	// 1. 周末
+	if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+		return result
+	}
+
+	// 2. 休市日
+	h := e.holidays[date.Format("2006-01-02")]
+	if h != nil {
+		result.Holiday = h.name
+		if !h.earlyClose {
+			return result
+		}
+		result.EarlyClose = true
+	}
+
+	// 3. 交易时段（提前收市时截断）
+	for _, s := range e.sessions {
+		closeAt := s.close
+		if h != nil && h.closeAt < closeAt {
+			closeAt = h.closeAt
+		}
+		if closeAt <= s.open {
+			continue
+		}
+		result.Sessions = append(result.Sessions, marketDomain.Session{
+			Open:  date.Add(time.Duration(s.open) * time.Minute),
+			Close: date.Add(time.Duration(closeAt) * time.Minute),
+		})
+	}
+	result.Open = len(result.Sessions) > 0
+	return result
+}
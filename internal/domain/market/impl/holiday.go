@@ -0,0 +1,86 @@
+package impl
+
+import (
+	"embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ==================== 休市日数据 ====================
+// 每个交易所一个 CSV 文件：date,close,name
+// - date：日期（交易所当地时间，2006-01-02）
+// - close：提前收市时间（15:04），空表示全天休市
+// - name：节日名称
+// # 开头的行为注释。内置数据随程序编译，配置了数据目录时同名文件优先
+
+//go:embed holidays/*.csv
+var builtinHolidays embed.FS
+
+// loadHolidays 读取一个交易所的休市日数据
+func loadHolidays(dir, file string) (map[string]*holiday, error) {
+	// 1. 数据目录中有同名文件时优先使用
+	var r io.ReadCloser
+	if dir != "" {
+		f, err := os.Open(filepath.Join(dir, file))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		if err == nil {
+			r = f
+		}
+	}
+	if r == nil {
+		f, err := builtinHolidays.Open("holidays/" + file)
+		if err != nil {
+			return nil, err
+		}
+		r = f
+	}
+	defer r.Close()
+
+	// 2. 逐行解析
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+
+	holidays := make(map[string]*holiday)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		// 表头
+		if strings.EqualFold(record[0], "date") {
+			continue
+		}
+
+		date, err := time.Parse("2006-01-02", record[0])
+		if err != nil {
+			return nil, fmt.Errorf("%s 第 %d 行：日期格式错误 %q", file, line, record[0])
+		}
+		h := &holiday{name: strings.TrimSpace(record[2])}
+		if record[1] != "" {
+			t, err := time.Parse("15:04", record[1])
+			if err != nil {
+				return nil, fmt.Errorf("%s 第 %d 行：收市时间格式错误 %q", file, line, record[1])
+			}
+			h.earlyClose = true
+			h.closeAt = hm(t.Hour(), t.Minute())
+		}
+		holidays[date.Format("2006-01-02")] = h
+	}
+
+	return holidays, nil
+}
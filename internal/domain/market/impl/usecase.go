@@ -0,0 +1,150 @@
+package impl
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/florentyang/smartfin-go/internal/config"
+	marketDomain "github.com/florentyang/smartfin-go/internal/domain/market"
+)
+
+// maxCalendarDays 单次查询交易日历的最大天数
+const maxCalendarDays = 366
+
+// maxLookbackDays 查找上一个交易日时最多往前找的天数（最长的长假也不超过两周）
+const maxLookbackDays = 30
+
+// ==================== UseCase 结构体 ====================
+
+type usecase struct {
+	exchanges map[string]*exchange // 交易所代码 → 定义
+	order     []string             // 交易所顺序
+}
+
+// ==================== 构造函数 ====================
+
+// NewMarketDomain 创建 Domain 实例
+// 启动时加载各交易所的时区和休市日数据，数据文件格式错误时返回错误
+func NewMarketDomain(cfg *config.MarketConfig) (marketDomain.Domain, error) {
+	u := &usecase{exchanges: make(map[string]*exchange)}
+	for _, spec := range exchangeSpecs {
+		loc, err := time.LoadLocation(spec.timezone)
+		if err != nil {
+			return nil, err
+		}
+		holidays, err := loadHolidays(cfg.HolidayDir, spec.file)
+		if err != nil {
+			return nil, fmt.Errorf("加载 %s 休市日失败: %w", spec.code, err)
+		}
+		u.exchanges[spec.code] = &exchange{
+			code:     spec.code,
+			name:     spec.name,
+			loc:      loc,
+			sessions: spec.sessions,
+			holidays: holidays,
+		}
+		u.order = append(u.order, spec.code)
+	}
+	return u, nil
+}
+
+// ==================== 业务方法实现 ====================
+
+// Exchanges 支持的交易所列表
+func (u *usecase) Exchanges() []*marketDomain.ExchangeInfo {
+	list := make([]*marketDomain.ExchangeInfo, len(u.order))
+	for i, code := range u.order {
+		list[i] = u.exchanges[code].info()
+	}
+	return list
+}
+
+// Calendar 查询交易所在日期范围内每天的开市情况
+func (u *usecase) Calendar(input *marketDomain.CalendarInput) (*marketDomain.CalendarOutput, error) {
+	// 1. 查找交易所
+	e, err := u.exchange(input.Exchange)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 校验日期范围（按年月日，不受时区影响）
+	start := dateOf(input.StartDate)
+	end := dateOf(input.EndDate)
+	if end.Before(start) || end.Sub(start) >= maxCalendarDays*24*time.Hour {
+		return nil, marketDomain.ErrInvalidRange
+	}
+
+	// 3. 逐日生成开市情况
+	output := &marketDomain.CalendarOutput{Exchange: e.info()}
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		output.Days = append(output.Days, e.day(d.Year(), d.Month(), d.Day()))
+	}
+
+	// 4. 开始日期之前的最后一个交易日
+	output.PreviousTradingDay = e.previousTradingDay(start)
+	return output, nil
+}
+
+// TradingDay 某个日期（按年月日）的开市情况
+func (u *usecase) TradingDay(code string, date time.Time) (*marketDomain.TradingDay, error) {
+	e, err := u.exchange(code)
+	if err != nil {
+		return nil, err
+	}
+	return e.day(date.Year(), date.Month(), date.Day()), nil
+}
+
+// IsOpen 某个时间点是否在交易时段内
+// 先换算到交易所当地时间，再看当地日期的交易时段（时段首尾均包含）
+func (u *usecase) IsOpen(code string, t time.Time) (bool, error) {
+	e, err := u.exchange(code)
+	if err != nil {
+		return false, err
+	}
+
+	local := t.In(e.loc)
+	day := e.day(local.Year(), local.Month(), local.Day())
+	for _, s := range day.Sessions {
+		if !local.Before(s.Open) && !local.After(s.Close) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// PreviousTradingDay 某个日期（按年月日）之前的最后一个交易日
+func (u *usecase) PreviousTradingDay(code string, date time.Time) (time.Time, error) {
+	e, err := u.exchange(code)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return e.previousTradingDay(dateOf(date)), nil
+}
+
+// ==================== 私有辅助函数 ====================
+
+// exchange 按代码查找交易所
+func (u *usecase) exchange(code string) (*exchange, error) {
+	e, ok := u.exchanges[code]
+	if !ok {
+		return nil, marketDomain.ErrUnknownExchange
+	}
+	return e, nil
+}
+
+// previousTradingDay 往前逐日查找第一个开市日（不含当天）
+// 超出查找范围时返回零值（休市日数据异常时不会死循环）
+func (e *exchange) previousTradingDay(date time.Time) time.Time {
+	for i := 1; i <= maxLookbackDays; i++ {
+		d := date.AddDate(0, 0, -i)
+		if day := e.day(d.Year(), d.Month(), d.Day()); day.Open {
+			return day.Date
+		}
+	}
+	return time.Time{}
+}
+
+// dateOf 取日期的年月日（UTC 零点），用于按日期遍历
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,188 @@
+package market
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// ==================== 错误定义 ====================
+// 领域层的业务错误（中文方便调试）
+
+var (
+	ErrUnknownExchange = errors.New("不支持的交易所，可选 NYSE、HKEX、SSE、SZSE")
+	ErrInvalidRange    = errors.New("日期范围无效：结束日期不能早于开始日期，且最多查询 366 天")
+
+	// ErrMarketClosed 交易时间不在交易所的交易时段内（休市日、周末或非交易时段）
+	ErrMarketClosed = errors.New("交易时间不在交易所的交易时段内")
+)
+
+// ==================== 交易所 ====================
+
+// 交易所代码
+const (
+	ExchangeNYSE = "NYSE" // 纽约证券交易所（美股统一使用，纳斯达克的休市安排和交易时段相同）
+	ExchangeHKEX = "HKEX" // 香港交易所
+	ExchangeSSE  = "SSE"  // 上海证券交易所
+	ExchangeSZSE = "SZSE" // 深圳证券交易所
+	ExchangeBSE  = "BSE"  // 北京证券交易所（暂无交易日历）
+)
+
+// ==================== Domain 输入结构体 ====================
+
+// CalendarInput 查询交易日历的输入参数
+type CalendarInput struct {
+	Exchange  string    // 交易所代码（必须）
+	StartDate time.Time // 开始日期（含，只取年月日）
+	EndDate   time.Time // 结束日期（含，只取年月日）
+}
+
+// ==================== Domain 输出结构体 ====================
+
+// ExchangeInfo 交易所信息
+type ExchangeInfo struct {
+	Code     string   // 交易所代码
+	Name     string   // 名称
+	Timezone string   // IANA 时区，如 America/New_York
+	Sessions []string // 常规交易时段（当地时间），如 09:30-11:30
+}
+
+// Session 一个交易时段
+type Session struct {
+	Open  time.Time // 开始时间（交易所当地时区）
+	Close time.Time // 结束时间（交易所当地时区，含）
+}
+
+// TradingDay 某个日期的开市情况
+type TradingDay struct {
+	Date       time.Time // 日期（交易所当地时区零点）
+	Open       bool      // 是否开市
+	Holiday    string    // 休市或提前收市的节日名称
+	EarlyClose bool      // 是否提前收市（如美股感恩节次日、港股半日市）
+	Sessions   []Session // 当日交易时段（休市时为空）
+}
+
+// CalendarOutput 交易日历
+type CalendarOutput struct {
+	Exchange           *ExchangeInfo
+	Days               []*TradingDay // 按日期排序，含周末和休市日
+	PreviousTradingDay time.Time     // 开始日期之前的最后一个交易日
+}
+
+// ==================== Domain 接口定义 ====================
+// Service 层和交易 Domain 会依赖这个接口
+
+type Domain interface {
+	// Exchanges 支持的交易所列表
+	Exchanges() []*ExchangeInfo
+
+	// Calendar 查询交易所在日期范围内每天的开市情况
+	Calendar(input *CalendarInput) (*CalendarOutput, error)
+
+	// TradingDay 某个日期（按交易所当地日期）的开市情况
+	TradingDay(exchange string, date time.Time) (*TradingDay, error)
+
+	// IsOpen 某个时间点是否在交易时段内（按交易所当地时间判断）
+	IsOpen(exchange string, t time.Time) (bool, error)
+
+	// PreviousTradingDay 某个日期之前的最后一个交易日（不含当天），用于取昨收价估值
+	PreviousTradingDay(exchange string, date time.Time) (time.Time, error)
+}
+
+// ==================== 工具函数 ====================
+
+// Symbol 从股票代码识别出的交易所
+type Symbol struct {
+	Code     string // 交易所内的证券代码，如 600519、0700、AAPL
+	Exchange string // 交易所代码
+}
+
+// ParseSymbol 按股票代码的写法识别所在交易所
+// - 带交易所后缀：600519.SH（或 .SS）、000001.SZ、430047.BJ、0700.HK、AAPL.US
+// - 带交易所前缀：SH600519、SZ000001、BJ430047
+// - 不带交易所标识：按交易币种推断（人民币 6 位数字代码按首位区分沪深北，港币数字代码为港股，美元字母代码为美股）
+// 无法识别时返回 false
+func ParseSymbol(symbol, currency string) (*Symbol, bool) {
+	s := strings.ToUpper(strings.TrimSpace(symbol))
+
+	// 1. 交易所后缀（不认识的后缀视为代码本身的一部分，如 BRK.B）
+	if i := strings.LastIndex(s, "."); i >= 0 {
+		code := s[:i]
+		switch s[i+1:] {
+		case "SH", "SS":
+			return aShare(code, ExchangeSSE)
+		case "SZ":
+			return aShare(code, ExchangeSZSE)
+		case "BJ":
+			return aShare(code, ExchangeBSE)
+		case "HK":
+			return hkShare(code)
+		case "US":
+			return usShare(code)
+		}
+	}
+
+	// 2. 交易所前缀
+	if len(s) == 8 {
+		switch s[:2] {
+		case "SH":
+			return aShare(s[2:], ExchangeSSE)
+		case "SZ":
+			return aShare(s[2:], ExchangeSZSE)
+		case "BJ":
+			return aShare(s[2:], ExchangeBSE)
+		}
+	}
+
+	// 3. 没有交易所标识：按币种推断
+	switch currency {
+	case "CNY":
+		if len(s) != 6 {
+			return nil, false
+		}
+		switch s[0] {
+		case '6':
+			return aShare(s, ExchangeSSE)
+		case '0', '3':
+			return aShare(s, ExchangeSZSE)
+		case '4', '8', '9':
+			return aShare(s, ExchangeBSE)
+		}
+	case "HKD":
+		return hkShare(s)
+	case "USD":
+		return usShare(s)
+	}
+	return nil, false
+}
+
+// aShare A 股代码必须是 6 位数字
+func aShare(code, exchange string) (*Symbol, bool) {
+	if len(code) != 6 || !isDigits(code) {
+		return nil, false
+	}
+	return &Symbol{Code: code, Exchange: exchange}, true
+}
+
+// hkShare 港股代码为 1~5 位数字
+func hkShare(code string) (*Symbol, bool) {
+	if len(code) == 0 || len(code) > 5 || !isDigits(code) {
+		return nil, false
+	}
+	return &Symbol{Code: code, Exchange: ExchangeHKEX}, true
+}
+
+// usShare 美股代码以字母开头，可含 . 和 -（如 BRK.B、BF-B）
+func usShare(code string) (*Symbol, bool) {
+	if len(code) == 0 || code[0] < 'A' || code[0] > 'Z' {
+		return nil, false
+	}
+	if strings.Trim(code, "ABCDEFGHIJKLMNOPQRSTUVWXYZ.-") != "" {
+		return nil, false
+	}
+	return &Symbol{Code: code, Exchange: ExchangeNYSE}, true
+}
+
+func isDigits(s string) bool {
+	return strings.Trim(s, "0123456789") == ""
+}
@@ -0,0 +1,95 @@
+package impl
+
+import (
+	"math"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ==================== 收益率计算 ====================
+
+// daysPerYear 年化使用的天数（与 XIRR 的惯例一致，按自然日计）
+const daysPerYear = 365
+
+// cashFlow 一笔带日期的现金流（投资者视角：投入为负，取回为正）
+type cashFlow struct {
+	date   time.Time
+	amount decimal.Decimal
+}
+
+// daysBetween 两个日期相差的自然日数（按四舍五入的小时数计算，不受夏令时影响）
+func daysBetween(from, to time.Time) int {
+	return int(math.Round(to.Sub(from).Hours() / 24))
+}
+
+// annualize 年化收益率 = 增长倍数 ^ (365 / 天数) - 1
+// 区间不足一年时不年化（短期收益年化后失真）；增长倍数不为正时无意义
+func annualize(growth decimal.Decimal, days int) (decimal.Decimal, bool) {
+	if days < daysPerYear || !growth.IsPositive() {
+		return decimal.Zero, false
+	}
+	exponent := decimal.NewFromInt(daysPerYear).Div(decimal.NewFromInt(int64(days)))
+	value, err := growth.PowWithPrecision(exponent, 16)
+	if err != nil {
+		return decimal.Zero, false
+	}
+	return value.Sub(decimal.NewFromInt(1)).Round(6), true
+}
+
+// xirr 求解年化内部收益率 r：Σ amount_i / (1 + r) ^ (t_i / 365) = 0，t_i 为距第一笔现金流的天数
+// 分数次幂需要反复迭代求值，decimal 的 Pow 代价过高，这里用 float64 二分求根（结果保留 6 位小数）；
+// 现金流全为同一方向时无解
+func xirr(flows []cashFlow) (decimal.Decimal, bool) {
+	// 1. 必须同时有投入和取回
+	var positive, negative bool
+	years := make([]float64, len(flows))
+	amounts := make([]float64, len(flows))
+	for i, f := range flows {
+		positive = positive || f.amount.IsPositive()
+		negative = negative || f.amount.IsNegative()
+		years[i] = float64(daysBetween(flows[0].date, f.date)) / daysPerYear
+		amounts[i] = f.amount.InexactFloat64()
+	}
+	if !positive || !negative {
+		return decimal.Zero, false
+	}
+	npv := func(rate float64) float64 {
+		sum := 0.0
+		for i := range amounts {
+			sum += amounts[i] / math.Pow(1+rate, years[i])
+		}
+		return sum
+	}
+
+	// 2. 确定区间：下限接近 -100%，上限不断翻倍直到净现值变号
+	lo, hi := -0.9999, 1.0
+	fLo, fHi := npv(lo), npv(hi)
+	for i := 0; fLo*fHi > 0 && i < 64; i++ {
+		hi *= 2
+		fHi = npv(hi)
+	}
+	if math.IsNaN(fLo) || math.IsNaN(fHi) || fLo*fHi > 0 {
+		return decimal.Zero, false
+	}
+
+	// 3. 二分求根
+	for i := 0; i < 200 && hi-lo > 1e-10; i++ {
+		mid := (lo + hi) / 2
+		fMid := npv(mid)
+		if fMid == 0 {
+			lo, hi = mid, mid
+			break
+		}
+		if (fMid > 0) == (fLo > 0) {
+			lo, fLo = mid, fMid
+		} else {
+			hi = mid
+		}
+	}
+	rate := (lo + hi) / 2
+	if math.IsNaN(rate) || math.IsInf(rate, 0) {
+		return decimal.Zero, false
+	}
+	return decimal.NewFromFloat(rate).Round(6), true
+}
@@ -0,0 +1,160 @@
+package impl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// rateTolerance 二分求根的结果与解析解之间允许的误差（结果保留 6 位小数）
+var rateTolerance = decimal.RequireFromString("0.000001")
+
+// date 构造本地时区的日期（与估值序列一致）
+func date(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.Local)
+}
+
+// flow 构造一笔现金流：距 2022-01-01 的天数 + 金额
+func flow(days int, amount string) cashFlow {
+	return cashFlow{date: date(2022, time.January, 1).AddDate(0, 0, days), amount: decimal.RequireFromString(amount)}
+}
+
+// assertRate 比较收益率，允许 rateTolerance 的误差
+func assertRate(t *testing.T, field string, got decimal.Decimal, want string) {
+	t.Helper()
+	if got.Sub(decimal.RequireFromString(want)).Abs().GreaterThan(rateTolerance) {
+		t.Errorf("%s = %s, want %s", field, got, want)
+	}
+}
+
+func TestXIRR(t *testing.T) {
+	tests := []struct {
+		name   string
+		flows  []cashFlow
+		want   string
+		wantOK bool
+	}{
+		{
+			name:   "两笔现金流间隔一年：1000 → 1100",
+			flows:  []cashFlow{flow(0, "-1000"), flow(365, "1100")},
+			want:   "0.1",
+			wantOK: true,
+		},
+		{
+			name:   "两笔现金流间隔两年：1000 → 1210",
+			flows:  []cashFlow{flow(0, "-1000"), flow(730, "1210")},
+			want:   "0.1",
+			wantOK: true,
+		},
+		{
+			// 解析解 (1050 / 1000) ^ (365 / 182) - 1
+			name:   "两笔现金流不足一年按年化",
+			flows:  []cashFlow{flow(0, "-1000"), flow(182, "1050")},
+			want:   "0.102796",
+			wantOK: true,
+		},
+		{
+			name:   "亏损为负收益率",
+			flows:  []cashFlow{flow(0, "-1000"), flow(365, "800")},
+			want:   "-0.2",
+			wantOK: true,
+		},
+		{
+			// 1000 × 1.1² + 1000 × 1.1 = 2310
+			name:   "多笔投入",
+			flows:  []cashFlow{flow(0, "-1000"), flow(365, "-1000"), flow(730, "2310")},
+			want:   "0.1",
+			wantOK: true,
+		},
+		{
+			// 1000 × 1.1² - 550 × 1.1 = 605
+			name:   "中途取回",
+			flows:  []cashFlow{flow(0, "-1000"), flow(365, "550"), flow(730, "605")},
+			want:   "0.1",
+			wantOK: true,
+		},
+		{
+			name:  "全部为投入",
+			flows: []cashFlow{flow(0, "-1000"), flow(365, "-500")},
+		},
+		{
+			name:  "全部为取回",
+			flows: []cashFlow{flow(0, "1000"), flow(365, "500")},
+		},
+		{
+			name:  "只有投入和零",
+			flows: []cashFlow{flow(0, "-1000"), flow(365, "0")},
+		},
+		{
+			// 净现值与利率无关，二分区间内不变号
+			name:  "现金流都在同一天",
+			flows: []cashFlow{flow(0, "-1000"), flow(0, "1100")},
+		},
+		{
+			// 收益率 -99.999%，低于求根区间下限 -99.99%
+			name:  "几乎全部亏损时不收敛",
+			flows: []cashFlow{flow(0, "-1000"), flow(365, "0.01")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := xirr(tt.flows)
+			if ok != tt.wantOK {
+				t.Fatalf("xirr() ok = %v, want %v (rate %s)", ok, tt.wantOK, got)
+			}
+			if ok {
+				assertRate(t, "xirr", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnnualize(t *testing.T) {
+	tests := []struct {
+		name   string
+		growth string
+		days   int
+		want   string
+		wantOK bool
+	}{
+		{name: "一年", growth: "1.1", days: 365, want: "0.1", wantOK: true},
+		{name: "两年", growth: "1.21", days: 730, want: "0.1", wantOK: true},
+		{name: "两年亏损", growth: "0.81", days: 730, want: "-0.1", wantOK: true},
+		{name: "不足一年不年化", growth: "1.1", days: 364},
+		{name: "增长倍数为零", growth: "0", days: 730},
+		{name: "增长倍数为负", growth: "-0.5", days: 730},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := annualize(decimal.RequireFromString(tt.growth), tt.days)
+			if ok != tt.wantOK {
+				t.Fatalf("annualize() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok {
+				assertRate(t, "annualized", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDaysBetweenAcrossDST(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("缺少时区数据: %v", err)
+	}
+	// 2024-03-10 开始夏令时，两个午夜之间只有 47 小时
+	from := time.Date(2024, time.March, 9, 0, 0, 0, 0, newYork)
+	to := time.Date(2024, time.March, 11, 0, 0, 0, 0, newYork)
+	if got := daysBetween(from, to); got != 2 {
+		t.Errorf("daysBetween() = %d, want 2", got)
+	}
+	// 2024-11-03 结束夏令时，两个午夜之间有 49 小时
+	from = time.Date(2024, time.November, 2, 0, 0, 0, 0, newYork)
+	to = time.Date(2024, time.November, 4, 0, 0, 0, 0, newYork)
+	if got := daysBetween(from, to); got != 2 {
+		t.Errorf("daysBetween() = %d, want 2", got)
+	}
+}
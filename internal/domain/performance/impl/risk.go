@@ -0,0 +1,286 @@
+package impl
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	fxDomain "github.com/florentyang/smartfin-go/internal/domain/fx"
+	performanceDomain "github.com/florentyang/smartfin-go/internal/domain/performance"
+	portfolioDomain "github.com/florentyang/smartfin-go/internal/domain/portfolio"
+	priceDomain "github.com/florentyang/smartfin-go/internal/domain/price"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 风险指标 ====================
+
+// benchmarkLookback 基准行情多取的天数：区间第一天之前最近的收盘价可能在长假之前
+const benchmarkLookback = 14
+
+// dailyReturn 某天的收益率（净值增长倍数 = 1 + 收益率）
+type dailyReturn struct {
+	date   time.Time
+	factor decimal.Decimal
+}
+
+// Risk 计算风险指标
+// 日收益率与 TWR 的逐日链接相同（剔除资金进出）；按自然日计算，周末、节假日市值不变时收益率为 0，
+// 年化统一乘以 365（与按交易日乘以 252 的结果相近）
+func (u *usecase) Risk(input *performanceDomain.RiskInput) (*performanceDomain.RiskOutput, error) {
+	// 1. 校验无风险利率和股票代码
+	riskFreeRate := u.cfg.RiskFreeRate
+	if input.RiskFreeRate != nil {
+		riskFreeRate = *input.RiskFreeRate
+	}
+	if riskFreeRate.LessThanOrEqual(decimal.NewFromInt(-1)) || riskFreeRate.GreaterThan(decimal.NewFromInt(1)) {
+		return nil, performanceDomain.ErrInvalidRiskFreeRate
+	}
+	symbol, err := normalizeSymbol(input.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 每日估值：多取开始日期的前一天，作为第一天收益率的基数
+	var from *time.Time
+	if input.StartDate != nil {
+		base := fxDomain.DateOf(*input.StartDate).AddDate(0, 0, -1)
+		from = &base
+	}
+	history, err := u.portfolioDomain.History(&portfolioDomain.HistoryInput{
+		UserID:          input.UserID,
+		AccountID:       input.AccountID,
+		StartDate:       from,
+		EndDate:         input.EndDate,
+		IncludeHoldings: symbol != "",
+	})
+	if err != nil {
+		return nil, err
+	}
+	series, err := u.series(input.UserID, input.AccountID, symbol, history)
+	if err != nil {
+		return nil, err
+	}
+	if len(series) == 0 {
+		return nil, performanceDomain.ErrNoHistory
+	}
+
+	// 3. 日收益率
+	returns, baseDate := dailyReturns(series, input.StartDate)
+	if len(returns) == 0 {
+		return nil, performanceDomain.ErrNoHistory
+	}
+	output := &performanceDomain.RiskOutput{
+		BaseCurrency: history.BaseCurrency,
+		Symbol:       symbol,
+		StartDate:    returns[0].date,
+		EndDate:      returns[len(returns)-1].date,
+		Days:         len(returns),
+		RiskFreeRate: riskFreeRate,
+	}
+
+	// 4. 净值、区间收益率和最大回撤（decimal）
+	navs := drawdown(output, returns, baseDate)
+	output.TotalReturn = navs[len(navs)-1].Sub(decimal.NewFromInt(1)).Round(6)
+
+	// 5. 波动率、夏普、索提诺（float64）
+	values := make([]float64, len(returns))
+	for i, r := range returns {
+		values[i] = r.factor.Sub(decimal.NewFromInt(1)).InexactFloat64()
+	}
+	riskStats(output, values, riskFreeRate.InexactFloat64())
+
+	// 6. 相对基准的 Beta 和相关系数
+	if input.Benchmark != "" {
+		if err := u.benchmarkStats(output, input.Benchmark, returns, baseDate); err != nil {
+			return nil, err
+		}
+	}
+	return output, nil
+}
+
+// ==================== 私有辅助函数 ====================
+
+// dailyReturns 每日市值 → 日收益率
+// 开始日期之前的一天只作为基数；还没有资金（前一天市值 + 当天流入不为正）的天数跳过，净值起点随之后移
+// 返回日收益率和净值起点的日期
+func dailyReturns(series []*day, startDate *time.Time) ([]*dailyReturn, time.Time) {
+	first := 0
+	prev := decimal.Zero
+	baseDate := series[0].date.AddDate(0, 0, -1)
+	if startDate != nil && series[0].date.Before(fxDomain.DateOf(*startDate)) {
+		first = 1
+		prev = series[0].value
+		baseDate = series[0].date
+	}
+
+	var returns []*dailyReturn
+	for _, d := range series[first:] {
+		if denominator := prev.Add(d.in); denominator.IsPositive() {
+			returns = append(returns, &dailyReturn{
+				date:   d.date,
+				factor: d.value.Add(d.out).Div(denominator),
+			})
+		} else if len(returns) == 0 {
+			baseDate = d.date
+		}
+		prev = d.value
+	}
+	return returns, baseDate
+}
+
+// drawdown 逐日链接净值（起点为 1），计算最大回撤及其峰值、谷底和恢复日期
+// 返回每天的净值
+func drawdown(output *performanceDomain.RiskOutput, returns []*dailyReturn, baseDate time.Time) []decimal.Decimal {
+	navs := make([]decimal.Decimal, len(returns))
+	nav := decimal.NewFromInt(1)
+	peak, peakDate := nav, baseDate
+	maxDrawdown, peakNav, trough := decimal.Zero, nav, -1
+	for i, r := range returns {
+		nav = nav.Mul(r.factor).Round(growthPrecision)
+		navs[i] = nav
+		if nav.GreaterThanOrEqual(peak) {
+			peak, peakDate = nav, r.date
+			continue
+		}
+		if dd := nav.Div(peak).Sub(decimal.NewFromInt(1)); dd.LessThan(maxDrawdown) {
+			maxDrawdown, peakNav, trough = dd, peak, i
+			peakAt := peakDate
+			output.PeakDate = &peakAt
+		}
+	}
+	output.MaxDrawdown = maxDrawdown.Round(6)
+	if trough < 0 {
+		return navs
+	}
+
+	// 谷底之后第一次回到峰值的日期
+	output.TroughDate = &returns[trough].date
+	for i := trough + 1; i < len(navs); i++ {
+		if navs[i].GreaterThanOrEqual(peakNav) {
+			output.RecoveryDate = &returns[i].date
+			break
+		}
+	}
+	return navs
+}
+
+// riskStats 波动率、夏普比率、索提诺比率
+// 需要开方，用 float64 计算，结果保留 6 位小数；少于 2 天收益率时不计算
+func riskStats(output *performanceDomain.RiskOutput, values []float64, riskFreeRate float64) {
+	if len(values) < 2 {
+		return
+	}
+	scale := math.Sqrt(daysPerYear)
+	dailyRiskFree := math.Pow(1+riskFreeRate, 1.0/daysPerYear) - 1
+
+	mean, stdev := meanStdev(values)
+	excess := mean - dailyRiskFree
+	output.Volatility = ratio(stdev * scale)
+	if stdev > 0 {
+		output.Sharpe = ratio(excess / stdev * scale)
+	}
+
+	// 下行标准差：只计低于无风险收益的部分，分母为全部天数
+	downside := 0.0
+	for _, v := range values {
+		if d := v - dailyRiskFree; d < 0 {
+			downside += d * d
+		}
+	}
+	if downside > 0 {
+		output.Sortino = ratio(excess / math.Sqrt(downside/float64(len(values))) * scale)
+	}
+}
+
+// benchmarkStats 基准的区间涨跌幅、Beta 和相关系数
+// 基准收盘价按自然日向前填充（与估值快照取最近收盘价一致），与组合的日收益率逐日对齐
+func (u *usecase) benchmarkStats(output *performanceDomain.RiskOutput, benchmark string, returns []*dailyReturn, baseDate time.Time) error {
+	// 1. 基准日线行情
+	start := baseDate.AddDate(0, 0, -benchmarkLookback)
+	end := output.EndDate.AddDate(0, 0, 1)
+	bars, err := u.priceDomain.List(&priceDomain.ListInput{
+		Symbol:    benchmark,
+		StartDate: &start,
+		EndDate:   &end,
+	})
+	if err != nil {
+		return err
+	}
+	output.Benchmark = bars.Instrument.Symbol
+	baseClose, okBase := closeOn(bars.Bars, baseDate)
+	endClose, okEnd := closeOn(bars.Bars, output.EndDate)
+	if !okEnd {
+		return performanceDomain.ErrNoBenchmarkPrices
+	}
+	if okBase {
+		benchmarkReturn := endClose.Div(baseClose).Sub(decimal.NewFromInt(1)).Round(6)
+		output.BenchmarkReturn = &benchmarkReturn
+	}
+
+	// 2. 逐日对齐：组合第 d 天的收益率对应基准从 d-1 到 d 的涨跌幅
+	var portfolio, market []float64
+	for _, r := range returns {
+		prev, okPrev := closeOn(bars.Bars, r.date.AddDate(0, 0, -1))
+		current, okCurrent := closeOn(bars.Bars, r.date)
+		if !okPrev || !okCurrent {
+			continue
+		}
+		portfolio = append(portfolio, r.factor.Sub(decimal.NewFromInt(1)).InexactFloat64())
+		market = append(market, current.Div(prev).Sub(decimal.NewFromInt(1)).InexactFloat64())
+	}
+	if len(portfolio) < 2 {
+		return nil
+	}
+
+	// 3. Beta = Cov / Var(基准)，相关系数 = Cov / (σ组合 × σ基准)
+	meanP, stdevP := meanStdev(portfolio)
+	meanM, stdevM := meanStdev(market)
+	covariance := 0.0
+	for i := range portfolio {
+		covariance += (portfolio[i] - meanP) * (market[i] - meanM)
+	}
+	covariance /= float64(len(portfolio) - 1)
+	if stdevM > 0 {
+		output.Beta = ratio(covariance / (stdevM * stdevM))
+		if stdevP > 0 {
+			output.Correlation = ratio(covariance / (stdevP * stdevM))
+		}
+	}
+	return nil
+}
+
+// closeOn 某个自然日的收盘价：当天或之前最近一个交易日的收盘价（bars 按日期正序）
+func closeOn(bars []*entity.PriceBar, date time.Time) (decimal.Decimal, bool) {
+	i := sort.Search(len(bars), func(i int) bool {
+		return fxDomain.DateOf(bars[i].BarDate).After(date)
+	})
+	if i == 0 {
+		return decimal.Zero, false
+	}
+	return bars[i-1].Close, true
+}
+
+// meanStdev 均值和样本标准差（分母 n-1）
+func meanStdev(values []float64) (float64, float64) {
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(variance / float64(len(values)-1))
+}
+
+// ratio float64 → 保留 6 位小数的 decimal，非有限值返回 nil
+func ratio(value float64) *decimal.Decimal {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return nil
+	}
+	d := decimal.NewFromFloat(value).Round(6)
+	return &d
+}
@@ -0,0 +1,269 @@
+package impl
+
+import (
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/florentyang/smartfin-go/internal/config"
+	txRepo "github.com/florentyang/smartfin-go/internal/dao/transaction"
+	fxDomain "github.com/florentyang/smartfin-go/internal/domain/fx"
+	instrumentDomain "github.com/florentyang/smartfin-go/internal/domain/instrument"
+	performanceDomain "github.com/florentyang/smartfin-go/internal/domain/performance"
+	portfolioDomain "github.com/florentyang/smartfin-go/internal/domain/portfolio"
+	priceDomain "github.com/florentyang/smartfin-go/internal/domain/price"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== UseCase 结构体 ====================
+
+type usecase struct {
+	portfolioDomain portfolioDomain.Domain // 持仓 Domain（每日估值快照）
+	txRepo          txRepo.Repo            // 交易 DAO（单只股票的买卖、分红现金流）
+	fxDomain        fxDomain.Domain        // 汇率 Domain（现金流换算为基准货币）
+	priceDomain     priceDomain.Domain     // 历史行情 Domain（基准的日线行情）
+	cfg             *config.PerformanceConfig
+}
+
+// ==================== 构造函数 ====================
+
+// NewPerformanceDomain 创建 Domain 实例
+func NewPerformanceDomain(portfolioDomain portfolioDomain.Domain, txRepo txRepo.Repo, fxDomain fxDomain.Domain, priceDomain priceDomain.Domain, cfg *config.PerformanceConfig) performanceDomain.Domain {
+	return &usecase{
+		portfolioDomain: portfolioDomain,
+		txRepo:          txRepo,
+		fxDomain:        fxDomain,
+		priceDomain:     priceDomain,
+		cfg:             cfg,
+	}
+}
+
+// ==================== 业务方法实现 ====================
+
+// Returns 计算各区间的时间加权收益率和资金加权收益率
+// 整个组合（或单个账户）：每日市值 = 持仓市值 + 现金，外部现金流 = 入金、出金；
+// 单只股票：每日市值 = 该股票的持仓市值，外部现金流 = 买入（流入）、卖出和分红（流出）
+// 证券转入转出不是现金流，转入的市值计入收益
+func (u *usecase) Returns(input *performanceDomain.ReturnsInput) (*performanceDomain.ReturnsOutput, error) {
+	// 1. 校验区间和股票代码
+	windows := input.Windows
+	if len(windows) == 0 {
+		windows = performanceDomain.DefaultWindows
+	}
+	for _, w := range windows {
+		if !performanceDomain.IsValidWindow(w) {
+			return nil, performanceDomain.ErrInvalidWindow
+		}
+	}
+	symbol, err := normalizeSymbol(input.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 每日估值（已生成的快照）
+	history, err := u.portfolioDomain.History(&portfolioDomain.HistoryInput{
+		UserID:          input.UserID,
+		AccountID:       input.AccountID,
+		IncludeHoldings: symbol != "",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 组装每日市值和外部现金流
+	series, err := u.series(input.UserID, input.AccountID, symbol, history)
+	if err != nil {
+		return nil, err
+	}
+	if len(series) == 0 {
+		return nil, performanceDomain.ErrNoHistory
+	}
+
+	// 4. 逐个区间计算
+	asOf := series[len(series)-1].date
+	output := &performanceDomain.ReturnsOutput{
+		BaseCurrency: history.BaseCurrency,
+		Symbol:       symbol,
+		AsOf:         asOf,
+		Windows:      make([]*performanceDomain.WindowReturn, 0, len(windows)),
+	}
+	for _, w := range windows {
+		output.Windows = append(output.Windows, windowReturn(w, series, windowBase(w, asOf)))
+	}
+	return output, nil
+}
+
+// ==================== 私有辅助函数 ====================
+
+// growthPrecision 逐日链接时净值保留的小数位数（不截断时位数随天数累积，乘法越来越慢）
+const growthPrecision = 16
+
+// day 某天收盘后的市值和当天的外部现金流（基准货币）
+// 流入视为当天开始时发生（参与当天收益），流出视为当天结束时发生，因此建仓、清仓当天的收益率都有意义
+type day struct {
+	date  time.Time
+	value decimal.Decimal // 收盘后市值
+	in    decimal.Decimal // 外部流入
+	out   decimal.Decimal // 外部流出（正数）
+}
+
+// normalizeSymbol 规范化股票代码，为空时表示整个组合
+func normalizeSymbol(symbol string) (string, error) {
+	if symbol == "" {
+		return "", nil
+	}
+	return instrumentDomain.NormalizeSymbol(symbol, "")
+}
+
+// series 组装每日市值和外部现金流：股票代码为空时为整个组合，否则为单只股票
+func (u *usecase) series(userID uint, accountID *uint, symbol string, history *portfolioDomain.HistoryOutput) ([]*day, error) {
+	if symbol == "" {
+		return portfolioSeries(history.Points), nil
+	}
+	return u.symbolSeries(userID, accountID, symbol, history)
+}
+
+// portfolioSeries 整个组合的每日市值（持仓市值 + 现金）和入金、出金
+func portfolioSeries(points []*portfolioDomain.HistoryPoint) []*day {
+	series := make([]*day, len(points))
+	for i, p := range points {
+		d := &day{
+			date:  p.Date,
+			value: p.MarketValue.Add(p.Cash),
+			in:    decimal.Zero,
+			out:   decimal.Zero,
+		}
+		if p.NetDeposit.IsPositive() {
+			d.in = p.NetDeposit
+		} else {
+			d.out = p.NetDeposit.Neg()
+		}
+		series[i] = d
+	}
+	return series
+}
+
+// symbolSeries 单只股票的每日持仓市值和买卖、分红现金流
+// 现金流取该股票的全部交易（CashFlow 的相反数：买入为流入，卖出、分红为流出），按交易日汇率换算
+func (u *usecase) symbolSeries(userID uint, accountID *uint, symbol string, history *portfolioDomain.HistoryOutput) ([]*day, error) {
+	// 1. 每日持仓市值（只保留首次持有之后的天数）
+	var series []*day
+	byDate := make(map[time.Time]*day)
+	for _, p := range history.Points {
+		value := decimal.Zero
+		held := false
+		for _, h := range p.Holdings {
+			if h.Symbol == symbol {
+				value = h.MarketValue
+				held = true
+				break
+			}
+		}
+		if !held && len(series) == 0 {
+			continue
+		}
+		d := &day{date: p.Date, value: value, in: decimal.Zero, out: decimal.Zero}
+		series = append(series, d)
+		byDate[p.Date] = d
+	}
+
+	// 2. 该股票的交易现金流
+	ledger, err := u.txRepo.FindLedger(&txRepo.LedgerFilter{
+		UserID:    userID,
+		AccountID: accountID,
+		Symbol:    symbol,
+	})
+	if err != nil {
+		return nil, err
+	}
+	converter := u.fxDomain.NewConverter(history.BaseCurrency)
+	for _, tx := range ledger {
+		d, ok := byDate[fxDomain.DateOf(tx.TradeTime)]
+		if !ok {
+			continue // 今天的交易尚无快照
+		}
+		flow, err := converter.Convert(entity.CashFlow(tx).Neg(), tx.Currency, tx.TradeTime)
+		if err != nil {
+			return nil, err
+		}
+		if flow.IsPositive() {
+			d.in = d.in.Add(flow)
+		} else {
+			d.out = d.out.Sub(flow)
+		}
+	}
+	return series, nil
+}
+
+// windowBase 区间的期初日期（该日收盘后的市值为期初市值）
+func windowBase(window string, asOf time.Time) time.Time {
+	switch window {
+	case performanceDomain.WindowMTD:
+		return time.Date(asOf.Year(), asOf.Month(), 0, 0, 0, 0, 0, time.Local)
+	case performanceDomain.WindowYTD:
+		return time.Date(asOf.Year(), time.January, 0, 0, 0, 0, 0, time.Local)
+	case performanceDomain.Window1Y:
+		return asOf.AddDate(-1, 0, 0)
+	default:
+		return time.Time{}
+	}
+}
+
+// windowReturn 计算一个区间的收益率
+// 期初日期早于第一天估值时从第一天算起，期初市值为 0
+func windowReturn(window string, series []*day, base time.Time) *performanceDomain.WindowReturn {
+	// 1. 定位期初：最后一个不晚于期初日期的估值
+	first := sort.Search(len(series), func(i int) bool {
+		return series[i].date.After(base)
+	})
+	startDate := series[0].date.AddDate(0, 0, -1)
+	startValue := decimal.Zero
+	if first > 0 {
+		startDate = series[first-1].date
+		startValue = series[first-1].value
+	}
+	end := series[len(series)-1]
+
+	// 2. 逐日链接时间加权收益率，同时收集资金加权收益率的现金流（投资者视角：投入为负，取回为正）
+	growth := decimal.NewFromInt(1)
+	prev := startValue
+	netFlow := decimal.Zero
+	var flows []cashFlow
+	if !startValue.IsZero() {
+		flows = append(flows, cashFlow{date: startDate, amount: startValue.Neg()})
+	}
+	for _, d := range series[first:] {
+		if denominator := prev.Add(d.in); denominator.IsPositive() {
+			growth = growth.Mul(d.value.Add(d.out).Div(denominator)).Round(growthPrecision)
+		}
+		prev = d.value
+		netFlow = netFlow.Add(d.in).Sub(d.out)
+		if flow := d.out.Sub(d.in); !flow.IsZero() {
+			flows = append(flows, cashFlow{date: d.date, amount: flow})
+		}
+	}
+	flows = append(flows, cashFlow{date: end.date, amount: end.value})
+
+	// 3. 组装结果
+	days := daysBetween(startDate, end.date)
+	twr := growth.Sub(decimal.NewFromInt(1))
+	result := &performanceDomain.WindowReturn{
+		Window:     window,
+		StartDate:  startDate,
+		EndDate:    end.date,
+		Days:       days,
+		StartValue: startValue.Round(4),
+		EndValue:   end.value.Round(4),
+		NetFlow:    netFlow.Round(4),
+		Profit:     end.value.Sub(startValue).Sub(netFlow).Round(4),
+		TWR:        twr.Round(6),
+	}
+	if annualized, ok := annualize(growth, days); ok {
+		result.TWRAnnualized = &annualized
+	}
+	if rate, ok := xirr(flows); ok {
+		result.XIRR = &rate
+	}
+	return result
+}
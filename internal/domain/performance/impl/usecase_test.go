@@ -0,0 +1,186 @@
+package impl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// valuation 构造一天的估值：2024 年 1 月 d 日的市值、流入、流出
+func valuation(d int, value, in, out string) *day {
+	return &day{
+		date:  date(2024, time.January, d),
+		value: decimal.RequireFromString(value),
+		in:    decimal.RequireFromString(in),
+		out:   decimal.RequireFromString(out),
+	}
+}
+
+func TestWindowReturnTWR(t *testing.T) {
+	tests := []struct {
+		name           string
+		series         []*day
+		base           time.Time
+		wantStartDate  time.Time
+		wantStartValue string
+		wantNetFlow    string
+		wantProfit     string
+		wantTWR        string
+	}{
+		{
+			name: "无现金流时等于市值增长",
+			series: []*day{
+				valuation(1, "100", "0", "0"),
+				valuation(2, "110", "0", "0"),
+				valuation(3, "121", "0", "0"),
+			},
+			base:           date(2024, time.January, 1),
+			wantStartDate:  date(2024, time.January, 1),
+			wantStartValue: "100",
+			wantNetFlow:    "0",
+			wantProfit:     "21",
+			wantTWR:        "0.21",
+		},
+		{
+			// 第 3 天开始时流入 100：(231) / (110 + 100) = 1.1，与前一段 1.1 链接
+			name: "流入视为当天开始时发生，子区间链接",
+			series: []*day{
+				valuation(1, "100", "0", "0"),
+				valuation(2, "110", "0", "0"),
+				valuation(3, "231", "100", "0"),
+			},
+			base:           date(2024, time.January, 1),
+			wantStartDate:  date(2024, time.January, 1),
+			wantStartValue: "100",
+			wantNetFlow:    "100",
+			wantProfit:     "31",
+			wantTWR:        "0.21",
+		},
+		{
+			// 第 2 天结束时流出 55：(55 + 55) / 100 = 1.1
+			name: "流出视为当天结束时发生",
+			series: []*day{
+				valuation(1, "100", "0", "0"),
+				valuation(2, "55", "0", "55"),
+				valuation(3, "60.5", "0", "0"),
+			},
+			base:           date(2024, time.January, 1),
+			wantStartDate:  date(2024, time.January, 1),
+			wantStartValue: "100",
+			wantNetFlow:    "-55",
+			wantProfit:     "15.5",
+			wantTWR:        "0.21",
+		},
+		{
+			// 期初日期早于第一天：期初为第一天的前一天，市值为 0，建仓当天的收益也计入
+			name: "期初市值为零时从建仓当天算起",
+			series: []*day{
+				valuation(2, "110", "100", "0"),
+				valuation(3, "121", "0", "0"),
+			},
+			base:           date(2023, time.December, 31),
+			wantStartDate:  date(2024, time.January, 1),
+			wantStartValue: "0",
+			wantNetFlow:    "100",
+			wantProfit:     "21",
+			wantTWR:        "0.21",
+		},
+		{
+			// 第 3 天空仓，分母为 0 的一天不参与链接；第 4 天重新建仓
+			name: "清仓后重新建仓",
+			series: []*day{
+				valuation(1, "110", "100", "0"),
+				valuation(2, "0", "0", "110"),
+				valuation(3, "0", "0", "0"),
+				valuation(4, "55", "50", "0"),
+			},
+			base:           date(2023, time.December, 31),
+			wantStartDate:  date(2023, time.December, 31),
+			wantStartValue: "0",
+			wantNetFlow:    "40",
+			wantProfit:     "15",
+			wantTWR:        "0.21",
+		},
+		{
+			// 期初市值为负（卖空）时分母不为正，该段不参与链接
+			name: "期初市值为负时跳过该段",
+			series: []*day{
+				valuation(1, "-100", "0", "0"),
+				valuation(2, "-90", "0", "0"),
+				valuation(3, "-80", "0", "0"),
+			},
+			base:           date(2024, time.January, 1),
+			wantStartDate:  date(2024, time.January, 1),
+			wantStartValue: "-100",
+			wantNetFlow:    "0",
+			wantProfit:     "20",
+			wantTWR:        "0",
+		},
+		{
+			// 期初为最后一个不晚于期初日期的估值
+			name: "期初日期落在两次估值之间",
+			series: []*day{
+				valuation(1, "100", "0", "0"),
+				valuation(5, "110", "0", "0"),
+				valuation(9, "121", "0", "0"),
+			},
+			base:           date(2024, time.January, 7),
+			wantStartDate:  date(2024, time.January, 5),
+			wantStartValue: "110",
+			wantNetFlow:    "0",
+			wantProfit:     "11",
+			wantTWR:        "0.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := windowReturn("ALL", tt.series, tt.base)
+			if !got.StartDate.Equal(tt.wantStartDate) {
+				t.Errorf("start_date = %s, want %s", got.StartDate, tt.wantStartDate)
+			}
+			for _, c := range []struct {
+				field string
+				got   decimal.Decimal
+				want  string
+			}{
+				{"start_value", got.StartValue, tt.wantStartValue},
+				{"net_flow", got.NetFlow, tt.wantNetFlow},
+				{"profit", got.Profit, tt.wantProfit},
+				{"twr", got.TWR, tt.wantTWR},
+			} {
+				if !c.got.Equal(decimal.RequireFromString(c.want)) {
+					t.Errorf("%s = %s, want %s", c.field, c.got, c.want)
+				}
+			}
+			// 区间不足一年不年化
+			if got.TWRAnnualized != nil {
+				t.Errorf("twr_annualized = %s, want nil", got.TWRAnnualized)
+			}
+		})
+	}
+}
+
+func TestWindowReturnAnnualized(t *testing.T) {
+	// 2022-01-01 至 2024-01-01 共 730 天，市值 100 → 121
+	series := []*day{
+		{date: date(2022, time.January, 1), value: decimal.NewFromInt(100)},
+		{date: date(2024, time.January, 1), value: decimal.NewFromInt(121)},
+	}
+	got := windowReturn("ALL", series, date(2022, time.January, 1))
+
+	if got.Days != 730 {
+		t.Errorf("days = %d, want 730", got.Days)
+	}
+	assertRate(t, "twr", got.TWR, "0.21")
+	if got.TWRAnnualized == nil {
+		t.Fatalf("twr_annualized = nil, want 0.1")
+	}
+	assertRate(t, "twr_annualized", *got.TWRAnnualized, "0.1")
+	// 没有中途现金流时资金加权与时间加权一致
+	if got.XIRR == nil {
+		t.Fatalf("xirr = nil, want 0.1")
+	}
+	assertRate(t, "xirr", *got.XIRR, "0.1")
+}
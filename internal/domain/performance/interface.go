@@ -0,0 +1,126 @@
+package performance
+
+import (
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ==================== 错误定义 ====================
+// 领域层的业务错误（中文方便调试）
+
+var (
+	ErrInvalidWindow = errors.New("区间无效，必须是 mtd、ytd、1y 或 inception")
+	ErrNoHistory     = errors.New("没有估值历史：请先录入交易，估值快照生成后再查询")
+
+	ErrInvalidRiskFreeRate = errors.New("无风险利率无效，必须在 -1 到 1 之间（0.02 表示 2%）")
+	ErrNoBenchmarkPrices   = errors.New("基准在统计区间内没有日线行情，请先导入行情")
+)
+
+// 统计区间常量（均截止到最近一天的估值快照）
+const (
+	WindowMTD       = "mtd"       // 本月至今：从上月最后一天收盘起
+	WindowYTD       = "ytd"       // 本年至今：从上年最后一天收盘起
+	Window1Y        = "1y"        // 近一年：从一年前的同一天收盘起
+	WindowInception = "inception" // 成立以来：从第一天的估值起（期初市值为 0）
+)
+
+// DefaultWindows 未指定区间时返回的全部区间
+var DefaultWindows = []string{WindowMTD, WindowYTD, Window1Y, WindowInception}
+
+// IsValidWindow 判断统计区间是否有效
+func IsValidWindow(window string) bool {
+	for _, w := range DefaultWindows {
+		if w == window {
+			return true
+		}
+	}
+	return false
+}
+
+// ==================== Domain 输入结构体 ====================
+
+// ReturnsInput 收益率计算的输入参数
+type ReturnsInput struct {
+	UserID    uint     // 用户ID（必须）
+	AccountID *uint    // 账户ID（可选，nil 表示全部账户）
+	Symbol    string   // 股票代码（可选，为空表示整个组合）
+	Windows   []string // 统计区间（可选，为空表示全部区间）
+}
+
+// RiskInput 风险指标计算的输入参数
+type RiskInput struct {
+	UserID       uint             // 用户ID（必须）
+	AccountID    *uint            // 账户ID（可选，nil 表示全部账户）
+	Symbol       string           // 股票代码（可选，为空表示整个组合）
+	StartDate    *time.Time       // 开始日期（可选，第一天的收益率相对前一天收盘计算）
+	EndDate      *time.Time       // 结束日期（可选，不含）
+	Benchmark    string           // 基准股票代码（可选，取历史行情库的收盘价，为空时不计算 Beta 和相关系数）
+	RiskFreeRate *decimal.Decimal // 年化无风险利率（可选，nil 表示使用配置的默认值）
+}
+
+// ==================== Domain 输出结构体 ====================
+
+// WindowReturn 一个区间的收益率
+// 收益率为小数（0.1 表示 10%）；区间早于第一天估值时从第一天算起（StartDate 为实际开始日期）
+type WindowReturn struct {
+	Window        string           // 统计区间
+	StartDate     time.Time        // 期初日期（该日收盘后的估值为期初市值）
+	EndDate       time.Time        // 期末日期
+	Days          int              // 区间天数
+	StartValue    decimal.Decimal  // 期初市值
+	EndValue      decimal.Decimal  // 期末市值
+	NetFlow       decimal.Decimal  // 期间外部净流入（组合：入金 - 出金；单只股票：买入 - 卖出 - 分红）
+	Profit        decimal.Decimal  // 收益 = 期末市值 - 期初市值 - 净流入
+	TWR           decimal.Decimal  // 时间加权收益率（累计，剔除资金进出的影响）
+	TWRAnnualized *decimal.Decimal // 年化时间加权收益率（区间不足一年时为空）
+	XIRR          *decimal.Decimal // 资金加权收益率（年化内部收益率，无解时为空）
+}
+
+// ReturnsOutput 收益率计算的输出结果（金额为基准货币）
+type ReturnsOutput struct {
+	BaseCurrency string          // 基准货币
+	Symbol       string          // 规范化的股票代码（整个组合时为空）
+	AsOf         time.Time       // 最近一天的估值日期
+	Windows      []*WindowReturn // 按请求顺序
+}
+
+// RiskOutput 风险指标计算的输出结果
+// 比率均为小数；样本不足（少于 2 天收益率）或无意义（波动为 0）的指标为空
+type RiskOutput struct {
+	BaseCurrency string          // 基准货币
+	Symbol       string          // 规范化的股票代码（整个组合时为空）
+	StartDate    time.Time       // 第一天收益率的日期
+	EndDate      time.Time       // 最后一天收益率的日期
+	Days         int             // 日收益率的天数（自然日）
+	RiskFreeRate decimal.Decimal // 使用的年化无风险利率
+	TotalReturn  decimal.Decimal // 区间时间加权收益率（累计）
+
+	Volatility *decimal.Decimal // 年化波动率 = 日收益率标准差 × √365
+	Sharpe     *decimal.Decimal // 夏普比率 = 日超额收益均值 / 日收益率标准差 × √365
+	Sortino    *decimal.Decimal // 索提诺比率 = 日超额收益均值 / 下行标准差 × √365
+
+	MaxDrawdown  decimal.Decimal // 最大回撤（按时间加权净值计算，0 或负数：-0.2 表示回撤 20%）
+	PeakDate     *time.Time      // 最大回撤的峰值日期（没有回撤时为空）
+	TroughDate   *time.Time      // 最大回撤的谷底日期
+	RecoveryDate *time.Time      // 回到峰值的日期（尚未恢复时为空）
+
+	Benchmark       string           // 规范化的基准股票代码
+	BenchmarkReturn *decimal.Decimal // 基准在区间内的涨跌幅（基准自身币种）
+	Beta            *decimal.Decimal // Beta = Cov(组合, 基准) / Var(基准)
+	Correlation     *decimal.Decimal // 相关系数
+}
+
+// ==================== Domain 接口定义 ====================
+// Service 层会依赖这个接口
+
+type Domain interface {
+	// Returns 计算时间加权收益率（TWR）和资金加权收益率（XIRR）
+	// 核心业务逻辑：基于每日估值快照和外部现金流，逐日链接计算 TWR，按日期现金流求解 XIRR
+	Returns(input *ReturnsInput) (*ReturnsOutput, error)
+
+	// Risk 计算风险指标：波动率、最大回撤、夏普、索提诺、相对基准的 Beta 和相关系数
+	// 核心业务逻辑：日收益率与 TWR 相同（剔除资金进出），基准收盘价按自然日向前填充后与组合逐日对齐
+	Risk(input *RiskInput) (*RiskOutput, error)
+}
@@ -0,0 +1,153 @@
+package impl
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 持仓计算器 ====================
+// 采用移动加权平均成本法回放交易流水：
+// - 买入：数量增加，成本 += 成交金额 + 手续费
+// - 卖出：按平均成本结转卖出部分的成本，已实现盈亏 = 卖出净收入 - 结转成本
+// - 转入：同买入，成本 = 转入成本 + 手续费
+// - 转出：按平均成本结转，成本随股票转出，不产生已实现盈亏
+// - 拆股：数量按比例缩放，总成本不变
+// - 分红、利息：计入收入；费用：计入累计手续费
+// 持仓数量可以为负（卖空），此时 cost 为负数，表示卖空收到的净额
+// 每个账户分别回放，多个账户的合并视图为各账户持仓之和
+
+// position 单只股票的持仓状态
+type position struct {
+	symbol     string
+	currency   string // 金额的币种
+	name       string
+	nameTime   time.Time       // 名称所取交易的时间（合并账户时取较新的名称）
+	quantity   decimal.Decimal // 带符号：正数为多头，负数为空头
+	cost       decimal.Decimal // 带符号：多头为买入成本，空头为卖空净收入的相反数
+	realized   decimal.Decimal // 已实现盈亏
+	income     decimal.Decimal // 分红、利息收入（税前）
+	fee        decimal.Decimal // 累计手续费（含费用类交易和预扣税）
+	tradeCount int
+}
+
+// newPosition 创建空持仓
+func newPosition(symbol, currency string) *position {
+	return &position{
+		symbol:   symbol,
+		currency: currency,
+		quantity: decimal.Zero,
+		cost:     decimal.Zero,
+		realized: decimal.Zero,
+		income:   decimal.Zero,
+		fee:      decimal.Zero,
+	}
+}
+
+// apply 回放一笔交易
+func (p *position) apply(tx *entity.Transaction) {
+	p.tradeCount++
+	p.fee = p.fee.Add(tx.Fee)
+	if tx.Name != "" {
+		p.name = tx.Name
+		p.nameTime = tx.TradeTime
+	}
+
+	// delta：带符号的数量变动；value：带符号的现金成本（买入为正，卖出为负）
+	var delta, value decimal.Decimal
+	switch tx.Type {
+	case entity.TransactionTypeBuy, entity.TransactionTypeTransferIn:
+		delta = tx.Quantity
+		value = tx.Amount.Add(tx.Fee)
+	case entity.TransactionTypeSell:
+		delta = tx.Quantity.Neg()
+		value = tx.Amount.Sub(tx.Fee).Neg()
+	case entity.TransactionTypeTransferOut:
+		// 按平均成本结转：平仓金额恰好等于结转成本，已实现盈亏不变
+		delta = tx.Quantity.Neg()
+		value = p.basisOf(tx.Quantity).Neg()
+	case entity.TransactionTypeSplit:
+		p.quantity = p.quantity.Mul(tx.Ratio).Round(4)
+		return
+	case entity.TransactionTypeDividend, entity.TransactionTypeInterest:
+		p.income = p.income.Add(tx.Amount)
+		return
+	case entity.TransactionTypeFee:
+		p.fee = p.fee.Add(tx.Amount)
+		return
+	default:
+		return
+	}
+
+	p.trade(delta, value)
+}
+
+// basisOf 多头持仓中 qty 数量对应的平均成本（超出持仓的部分不计成本）
+func (p *position) basisOf(qty decimal.Decimal) decimal.Decimal {
+	if !p.quantity.IsPositive() {
+		return decimal.Zero
+	}
+	return p.cost.Mul(decimal.Min(qty, p.quantity)).Div(p.quantity)
+}
+
+// trade 按带符号的数量和金额更新持仓
+func (p *position) trade(delta, value decimal.Decimal) {
+	if delta.IsZero() {
+		return
+	}
+
+	// 1. 空仓或同方向：直接加仓
+	if p.quantity.IsZero() || p.quantity.Sign() == delta.Sign() {
+		p.quantity = p.quantity.Add(delta)
+		p.cost = p.cost.Add(value)
+		return
+	}
+
+	// 2. 反方向：先平仓，按平均成本结转
+	held := p.quantity.Abs()
+	size := delta.Abs()
+	closeQty := decimal.Min(held, size)
+
+	basis := p.cost.Mul(closeQty).Div(held)     // 被平掉部分的成本
+	closeValue := value.Mul(closeQty).Div(size) // 本笔交易中用于平仓的金额
+	p.realized = p.realized.Sub(closeValue.Add(basis))
+
+	if closeQty.Equal(held) {
+		// 完全平仓：成本清零，避免除法产生的尾差残留
+		p.quantity = decimal.Zero
+		p.cost = decimal.Zero
+	} else {
+		p.quantity = p.quantity.Add(closeQty.Mul(decimal.NewFromInt(int64(delta.Sign()))))
+		p.cost = p.cost.Sub(basis)
+	}
+
+	// 3. 剩余部分反向开仓（如多头卖超后转为空头）
+	if remain := size.Sub(closeQty); remain.IsPositive() {
+		p.quantity = remain.Mul(decimal.NewFromInt(int64(delta.Sign())))
+		p.cost = value.Sub(closeValue)
+	}
+}
+
+// merge 合并另一个账户中同一股票的持仓（数量、成本、盈亏、收入、费用直接相加）
+func (p *position) merge(other *position) {
+	p.quantity = p.quantity.Add(other.quantity)
+	p.cost = p.cost.Add(other.cost)
+	p.realized = p.realized.Add(other.realized)
+	p.income = p.income.Add(other.income)
+	p.fee = p.fee.Add(other.fee)
+	p.tradeCount += other.tradeCount
+	if other.name != "" && (p.name == "" || other.nameTime.After(p.nameTime)) {
+		p.name = other.name
+		p.nameTime = other.nameTime
+	}
+}
+
+// averageCost 平均成本（空仓时为 0）
+func (p *position) averageCost() decimal.Decimal {
+	if p.quantity.IsZero() {
+		return decimal.Zero
+	}
+	return p.cost.Div(p.quantity)
+}
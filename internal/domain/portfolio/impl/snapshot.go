@@ -0,0 +1,396 @@
+package impl
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	snapshotRepo "github.com/florentyang/smartfin-go/internal/dao/snapshot"
+	txRepo "github.com/florentyang/smartfin-go/internal/dao/transaction"
+	fxDomain "github.com/florentyang/smartfin-go/internal/domain/fx"
+	portfolioDomain "github.com/florentyang/smartfin-go/internal/domain/portfolio"
+	priceDomain "github.com/florentyang/smartfin-go/internal/domain/price"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// snapshotChunkDays 每个数据库事务最多生成的天数
+// 生成期间锁定用户行（与交易写入串行），分段提交避免长时间阻塞用户的写操作
+const snapshotChunkDays = 92
+
+// ==================== 业务方法实现 ====================
+
+// BuildSnapshots 补齐用户截止到 through（含）的每日估值快照
+func (u *usecase) BuildSnapshots(userID uint, through time.Time) (int, error) {
+	return u.buildSnapshots(userID, fxDomain.DateOf(through), false)
+}
+
+// RebuildSnapshots 删除用户的全部快照并重新生成
+func (u *usecase) RebuildSnapshots(userID uint, through time.Time) (int, error) {
+	return u.buildSnapshots(userID, fxDomain.DateOf(through), true)
+}
+
+// SnapshotUserIDs 需要生成快照的用户
+func (u *usecase) SnapshotUserIDs() ([]uint, error) {
+	return u.txRepo.FindUserIDs()
+}
+
+// History 估值历史
+// 核心业务逻辑：按日期范围读取快照 → 合并各账户同一天的快照
+// 只读：快照由后台任务和交易写入后的异步队列补齐，查询不写库、不加锁
+func (u *usecase) History(input *portfolioDomain.HistoryInput) (*portfolioDomain.HistoryOutput, error) {
+	// 1. 校验日期范围（结束日期不含）
+	filter := &snapshotRepo.SnapshotFilter{
+		UserID:          input.UserID,
+		AccountID:       input.AccountID,
+		IncludeHoldings: input.IncludeHoldings,
+	}
+	if input.StartDate != nil {
+		start := fxDomain.DateOf(*input.StartDate)
+		filter.StartDate = &start
+	}
+	if input.EndDate != nil {
+		end := fxDomain.DateOf(*input.EndDate)
+		filter.EndDate = &end
+	}
+	if filter.StartDate != nil && filter.EndDate != nil && !filter.StartDate.Before(*filter.EndDate) {
+		return nil, portfolioDomain.ErrInvalidDateRange
+	}
+
+	// 2. 基准货币
+	user, err := u.userRepo.GetByID(input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 查询快照（按日期、账户正序）
+	snapshots, err := u.snapshotRepo.FindSnapshots(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. 同一天的各账户快照相加，持仓按股票合并
+	// 基准货币变更后旧快照尚未重新生成，币种不一致的快照不返回
+	output := &portfolioDomain.HistoryOutput{
+		BaseCurrency: fxDomain.BaseCurrencyOf(user),
+		Points:       make([]*portfolioDomain.HistoryPoint, 0),
+	}
+	var point *portfolioDomain.HistoryPoint
+	var holdings map[string]*portfolioDomain.HistoryHolding
+	for _, s := range snapshots {
+		if s.Currency != output.BaseCurrency {
+			continue
+		}
+		date := fxDomain.DateOf(s.SnapshotDate)
+		if point == nil || !point.Date.Equal(date) {
+			point = &portfolioDomain.HistoryPoint{
+				Date:        date,
+				MarketValue: decimal.Zero,
+				Cost:        decimal.Zero,
+				Cash:        decimal.Zero,
+				NetDeposit:  decimal.Zero,
+			}
+			holdings = make(map[string]*portfolioDomain.HistoryHolding)
+			output.Points = append(output.Points, point)
+		}
+		point.MarketValue = point.MarketValue.Add(s.MarketValue)
+		point.Cost = point.Cost.Add(s.Cost)
+		point.Cash = point.Cash.Add(s.Cash)
+		point.NetDeposit = point.NetDeposit.Add(s.NetDeposit)
+
+		for _, h := range s.Holdings {
+			merged, ok := holdings[h.Symbol]
+			if !ok {
+				merged = &portfolioDomain.HistoryHolding{
+					Symbol:        h.Symbol,
+					Quantity:      decimal.Zero,
+					Price:         h.Price,
+					PriceCurrency: h.PriceCurrency,
+					MarketValue:   decimal.Zero,
+					Cost:          decimal.Zero,
+				}
+				holdings[h.Symbol] = merged
+				point.Holdings = append(point.Holdings, merged)
+			}
+			merged.Quantity = merged.Quantity.Add(h.Quantity)
+			merged.MarketValue = merged.MarketValue.Add(h.MarketValue)
+			merged.Cost = merged.Cost.Add(h.Cost)
+		}
+	}
+	for _, p := range output.Points {
+		sort.Slice(p.Holdings, func(i, j int) bool {
+			return p.Holdings[i].Symbol < p.Holdings[j].Symbol
+		})
+	}
+
+	return output, nil
+}
+
+// ==================== 快照生成 ====================
+
+// buildSnapshots 分段生成快照直到 through，reset 时先删除全部快照
+func (u *usecase) buildSnapshots(userID uint, through time.Time, reset bool) (int, error) {
+	total := 0
+	for {
+		days, err := u.buildChunk(userID, through, reset)
+		if err != nil {
+			return total, err
+		}
+		if days == 0 {
+			return total, nil
+		}
+		total += days
+		reset = false
+	}
+}
+
+// buildChunk 在锁定用户行的事务中生成一段快照（最多 snapshotChunkDays 天），返回生成的天数，0 表示已补齐
+// 锁定用户行与交易写入串行：交易变更删除快照和这里基于变更前的流水写入快照不会交错
+func (u *usecase) buildChunk(userID uint, through time.Time, reset bool) (int, error) {
+	days := 0
+	err := u.transactor.Transaction(func(db *gorm.DB) error {
+		snapshots := u.snapshotRepo.WithTx(db)
+
+		// 1. 锁定用户行，读取基准货币
+		user, err := u.userRepo.WithTx(db).GetByIDForUpdate(userID)
+		if err != nil {
+			return err
+		}
+		base := fxDomain.BaseCurrencyOf(user)
+
+		// 2. 确定开始日期：最近一天快照的次日；没有快照或基准货币已变更时从头生成
+		if reset {
+			if err := snapshots.DeleteAll(userID); err != nil {
+				return err
+			}
+		}
+		var start time.Time
+		latest, err := snapshots.FindLatest(userID)
+		switch {
+		case errors.Is(err, snapshotRepo.ErrSnapshotNotFound):
+		case err != nil:
+			return err
+		case latest.Currency != base:
+			if err := snapshots.DeleteAll(userID); err != nil {
+				return err
+			}
+		default:
+			start = fxDomain.DateOf(latest.SnapshotDate).AddDate(0, 0, 1)
+		}
+
+		// 3. 截止到 through 当天结束的交易流水（按交易时间正序）
+		end := through.AddDate(0, 0, 1)
+		ledger, err := u.txRepo.WithTx(db).FindLedger(&txRepo.LedgerFilter{
+			UserID:  userID,
+			EndTime: &end,
+		})
+		if err != nil {
+			return err
+		}
+		if len(ledger) == 0 {
+			return nil
+		}
+		if start.IsZero() {
+			start = fxDomain.DateOf(ledger[0].TradeTime)
+		}
+		if start.After(through) {
+			return nil
+		}
+		last := start.AddDate(0, 0, snapshotChunkDays-1)
+		if last.After(through) {
+			last = through
+		}
+
+		// 4. 逐日回放并估值，写入快照
+		list, n, err := u.snapshotRange(userID, base, ledger, start, last)
+		if err != nil {
+			return err
+		}
+		if err := snapshots.Create(list); err != nil {
+			return err
+		}
+		days = n
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return days, nil
+}
+
+// snapshotRange 逐日回放交易流水，生成 [start, last] 每天每个账户的快照，返回快照和天数
+// 每天的状态为当天结束时（次日 0 点之前）的全部交易；估值价格为当天或之前最近的收盘价，按当天汇率换算
+// 整段的行情一次加载，逐日估值在内存中完成
+func (u *usecase) snapshotRange(userID uint, base string, ledger []*entity.Transaction, start, last time.Time) ([]*entity.PortfolioSnapshot, int, error) {
+	converter := u.fxDomain.NewConverter(base)
+	marker, err := u.priceDomain.NewMarker(ledger, start.AddDate(0, 0, 1), last.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, 0, err
+	}
+	accounts := make(map[uint]*accountState)
+	apply := func(tx *entity.Transaction) error {
+		a, ok := accounts[tx.AccountID]
+		if !ok {
+			a = newAccountState(userID, tx.AccountID)
+			accounts[tx.AccountID] = a
+		}
+		return a.apply(tx, converter)
+	}
+
+	// 1. 开始日期之前的交易只累计状态
+	i := 0
+	for ; i < len(ledger) && ledger[i].TradeTime.Before(start); i++ {
+		if err := apply(ledger[i]); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	// 2. 逐日回放当天的交易，生成各账户的快照
+	var list []*entity.PortfolioSnapshot
+	days := 0
+	for day := start; !day.After(last); day = day.AddDate(0, 0, 1) {
+		next := day.AddDate(0, 0, 1)
+		for _, a := range accounts {
+			a.netDeposit = decimal.Zero
+		}
+		for ; i < len(ledger) && ledger[i].TradeTime.Before(next); i++ {
+			if err := apply(ledger[i]); err != nil {
+				return nil, 0, err
+			}
+		}
+
+		marks, err := marker.Marks(next)
+		if err != nil {
+			return nil, 0, err
+		}
+		ids := make([]uint, 0, len(accounts))
+		for id := range accounts {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(a, b int) bool { return ids[a] < ids[b] })
+		for _, id := range ids {
+			snapshot, err := accounts[id].snapshot(day, base, marks, converter)
+			if err != nil {
+				return nil, 0, err
+			}
+			list = append(list, snapshot)
+		}
+		days++
+	}
+	return list, days, nil
+}
+
+// ==================== 账户状态 ====================
+
+// accountState 回放到某一时点的单个账户状态
+type accountState struct {
+	userID     uint
+	accountID  uint
+	positions  map[string]*position       // 股票代码 → 持仓（按交易日汇率换算为基准货币）
+	cash       map[string]decimal.Decimal // 币种 → 现金余额（原币）
+	netDeposit decimal.Decimal            // 当天外部资金净流入（基准货币，每天清零）
+}
+
+// newAccountState 创建空账户状态
+func newAccountState(userID, accountID uint) *accountState {
+	return &accountState{
+		userID:     userID,
+		accountID:  accountID,
+		positions:  make(map[string]*position),
+		cash:       make(map[string]decimal.Decimal),
+		netDeposit: decimal.Zero,
+	}
+}
+
+// apply 回放一笔交易：现金按原币累计，入金出金计入当天净流入，持仓按交易日汇率换算后回放
+func (a *accountState) apply(tx *entity.Transaction, converter fxDomain.Converter) error {
+	if flow := entity.CashFlow(tx); !flow.IsZero() {
+		a.cash[tx.Currency] = a.cash[tx.Currency].Add(flow)
+		if tx.Type == entity.TransactionTypeDeposit || tx.Type == entity.TransactionTypeWithdrawal {
+			converted, err := converter.Convert(flow, tx.Currency, tx.TradeTime)
+			if err != nil {
+				return err
+			}
+			a.netDeposit = a.netDeposit.Add(converted)
+		}
+	}
+
+	// 没有股票代码的现金交易不属于任何持仓
+	if tx.Symbol == "" {
+		return nil
+	}
+	converted, err := convertTx(tx, converter)
+	if err != nil {
+		return err
+	}
+	p, ok := a.positions[tx.Symbol]
+	if !ok {
+		p = newPosition(tx.Symbol, converter.Currency())
+		a.positions[tx.Symbol] = p
+	}
+	p.apply(converted)
+	return nil
+}
+
+// snapshot 生成账户在 day 收盘后的快照
+// 持仓市值按估值价格和当天汇率换算，没有估值价格的持仓按成本计价；现金按当天汇率换算
+func (a *accountState) snapshot(day time.Time, base string, marks map[string]*priceDomain.Mark, converter fxDomain.Converter) (*entity.PortfolioSnapshot, error) {
+	s := &entity.PortfolioSnapshot{
+		UserID:       a.userID,
+		SnapshotDate: day,
+		AccountID:    a.accountID,
+		Currency:     base,
+		MarketValue:  decimal.Zero,
+		Cost:         decimal.Zero,
+		Cash:         decimal.Zero,
+		NetDeposit:   a.netDeposit.Round(4),
+	}
+
+	// 1. 持仓（按股票代码排序，已清仓的不记录）
+	symbols := make([]string, 0, len(a.positions))
+	for symbol, p := range a.positions {
+		if !p.quantity.IsZero() {
+			symbols = append(symbols, symbol)
+		}
+	}
+	sort.Strings(symbols)
+	for _, symbol := range symbols {
+		p := a.positions[symbol]
+		h := entity.SnapshotHolding{
+			Symbol:   symbol,
+			Quantity: p.quantity,
+			Price:    decimal.Zero,
+			Cost:     p.cost.Round(4),
+		}
+		h.MarketValue = h.Cost
+		if mark, ok := marks[symbol]; ok {
+			value, err := converter.Convert(p.quantity.Mul(mark.Price).Round(4), mark.Currency, day)
+			if err != nil {
+				return nil, err
+			}
+			h.Price = mark.Price
+			h.PriceCurrency = mark.Currency
+			h.MarketValue = value.Round(4)
+		}
+		s.MarketValue = s.MarketValue.Add(h.MarketValue)
+		s.Cost = s.Cost.Add(h.Cost)
+		s.Holdings = append(s.Holdings, h)
+	}
+
+	// 2. 现金（各币种按当天汇率换算后相加）
+	for currency, balance := range a.cash {
+		if balance.IsZero() {
+			continue
+		}
+		value, err := converter.Convert(balance, currency, day)
+		if err != nil {
+			return nil, err
+		}
+		s.Cash = s.Cash.Add(value)
+	}
+	s.Cash = s.Cash.Round(4)
+
+	return s, nil
+}
@@ -0,0 +1,312 @@
+package impl
+
+import (
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/florentyang/smartfin-go/internal/dao"
+	snapshotRepo "github.com/florentyang/smartfin-go/internal/dao/snapshot"
+	txRepo "github.com/florentyang/smartfin-go/internal/dao/transaction"
+	userRepo "github.com/florentyang/smartfin-go/internal/dao/user"
+	fxDomain "github.com/florentyang/smartfin-go/internal/domain/fx"
+	portfolioDomain "github.com/florentyang/smartfin-go/internal/domain/portfolio"
+	priceDomain "github.com/florentyang/smartfin-go/internal/domain/price"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== UseCase 结构体 ====================
+
+type usecase struct {
+	txRepo       txRepo.Repo        // 依赖交易 DAO 层接口（持仓由交易流水推导，不单独建表）
+	userRepo     userRepo.Repo      // 用户 DAO（读取基准货币，生成快照时加锁）
+	snapshotRepo snapshotRepo.Repo  // 估值快照 DAO（估值历史）
+	fxDomain     fxDomain.Domain    // 汇率 Domain（换算为基准货币）
+	priceDomain  priceDomain.Domain // 历史行情 Domain（估值价格）
+	transactor   dao.Transactor     // 事务管理器（生成快照时与交易写入串行）
+}
+
+// ==================== 构造函数 ====================
+
+// NewPortfolioDomain 创建 Domain 实例
+func NewPortfolioDomain(
+	repo txRepo.Repo,
+	userRepo userRepo.Repo,
+	snapshotRepo snapshotRepo.Repo,
+	fxDomain fxDomain.Domain,
+	priceDomain priceDomain.Domain,
+	transactor dao.Transactor,
+) portfolioDomain.Domain {
+	return &usecase{
+		txRepo:       repo,
+		userRepo:     userRepo,
+		snapshotRepo: snapshotRepo,
+		fxDomain:     fxDomain,
+		priceDomain:  priceDomain,
+		transactor:   transactor,
+	}
+}
+
+// ==================== 业务方法实现 ====================
+
+// Holdings 持仓汇总
+// 核心业务逻辑：按时间顺序回放交易流水，逐个账户、逐只股票累计持仓；
+// 未指定账户时把各账户的持仓相加，得到合并视图
+func (u *usecase) Holdings(input *portfolioDomain.HoldingsInput) (*portfolioDomain.HoldingsOutput, error) {
+	// 1. 校验报表币种，读取基准货币
+	if input.Currency == "" {
+		input.Currency = fxDomain.ReportCurrencyBase
+	}
+	if input.Currency != fxDomain.ReportCurrencyBase && input.Currency != fxDomain.ReportCurrencyTrade {
+		return nil, portfolioDomain.ErrInvalidCurrencyMode
+	}
+	user, err := u.userRepo.GetByID(input.UserID)
+	if err != nil {
+		return nil, err
+	}
+	base := fxDomain.BaseCurrencyOf(user)
+
+	// 2. 查询用户全部交易（按交易时间正序）
+	txList, err := u.txRepo.FindLedger(&txRepo.LedgerFilter{
+		UserID:    input.UserID,
+		AccountID: input.AccountID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 按基准货币回放（合计始终按基准货币）
+	converter := u.fxDomain.NewConverter(base)
+	basePositions, err := replay(txList, converter)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. 按交易币种列示时，不换算再回放一次
+	rowPositions := basePositions
+	if input.Currency == fxDomain.ReportCurrencyTrade {
+		if rowPositions, err = replay(txList, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	// 5. 当前估值价格（默认取历史行情库的收盘价）
+	now := time.Now()
+	marks, err := u.priceDomain.Marks(txList, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// 6. 组装输出（按股票代码排序，保证结果稳定）
+	output := &portfolioDomain.HoldingsOutput{
+		BaseCurrency:     base,
+		Holdings:         make([]*portfolioDomain.Holding, 0, len(rowPositions)),
+		TotalCost:        decimal.Zero,
+		TotalRealizedPnL: decimal.Zero,
+		TotalIncome:      decimal.Zero,
+		TotalMarketValue: decimal.Zero,
+		TotalUnrealized:  decimal.Zero,
+	}
+	for _, p := range basePositions {
+		output.TotalRealizedPnL = output.TotalRealizedPnL.Add(p.realized)
+		output.TotalIncome = output.TotalIncome.Add(p.income)
+		output.TotalCost = output.TotalCost.Add(p.cost)
+
+		h := toHolding(p)
+		if err := applyMark(h, marks[p.symbol], converter, now); err != nil {
+			return nil, err
+		}
+		if h.MarketValue != nil {
+			output.TotalMarketValue = output.TotalMarketValue.Add(*h.MarketValue)
+			output.TotalUnrealized = output.TotalUnrealized.Add(*h.UnrealizedPnL)
+		}
+	}
+	rowConverter := converter
+	if input.Currency == fxDomain.ReportCurrencyTrade {
+		rowConverter = nil
+	}
+	for _, p := range rowPositions {
+		// 已清仓的股票默认不返回
+		if p.quantity.IsZero() && !input.IncludeClosed {
+			continue
+		}
+		h := toHolding(p)
+		if err := applyMark(h, marks[p.symbol], rowConverter, now); err != nil {
+			return nil, err
+		}
+		output.Holdings = append(output.Holdings, h)
+	}
+	sort.Slice(output.Holdings, func(i, j int) bool {
+		if output.Holdings[i].Symbol != output.Holdings[j].Symbol {
+			return output.Holdings[i].Symbol < output.Holdings[j].Symbol
+		}
+		return output.Holdings[i].Currency < output.Holdings[j].Currency
+	})
+
+	output.TotalCost = output.TotalCost.Round(4)
+	output.TotalRealizedPnL = output.TotalRealizedPnL.Round(4)
+	output.TotalMarketValue = output.TotalMarketValue.Round(4)
+	output.TotalUnrealized = output.TotalUnrealized.Round(4)
+
+	return output, nil
+}
+
+// HeldSymbols 用户当前持有的股票代码
+// 只看数量，不需要汇率换算；同一只股票在多个账户或多个币种下只返回一次
+func (u *usecase) HeldSymbols(userID uint) ([]string, error) {
+	txList, err := u.txRepo.FindLedger(&txRepo.LedgerFilter{UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+	positions, err := replay(txList, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(positions))
+	symbols := make([]string, 0, len(positions))
+	for _, p := range positions {
+		if p.quantity.IsZero() || seen[p.symbol] {
+			continue
+		}
+		seen[p.symbol] = true
+		symbols = append(symbols, p.symbol)
+	}
+	sort.Strings(symbols)
+	return symbols, nil
+}
+
+// ==================== 私有辅助函数 ====================
+
+// positionKey 持仓的分组键：股票代码 + 币种
+type positionKey struct {
+	symbol   string
+	currency string
+}
+
+// accountPositionKey 回放时的分组键：账户 + 股票代码 + 币种
+// 平均成本按账户分别计算（各账户的买卖互不影响），输出前再按股票合并
+type accountPositionKey struct {
+	accountID uint
+	positionKey
+}
+
+// replay 按账户、股票代码回放交易流水，再把各账户的持仓按股票合并
+// converter 不为空时，每笔交易按交易日汇率换算为目标币种后回放，每只股票一行；
+// 为空时保持交易币种：现金类交易按自身币种分行，其余交易归入股票的持仓币种
+func replay(txList []*entity.Transaction, converter fxDomain.Converter) (map[positionKey]*position, error) {
+	// 1. 每只股票的持仓币种（取第一笔买卖、转入转出的币种；拆股不关心币种）
+	holdingCurrency := make(map[string]string)
+	for _, tx := range txList {
+		if _, ok := holdingCurrency[tx.Symbol]; !ok && entity.AffectsCostBasis(tx.Type) {
+			holdingCurrency[tx.Symbol] = tx.Currency
+		}
+	}
+
+	// 2. 逐笔回放
+	accountPositions := make(map[accountPositionKey]*position)
+	for _, tx := range txList {
+		// 没有股票代码的现金交易（入金、出金、账户利息等）不属于任何持仓
+		if tx.Symbol == "" {
+			continue
+		}
+
+		key := positionKey{symbol: tx.Symbol, currency: tx.Currency}
+		if converter != nil {
+			key.currency = converter.Currency()
+			converted, err := convertTx(tx, converter)
+			if err != nil {
+				return nil, err
+			}
+			tx = converted
+		} else if c, ok := holdingCurrency[tx.Symbol]; ok && !entity.IsCashTransactionType(tx.Type) {
+			key.currency = c
+		}
+
+		accountKey := accountPositionKey{accountID: tx.AccountID, positionKey: key}
+		p, ok := accountPositions[accountKey]
+		if !ok {
+			p = newPosition(key.symbol, key.currency)
+			accountPositions[accountKey] = p
+		}
+		p.apply(tx)
+	}
+
+	// 3. 按股票合并各账户的持仓
+	positions := make(map[positionKey]*position)
+	for key, p := range accountPositions {
+		merged, ok := positions[key.positionKey]
+		if !ok {
+			merged = newPosition(key.symbol, key.currency)
+			positions[key.positionKey] = merged
+		}
+		merged.merge(p)
+	}
+	return positions, nil
+}
+
+// convertTx 按交易日汇率把交易的单价、金额、手续费换算为目标币种（返回副本）
+func convertTx(tx *entity.Transaction, converter fxDomain.Converter) (*entity.Transaction, error) {
+	rate, err := converter.Rate(tx.Currency, tx.TradeTime)
+	if err != nil {
+		return nil, err
+	}
+	if rate.Equal(decimal.NewFromInt(1)) {
+		return tx, nil
+	}
+	converted := *tx
+	converted.Price = tx.Price.Mul(rate).Round(4)
+	converted.Amount = tx.Amount.Mul(rate).Round(4)
+	converted.Fee = tx.Fee.Mul(rate).Round(4)
+	converted.Currency = converter.Currency()
+	return &converted, nil
+}
+
+// applyMark 按估值价格计算持仓的市值和未实现盈亏
+// converter 不为空时价格按 at 当天汇率换算为目标币种；为空时价格必须与持仓同币种，否则不估值。
+// 已清仓或没有估值价格的持仓不估值
+func applyMark(h *portfolioDomain.Holding, mark *priceDomain.Mark, converter fxDomain.Converter, at time.Time) error {
+	if mark == nil || h.Quantity.IsZero() {
+		return nil
+	}
+	price := mark.Price
+	marketValue := h.Quantity.Mul(mark.Price).Round(4)
+	if converter != nil {
+		var err error
+		if price, err = converter.Convert(price, mark.Currency, at); err != nil {
+			return err
+		}
+		if marketValue, err = converter.Convert(marketValue, mark.Currency, at); err != nil {
+			return err
+		}
+	} else if mark.Currency != h.Currency {
+		return nil
+	}
+
+	unrealized := marketValue.Sub(h.TotalCost)
+	priceDate := mark.Date
+	h.MarketPrice = &price
+	h.MarketValue = &marketValue
+	h.UnrealizedPnL = &unrealized
+	h.PriceDate = &priceDate
+	h.PriceSource = mark.Source
+	return nil
+}
+
+// toHolding 将持仓状态转换为输出结构
+// 金额统一保留 4 位小数，与数据库 decimal(18,4) 一致
+func toHolding(p *position) *portfolioDomain.Holding {
+	return &portfolioDomain.Holding{
+		Symbol:      p.symbol,
+		Currency:    p.currency,
+		Name:        p.name,
+		Quantity:    p.quantity,
+		TotalCost:   p.cost.Round(4),
+		AverageCost: p.averageCost().Round(4),
+		RealizedPnL: p.realized.Round(4),
+		Income:      p.income,
+		TotalFee:    p.fee,
+		TradeCount:  p.tradeCount,
+	}
+}
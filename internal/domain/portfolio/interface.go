@@ -0,0 +1,133 @@
+package portfolio
+
+import (
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ==================== 错误定义 ====================
+
+var (
+	ErrInvalidCurrencyMode = errors.New("报表币种无效，必须是 base 或 trade")
+	ErrInvalidDateRange    = errors.New("开始日期不能晚于结束日期")
+)
+
+// ==================== Domain 输入结构体 ====================
+// Service 层通过这些结构体向 Domain 层传递参数
+
+// HoldingsInput 持仓汇总的输入参数
+type HoldingsInput struct {
+	UserID        uint   // 用户ID（必须）
+	AccountID     *uint  // 账户ID（可选，nil 表示合并全部账户，0 表示未指定账户的交易）
+	IncludeClosed bool   // 是否包含已清仓的股票（数量为 0，但可能有已实现盈亏）
+	Currency      string // 报表币种：base（默认）/ trade，见 fx.ReportCurrency*
+}
+
+// HistoryInput 查询估值历史的输入参数
+type HistoryInput struct {
+	UserID          uint       // 用户ID（必须）
+	AccountID       *uint      // 账户ID（可选，nil 表示合并全部账户）
+	StartDate       *time.Time // 开始日期（可选）
+	EndDate         *time.Time // 结束日期（可选，不含）
+	IncludeHoldings bool       // 是否返回每天的持仓明细
+}
+
+// ==================== Domain 输出结构体 ====================
+
+// Holding 单只股票的持仓汇总
+// 成本采用移动加权平均法，手续费计入成本
+// 按交易币种列示时，同一股票以其他币种收取的分红、利息单独成行
+type Holding struct {
+	Symbol      string          // 股票代码
+	Currency    string          // 金额的币种
+	Name        string          // 股票名称（取最近一笔非空名称）
+	Quantity    decimal.Decimal // 当前持仓数量
+	TotalCost   decimal.Decimal // 当前持仓总成本（含买入手续费）
+	AverageCost decimal.Decimal // 平均成本 = 总成本 / 数量
+	RealizedPnL decimal.Decimal // 已实现盈亏（卖出净收入 - 对应成本）
+	Income      decimal.Decimal // 分红、利息收入（税前）
+	TotalFee    decimal.Decimal // 累计手续费（含费用类交易和预扣税）
+	TradeCount  int             // 交易笔数
+
+	// 估值：价格见 price.Domain.Marks（默认取历史行情库的收盘价），没有价格时为空
+	MarketPrice   *decimal.Decimal // 估值价格
+	MarketValue   *decimal.Decimal // 市值 = 数量 × 估值价格
+	UnrealizedPnL *decimal.Decimal // 未实现盈亏 = 市值 - 总成本
+	PriceDate     *time.Time       // 估值价格的日期
+	PriceSource   string           // 估值价格来源：bar / trade，见 price.PriceSource*
+}
+
+// HoldingsOutput 持仓汇总的输出结果
+// 合计始终按基准货币计算（交易币种各不相同时无法直接相加）
+type HoldingsOutput struct {
+	BaseCurrency     string          // 基准货币（合计的币种）
+	Holdings         []*Holding      // 按股票代码排序的持仓列表
+	TotalCost        decimal.Decimal // 全部持仓总成本
+	TotalRealizedPnL decimal.Decimal // 全部已实现盈亏
+	TotalIncome      decimal.Decimal // 全部分红、利息收入
+	TotalMarketValue decimal.Decimal // 全部持仓市值（不含没有估值价格的持仓）
+	TotalUnrealized  decimal.Decimal // 全部未实现盈亏（不含没有估值价格的持仓）
+}
+
+// HistoryHolding 某天收盘后的一只持仓
+type HistoryHolding struct {
+	Symbol        string          // 股票代码
+	Quantity      decimal.Decimal // 持仓数量
+	Price         decimal.Decimal // 估值价格（没有价格时为 0，按成本计价）
+	PriceCurrency string          // 估值价格的币种
+	MarketValue   decimal.Decimal // 市值（基准货币）
+	Cost          decimal.Decimal // 成本（基准货币）
+}
+
+// HistoryPoint 某天收盘后的估值（金额为基准货币）
+type HistoryPoint struct {
+	Date        time.Time
+	MarketValue decimal.Decimal   // 持仓市值
+	Cost        decimal.Decimal   // 持仓成本
+	Cash        decimal.Decimal   // 现金余额
+	NetDeposit  decimal.Decimal   // 当天外部资金净流入（入金 - 出金）
+	Holdings    []*HistoryHolding // 持仓明细（按股票代码排序，IncludeHoldings 时返回）
+}
+
+// HistoryOutput 估值历史的输出结果
+type HistoryOutput struct {
+	BaseCurrency string          // 基准货币
+	Points       []*HistoryPoint // 按日期正序，每个自然日一条
+}
+
+// ==================== Domain 接口定义 ====================
+// Service 层会依赖这个接口
+
+type Domain interface {
+	// Holdings 持仓汇总
+	// 核心业务逻辑：按股票代码回放交易流水，计算持仓数量、成本和已实现盈亏，再按估值价格计算市值；
+	// 换算为基准货币时每笔交易按交易日汇率换算，已实现盈亏中包含汇兑损益，市值按当天汇率换算
+	Holdings(input *HoldingsInput) (*HoldingsOutput, error)
+
+	// HeldSymbols 用户当前持有（数量不为 0）的股票代码，按代码排序
+	// 实时行情推送默认订阅这些股票
+	HeldSymbols(userID uint) ([]string, error)
+
+	// BuildSnapshots 补齐用户截止到 through（含）的每日估值快照，返回新生成的天数
+	// 从最近一天快照的次日（没有快照时从第一笔交易的日期）开始，每个账户每天一条；
+	// 交易变更会删除交易日期及之后的快照，提交后由 SnapshotQueue 异步重新生成。基准货币变更后全部重新生成
+	BuildSnapshots(userID uint, through time.Time) (int, error)
+
+	// RebuildSnapshots 删除用户的全部快照并重新生成（用于补导历史行情、汇率之后），返回生成的天数
+	RebuildSnapshots(userID uint, through time.Time) (int, error)
+
+	// SnapshotUserIDs 需要生成快照的用户（有交易记录的用户）
+	SnapshotUserIDs() ([]uint, error)
+
+	// History 估值历史（资产曲线）
+	// 只读取已生成的快照（由每日任务和交易写入后的异步队列补齐），不指定账户时把各账户同一天的快照相加
+	History(input *HistoryInput) (*HistoryOutput, error)
+}
+
+// SnapshotQueue 快照补齐队列
+// 交易写入提交后入队，由后台 goroutine 异步补齐，写入请求不等待快照生成
+type SnapshotQueue interface {
+	Enqueue(userID uint)
+}
@@ -0,0 +1,388 @@
+package impl
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	instrumentRepo "github.com/florentyang/smartfin-go/internal/dao/instrument"
+	priceBarRepo "github.com/florentyang/smartfin-go/internal/dao/pricebar"
+	fxDomain "github.com/florentyang/smartfin-go/internal/domain/fx"
+	instrumentDomain "github.com/florentyang/smartfin-go/internal/domain/instrument"
+	priceDomain "github.com/florentyang/smartfin-go/internal/domain/price"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== UseCase 结构体 ====================
+
+type usecase struct {
+	priceBarRepo     priceBarRepo.Repo       // 日线行情 DAO
+	instrumentRepo   instrumentRepo.Repo     // 证券 DAO（估值时按代码批量查找证券）
+	instrumentDomain instrumentDomain.Domain // 证券 Domain（导入、查询时解析股票代码）
+}
+
+// ==================== 构造函数 ====================
+
+// NewPriceDomain 创建 Domain 实例
+func NewPriceDomain(priceBarRepo priceBarRepo.Repo, instrumentRepo instrumentRepo.Repo, instrumentDomain instrumentDomain.Domain) priceDomain.Domain {
+	return &usecase{
+		priceBarRepo:     priceBarRepo,
+		instrumentRepo:   instrumentRepo,
+		instrumentDomain: instrumentDomain,
+	}
+}
+
+// ==================== 业务方法实现 ====================
+
+// Import 批量导入日线行情
+func (u *usecase) Import(input *priceDomain.ImportInput) (*priceDomain.ImportOutput, error) {
+	output := &priceDomain.ImportOutput{
+		Total: len(input.Bars),
+	}
+
+	// barKey 文件内去重的键：证券 + 日期
+	type barKey struct {
+		instrumentID uint
+		date         string
+	}
+
+	// 1. 逐行解析股票代码、校验并转换为实体（同一代码只解析一次）
+	instruments := make(map[string]*entity.Instrument)
+	seen := make(map[barKey]bool)
+	bars := make([]*entity.PriceBar, 0, len(input.Bars))
+	for i, row := range input.Bars {
+		instrument, ok := instruments[row.Symbol]
+		if !ok {
+			var err error
+			if instrument, err = u.resolve(row.Symbol); err != nil {
+				if !isBusinessError(err) {
+					return nil, err
+				}
+				output.Errors = append(output.Errors, &priceDomain.ImportRowError{Index: i, Err: err})
+				continue
+			}
+			instruments[row.Symbol] = instrument
+		}
+
+		bar, err := toBar(instrument.ID, row)
+		if err != nil {
+			output.Errors = append(output.Errors, &priceDomain.ImportRowError{Index: i, Err: err})
+			continue
+		}
+		key := barKey{instrumentID: bar.InstrumentID, date: bar.BarDate.Format("2006-01-02")}
+		if seen[key] {
+			output.Errors = append(output.Errors, &priceDomain.ImportRowError{Index: i, Err: priceDomain.ErrDuplicateBar})
+			continue
+		}
+		seen[key] = true
+		bars = append(bars, bar)
+	}
+	if len(output.Errors) > 0 || input.DryRun {
+		return output, nil
+	}
+
+	// 2. 全部通过才写入
+	if err := u.priceBarRepo.Upsert(bars); err != nil {
+		return nil, err
+	}
+	output.Committed = true
+	return output, nil
+}
+
+// List 查询某只证券的日线行情
+func (u *usecase) List(input *priceDomain.ListInput) (*priceDomain.ListOutput, error) {
+	// 1. 解析股票代码
+	instrument, err := u.resolve(input.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 日期统一按日历日期比较（与 date 列一致）
+	filter := &priceBarRepo.BarFilter{InstrumentID: instrument.ID}
+	if input.StartDate != nil {
+		start := fxDomain.DateOf(*input.StartDate)
+		filter.StartDate = &start
+	}
+	if input.EndDate != nil {
+		end := fxDomain.DateOf(*input.EndDate)
+		filter.EndDate = &end
+	}
+	bars, err := u.priceBarRepo.FindBars(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &priceDomain.ListOutput{Instrument: instrument, Bars: bars}, nil
+}
+
+// Marks 交易流水中各股票在 asOf 时点的估值价格
+func (u *usecase) Marks(ledger []*entity.Transaction, asOf time.Time) (map[string]*priceDomain.Mark, error) {
+	// 1. asOf 之前的最新成交价、持仓币种和拆股记录
+	state := scanTrades(ledger, asOf)
+	if len(state.symbols) == 0 {
+		return state.marks, nil
+	}
+
+	// 2. 行情库中 asOf 之前最近一个交易日的收盘价（asOf 当天 0 点时不含当天）
+	instruments, err := u.instrumentRepo.FindBySymbols(state.symbols)
+	if err != nil {
+		return nil, err
+	}
+	if len(instruments) == 0 {
+		return state.marks, nil
+	}
+	byID := make(map[uint]*entity.Instrument, len(instruments))
+	ids := make([]uint, len(instruments))
+	for i, instrument := range instruments {
+		byID[instrument.ID] = instrument
+		ids[i] = instrument.ID
+	}
+	bars, err := u.priceBarRepo.FindLatestOnOrBefore(ids, fxDomain.DateOf(asOf.Add(-time.Nanosecond)))
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 收盘价优先；币种不一致或已有更晚的成交时保留成交价
+	for _, bar := range bars {
+		state.applyBar(byID[bar.InstrumentID], bar)
+	}
+
+	return state.marks, nil
+}
+
+// NewMarker 创建 [from, through] 内逐日估值用的估值器
+// 证券和行情各一次查询加载到内存（from 之前最近的一条 + 范围内的全部），之后每次估值不再访问数据库
+func (u *usecase) NewMarker(ledger []*entity.Transaction, from, through time.Time) (priceDomain.Marker, error) {
+	m := &marker{
+		usecase:  u,
+		ledger:   ledger,
+		from:     from,
+		through:  through,
+		bySymbol: make(map[string]*entity.Instrument),
+		bars:     make(map[uint][]*entity.PriceBar),
+	}
+
+	// 1. through 之前出现过的股票
+	seen := make(map[string]bool)
+	var symbols []string
+	for _, tx := range ledger {
+		if !tx.TradeTime.Before(through) {
+			break
+		}
+		if tx.Symbol != "" && !seen[tx.Symbol] {
+			seen[tx.Symbol] = true
+			symbols = append(symbols, tx.Symbol)
+		}
+	}
+	if len(symbols) == 0 {
+		return m, nil
+	}
+
+	// 2. 证券主数据
+	instruments, err := u.instrumentRepo.FindBySymbols(symbols)
+	if err != nil {
+		return nil, err
+	}
+	if len(instruments) == 0 {
+		return m, nil
+	}
+	ids := make([]uint, len(instruments))
+	for i, instrument := range instruments {
+		m.bySymbol[instrument.Symbol] = instrument
+		ids[i] = instrument.ID
+	}
+
+	// 3. 第一天估值用的最近一条行情 + 之后到最后一天的全部行情（按证券、日期正序）
+	firstDay := fxDomain.DateOf(from.Add(-time.Nanosecond))
+	lastDay := fxDomain.DateOf(through.Add(-time.Nanosecond))
+	latest, err := u.priceBarRepo.FindLatestOnOrBefore(ids, firstDay)
+	if err != nil {
+		return nil, err
+	}
+	for _, bar := range latest {
+		m.bars[bar.InstrumentID] = append(m.bars[bar.InstrumentID], bar)
+	}
+	if lastDay.After(firstDay) {
+		bars, err := u.priceBarRepo.FindBarsIn(ids, firstDay.AddDate(0, 0, 1), lastDay.AddDate(0, 0, 1))
+		if err != nil {
+			return nil, err
+		}
+		for _, bar := range bars {
+			m.bars[bar.InstrumentID] = append(m.bars[bar.InstrumentID], bar)
+		}
+	}
+
+	return m, nil
+}
+
+// ==================== 估值器 ====================
+
+// marker 预加载了行情的估值器
+type marker struct {
+	usecase  *usecase
+	ledger   []*entity.Transaction
+	from     time.Time
+	through  time.Time
+	bySymbol map[string]*entity.Instrument // 股票代码 → 证券
+	bars     map[uint][]*entity.PriceBar   // 证券ID → 按日期正序的行情
+}
+
+// Marks 估值价格，规则与 Domain.Marks 相同；asOf 超出预加载范围时按 Domain.Marks 查询
+func (m *marker) Marks(asOf time.Time) (map[string]*priceDomain.Mark, error) {
+	if asOf.Before(m.from) || asOf.After(m.through) {
+		return m.usecase.Marks(m.ledger, asOf)
+	}
+
+	state := scanTrades(m.ledger, asOf)
+	day := fxDomain.DateOf(asOf.Add(-time.Nanosecond))
+	for _, symbol := range state.symbols {
+		instrument, ok := m.bySymbol[symbol]
+		if !ok {
+			continue
+		}
+		// 当天或之前最近的一条行情
+		bars := m.bars[instrument.ID]
+		i := sort.Search(len(bars), func(i int) bool {
+			return fxDomain.DateOf(bars[i].BarDate).After(day)
+		})
+		if i > 0 {
+			state.applyBar(instrument, bars[i-1])
+		}
+	}
+	return state.marks, nil
+}
+
+// ==================== 估值价格计算 ====================
+
+// tradeState 交易流水在 asOf 之前的成交价、持仓币种和拆股记录
+type tradeState struct {
+	marks           map[string]*priceDomain.Mark
+	holdingCurrency map[string]string
+	splits          map[string][]*entity.Transaction
+	symbols         []string // 按首次出现顺序
+}
+
+// scanTrades 扫描 asOf 之前的交易流水（按时间正序）
+func scanTrades(ledger []*entity.Transaction, asOf time.Time) *tradeState {
+	state := &tradeState{
+		marks:           make(map[string]*priceDomain.Mark),
+		holdingCurrency: make(map[string]string),
+		splits:          make(map[string][]*entity.Transaction),
+	}
+	for _, tx := range ledger {
+		if !tx.TradeTime.Before(asOf) {
+			break
+		}
+		if tx.Symbol == "" {
+			continue
+		}
+		if _, ok := state.holdingCurrency[tx.Symbol]; !ok {
+			state.symbols = append(state.symbols, tx.Symbol)
+			state.holdingCurrency[tx.Symbol] = ""
+		}
+		if state.holdingCurrency[tx.Symbol] == "" && entity.AffectsCostBasis(tx.Type) {
+			state.holdingCurrency[tx.Symbol] = tx.Currency
+		}
+
+		// 只取买卖成交价（转入单价是成本而非市价）；成交之后发生的拆股按比例复权
+		switch tx.Type {
+		case entity.TransactionTypeBuy, entity.TransactionTypeSell:
+			state.marks[tx.Symbol] = &priceDomain.Mark{
+				Price:    tx.Price,
+				Currency: tx.Currency,
+				Date:     fxDomain.DateOf(tx.TradeTime),
+				Source:   priceDomain.PriceSourceTrade,
+			}
+		case entity.TransactionTypeSplit:
+			state.splits[tx.Symbol] = append(state.splits[tx.Symbol], tx)
+			if mark, ok := state.marks[tx.Symbol]; ok {
+				mark.Price = mark.Price.Div(tx.Ratio).Round(4)
+			}
+		}
+	}
+	return state
+}
+
+// applyBar 用收盘价替换成交价；币种不一致或已有更晚的成交时保留成交价
+func (s *tradeState) applyBar(instrument *entity.Instrument, bar *entity.PriceBar) {
+	if currency := s.holdingCurrency[instrument.Symbol]; currency != "" && currency != instrument.Currency {
+		return
+	}
+	if trade, ok := s.marks[instrument.Symbol]; ok && trade.Date.After(bar.BarDate) {
+		return
+	}
+	price := bar.Close
+	for _, split := range s.splits[instrument.Symbol] {
+		// 拆股当天的收盘价已是拆股后的价格
+		if fxDomain.DateOf(split.TradeTime).After(bar.BarDate) {
+			price = price.Div(split.Ratio).Round(4)
+		}
+	}
+	s.marks[instrument.Symbol] = &priceDomain.Mark{
+		Price:    price,
+		Currency: instrument.Currency,
+		Date:     bar.BarDate,
+		Source:   priceDomain.PriceSourceBar,
+	}
+}
+
+// ==================== 私有辅助函数 ====================
+
+// resolve 按证券主数据解析股票代码，主数据中没有时返回 ErrInstrumentNotFound
+func (u *usecase) resolve(symbol string) (*entity.Instrument, error) {
+	resolution, err := u.instrumentDomain.Resolve(symbol, "")
+	if err != nil {
+		return nil, err
+	}
+	if resolution.Instrument == nil {
+		return nil, priceDomain.ErrInstrumentNotFound
+	}
+	return resolution.Instrument, nil
+}
+
+// isBusinessError 解析股票代码的错误是否为行级业务错误（其余为数据库错误）
+func isBusinessError(err error) bool {
+	return errors.Is(err, priceDomain.ErrInstrumentNotFound) || errors.Is(err, instrumentDomain.ErrInvalidSymbol)
+}
+
+// toBar 校验一行行情并转换为实体
+// 开盘、最高、最低价未提供时取收盘价；最高价不低于其余三价，最低价不高于其余三价
+func toBar(instrumentID uint, row *priceDomain.BarInput) (*entity.PriceBar, error) {
+	if row.Date.IsZero() {
+		return nil, priceDomain.ErrInvalidDate
+	}
+	if !row.Close.IsPositive() {
+		return nil, priceDomain.ErrInvalidPrice
+	}
+	open, high, low := row.Open, row.High, row.Low
+	if open.IsZero() {
+		open = row.Close
+	}
+	if high.IsZero() {
+		high = decimal.Max(open, row.Close)
+	}
+	if low.IsZero() {
+		low = decimal.Min(open, row.Close)
+	}
+	if !open.IsPositive() || !high.IsPositive() || !low.IsPositive() {
+		return nil, priceDomain.ErrInvalidPrice
+	}
+	if high.LessThan(decimal.Max(open, row.Close, low)) || low.GreaterThan(decimal.Min(open, row.Close)) {
+		return nil, priceDomain.ErrInvalidRange
+	}
+	if row.Volume.IsNegative() {
+		return nil, priceDomain.ErrInvalidVolume
+	}
+
+	return &entity.PriceBar{
+		InstrumentID: instrumentID,
+		BarDate:      fxDomain.DateOf(row.Date),
+		Open:         open,
+		High:         high,
+		Low:          low,
+		Close:        row.Close,
+		Volume:       row.Volume,
+	}, nil
+}
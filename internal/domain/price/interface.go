@@ -0,0 +1,111 @@
+package price
+
+import (
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 错误定义 ====================
+// 领域层的业务错误（中文方便调试）
+
+var (
+	ErrInstrumentNotFound = errors.New("证券主数据中没有该股票代码，请先创建证券")
+	ErrInvalidDate        = errors.New("行情日期不能为空")
+	ErrInvalidPrice       = errors.New("开盘、最高、最低、收盘价必须大于 0")
+	ErrInvalidRange       = errors.New("最高价不能低于开盘、收盘、最低价，最低价不能高于开盘、收盘价")
+	ErrInvalidVolume      = errors.New("成交量不能为负数")
+	ErrDuplicateBar       = errors.New("同一证券同一天的行情在文件中重复出现")
+)
+
+// 估值价格来源
+const (
+	PriceSourceBar   = "bar"   // 历史行情库的收盘价
+	PriceSourceTrade = "trade" // 交易流水中的最新成交价
+)
+
+// ==================== Domain 输入结构体 ====================
+
+// BarInput 一条待导入的日线行情
+// 价格为 0 时视为未提供：开盘价取收盘价，最高、最低价取开盘、收盘价中的较高、较低者
+type BarInput struct {
+	Symbol string          // 股票代码（按证券主数据解析，支持 ISIN）
+	Date   time.Time       // 交易日期
+	Open   decimal.Decimal // 开盘价
+	High   decimal.Decimal // 最高价
+	Low    decimal.Decimal // 最低价
+	Close  decimal.Decimal // 收盘价（必须）
+	Volume decimal.Decimal // 成交量
+}
+
+// ImportInput 批量导入日线行情的输入参数
+type ImportInput struct {
+	Bars   []*BarInput // 按文件顺序的待导入行情
+	DryRun bool        // 试运行：只校验不写入
+}
+
+// ListInput 查询日线行情的输入参数
+type ListInput struct {
+	Symbol    string     // 股票代码（必须）
+	StartDate *time.Time // 开始日期（可选）
+	EndDate   *time.Time // 结束日期（可选，不含）
+}
+
+// ==================== Domain 输出结构体 ====================
+
+// ImportRowError 单行导入错误
+type ImportRowError struct {
+	Index int   // 行下标（对应 ImportInput.Bars）
+	Err   error // 业务错误
+}
+
+// ImportOutput 批量导入日线行情的输出结果
+type ImportOutput struct {
+	Total     int               // 总行数
+	Committed bool              // 是否已写入（存在错误行或试运行时不写入）
+	Errors    []*ImportRowError // 错误行
+}
+
+// ListOutput 日线行情查询结果
+type ListOutput struct {
+	Instrument *entity.Instrument // 证券主数据
+	Bars       []*entity.PriceBar // 按日期正序的日线行情
+}
+
+// Mark 某只股票在估值时点的价格
+type Mark struct {
+	Price    decimal.Decimal // 估值价格（已按之后发生的拆股复权）
+	Currency string          // 价格的币种
+	Date     time.Time       // 价格日期（行情日期或成交日期）
+	Source   string          // 价格来源：bar / trade，见 PriceSource*
+}
+
+// ==================== Domain 接口定义 ====================
+// Service 层和持仓、报表 Domain 会依赖这个接口
+
+type Domain interface {
+	// Import 批量导入日线行情
+	// 逐行校验，全部通过才写入；同一证券同一天已存在时覆盖
+	Import(input *ImportInput) (*ImportOutput, error)
+
+	// List 查询某只证券的日线行情（按日期正序）
+	List(input *ListInput) (*ListOutput, error)
+
+	// Marks 估值价格：交易流水（按时间正序）中各股票在 asOf 时点的价格
+	// 默认取行情库中 asOf 之前最近一个交易日的收盘价；没有行情、行情币种与持仓币种不一致，
+	// 或之后还有更新的成交时，取 asOf 之前的最新成交价
+	Marks(ledger []*entity.Transaction, asOf time.Time) (map[string]*Mark, error)
+
+	// NewMarker 逐日估值用的估值器：交易流水中各股票 [from, through] 内的行情一次加载，
+	// 之后每天的估值在内存中计算（快照生成、报表按月估值使用）
+	NewMarker(ledger []*entity.Transaction, from, through time.Time) (Marker, error)
+}
+
+// Marker 预加载了行情的估值器
+type Marker interface {
+	// Marks 估值价格，规则与 Domain.Marks 相同，asOf 应在创建时的 [from, through] 内
+	Marks(asOf time.Time) (map[string]*Mark, error)
+}
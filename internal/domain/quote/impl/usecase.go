@@ -0,0 +1,172 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/florentyang/smartfin-go/internal/config"
+	instrumentDomain "github.com/florentyang/smartfin-go/internal/domain/instrument"
+	quoteDomain "github.com/florentyang/smartfin-go/internal/domain/quote"
+	"github.com/florentyang/smartfin-go/internal/quote"
+)
+
+// ==================== UseCase 结构体 ====================
+
+type usecase struct {
+	provider   quote.Provider // 行情源
+	workers    int            // 最大并发数
+	timeout    time.Duration  // 单只股票的查询超时
+	maxSymbols int            // 单次最多查询的股票数
+}
+
+// ==================== 构造函数 ====================
+
+// NewQuoteDomain 创建 Domain 实例
+// 配置项不合法时使用默认配置中的值
+func NewQuoteDomain(provider quote.Provider, cfg *config.QuoteConfig) quoteDomain.Domain {
+	defaults := config.DefaultQuoteConfig()
+	u := &usecase{
+		provider:   provider,
+		workers:    cfg.Workers,
+		timeout:    cfg.Timeout,
+		maxSymbols: cfg.MaxSymbols,
+	}
+	if u.workers <= 0 {
+		u.workers = defaults.Workers
+	}
+	if u.timeout <= 0 {
+		u.timeout = defaults.Timeout
+	}
+	if u.maxSymbols <= 0 {
+		u.maxSymbols = defaults.MaxSymbols
+	}
+	return u
+}
+
+// ==================== 业务方法实现 ====================
+
+// slot 一只股票的查询任务和结果（每个 worker 只写自己领到的 slot，无需加锁）
+type slot struct {
+	symbol string
+	quote  *quote.Quote
+	err    error
+}
+
+// Fetch 批量查询最新行情
+func (u *usecase) Fetch(ctx context.Context, symbols []string) (*quoteDomain.FetchOutput, error) {
+	// 1. 规范化并去重（无效的代码直接记为错误，不请求行情源）
+	var slots []*slot
+	seen := make(map[string]bool, len(symbols))
+	for _, raw := range symbols {
+		symbol, err := instrumentDomain.NormalizeSymbol(raw, "")
+		if err != nil {
+			slots = append(slots, &slot{symbol: raw, err: err})
+			continue
+		}
+		if seen[symbol] {
+			continue
+		}
+		seen[symbol] = true
+		slots = append(slots, &slot{symbol: symbol})
+	}
+	if len(slots) == 0 {
+		return nil, quoteDomain.ErrNoSymbols
+	}
+	if len(slots) > u.maxSymbols {
+		return nil, fmt.Errorf("%w（最多 %d 个）", quoteDomain.ErrTooManySymbols, u.maxSymbols)
+	}
+
+	// 2. 待查询的任务
+	jobs := make(chan *slot, len(slots))
+	for _, s := range slots {
+		if s.err == nil {
+			jobs <- s
+		}
+	}
+	close(jobs)
+
+	// 3. 固定数量的 worker 并发查询，并发数不超过任务数
+	workers := u.workers
+	if workers > len(jobs) {
+		workers = len(jobs)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for s := range jobs {
+				s.quote, s.err = u.fetchOne(ctx, s.symbol)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 4. 按请求顺序汇总成功和失败的股票
+	output := &quoteDomain.FetchOutput{}
+	for _, s := range slots {
+		if s.err != nil {
+			output.Errors = append(output.Errors, &quoteDomain.FetchError{Symbol: s.symbol, Err: s.err})
+			continue
+		}
+		output.Quotes = append(output.Quotes, s.quote)
+	}
+	return output, nil
+}
+
+// ==================== 私有辅助函数 ====================
+
+// fetchResult 行情源的返回结果
+type fetchResult struct {
+	quote *quote.Quote
+	err   error
+}
+
+// fetchOne 查询一只股票，超时或 ctx 取消时立即返回
+// 行情源在单独的 goroutine 中调用：即使行情源不响应 ctx 也不会拖住 worker，
+// 行情源 panic 时转换为错误，不影响其他股票和整个进程
+func (u *usecase) fetchOne(ctx context.Context, symbol string) (*quote.Quote, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, u.timeout)
+	defer cancel()
+
+	// 带缓冲：超时返回后，行情源 goroutine 结束时写入结果也不会阻塞
+	done := make(chan fetchResult, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fetchResult{err: fmt.Errorf("%w: %v", quoteDomain.ErrProviderPanic, r)}
+			}
+		}()
+		q, err := u.provider.Fetch(ctx, symbol)
+		done <- fetchResult{quote: q, err: err}
+	}()
+
+	var result fetchResult
+	select {
+	case result = <-done:
+	case <-ctx.Done():
+		result = fetchResult{err: ctx.Err()}
+	}
+
+	if errors.Is(result.err, context.DeadlineExceeded) {
+		return nil, fmt.Errorf("%w（%s）", quoteDomain.ErrFetchTimeout, u.timeout)
+	}
+	if result.err != nil {
+		return nil, result.err
+	}
+	if result.quote == nil {
+		return nil, quote.ErrQuoteNotFound
+	}
+	result.quote.Symbol = symbol
+	if result.quote.Source == "" {
+		result.quote.Source = u.provider.Name()
+	}
+	return result.quote, nil
+}
@@ -0,0 +1,43 @@
+package quote
+
+import (
+	"context"
+	"errors"
+
+	"github.com/florentyang/smartfin-go/internal/quote"
+)
+
+// ==================== 错误定义 ====================
+// 领域层的业务错误（中文方便调试）
+
+var (
+	ErrNoSymbols      = errors.New("请至少填写一个股票代码")
+	ErrTooManySymbols = errors.New("单次查询的股票数超过上限")
+	ErrFetchTimeout   = errors.New("行情查询超时")
+	ErrProviderPanic  = errors.New("行情源内部错误")
+)
+
+// ==================== Domain 输出结构体 ====================
+
+// FetchError 单只股票的查询错误
+type FetchError struct {
+	Symbol string // 规范化的股票代码（代码本身无效时为原始输入）
+	Err    error  // 错误原因
+}
+
+// FetchOutput 批量查询行情的结果
+// 部分股票失败不影响其他股票，失败的股票放在 Errors 中
+type FetchOutput struct {
+	Quotes []*quote.Quote // 查询成功的行情（按请求顺序）
+	Errors []*FetchError  // 查询失败的股票（按请求顺序）
+}
+
+// ==================== Domain 接口定义 ====================
+// Service 层会依赖这个接口
+
+type Domain interface {
+	// Fetch 批量查询最新行情
+	// 股票代码先规范化并去重，再以有限的并发数同时向行情源查询，每只股票单独计时；
+	// ctx 取消时尚未完成的查询立即返回
+	Fetch(ctx context.Context, symbols []string) (*FetchOutput, error)
+}
@@ -0,0 +1,585 @@
+package impl
+
+import (
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	lotRepo "github.com/florentyang/smartfin-go/internal/dao/lot"
+	txRepo "github.com/florentyang/smartfin-go/internal/dao/transaction"
+	userRepo "github.com/florentyang/smartfin-go/internal/dao/user"
+	fxDomain "github.com/florentyang/smartfin-go/internal/domain/fx"
+	lotDomain "github.com/florentyang/smartfin-go/internal/domain/lot"
+	priceDomain "github.com/florentyang/smartfin-go/internal/domain/price"
+	reportDomain "github.com/florentyang/smartfin-go/internal/domain/report"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== UseCase 结构体 ====================
+
+type usecase struct {
+	txRepo      txRepo.Repo        // 交易 DAO（读取交易流水）
+	lotRepo     lotRepo.Repo       // 批次 DAO（读取已实现盈亏）
+	userRepo    userRepo.Repo      // 用户 DAO（读取基准货币）
+	lotDomain   lotDomain.Domain   // 批次 Domain（回放历史时点的未平仓批次）
+	fxDomain    fxDomain.Domain    // 汇率 Domain（换算为基准货币）
+	priceDomain priceDomain.Domain // 历史行情 Domain（估值价格）
+}
+
+// ==================== 构造函数 ====================
+
+// NewReportDomain 创建 Domain 实例
+func NewReportDomain(txRepo txRepo.Repo, lotRepo lotRepo.Repo, userRepo userRepo.Repo, lotDomain lotDomain.Domain, fxDomain fxDomain.Domain, priceDomain priceDomain.Domain) reportDomain.Domain {
+	return &usecase{
+		txRepo:      txRepo,
+		lotRepo:     lotRepo,
+		userRepo:    userRepo,
+		lotDomain:   lotDomain,
+		fxDomain:    fxDomain,
+		priceDomain: priceDomain,
+	}
+}
+
+// ==================== 业务方法实现 ====================
+
+// PnL 盈亏报表
+func (u *usecase) PnL(input *reportDomain.PnLInput) (*reportDomain.PnLOutput, error) {
+	// 1. 校验参数
+	if input.GroupBy != reportDomain.GroupBySymbol && input.GroupBy != reportDomain.GroupByMonth {
+		return nil, reportDomain.ErrInvalidGroupBy
+	}
+	if input.Currency == "" {
+		input.Currency = fxDomain.ReportCurrencyBase
+	}
+	if input.Currency != fxDomain.ReportCurrencyBase && input.Currency != fxDomain.ReportCurrencyTrade {
+		return nil, reportDomain.ErrInvalidCurrencyMode
+	}
+	if input.Currency == fxDomain.ReportCurrencyTrade && input.GroupBy == reportDomain.GroupByMonth {
+		return nil, reportDomain.ErrTradeCurrencyByMonth
+	}
+	asOf := time.Now()
+	if input.EndTime != nil {
+		asOf = *input.EndTime
+	}
+	if input.StartTime != nil && input.StartTime.After(asOf) {
+		return nil, reportDomain.ErrInvalidPeriod
+	}
+
+	// 2. 确定金额换算方式：合计始终按基准货币，明细行按请求的报表币种
+	user, err := u.userRepo.GetByID(input.UserID)
+	if err != nil {
+		return nil, err
+	}
+	baseMoney := money{converter: u.fxDomain.NewConverter(fxDomain.BaseCurrencyOf(user))}
+	rowMoney := baseMoney
+	if input.Currency == fxDomain.ReportCurrencyTrade {
+		rowMoney = money{}
+	}
+
+	// 3. 期间已实现盈亏（按卖出时间筛选批次分配记录）
+	assignments, err := u.lotRepo.FindAssignments(&lotRepo.AssignmentFilter{
+		UserID:    input.UserID,
+		AccountID: input.AccountID,
+		StartTime: input.StartTime,
+		EndTime:   &asOf,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. 截至期末的交易流水（全部账户，用于取估值价格；收入和费用按账户筛选）
+	ledger, err := u.txRepo.FindLedger(&txRepo.LedgerFilter{
+		UserID:  input.UserID,
+		EndTime: &asOf,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 5. 估值时点：期初（指定开始时间时）、各月末（按月分组时）和期末
+	// 一次回放批次、一次加载行情，得到每个时点的未平仓批次和估值价格
+	var months []time.Time
+	if input.GroupBy == reportDomain.GroupByMonth {
+		months = monthEnds(input.AccountID, input.StartTime, asOf, ledger)
+	}
+	var times []time.Time
+	if input.StartTime != nil {
+		times = append(times, *input.StartTime)
+	}
+	times = append(times, months...)
+	if len(months) == 0 || !months[len(months)-1].Equal(asOf) {
+		times = append(times, asOf)
+	}
+	points, err := u.checkpoints(input.UserID, input.AccountID, times, ledger)
+	if err != nil {
+		return nil, err
+	}
+	var startPoint *checkpoint
+	if input.StartTime != nil {
+		startPoint = points[0]
+	}
+	endPoint := points[len(points)-1]
+
+	// 6. 按分组方式生成明细行
+	var rows []*reportDomain.PnLRow
+	if input.GroupBy == reportDomain.GroupBySymbol {
+		rows, err = rowsBySymbol(input.AccountID, input.StartTime, asOf, assignments, ledger, startPoint, endPoint, rowMoney)
+	} else {
+		monthPoints := points
+		if startPoint != nil {
+			monthPoints = points[1:]
+		}
+		rows, err = rowsByMonth(input.AccountID, assignments, ledger, startPoint, monthPoints[:len(months)], rowMoney)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 7. 汇总（基准货币）：已实现、收入、费用取期间合计，未实现取期末值及相对期初的变动
+	output := &reportDomain.PnLOutput{
+		GroupBy:               input.GroupBy,
+		BaseCurrency:          baseMoney.converter.Currency(),
+		AsOf:                  asOf,
+		TotalRealized:         decimal.Zero,
+		TotalUnrealized:       decimal.Zero,
+		TotalUnrealizedChange: decimal.Zero,
+		TotalIncome:           decimal.Zero,
+		TotalExpenses:         decimal.Zero,
+		Rows:                  rows,
+	}
+	for _, a := range assignments {
+		gain, err := baseMoney.realized(a)
+		if err != nil {
+			return nil, err
+		}
+		output.TotalRealized = output.TotalRealized.Add(gain)
+	}
+	for _, tx := range ledger {
+		if inAccount(tx, input.AccountID) && inPeriod(tx.TradeTime, input.StartTime, asOf) {
+			income, expense, err := baseMoney.cashPnL(tx)
+			if err != nil {
+				return nil, err
+			}
+			output.TotalIncome = output.TotalIncome.Add(income)
+			output.TotalExpenses = output.TotalExpenses.Add(expense)
+		}
+	}
+	endValues, err := endPoint.valuate(baseMoney)
+	if err != nil {
+		return nil, err
+	}
+	output.TotalUnrealized = sumUnrealized(endValues)
+	output.TotalUnrealizedChange = output.TotalUnrealized
+	if startPoint != nil {
+		startValues, err := startPoint.valuate(baseMoney)
+		if err != nil {
+			return nil, err
+		}
+		output.TotalUnrealizedChange = output.TotalUnrealized.Sub(sumUnrealized(startValues))
+	}
+	output.TotalPnL = output.TotalRealized.Add(output.TotalUnrealizedChange).
+		Add(output.TotalIncome).Sub(output.TotalExpenses)
+
+	return output, nil
+}
+
+// ==================== 私有辅助函数 ====================
+
+// rowKey 明细行的分组键：股票代码（或 CASH）+ 币种
+type rowKey struct {
+	key      string
+	currency string
+}
+
+// valuation 单只股票在某个时点的估值
+type valuation struct {
+	quantity    decimal.Decimal
+	costBasis   decimal.Decimal
+	price       decimal.Decimal
+	marketValue decimal.Decimal
+	unrealized  decimal.Decimal
+}
+
+// rowsBySymbol 按股票代码分组
+// 未实现变动 = 期末未实现 - 期初未实现（未指定开始时间时期初为 0）
+func rowsBySymbol(accountID *uint, startTime *time.Time, asOf time.Time, assignments []*entity.LotAssignment, ledger []*entity.Transaction, startPoint, endPoint *checkpoint, m money) ([]*reportDomain.PnLRow, error) {
+	rowMap := make(map[rowKey]*reportDomain.PnLRow)
+	getRow := func(key rowKey) *reportDomain.PnLRow {
+		row, ok := rowMap[key]
+		if !ok {
+			row = &reportDomain.PnLRow{
+				Key:              key.key,
+				Currency:         key.currency,
+				Realized:         decimal.Zero,
+				Unrealized:       decimal.Zero,
+				UnrealizedChange: decimal.Zero,
+				Income:           decimal.Zero,
+				Expenses:         decimal.Zero,
+				Quantity:         decimal.Zero,
+				CostBasis:        decimal.Zero,
+				MarketPrice:      decimal.Zero,
+				MarketValue:      decimal.Zero,
+			}
+			rowMap[key] = row
+		}
+		return row
+	}
+
+	// 1. 已实现盈亏
+	for _, a := range assignments {
+		gain, err := m.realized(a)
+		if err != nil {
+			return nil, err
+		}
+		row := getRow(rowKey{key: a.Symbol, currency: m.currency(a.Currency)})
+		row.Realized = row.Realized.Add(gain)
+	}
+
+	// 2. 期间收入和费用（没有股票代码的归入现金行）
+	for _, tx := range ledger {
+		if !inAccount(tx, accountID) || !inPeriod(tx.TradeTime, startTime, asOf) {
+			continue
+		}
+		income, expense, err := m.cashPnL(tx)
+		if err != nil {
+			return nil, err
+		}
+		if income.IsZero() && expense.IsZero() {
+			continue
+		}
+		key := tx.Symbol
+		if key == "" {
+			key = reportDomain.CashKey
+		}
+		row := getRow(rowKey{key: key, currency: m.currency(tx.Currency)})
+		row.Income = row.Income.Add(income)
+		row.Expenses = row.Expenses.Add(expense)
+	}
+
+	// 3. 期末未实现盈亏，以及相对期初的变动
+	values, err := endPoint.valuate(m)
+	if err != nil {
+		return nil, err
+	}
+	for key, v := range values {
+		row := getRow(key)
+		row.Unrealized = v.unrealized
+		row.UnrealizedChange = v.unrealized
+		row.Quantity = v.quantity
+		row.CostBasis = v.costBasis
+		row.MarketPrice = v.price
+		row.MarketValue = v.marketValue
+	}
+	if startPoint != nil {
+		startValues, err := startPoint.valuate(m)
+		if err != nil {
+			return nil, err
+		}
+		for key, v := range startValues {
+			row := getRow(key)
+			row.UnrealizedChange = row.UnrealizedChange.Sub(v.unrealized)
+		}
+	}
+
+	// 4. 合计并排序
+	rows := make([]*reportDomain.PnLRow, 0, len(rowMap))
+	for _, row := range rowMap {
+		row.PnL = row.Realized.Add(row.UnrealizedChange).Add(row.Income).Sub(row.Expenses)
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Key != rows[j].Key {
+			return rows[i].Key < rows[j].Key
+		}
+		return rows[i].Currency < rows[j].Currency
+	})
+
+	return rows, nil
+}
+
+// monthEnds 按月分组的各月末时点（下个月 1 号 0 点，最后一个月为期末）
+// 期初未指定时从（该账户的）第一笔交易开始，没有交易时返回空
+func monthEnds(accountID *uint, startTime *time.Time, asOf time.Time, ledger []*entity.Transaction) []time.Time {
+	var start time.Time
+	if startTime != nil {
+		start = *startTime
+	} else if first := firstInAccount(ledger, accountID); first != nil {
+		start = first.TradeTime
+	} else {
+		return nil
+	}
+
+	var ends []time.Time
+	for periodStart := start; periodStart.Before(asOf); {
+		periodEnd := time.Date(periodStart.Year(), periodStart.Month()+1, 1, 0, 0, 0, 0, periodStart.Location())
+		if periodEnd.After(asOf) {
+			periodEnd = asOf
+		}
+		ends = append(ends, periodEnd)
+		periodStart = periodEnd
+	}
+	return ends
+}
+
+// rowsByMonth 按月份分组（金额均为基准货币）
+// 每个月：已实现 = 当月卖出的已实现盈亏；未实现变动 = 月末未实现 - 上月末（或期初）未实现；
+// 收入、费用 = 当月现金类交易。months 为各月末的估值时点（见 monthEnds），期初未指定时第一个月的基准为 0
+func rowsByMonth(accountID *uint, assignments []*entity.LotAssignment, ledger []*entity.Transaction, startPoint *checkpoint, months []*checkpoint, m money) ([]*reportDomain.PnLRow, error) {
+	if len(months) == 0 {
+		return []*reportDomain.PnLRow{}, nil
+	}
+
+	// 1. 期初未实现盈亏（作为第一个月的基准）和第一个月的开始时间
+	prevUnrealized := decimal.Zero
+	var periodStart time.Time
+	if startPoint != nil {
+		values, err := startPoint.valuate(m)
+		if err != nil {
+			return nil, err
+		}
+		prevUnrealized = sumUnrealized(values)
+		periodStart = startPoint.at
+	} else {
+		periodStart = firstInAccount(ledger, accountID).TradeTime
+	}
+
+	// 2. 逐月生成
+	rows := make([]*reportDomain.PnLRow, 0, len(months))
+	for _, point := range months {
+		periodEnd := point.at
+		row := &reportDomain.PnLRow{
+			Key:         periodStart.Format("2006-01"),
+			Currency:    m.currency(""),
+			Realized:    decimal.Zero,
+			Income:      decimal.Zero,
+			Expenses:    decimal.Zero,
+			Quantity:    decimal.Zero,
+			CostBasis:   decimal.Zero,
+			MarketPrice: decimal.Zero,
+			MarketValue: decimal.Zero,
+		}
+
+		// 2.1 当月已实现盈亏
+		for _, a := range assignments {
+			if !a.CloseTime.Before(periodStart) && a.CloseTime.Before(periodEnd) {
+				gain, err := m.realized(a)
+				if err != nil {
+					return nil, err
+				}
+				row.Realized = row.Realized.Add(gain)
+			}
+		}
+
+		// 2.2 当月收入和费用
+		for _, tx := range ledger {
+			if inAccount(tx, accountID) && !tx.TradeTime.Before(periodStart) && tx.TradeTime.Before(periodEnd) {
+				income, expense, err := m.cashPnL(tx)
+				if err != nil {
+					return nil, err
+				}
+				row.Income = row.Income.Add(income)
+				row.Expenses = row.Expenses.Add(expense)
+			}
+		}
+
+		// 2.3 月末未实现盈亏及变动
+		values, err := point.valuate(m)
+		if err != nil {
+			return nil, err
+		}
+		row.Unrealized = sumUnrealized(values)
+		row.UnrealizedChange = row.Unrealized.Sub(prevUnrealized)
+		row.PnL = row.Realized.Add(row.UnrealizedChange).Add(row.Income).Sub(row.Expenses)
+		prevUnrealized = row.Unrealized
+
+		rows = append(rows, row)
+		periodStart = periodEnd
+	}
+
+	return rows, nil
+}
+
+// ==================== 估值时点 ====================
+
+// checkpoint 某个时点的未平仓批次和估值价格
+type checkpoint struct {
+	at    time.Time
+	lots  []*entity.TaxLot
+	marks map[string]*priceDomain.Mark
+}
+
+// checkpoints 各时点（按时间正序）的未平仓批次和估值价格
+// 批次一次回放得到（OpenLotsSeries），行情一次加载（NewMarker），不随时点数量增加查询次数
+func (u *usecase) checkpoints(userID uint, accountID *uint, times []time.Time, ledger []*entity.Transaction) ([]*checkpoint, error) {
+	series, err := u.lotDomain.OpenLotsSeries(userID, accountID, times)
+	if err != nil {
+		return nil, err
+	}
+	marker, err := u.priceDomain.NewMarker(ledger, times[0], times[len(times)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]*checkpoint, len(times))
+	for i, at := range times {
+		marks, err := marker.Marks(at)
+		if err != nil {
+			return nil, err
+		}
+		points[i] = &checkpoint{at: at, lots: series[i], marks: marks}
+	}
+	return points, nil
+}
+
+// valuate 计算该时点各股票的未实现盈亏
+// 估值价格默认取行情库中该时点之前最近的收盘价，没有时取最新成交价；
+// 换算为基准货币时，成本按开仓日汇率，价格和市值按该时点汇率
+func (c *checkpoint) valuate(m money) (map[rowKey]*valuation, error) {
+	// 1. 按股票汇总（数量和市值先按交易币种累计）
+	values := make(map[rowKey]*valuation)
+	lotCurrency := make(map[rowKey]string)
+	for _, lot := range c.lots {
+		key := rowKey{key: lot.Symbol, currency: m.currency(lot.Currency)}
+		v, ok := values[key]
+		if !ok {
+			v = &valuation{
+				quantity:  decimal.Zero,
+				costBasis: decimal.Zero,
+				price:     decimal.Zero,
+			}
+			if mark, ok := c.marks[lot.Symbol]; ok {
+				v.price = mark.Price
+			}
+			values[key] = v
+			lotCurrency[key] = lot.Currency
+		}
+		cost, err := m.convert(lot.RemainingCost, lot.Currency, lot.OpenTime)
+		if err != nil {
+			return nil, err
+		}
+		v.quantity = v.quantity.Add(lot.RemainingQuantity)
+		v.costBasis = v.costBasis.Add(cost)
+	}
+
+	// 2. 市值和价格按该时点汇率换算
+	for key, v := range values {
+		marketValue, err := m.convert(v.quantity.Mul(v.price).Round(4), lotCurrency[key], c.at)
+		if err != nil {
+			return nil, err
+		}
+		price, err := m.convert(v.price, lotCurrency[key], c.at)
+		if err != nil {
+			return nil, err
+		}
+		v.price = price
+		v.marketValue = marketValue
+		v.unrealized = v.marketValue.Sub(v.costBasis)
+	}
+
+	return values, nil
+}
+
+// money 报表金额的币种视图
+// converter 为空表示按交易币种列示（不换算），否则换算为 converter 的目标货币
+type money struct {
+	converter fxDomain.Converter
+}
+
+// currency 金额列示的币种：不换算时为交易本身的币种
+func (m money) currency(own string) string {
+	if m.converter != nil {
+		return m.converter.Currency()
+	}
+	if own == "" {
+		return entity.DefaultCurrency
+	}
+	return own
+}
+
+// convert 按 at 当天的汇率换算金额（不换算时原样返回）
+func (m money) convert(amount decimal.Decimal, currency string, at time.Time) (decimal.Decimal, error) {
+	if m.converter == nil || amount.IsZero() {
+		return amount, nil
+	}
+	return m.converter.Convert(amount, currency, at)
+}
+
+// realized 批次分配记录的已实现盈亏
+// 换算时卖出收入按卖出日汇率、成本按开仓日汇率，差额中包含汇兑损益
+func (m money) realized(a *entity.LotAssignment) (decimal.Decimal, error) {
+	if m.converter == nil {
+		return a.RealizedGain, nil
+	}
+	proceeds, err := m.convert(a.Proceeds, a.Currency, a.CloseTime)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	cost, err := m.convert(a.CostBasis, a.Currency, a.OpenTime)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return proceeds.Sub(cost), nil
+}
+
+// cashPnL 交易的收入和费用，按交易日汇率换算
+func (m money) cashPnL(tx *entity.Transaction) (income, expense decimal.Decimal, err error) {
+	income, expense = cashPnL(tx)
+	if income, err = m.convert(income, tx.Currency, tx.TradeTime); err != nil {
+		return decimal.Zero, decimal.Zero, err
+	}
+	if expense, err = m.convert(expense, tx.Currency, tx.TradeTime); err != nil {
+		return decimal.Zero, decimal.Zero, err
+	}
+	return income, expense, nil
+}
+
+// cashPnL 交易带来的收入和费用（不含已计入批次成本或卖出收入的部分）
+// - 分红、利息：收入 = 金额 - 预扣税
+// - 费用：费用 = 金额 + 手续费
+// - 买入、卖出、转入：手续费已计入成本或冲减收入，这里不重复计算
+// - 其他（转出、拆股、出入金）：手续费计为费用
+func cashPnL(tx *entity.Transaction) (income, expense decimal.Decimal) {
+	switch tx.Type {
+	case entity.TransactionTypeDividend, entity.TransactionTypeInterest:
+		return tx.Amount.Sub(tx.Fee), decimal.Zero
+	case entity.TransactionTypeFee:
+		return decimal.Zero, tx.Amount.Add(tx.Fee)
+	case entity.TransactionTypeBuy, entity.TransactionTypeSell, entity.TransactionTypeTransferIn:
+		return decimal.Zero, decimal.Zero
+	default:
+		return decimal.Zero, tx.Fee
+	}
+}
+
+// inAccount 交易是否属于报表的账户范围（accountID 为 nil 表示全部账户）
+func inAccount(tx *entity.Transaction, accountID *uint) bool {
+	return accountID == nil || tx.AccountID == *accountID
+}
+
+// firstInAccount 账户范围内的第一笔交易（ledger 已按时间正序），没有时返回 nil
+func firstInAccount(ledger []*entity.Transaction, accountID *uint) *entity.Transaction {
+	for _, tx := range ledger {
+		if inAccount(tx, accountID) {
+			return tx
+		}
+	}
+	return nil
+}
+
+// inPeriod 时间是否在报表期间内：[startTime, asOf)，startTime 为空表示不限
+func inPeriod(t time.Time, startTime *time.Time, asOf time.Time) bool {
+	if startTime != nil && t.Before(*startTime) {
+		return false
+	}
+	return t.Before(asOf)
+}
+
+// sumUnrealized 未实现盈亏合计
+func sumUnrealized(values map[rowKey]*valuation) decimal.Decimal {
+	total := decimal.Zero
+	for _, v := range values {
+		total = total.Add(v.unrealized)
+	}
+	return total
+}
@@ -0,0 +1,89 @@
+package report
+
+import (
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ==================== 错误定义 ====================
+// 领域层的业务错误（中文方便调试）
+
+var (
+	ErrInvalidGroupBy = errors.New("分组方式无效，必须是 symbol 或 month")
+	ErrInvalidPeriod  = errors.New("开始日期不能晚于结束日期")
+
+	ErrInvalidCurrencyMode  = errors.New("报表币种无效，必须是 base 或 trade")
+	ErrTradeCurrencyByMonth = errors.New("按月分组只支持换算为基准货币（currency=base）")
+)
+
+// 分组方式常量
+const (
+	GroupBySymbol = "symbol" // 按股票代码分组
+	GroupByMonth  = "month"  // 按月份分组
+)
+
+// CashKey 按股票分组时，没有股票代码的现金交易（账户利息、费用等）归入这一行
+const CashKey = "CASH"
+
+// ==================== Domain 输入结构体 ====================
+
+// PnLInput 盈亏报表的输入参数
+type PnLInput struct {
+	UserID    uint       // 用户ID（必须）
+	AccountID *uint      // 账户ID（可选，nil 表示合并全部账户，0 表示未指定账户的交易）
+	StartTime *time.Time // 开始时间（可选，不传则从第一笔交易开始）
+	EndTime   *time.Time // 结束时间（可选，不含；不传则到当前时间）
+	GroupBy   string     // 分组方式：symbol/month
+	Currency  string     // 报表币种：base（默认）/ trade，见 fx.ReportCurrency*
+}
+
+// ==================== Domain 输出结构体 ====================
+
+// PnLRow 盈亏报表的一行
+// PnL = Realized + UnrealizedChange + Income - Expenses，未实现部分始终取期间变动：
+// 按股票分组时 Unrealized 为期末未实现盈亏，UnrealizedChange = 期末 - 期初（未指定开始时间时期初为 0）；
+// 按月分组时 Unrealized 为月末未实现盈亏，UnrealizedChange = 月末 - 上月末（第一个月为期初）
+// 按交易币种列示时，同一股票以其他币种收取的分红等单独成行
+type PnLRow struct {
+	Key              string          // 股票代码或月份（2024-01）
+	Currency         string          // 金额的币种
+	Realized         decimal.Decimal // 期间已实现盈亏（按卖出时间归属）
+	Unrealized       decimal.Decimal // 期末未实现盈亏
+	UnrealizedChange decimal.Decimal // 期间（当月）未实现盈亏变动
+	Income           decimal.Decimal // 期间分红、利息净收入（扣除预扣税）
+	Expenses         decimal.Decimal // 期间费用（费用类交易，以及转出、出入金等未计入成本的手续费）
+	PnL              decimal.Decimal // 合计盈亏
+
+	// 以下字段仅按股票分组时有值（期末持仓）
+	Quantity    decimal.Decimal // 期末持仓数量
+	CostBasis   decimal.Decimal // 期末持仓成本
+	MarketPrice decimal.Decimal // 估值价格（截至期末的最新价格）
+	MarketValue decimal.Decimal // 期末市值
+}
+
+// PnLOutput 盈亏报表的输出结果
+type PnLOutput struct {
+	GroupBy               string          // 分组方式
+	BaseCurrency          string          // 基准货币（合计的币种，合计始终换算为基准货币）
+	AsOf                  time.Time       // 估值时点（期末）
+	TotalRealized         decimal.Decimal // 期间已实现盈亏合计
+	TotalUnrealized       decimal.Decimal // 期末未实现盈亏合计
+	TotalUnrealizedChange decimal.Decimal // 期间未实现盈亏变动 = 期末 - 期初（未指定开始时间时等于期末值）
+	TotalIncome           decimal.Decimal // 期间分红、利息净收入合计
+	TotalExpenses         decimal.Decimal // 期间费用合计
+	TotalPnL              decimal.Decimal // 合计 = 已实现 + 未实现变动 + 收入 - 费用（按基准货币列示时等于各明细行 PnL 之和）
+	Rows                  []*PnLRow       // 明细行
+}
+
+// ==================== Domain 接口定义 ====================
+// Service 层会依赖这个接口
+
+type Domain interface {
+	// PnL 盈亏报表
+	// 核心业务逻辑：已实现盈亏取自批次分配记录，未实现盈亏按期末未平仓批次和最新价格估值，
+	// 收入和费用取自期间内的现金类交易；
+	// 换算为基准货币时，成本按开仓日汇率、卖出收入和现金收支按交易日汇率、市值按期末汇率
+	PnL(input *PnLInput) (*PnLOutput, error)
+}
@@ -0,0 +1,308 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/florentyang/smartfin-go/internal/config"
+	instrumentDomain "github.com/florentyang/smartfin-go/internal/domain/instrument"
+	quoteDomain "github.com/florentyang/smartfin-go/internal/domain/quote"
+	streamDomain "github.com/florentyang/smartfin-go/internal/domain/stream"
+	"github.com/florentyang/smartfin-go/internal/quote"
+)
+
+// ==================== Hub 结构体 ====================
+// 一个 Hub goroutine 持有全部订阅状态（不加锁）：
+// - 定时轮询被订阅的股票（所有连接共享一次查询，同一只股票不会因订阅者多而重复请求行情源）
+// - 行情有变化时分发给订阅了该股票的连接：只写入订阅者的积压区并非阻塞通知，慢客户端不会拖住其他人
+// - 订阅者积压超时则断开，积压期间同一只股票只保留最新一条
+
+type hub struct {
+	quoteDomain quoteDomain.Domain // 行情 Domain（并发查询、超时、缓存都在这一层）
+	interval    time.Duration      // 轮询间隔
+	maxSymbols  int                // 单个连接最多订阅的股票数
+	slowTimeout time.Duration      // 订阅者积压的最长时间
+	batchSize   int                // 每次请求行情 Domain 的股票数上限
+
+	register   chan *subscriber
+	unregister chan *subscriber
+	results    chan []*quote.Quote
+
+	// 以下字段只由 Hub goroutine 访问
+	subscribers map[string]map[*subscriber]struct{} // 股票代码 → 订阅者
+	last        map[string]*quote.Quote             // 股票代码 → 最近一次推送的行情
+	fetching    bool                                // 是否有轮询正在进行
+}
+
+// ==================== 构造函数 ====================
+
+// NewStreamDomain 创建 Domain 实例并启动 Hub goroutine（随进程运行）
+// batchSize 为行情 Domain 单次查询的股票数上限（config.QuoteConfig.MaxSymbols）
+func NewStreamDomain(quoteDomain quoteDomain.Domain, cfg *config.StreamConfig, batchSize int) streamDomain.Domain {
+	defaults := config.DefaultStreamConfig()
+	h := &hub{
+		quoteDomain: quoteDomain,
+		interval:    cfg.Interval,
+		maxSymbols:  cfg.MaxSymbols,
+		slowTimeout: cfg.SlowClientTimeout,
+		batchSize:   batchSize,
+		register:    make(chan *subscriber),
+		unregister:  make(chan *subscriber),
+		results:     make(chan []*quote.Quote),
+		subscribers: make(map[string]map[*subscriber]struct{}),
+		last:        make(map[string]*quote.Quote),
+	}
+	if h.interval <= 0 {
+		h.interval = defaults.Interval
+	}
+	if h.maxSymbols <= 0 {
+		h.maxSymbols = defaults.MaxSymbols
+	}
+	if h.slowTimeout <= 0 {
+		h.slowTimeout = defaults.SlowClientTimeout
+	}
+	if h.batchSize <= 0 {
+		h.batchSize = config.DefaultQuoteConfig().MaxSymbols
+	}
+	go h.run()
+	return h
+}
+
+// ==================== 业务方法实现 ====================
+
+// Subscribe 订阅股票的实时行情
+func (h *hub) Subscribe(symbols []string) (streamDomain.Subscription, error) {
+	// 1. 规范化并去重（与行情查询相同的规则）
+	var normalized []string
+	seen := make(map[string]bool, len(symbols))
+	for _, raw := range symbols {
+		symbol, err := instrumentDomain.NormalizeSymbol(raw, "")
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", raw, err)
+		}
+		if !seen[symbol] {
+			seen[symbol] = true
+			normalized = append(normalized, symbol)
+		}
+	}
+	if len(normalized) == 0 {
+		return nil, streamDomain.ErrNoSymbols
+	}
+	if len(normalized) > h.maxSymbols {
+		return nil, fmt.Errorf("%w（最多 %d 个）", streamDomain.ErrTooManySymbols, h.maxSymbols)
+	}
+
+	// 2. 交给 Hub 登记
+	sub := &subscriber{
+		hub:     h,
+		symbols: normalized,
+		ready:   make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		pending: make(map[string]*quote.Quote),
+	}
+	h.register <- sub
+	return sub, nil
+}
+
+// ==================== Hub goroutine ====================
+
+// run Hub 主循环：登记、注销、分发轮询结果、定时轮询
+func (h *hub) run() {
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case sub := <-h.register:
+			h.add(sub)
+		case sub := <-h.unregister:
+			h.remove(sub)
+		case quotes := <-h.results:
+			h.fetching = false
+			h.broadcast(quotes)
+		case <-ticker.C:
+			h.poll()
+		}
+	}
+}
+
+// add 登记订阅者：先推送已知的最新行情，有尚未查询过的股票时立即轮询
+func (h *hub) add(sub *subscriber) {
+	now := time.Now()
+	unknown := false
+	for _, symbol := range sub.symbols {
+		subs, ok := h.subscribers[symbol]
+		if !ok {
+			subs = make(map[*subscriber]struct{})
+			h.subscribers[symbol] = subs
+		}
+		subs[sub] = struct{}{}
+
+		if q, ok := h.last[symbol]; ok {
+			sub.deliver(q, now)
+		} else {
+			unknown = true
+		}
+	}
+	if unknown {
+		h.poll()
+	}
+}
+
+// remove 注销订阅者；没有人订阅的股票不再轮询
+func (h *hub) remove(sub *subscriber) {
+	for _, symbol := range sub.symbols {
+		subs := h.subscribers[symbol]
+		delete(subs, sub)
+		if len(subs) == 0 {
+			delete(h.subscribers, symbol)
+			delete(h.last, symbol)
+		}
+	}
+}
+
+// poll 在后台查询全部被订阅的股票，结果交回 Hub goroutine（同一时间只有一次轮询）
+func (h *hub) poll() {
+	if h.fetching || len(h.subscribers) == 0 {
+		return
+	}
+	symbols := make([]string, 0, len(h.subscribers))
+	for symbol := range h.subscribers {
+		symbols = append(symbols, symbol)
+	}
+	h.fetching = true
+
+	go func() {
+		var quotes []*quote.Quote
+		for start := 0; start < len(symbols); start += h.batchSize {
+			end := start + h.batchSize
+			if end > len(symbols) {
+				end = len(symbols)
+			}
+			output, err := h.quoteDomain.Fetch(context.Background(), symbols[start:end])
+			if err != nil {
+				log.Printf("行情推送轮询失败: %v", err)
+				continue
+			}
+			quotes = append(quotes, output.Quotes...)
+		}
+		h.results <- quotes
+	}()
+}
+
+// broadcast 把有变化的行情分发给订阅者，积压超时的订阅者断开
+func (h *hub) broadcast(quotes []*quote.Quote) {
+	now := time.Now()
+	for _, q := range quotes {
+		subs, ok := h.subscribers[q.Symbol]
+		if !ok {
+			continue // 轮询期间已无人订阅
+		}
+		if prev, ok := h.last[q.Symbol]; ok && sameQuote(prev, q) {
+			continue
+		}
+		h.last[q.Symbol] = q
+
+		for sub := range subs {
+			if sub.stalled(now, h.slowTimeout) {
+				h.remove(sub)
+				sub.drop()
+				continue
+			}
+			sub.deliver(q, now)
+		}
+	}
+}
+
+// sameQuote 行情是否没有变化
+func sameQuote(a, b *quote.Quote) bool {
+	return a.Price.Equal(b.Price) && a.PrevClose.Equal(b.PrevClose) && a.Time.Equal(b.Time)
+}
+
+// ==================== 订阅者 ====================
+
+// subscriber 一个连接的订阅
+// 积压区由 Hub goroutine 写入、连接的 goroutine 取走，用自己的锁保护，不影响 Hub 和其他订阅者
+type subscriber struct {
+	hub     *hub
+	symbols []string
+	ready   chan struct{} // 容量 1：多次通知合并为一次
+	done    chan struct{} // Hub 断开时关闭
+
+	mu           sync.Mutex
+	pending      map[string]*quote.Quote // 股票代码 → 尚未取走的最新行情
+	pendingSince time.Time               // 积压区中最早一条行情的写入时间
+
+	dropOnce  sync.Once
+	closeOnce sync.Once
+}
+
+// Symbols 订阅的股票代码
+func (s *subscriber) Symbols() []string {
+	return s.symbols
+}
+
+// Ready 有新行情待取时可读
+func (s *subscriber) Ready() <-chan struct{} {
+	return s.ready
+}
+
+// Done 被 Hub 断开时关闭
+func (s *subscriber) Done() <-chan struct{} {
+	return s.done
+}
+
+// Drain 取出积压的行情
+func (s *subscriber) Drain() []*quote.Quote {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[string]*quote.Quote, len(pending))
+	s.mu.Unlock()
+
+	quotes := make([]*quote.Quote, 0, len(pending))
+	for _, symbol := range s.symbols {
+		if q, ok := pending[symbol]; ok {
+			quotes = append(quotes, q)
+		}
+	}
+	return quotes
+}
+
+// Close 取消订阅
+func (s *subscriber) Close() {
+	s.closeOnce.Do(func() {
+		s.hub.unregister <- s
+	})
+}
+
+// deliver 写入积压区（同一只股票覆盖旧行情）并非阻塞通知
+func (s *subscriber) deliver(q *quote.Quote, now time.Time) {
+	s.mu.Lock()
+	if len(s.pending) == 0 {
+		s.pendingSince = now
+	}
+	s.pending[q.Symbol] = q
+	s.mu.Unlock()
+
+	select {
+	case s.ready <- struct{}{}:
+	default:
+	}
+}
+
+// stalled 积压区中的行情是否超过 timeout 仍未取走
+func (s *subscriber) stalled(now time.Time, timeout time.Duration) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.pending) > 0 && now.Sub(s.pendingSince) > timeout
+}
+
+// drop Hub 断开订阅
+func (s *subscriber) drop() {
+	s.dropOnce.Do(func() {
+		close(s.done)
+	})
+}
@@ -0,0 +1,47 @@
+package stream
+
+import (
+	"errors"
+
+	"github.com/florentyang/smartfin-go/internal/quote"
+)
+
+// ==================== 错误定义 ====================
+// 领域层的业务错误（中文方便调试）
+
+var (
+	ErrNoSymbols      = errors.New("没有可订阅的股票：请传入股票代码，或先持有股票")
+	ErrTooManySymbols = errors.New("单个连接订阅的股票数超过上限")
+	ErrSlowConsumer   = errors.New("客户端接收过慢，连接已断开")
+)
+
+// ==================== Subscription 接口 ====================
+
+// Subscription 一个连接的行情订阅
+// Hub 不会因为某个订阅者阻塞：行情先放入订阅者自己的积压区，同一只股票只保留最新一条（合并），
+// 再通过 Ready 通知；积压超过 SlowClientTimeout 仍未取走时 Hub 断开该订阅（Done 关闭）
+type Subscription interface {
+	// Symbols 订阅的股票代码（已规范化、去重）
+	Symbols() []string
+
+	// Ready 有新行情待取时可读（多次通知会合并为一次）
+	Ready() <-chan struct{}
+
+	// Drain 取出积压的行情（每只股票最多一条，按订阅顺序）
+	Drain() []*quote.Quote
+
+	// Done 订阅被 Hub 断开（客户端过慢）时关闭
+	Done() <-chan struct{}
+
+	// Close 取消订阅（连接断开时调用，可重复调用）
+	Close()
+}
+
+// ==================== Domain 接口定义 ====================
+// Service 层会依赖这个接口
+
+type Domain interface {
+	// Subscribe 订阅股票的实时行情
+	// 立即推送已知的最新行情，之后行情有变化时推送
+	Subscribe(symbols []string) (Subscription, error)
+}
@@ -0,0 +1,119 @@
+package impl
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	accountRepo "github.com/florentyang/smartfin-go/internal/dao/account"
+	txRepo "github.com/florentyang/smartfin-go/internal/dao/transaction"
+	txDomain "github.com/florentyang/smartfin-go/internal/domain/transaction"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 现金校验 ====================
+// 防止交易使非融资账户的现金余额变为负数
+//
+// 现金按账户 + 币种隔离，每笔交易的现金变动见 entity.CashFlow。
+// 与持仓校验相同：合并回放变更前后的流水，只有某个时间点的余额
+// 在变更后小于 0 且比变更前更少时才拒绝，历史上已存在的负余额不阻塞无关交易。
+// 未指定账户（AccountID 为 0）和融资账户不校验。
+
+// cashScope 现金的校验范围：账户 + 币种
+type cashScope struct {
+	accountID uint
+	currency  string
+}
+
+// cashScopeOf 交易所属的现金校验范围
+func cashScopeOf(tx *entity.Transaction) cashScope {
+	return cashScope{accountID: tx.AccountID, currency: tx.Currency}
+}
+
+// checkCash 校验一次变更是否使现金余额变为负数
+// before：变更前的交易（新增时为 nil）
+// after：变更后的交易（删除时为 nil）
+func (u *usecase) checkCash(userID uint, before, after *entity.Transaction) error {
+	// 1. 变更可能涉及两个校验范围（修改了账户或币种），分别校验
+	scopes := make([]cashScope, 0, 2)
+	if before != nil {
+		scopes = append(scopes, cashScopeOf(before))
+	}
+	if after != nil && (before == nil || cashScopeOf(after) != cashScopeOf(before)) {
+		scopes = append(scopes, cashScopeOf(after))
+	}
+
+	for _, scope := range scopes {
+		// 2. 未指定账户和融资账户允许负余额
+		if scope.accountID == 0 {
+			continue
+		}
+		account, err := u.accountRepo.GetByID(scope.accountID)
+		if err != nil {
+			if errors.Is(err, accountRepo.ErrAccountNotFound) {
+				continue
+			}
+			return err
+		}
+		if account.Margin {
+			continue
+		}
+
+		// 3. 加载账户流水，只保留同币种的交易
+		ledger, err := u.txRepo.FindLedger(&txRepo.LedgerFilter{
+			UserID:    userID,
+			AccountID: &scope.accountID,
+		})
+		if err != nil {
+			return err
+		}
+		inScope := make([]*entity.Transaction, 0, len(ledger))
+		for _, tx := range ledger {
+			if cashScopeOf(tx) == scope {
+				inScope = append(inScope, tx)
+			}
+		}
+
+		if err := checkCashLedger(scope, account.Name, inScope, before, after); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkCashLedger 合并回放一个账户内单一币种的现金流水
+func checkCashLedger(scope cashScope, accountName string, ledger []*entity.Transaction, before, after *entity.Transaction) error {
+	// 1. 合并变更前后的流水
+	events := mergeLedger(ledger, before, after, func(tx *entity.Transaction) bool {
+		return cashScopeOf(tx) == scope
+	})
+
+	// 2. 回放并比较每个时间点的余额
+	oldCash, newCash := decimal.Zero, decimal.Zero
+	for _, e := range events {
+		oldCash = applyCash(oldCash, e.oldTx)
+		newCash = applyCash(newCash, e.newTx)
+		if newCash.IsNegative() && newCash.LessThan(oldCash) {
+			return fmt.Errorf("%w：账户 %s 在 %s 的 %s 余额将变为 %s",
+				txDomain.ErrInsufficientCash,
+				accountName,
+				e.tradeTime.Format(time.RFC3339),
+				scope.currency,
+				newCash.String(),
+			)
+		}
+	}
+
+	return nil
+}
+
+// applyCash 回放一笔交易后的现金余额
+func applyCash(cash decimal.Decimal, tx *entity.Transaction) decimal.Decimal {
+	if tx == nil {
+		return cash
+	}
+	return cash.Add(entity.CashFlow(tx))
+}
@@ -0,0 +1,208 @@
+package impl
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	txRepo "github.com/florentyang/smartfin-go/internal/dao/transaction"
+	marketDomain "github.com/florentyang/smartfin-go/internal/domain/market"
+	txDomain "github.com/florentyang/smartfin-go/internal/domain/transaction"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 交易所规则 ====================
+// 用户开启 EnforceMarketRules 后，对买卖校验：
+// - 交易时段：能识别出交易所时，交易时间必须在交易所的交易时段内（NYSE、HKEX、SSE、SZSE，按交易日历），否则返回 ErrMarketClosed
+// - 整手：买入数量必须为每手股数的整数倍（卖出允许零股）；每手股数取证券主数据，主数据中没有时 A 股为 100 股
+// 沪深北交易所的股票（A 股）另外校验：
+// - T+1：当日买入的股票当日不能卖出（按北京时间划分交易日）
+// - 涨跌停：填写了昨收价时，成交价必须在涨跌停价格范围内
+//   主板 10%（简称以 ST、*ST、S*ST 开头的 ST 股 5%），创业板、科创板 20%，北交所 30%，涨跌停价四舍五入到分
+// 其他市场的股票不受影响。
+
+// boardLotSize A 股买入的最小交易单位（1 手，证券主数据中没有该股票时使用）
+var boardLotSize = decimal.NewFromInt(100)
+
+// chinaZone 北京时间（没有夏令时，使用固定时区，不依赖系统时区数据）
+var chinaZone = time.FixedZone("CST", 8*3600)
+
+// isAShare 是否沪深北交易所的股票
+func isAShare(symbol *marketDomain.Symbol) bool {
+	switch symbol.Exchange {
+	case marketDomain.ExchangeSSE, marketDomain.ExchangeSZSE, marketDomain.ExchangeBSE:
+		return true
+	}
+	return false
+}
+
+// A 股涨跌幅限制比例
+var (
+	limitRateBSE   = decimal.New(3, -1) // 北交所 30%
+	limitRateGEM   = decimal.New(2, -1) // 创业板、科创板 20%
+	limitRateST    = decimal.New(5, -2) // 主板 ST 股 5%
+	limitRateBoard = decimal.New(1, -1) // 主板 10%
+)
+
+// stPrefixes ST 股的简称前缀（风险警示；S 表示尚未完成股改），较长的前缀在前
+var stPrefixes = []string{"S*ST", "*ST", "ST"}
+
+// limitRate A 股涨跌幅限制比例
+func limitRate(symbol *marketDomain.Symbol, name string) decimal.Decimal {
+	switch {
+	case symbol.Exchange == marketDomain.ExchangeBSE:
+		return limitRateBSE
+	case strings.HasPrefix(symbol.Code, "688"), strings.HasPrefix(symbol.Code, "689"), // 科创板
+		strings.HasPrefix(symbol.Code, "300"), strings.HasPrefix(symbol.Code, "301"): // 创业板
+		return limitRateGEM
+	case isSTName(name): // 主板 ST、*ST
+		return limitRateST
+	default:
+		return limitRateBoard
+	}
+}
+
+// isSTName 证券简称是否为 ST 股：以 ST、*ST、S*ST 开头（如 "ST 华仪"、"*ST 海润"）
+// 前缀之后紧跟英文字母时不算（如 "Stanley"），避免英文名称误判
+func isSTName(name string) bool {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	for _, prefix := range stPrefixes {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		rest := name[len(prefix):]
+		return rest == "" || rest[0] < 'A' || rest[0] > 'Z'
+	}
+	return false
+}
+
+// checkMarketRules 校验交易所规则（用户未开启时跳过）
+// instrument 为证券主数据（没有时为 nil），prevClose 为昨收价，为 0 时不校验涨跌停
+func (u *usecase) checkMarketRules(user *entity.User, tx *entity.Transaction, instrument *entity.Instrument, prevClose decimal.Decimal) error {
+	// 1. 未开启或不是买卖：跳过
+	if !user.EnforceMarketRules {
+		return nil
+	}
+	if tx.Type != entity.TransactionTypeBuy && tx.Type != entity.TransactionTypeSell {
+		return nil
+	}
+	symbol, ok := marketDomain.ParseSymbol(tx.Symbol, tx.Currency)
+
+	// 2. 交易时间必须在交易所的交易时段内（无法识别交易所或没有交易日历的交易所不校验）
+	if ok {
+		if err := u.checkSession(symbol, tx); err != nil {
+			return err
+		}
+	}
+
+	// 3. 买入整手
+	lotSize := decimal.Zero
+	switch {
+	case instrument != nil:
+		lotSize = instrument.LotSize
+	case ok && isAShare(symbol):
+		lotSize = boardLotSize
+	}
+	if tx.Type == entity.TransactionTypeBuy && lotSize.GreaterThan(decimal.NewFromInt(1)) && !tx.Quantity.Mod(lotSize).IsZero() {
+		return fmt.Errorf("%w：%s 每手 %s 股，买入 %s 股", txDomain.ErrBoardLot, tx.Symbol, lotSize.String(), tx.Quantity.String())
+	}
+	if !ok || !isAShare(symbol) {
+		return nil
+	}
+
+	// 4. 涨跌停价格
+	if prevClose.IsNegative() {
+		return txDomain.ErrInvalidPrevClose
+	}
+	if prevClose.IsPositive() {
+		rate := limitRate(symbol, tx.Name)
+		one := decimal.NewFromInt(1)
+		up := prevClose.Mul(one.Add(rate)).Round(2)
+		down := prevClose.Mul(one.Sub(rate)).Round(2)
+		if tx.Price.GreaterThan(up) || tx.Price.LessThan(down) {
+			return fmt.Errorf("%w：%s 昨收 %s，价格范围 %s ~ %s，成交价 %s",
+				txDomain.ErrPriceLimit, tx.Symbol, prevClose.String(), down.String(), up.String(), tx.Price.String())
+		}
+	}
+
+	// 5. 卖出 T+1
+	if tx.Type == entity.TransactionTypeSell {
+		return u.checkT1(user.ID, tx)
+	}
+	return nil
+}
+
+// checkSession 校验交易时间在交易所的交易时段内
+// 只有买卖在交易所成交，现金类交易、转入转出不校验
+func (u *usecase) checkSession(symbol *marketDomain.Symbol, tx *entity.Transaction) error {
+	open, err := u.marketDomain.IsOpen(symbol.Exchange, tx.TradeTime)
+	if errors.Is(err, marketDomain.ErrUnknownExchange) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !open {
+		return fmt.Errorf("%w：%s（%s）%s",
+			marketDomain.ErrMarketClosed, tx.Symbol, symbol.Exchange, tx.TradeTime.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// checkT1 校验卖出没有用到当日买入的股票
+// 可卖数量 = 当日开盘前的持仓 - 当日此前已卖出（转出）的数量；
+// 卖出超过可卖数量且当日有买入时，说明卖出了当日买入的股票。
+// 当日没有买入时，超出部分属于超卖，由持仓校验处理
+func (u *usecase) checkT1(userID uint, tx *entity.Transaction) error {
+	// 1. 查询同一账户、同一股票的交易流水
+	ledger, err := u.txRepo.FindLedger(&txRepo.LedgerFilter{
+		UserID:    userID,
+		AccountID: &tx.AccountID,
+		Symbol:    tx.Symbol,
+	})
+	if err != nil {
+		return err
+	}
+
+	// 2. 北京时间的当日零点
+	local := tx.TradeTime.In(chinaZone)
+	dayStart := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, chinaZone)
+
+	// 3. 回放卖出之前的流水（排序规则与持仓校验一致），排除交易自身（修改时）
+	sellable, bought := decimal.Zero, decimal.Zero
+	for _, e := range ledger {
+		if e.ID == tx.ID {
+			continue
+		}
+		if e.TradeTime.After(tx.TradeTime) ||
+			(e.TradeTime.Equal(tx.TradeTime) && sortID(e.ID) > sortID(tx.ID)) {
+			break
+		}
+		if e.TradeTime.Before(dayStart) {
+			sellable = applyQuantity(sellable, e)
+			continue
+		}
+		switch e.Type {
+		case entity.TransactionTypeBuy:
+			bought = bought.Add(e.Quantity)
+		case entity.TransactionTypeSplit:
+			sellable = applyQuantity(sellable, e)
+			bought = applyQuantity(bought, e)
+		default:
+			sellable = applyQuantity(sellable, e)
+		}
+	}
+
+	// 4. 卖出超过可卖数量且当日有买入
+	if tx.Quantity.GreaterThan(sellable) && bought.IsPositive() {
+		if sellable.IsNegative() {
+			sellable = decimal.Zero
+		}
+		return fmt.Errorf("%w：%s 在 %s 可卖 %s 股，当日买入 %s 股",
+			txDomain.ErrT1Violation, tx.Symbol, local.Format("2006-01-02"), sellable.String(), bought.String())
+	}
+	return nil
+}
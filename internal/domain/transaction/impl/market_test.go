@@ -0,0 +1,308 @@
+package impl
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	txRepo "github.com/florentyang/smartfin-go/internal/dao/transaction"
+	marketDomain "github.com/florentyang/smartfin-go/internal/domain/market"
+	txDomain "github.com/florentyang/smartfin-go/internal/domain/transaction"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 测试替身 ====================
+
+// fakeLedgerRepo 只实现 FindLedger，返回固定的交易流水（已按时间排序）
+type fakeLedgerRepo struct {
+	txRepo.Repo
+	ledger []*entity.Transaction
+}
+
+func (r *fakeLedgerRepo) FindLedger(*txRepo.LedgerFilter) ([]*entity.Transaction, error) {
+	return r.ledger, nil
+}
+
+// fakeMarket 只实现 IsOpen，所有交易所同样开市或休市
+type fakeMarket struct {
+	marketDomain.Domain
+	closed bool
+}
+
+func (m *fakeMarket) IsOpen(string, time.Time) (bool, error) {
+	return !m.closed, nil
+}
+
+// ==================== 测试数据构造 ====================
+
+// cst 北京时间 2024 年 1 月 d 日 hour 时
+func cst(d, hour int) time.Time {
+	return time.Date(2024, time.January, d, hour, 0, 0, 0, chinaZone)
+}
+
+// marketTx 构造一笔买卖交易
+func marketTx(id uint, txType, symbol string, at time.Time, quantity, price string) *entity.Transaction {
+	return &entity.Transaction{
+		ID:        id,
+		Symbol:    symbol,
+		Type:      txType,
+		Quantity:  decimal.RequireFromString(quantity),
+		Price:     decimal.RequireFromString(price),
+		Currency:  "CNY",
+		TradeTime: at,
+	}
+}
+
+// marketSplit 构造一笔拆股
+func marketSplit(id uint, symbol string, at time.Time, ratio string) *entity.Transaction {
+	tx := marketTx(id, entity.TransactionTypeSplit, symbol, at, "0", "0")
+	tx.Ratio = decimal.RequireFromString(ratio)
+	return tx
+}
+
+// named 设置证券简称
+func named(tx *entity.Transaction, name string) *entity.Transaction {
+	tx.Name = name
+	return tx
+}
+
+// ==================== 测试用例 ====================
+
+func TestCheckMarketRules(t *testing.T) {
+	const (
+		buy  = entity.TransactionTypeBuy
+		sell = entity.TransactionTypeSell
+	)
+	now := cst(3, 10)
+	hkInstrument := &entity.Instrument{LotSize: decimal.NewFromInt(500)}
+
+	tests := []struct {
+		name       string
+		tx         *entity.Transaction
+		instrument *entity.Instrument
+		prevClose  string
+		ledger     []*entity.Transaction
+		closed     bool
+		disabled   bool
+		wantErr    error
+	}{
+		// ---------- 开关与交易时段 ----------
+		{
+			name:     "未开启时不校验",
+			tx:       marketTx(0, buy, "600519.SH", now, "150", "10"),
+			disabled: true,
+		},
+		{
+			name:    "非交易时段",
+			tx:      marketTx(0, buy, "600519.SH", now, "100", "10"),
+			closed:  true,
+			wantErr: marketDomain.ErrMarketClosed,
+		},
+		{
+			name:   "现金类交易不校验",
+			tx:     marketTx(0, entity.TransactionTypeDividend, "600519.SH", now, "0", "0"),
+			closed: true,
+		},
+
+		// ---------- 整手 ----------
+		{
+			name:    "A 股买入不是整手",
+			tx:      marketTx(0, buy, "600519.SH", now, "150", "10"),
+			wantErr: txDomain.ErrBoardLot,
+		},
+		{
+			name: "A 股买入整手",
+			tx:   marketTx(0, buy, "600519.SH", now, "200", "10"),
+		},
+		{
+			name:   "A 股卖出允许零股",
+			tx:     marketTx(0, sell, "600519.SH", now, "50", "10"),
+			ledger: []*entity.Transaction{marketTx(1, buy, "600519.SH", cst(2, 10), "150", "10")},
+		},
+		{
+			name:       "每手股数取证券主数据",
+			tx:         marketTx(0, buy, "0700.HK", now, "100", "300"),
+			instrument: hkInstrument,
+			wantErr:    txDomain.ErrBoardLot,
+		},
+		{
+			name:       "港股按证券主数据买入整手",
+			tx:         marketTx(0, buy, "0700.HK", now, "1000", "300"),
+			instrument: hkInstrument,
+		},
+		{
+			name: "美股没有整手限制",
+			tx:   marketTx(0, buy, "AAPL.US", now, "7", "180"),
+		},
+
+		// ---------- 涨跌停：主板 10% ----------
+		{name: "主板涨停价", tx: marketTx(0, buy, "600000.SH", now, "100", "11"), prevClose: "10"},
+		{name: "主板超过涨停价", tx: marketTx(0, buy, "600000.SH", now, "100", "11.01"), prevClose: "10", wantErr: txDomain.ErrPriceLimit},
+		{name: "主板跌停价", tx: marketTx(0, buy, "000001.SZ", now, "100", "9"), prevClose: "10"},
+		{name: "主板低于跌停价", tx: marketTx(0, buy, "000001.SZ", now, "100", "8.99"), prevClose: "10", wantErr: txDomain.ErrPriceLimit},
+		{
+			// 10.05 × 1.1 = 11.055 → 11.06，10.05 × 0.9 = 9.045 → 9.05
+			name: "涨停价四舍五入到分", tx: marketTx(0, buy, "600000.SH", now, "100", "11.06"), prevClose: "10.05",
+		},
+		{name: "超过四舍五入后的涨停价", tx: marketTx(0, buy, "600000.SH", now, "100", "11.07"), prevClose: "10.05", wantErr: txDomain.ErrPriceLimit},
+		{name: "低于四舍五入后的跌停价", tx: marketTx(0, buy, "600000.SH", now, "100", "9.04"), prevClose: "10.05", wantErr: txDomain.ErrPriceLimit},
+
+		// ---------- 涨跌停：创业板、科创板 20% ----------
+		{name: "创业板涨停价", tx: marketTx(0, buy, "300750.SZ", now, "100", "12"), prevClose: "10"},
+		{name: "创业板超过涨停价", tx: marketTx(0, buy, "300750.SZ", now, "100", "12.01"), prevClose: "10", wantErr: txDomain.ErrPriceLimit},
+		{name: "创业板低于跌停价", tx: marketTx(0, buy, "301236.SZ", now, "100", "7.99"), prevClose: "10", wantErr: txDomain.ErrPriceLimit},
+		{name: "科创板跌停价", tx: marketTx(0, buy, "688981.SH", now, "100", "8"), prevClose: "10"},
+		{name: "科创板超过涨停价", tx: marketTx(0, buy, "688981.SH", now, "100", "12.01"), prevClose: "10", wantErr: txDomain.ErrPriceLimit},
+		{
+			// 创业板 ST 股仍为 20%
+			name: "创业板 ST 股按 20%", tx: named(marketTx(0, buy, "300001.SZ", now, "100", "11.5"), "ST 特锐"), prevClose: "10",
+		},
+
+		// ---------- 涨跌停：主板 ST 5% ----------
+		{name: "ST 股涨停价", tx: named(marketTx(0, buy, "600000.SH", now, "100", "10.5"), "ST 华仪"), prevClose: "10"},
+		{name: "ST 股超过涨停价", tx: named(marketTx(0, buy, "600000.SH", now, "100", "10.51"), "ST 华仪"), prevClose: "10", wantErr: txDomain.ErrPriceLimit},
+		{name: "ST 股低于跌停价", tx: named(marketTx(0, buy, "600000.SH", now, "100", "9.49"), "ST 华仪"), prevClose: "10", wantErr: txDomain.ErrPriceLimit},
+		{name: "*ST 股超过涨停价", tx: named(marketTx(0, buy, "600000.SH", now, "100", "10.51"), "*ST 海润"), prevClose: "10", wantErr: txDomain.ErrPriceLimit},
+		{name: "S*ST 股超过涨停价", tx: named(marketTx(0, buy, "600000.SH", now, "100", "10.51"), "S*ST 前锋"), prevClose: "10", wantErr: txDomain.ErrPriceLimit},
+
+		// ---------- 涨跌停：北交所 30% ----------
+		{name: "北交所涨停价", tx: marketTx(0, buy, "430047.BJ", now, "100", "13"), prevClose: "10"},
+		{name: "北交所超过涨停价", tx: marketTx(0, buy, "430047.BJ", now, "100", "13.01"), prevClose: "10", wantErr: txDomain.ErrPriceLimit},
+
+		// ---------- 涨跌停：其他 ----------
+		{name: "未填写昨收价不校验", tx: marketTx(0, buy, "600000.SH", now, "100", "50")},
+		{name: "昨收价为负", tx: marketTx(0, buy, "600000.SH", now, "100", "10"), prevClose: "-1", wantErr: txDomain.ErrInvalidPrevClose},
+		{name: "港股没有涨跌停", tx: marketTx(0, buy, "0700.HK", now, "100", "50"), prevClose: "10"},
+
+		// ---------- T+1 ----------
+		{
+			name:    "当日买入当日卖出",
+			tx:      marketTx(0, sell, "600519.SH", cst(3, 14), "100", "10"),
+			ledger:  []*entity.Transaction{marketTx(1, buy, "600519.SH", cst(3, 10), "100", "10")},
+			wantErr: txDomain.ErrT1Violation,
+		},
+		{
+			name:   "前一日买入今日卖出",
+			tx:     marketTx(0, sell, "600519.SH", cst(3, 10), "100", "10"),
+			ledger: []*entity.Transaction{marketTx(1, buy, "600519.SH", cst(2, 14), "100", "10")},
+		},
+		{
+			name: "只卖出昨日持仓",
+			tx:   marketTx(0, sell, "600519.SH", cst(3, 14), "100", "10"),
+			ledger: []*entity.Transaction{
+				marketTx(1, buy, "600519.SH", cst(2, 10), "100", "10"),
+				marketTx(2, buy, "600519.SH", cst(3, 10), "100", "10"),
+			},
+		},
+		{
+			name: "卖出超过昨日持仓",
+			tx:   marketTx(0, sell, "600519.SH", cst(3, 14), "150", "10"),
+			ledger: []*entity.Transaction{
+				marketTx(1, buy, "600519.SH", cst(2, 10), "100", "10"),
+				marketTx(2, buy, "600519.SH", cst(3, 10), "100", "10"),
+			},
+			wantErr: txDomain.ErrT1Violation,
+		},
+		{
+			name: "当日已卖出的昨日持仓不能重复计算",
+			tx:   marketTx(0, sell, "600519.SH", cst(3, 14), "50", "10"),
+			ledger: []*entity.Transaction{
+				marketTx(1, buy, "600519.SH", cst(2, 10), "100", "10"),
+				marketTx(2, buy, "600519.SH", cst(3, 10), "100", "10"),
+				marketTx(3, sell, "600519.SH", cst(3, 11), "100", "10"),
+			},
+			wantErr: txDomain.ErrT1Violation,
+		},
+		{
+			name: "当日拆股后按新数量卖出昨日持仓",
+			tx:   marketTx(0, sell, "600519.SH", cst(3, 14), "200", "10"),
+			ledger: []*entity.Transaction{
+				marketTx(1, buy, "600519.SH", cst(2, 10), "100", "10"),
+				marketSplit(2, "600519.SH", cst(3, 9), "2"),
+			},
+		},
+		{
+			// 修改卖出时排除交易自身
+			name: "修改已有的卖出",
+			tx:   marketTx(2, sell, "600519.SH", cst(3, 14), "100", "10"),
+			ledger: []*entity.Transaction{
+				marketTx(1, buy, "600519.SH", cst(2, 10), "100", "10"),
+				marketTx(2, sell, "600519.SH", cst(3, 14), "100", "10"),
+			},
+		},
+		{
+			// UTC 为两天，北京时间为同一天（1 月 3 日 07:00 与 10:00）
+			name:    "按北京时间划分交易日",
+			tx:      marketTx(0, sell, "600519.SH", time.Date(2024, time.January, 3, 2, 0, 0, 0, time.UTC), "100", "10"),
+			ledger:  []*entity.Transaction{marketTx(1, buy, "600519.SH", time.Date(2024, time.January, 2, 23, 0, 0, 0, time.UTC), "100", "10")},
+			wantErr: txDomain.ErrT1Violation,
+		},
+		{
+			// 当日没有买入，超出部分由持仓校验处理
+			name: "当日没有买入时不按 T+1 报错",
+			tx:   marketTx(0, sell, "600519.SH", cst(3, 14), "500", "10"),
+			ledger: []*entity.Transaction{
+				marketTx(1, buy, "600519.SH", cst(2, 10), "100", "10"),
+			},
+		},
+		{
+			name:   "美股不受 T+1 限制",
+			tx:     marketTx(0, sell, "AAPL.US", cst(3, 23), "100", "180"),
+			ledger: []*entity.Transaction{marketTx(1, buy, "AAPL.US", cst(3, 22), "100", "180")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &usecase{
+				txRepo:       &fakeLedgerRepo{ledger: tt.ledger},
+				marketDomain: &fakeMarket{closed: tt.closed},
+			}
+			user := &entity.User{ID: 1, EnforceMarketRules: !tt.disabled}
+			prevClose := decimal.Zero
+			if tt.prevClose != "" {
+				prevClose = decimal.RequireFromString(tt.prevClose)
+			}
+
+			err := u.checkMarketRules(user, tt.tx, tt.instrument, prevClose)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("checkMarketRules() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("checkMarketRules() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIsSTName(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"ST 华仪", true},
+		{"ST华仪", true},
+		{"*ST 海润", true},
+		{"S*ST 前锋", true},
+		{" st 华仪 ", true},
+		{"ST", true},
+		{"贵州茅台", false},
+		{"Stanley Black & Decker", false},
+		{"STMicroelectronics", false},
+		{"华仪 ST", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isSTName(tt.name); got != tt.want {
+				t.Errorf("isSTName(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package impl
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	accountRepo "github.com/florentyang/smartfin-go/internal/dao/account"
+	txRepo "github.com/florentyang/smartfin-go/internal/dao/transaction"
+	txDomain "github.com/florentyang/smartfin-go/internal/domain/transaction"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 持仓校验 ====================
+// 防止卖出数量超过持仓（不允许卖空的用户）
+//
+// 持仓按股票 + 账户隔离：一个账户的持仓不能用来覆盖另一个账户的卖出。
+// 校验方式：把"变更前"和"变更后"两份交易流水按时间合并回放，
+// 逐个时间点比较持仓数量。只要某个时间点的持仓在变更后小于 0
+// 且比变更前更少，就说明这次变更造成了超卖。
+// 这样可以同时覆盖：
+// - 新增卖出/转出（包括补录更早日期的卖出）
+// - 修改交易（数量、类型、时间、股票代码、账户、拆股比例变化）
+// - 删除买入/转入/拆股（导致其后的卖出失去持仓）
+// 历史上已存在的负持仓不会阻塞与之无关的新交易。
+// 拆股按比例缩放持仓，因此回放的是交易本身而不是数量增量。
+
+// positionScope 持仓的校验范围：股票代码 + 账户
+type positionScope struct {
+	symbol    string
+	accountID uint
+}
+
+// scopeOf 交易所属的校验范围
+func scopeOf(tx *entity.Transaction) positionScope {
+	return positionScope{symbol: tx.Symbol, accountID: tx.AccountID}
+}
+
+// ledgerEvent 回放事件（持仓校验和现金校验共用）
+type ledgerEvent struct {
+	id        uint
+	tradeTime time.Time
+	oldTx     *entity.Transaction // 变更前流水中的交易（nil 表示不存在）
+	newTx     *entity.Transaction // 变更后流水中的交易（nil 表示不存在）
+}
+
+// checkPosition 校验一次变更是否造成超卖
+// before：变更前的交易（新增时为 nil）
+// after：变更后的交易（删除时为 nil）
+func (u *usecase) checkPosition(user *entity.User, before, after *entity.Transaction) error {
+	// 1. 变更可能涉及两个校验范围（修改了股票代码或账户），分别校验
+	scopes := make([]positionScope, 0, 2)
+	if before != nil {
+		scopes = append(scopes, scopeOf(before))
+	}
+	if after != nil && (before == nil || scopeOf(after) != scopeOf(before)) {
+		scopes = append(scopes, scopeOf(after))
+	}
+
+	for _, scope := range scopes {
+		// 2. 允许卖空的账户跳过校验
+		allowed, err := u.allowShortSelling(user, scope.accountID)
+		if err != nil {
+			return err
+		}
+		if allowed {
+			continue
+		}
+
+		// 3. 加载账户内该股票的流水并回放
+		ledger, err := u.txRepo.FindLedger(&txRepo.LedgerFilter{
+			UserID:    user.ID,
+			AccountID: &scope.accountID,
+			Symbol:    scope.symbol,
+		})
+		if err != nil {
+			return err
+		}
+		if err := checkLedger(scope, ledger, before, after); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// allowShortSelling 交易所属账户是否允许卖空
+// 未指定账户（0）取用户的设置；账户不存在时按不允许处理（归属校验另行报错）
+func (u *usecase) allowShortSelling(user *entity.User, accountID uint) (bool, error) {
+	if accountID == 0 {
+		return user.AllowShortSelling, nil
+	}
+	account, err := u.accountRepo.GetByID(accountID)
+	if err != nil {
+		if errors.Is(err, accountRepo.ErrAccountNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return account.AllowShortSelling, nil
+}
+
+// checkLedger 合并回放一个账户内单只股票的交易流水
+func checkLedger(scope positionScope, ledger []*entity.Transaction, before, after *entity.Transaction) error {
+	// 1. 合并变更前后的流水
+	events := mergeLedger(ledger, before, after, func(tx *entity.Transaction) bool {
+		return scopeOf(tx) == scope
+	})
+
+	// 2. 回放并比较每个时间点的持仓
+	oldQty, newQty := decimal.Zero, decimal.Zero
+	for _, e := range events {
+		oldQty = applyQuantity(oldQty, e.oldTx)
+		newQty = applyQuantity(newQty, e.newTx)
+		if newQty.IsNegative() && newQty.LessThan(oldQty) {
+			return fmt.Errorf("%w：%s 在 %s 的持仓将变为 %s",
+				txDomain.ErrInsufficientPosition,
+				scope.symbol,
+				e.tradeTime.Format(time.RFC3339),
+				newQty.String(),
+			)
+		}
+	}
+
+	return nil
+}
+
+// mergeLedger 把"变更前"和"变更后"两份流水合并为按时间排序的回放事件
+// ledger 为校验范围内的现有流水，inScope 判断变更后的交易是否属于该范围
+func mergeLedger(ledger []*entity.Transaction, before, after *entity.Transaction, inScope func(tx *entity.Transaction) bool) []*ledgerEvent {
+	events := make([]*ledgerEvent, 0, len(ledger)+1)
+
+	// 1. 现有流水：被修改/删除的那笔只计入"变更前"
+	for _, tx := range ledger {
+		newTx := tx
+		if before != nil && tx.ID == before.ID {
+			newTx = nil
+		}
+		events = append(events, &ledgerEvent{
+			id:        tx.ID,
+			tradeTime: tx.TradeTime,
+			oldTx:     tx,
+			newTx:     newTx,
+		})
+	}
+
+	// 2. 变更后的交易只计入"变更后"
+	if after != nil && inScope(after) {
+		events = append(events, &ledgerEvent{
+			id:        after.ID,
+			tradeTime: after.TradeTime,
+			newTx:     after,
+		})
+	}
+
+	// 3. 按交易时间排序；同一时间按 ID 排序，新交易（ID 为 0）排在最后
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].tradeTime.Equal(events[j].tradeTime) {
+			return events[i].tradeTime.Before(events[j].tradeTime)
+		}
+		return sortID(events[i].id) < sortID(events[j].id)
+	})
+
+	return events
+}
+
+// applyQuantity 回放一笔交易后的持仓数量
+// 买入/转入增加，卖出/转出减少，拆股按比例缩放，现金类交易不影响
+func applyQuantity(qty decimal.Decimal, tx *entity.Transaction) decimal.Decimal {
+	if tx == nil {
+		return qty
+	}
+	switch tx.Type {
+	case entity.TransactionTypeBuy, entity.TransactionTypeTransferIn:
+		return qty.Add(tx.Quantity)
+	case entity.TransactionTypeSell, entity.TransactionTypeTransferOut:
+		return qty.Sub(tx.Quantity)
+	case entity.TransactionTypeSplit:
+		return qty.Mul(tx.Ratio).Round(4)
+	default:
+		return qty
+	}
+}
+
+// sortID 排序用 ID：尚未入库的交易（ID 为 0）视为最新
+func sortID(id uint) uint {
+	if id == 0 {
+		return math.MaxUint
+	}
+	return id
+}
@@ -0,0 +1,233 @@
+package impl
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	txDomain "github.com/florentyang/smartfin-go/internal/domain/transaction"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 测试数据构造 ====================
+
+// ledgerTx 构造账户 1 的一笔 AAPL 交易（2024 年 1 月 d 日）
+// id 为 0 表示尚未入库的新交易
+func ledgerTx(id uint, txType string, d int, quantity string) *entity.Transaction {
+	return &entity.Transaction{
+		ID:        id,
+		AccountID: 1,
+		Symbol:    "AAPL",
+		Type:      txType,
+		Quantity:  decimal.RequireFromString(quantity),
+		TradeTime: time.Date(2024, 1, d, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+// splitTx 构造一笔拆股
+func splitTx(id uint, d int, ratio string) *entity.Transaction {
+	tx := ledgerTx(id, entity.TransactionTypeSplit, d, "0")
+	tx.Ratio = decimal.RequireFromString(ratio)
+	return tx
+}
+
+// modified 复制一笔交易并修改（模拟 Update 的变更后交易）
+func modified(tx *entity.Transaction, change func(tx *entity.Transaction)) *entity.Transaction {
+	copied := *tx
+	change(&copied)
+	return &copied
+}
+
+// ==================== 测试用例 ====================
+
+func TestCheckLedger(t *testing.T) {
+	const (
+		buy         = entity.TransactionTypeBuy
+		sell        = entity.TransactionTypeSell
+		transferIn  = entity.TransactionTypeTransferIn
+		transferOut = entity.TransactionTypeTransferOut
+	)
+	scope := positionScope{symbol: "AAPL", accountID: 1}
+
+	buy1 := ledgerTx(1, buy, 1, "10")
+	buy2 := ledgerTx(2, buy, 2, "10")
+	split2 := splitTx(2, 2, "2")
+	transferIn1 := ledgerTx(1, transferIn, 1, "10")
+
+	tests := []struct {
+		name    string
+		ledger  []*entity.Transaction
+		before  *entity.Transaction
+		after   *entity.Transaction
+		wantErr bool
+	}{
+		// ---------- 新增卖出 ----------
+		{
+			name:   "卖出不超过持仓",
+			ledger: []*entity.Transaction{buy1},
+			after:  ledgerTx(0, sell, 2, "10"),
+		},
+		{
+			name:    "卖出超过持仓",
+			ledger:  []*entity.Transaction{buy1},
+			after:   ledgerTx(0, sell, 2, "10.5"),
+			wantErr: true,
+		},
+		{
+			// 卖出时点之后才有买入，最终持仓为正也不行
+			name:    "补录更早日期的卖出，当时没有持仓",
+			ledger:  []*entity.Transaction{ledgerTx(1, buy, 5, "10")},
+			after:   ledgerTx(0, sell, 2, "5"),
+			wantErr: true,
+		},
+		{
+			name:   "补录更早日期的卖出，当时持仓足够",
+			ledger: []*entity.Transaction{buy1, ledgerTx(2, sell, 5, "5")},
+			after:  ledgerTx(0, sell, 3, "5"),
+		},
+		{
+			name:    "补录更早日期的卖出，导致之后已有的卖出超卖",
+			ledger:  []*entity.Transaction{buy1, ledgerTx(2, sell, 5, "8")},
+			after:   ledgerTx(0, sell, 3, "5"),
+			wantErr: true,
+		},
+		{
+			// 同一时间的新交易排在已有交易之后
+			name:    "同一时间的新卖出排在已有卖出之后",
+			ledger:  []*entity.Transaction{buy1, ledgerTx(2, sell, 1, "10")},
+			after:   ledgerTx(0, sell, 1, "1"),
+			wantErr: true,
+		},
+
+		// ---------- 修改、删除 ----------
+		{
+			name:    "删除买入导致之后的卖出超卖",
+			ledger:  []*entity.Transaction{buy1, buy2, ledgerTx(3, sell, 3, "15")},
+			before:  buy2,
+			wantErr: true,
+		},
+		{
+			name:   "删除卖出不会超卖",
+			ledger: []*entity.Transaction{buy1, ledgerTx(2, sell, 3, "10")},
+			before: ledgerTx(2, sell, 3, "10"),
+		},
+		{
+			name:    "买入改到卖出之后",
+			ledger:  []*entity.Transaction{buy1, buy2, ledgerTx(3, sell, 5, "15")},
+			before:  buy2,
+			after:   modified(buy2, func(tx *entity.Transaction) { tx.TradeTime = tx.TradeTime.AddDate(0, 0, 4) }),
+			wantErr: true,
+		},
+		{
+			name:   "卖出改到更晚的日期，持仓足够",
+			ledger: []*entity.Transaction{buy1, ledgerTx(2, sell, 3, "10")},
+			before: ledgerTx(2, sell, 3, "10"),
+			after:  ledgerTx(2, sell, 6, "10"),
+		},
+		{
+			name:    "买入改到其他账户，原账户的卖出超卖",
+			ledger:  []*entity.Transaction{buy1, ledgerTx(2, sell, 3, "5")},
+			before:  buy1,
+			after:   modified(buy1, func(tx *entity.Transaction) { tx.AccountID = 2 }),
+			wantErr: true,
+		},
+		{
+			name:   "其他账户的新卖出不计入本账户",
+			ledger: []*entity.Transaction{buy1},
+			after:  modified(ledgerTx(0, sell, 2, "20"), func(tx *entity.Transaction) { tx.AccountID = 2 }),
+		},
+
+		// ---------- 拆股 ----------
+		{
+			name:   "拆股后按新数量卖出",
+			ledger: []*entity.Transaction{buy1, split2},
+			after:  ledgerTx(0, sell, 3, "20"),
+		},
+		{
+			name:    "拆股前按原数量校验",
+			ledger:  []*entity.Transaction{buy1, splitTx(2, 3, "2")},
+			after:   ledgerTx(0, sell, 2, "15"),
+			wantErr: true,
+		},
+		{
+			name:    "合股后持仓减少",
+			ledger:  []*entity.Transaction{buy1, splitTx(2, 2, "0.5")},
+			after:   ledgerTx(0, sell, 3, "6"),
+			wantErr: true,
+		},
+		{
+			name:    "调小拆股比例导致之后的卖出超卖",
+			ledger:  []*entity.Transaction{buy1, split2, ledgerTx(3, sell, 4, "20")},
+			before:  split2,
+			after:   modified(split2, func(tx *entity.Transaction) { tx.Ratio = decimal.RequireFromString("1.5") }),
+			wantErr: true,
+		},
+		{
+			name:    "删除拆股导致之后的卖出超卖",
+			ledger:  []*entity.Transaction{buy1, split2, ledgerTx(3, sell, 4, "20")},
+			before:  split2,
+			wantErr: true,
+		},
+
+		// ---------- 转托管 ----------
+		{
+			name:   "转入的持仓可以卖出",
+			ledger: []*entity.Transaction{transferIn1},
+			after:  ledgerTx(0, sell, 2, "10"),
+		},
+		{
+			name:    "转出超过持仓",
+			ledger:  []*entity.Transaction{buy1},
+			after:   ledgerTx(0, transferOut, 2, "12"),
+			wantErr: true,
+		},
+		{
+			name:    "转出之后的卖出超卖",
+			ledger:  []*entity.Transaction{buy1, ledgerTx(2, sell, 5, "5")},
+			after:   ledgerTx(0, transferOut, 3, "10"),
+			wantErr: true,
+		},
+		{
+			name:    "删除转入导致之后的卖出超卖",
+			ledger:  []*entity.Transaction{transferIn1, ledgerTx(2, sell, 3, "10")},
+			before:  transferIn1,
+			wantErr: true,
+		},
+
+		// ---------- 已有负持仓 ----------
+		{
+			name:   "已有的负持仓不阻塞无关的买入",
+			ledger: []*entity.Transaction{ledgerTx(1, sell, 1, "5")},
+			after:  ledgerTx(0, buy, 2, "2"),
+		},
+		{
+			name:   "已有的负持仓不阻塞修改备注",
+			ledger: []*entity.Transaction{ledgerTx(1, sell, 1, "5")},
+			before: ledgerTx(1, sell, 1, "5"),
+			after:  modified(ledgerTx(1, sell, 1, "5"), func(tx *entity.Transaction) { tx.Notes = "补录" }),
+		},
+		{
+			name:    "已有负持仓时继续卖出",
+			ledger:  []*entity.Transaction{ledgerTx(1, sell, 1, "5")},
+			after:   ledgerTx(0, sell, 2, "1"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkLedger(scope, tt.ledger, tt.before, tt.after)
+			if tt.wantErr {
+				if !errors.Is(err, txDomain.ErrInsufficientPosition) {
+					t.Fatalf("checkLedger() error = %v, want %v", err, txDomain.ErrInsufficientPosition)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("checkLedger() error = %v", err)
+			}
+		})
+	}
+}
@@ -1,76 +1,321 @@
 package impl
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
 
+	"github.com/florentyang/smartfin-go/internal/dao"
+	accountRepo "github.com/florentyang/smartfin-go/internal/dao/account"
+	snapshotRepo "github.com/florentyang/smartfin-go/internal/dao/snapshot"
 	txRepo "github.com/florentyang/smartfin-go/internal/dao/transaction"
+	userRepo "github.com/florentyang/smartfin-go/internal/dao/user"
+	accountDomain "github.com/florentyang/smartfin-go/internal/domain/account"
+	feeDomain "github.com/florentyang/smartfin-go/internal/domain/fee"
+	fxDomain "github.com/florentyang/smartfin-go/internal/domain/fx"
+	instrumentDomain "github.com/florentyang/smartfin-go/internal/domain/instrument"
+	journalDomain "github.com/florentyang/smartfin-go/internal/domain/journal"
+	lotDomain "github.com/florentyang/smartfin-go/internal/domain/lot"
+	marketDomain "github.com/florentyang/smartfin-go/internal/domain/market"
+	portfolioDomain "github.com/florentyang/smartfin-go/internal/domain/portfolio"
 	txDomain "github.com/florentyang/smartfin-go/internal/domain/transaction"
 	"github.com/florentyang/smartfin-go/internal/entity"
 )
 
+// errImportRollback 批量导入需要回滚时返回（存在错误行或试运行），不对外暴露
+var errImportRollback = errors.New("import rollback")
+
 // ==================== UseCase 结构体 ====================
 
 type usecase struct {
-	txRepo txRepo.Repo // 依赖 DAO 层接口
+	txRepo           txRepo.Repo                   // 依赖 DAO 层接口
+	userRepo         userRepo.Repo                 // 用户 DAO（读取卖空开关、成本计算方法，并加锁）
+	accountRepo      accountRepo.Repo              // 账户 DAO（校验交易所属账户的归属）
+	snapshotRepo     snapshotRepo.Repo             // 估值快照 DAO（交易变更后删除受影响的快照）
+	instrumentDomain instrumentDomain.Domain       // 证券主数据 Domain（规范化股票代码，补全名称）
+	feeDomain        feeDomain.Domain              // 费率表 Domain（未填写手续费时自动计算）
+	marketDomain     marketDomain.Domain           // 交易日历 Domain（校验交易时段）
+	lotDomain        lotDomain.Domain              // 批次 Domain（交易变更后重建批次）
+	journalDomain    journalDomain.Domain          // 分录 Domain（交易变更后重新过账）
+	snapshotQueue    portfolioDomain.SnapshotQueue // 快照补齐队列（提交后异步重新生成失效的快照）
+	transactor       dao.Transactor                // 事务管理器（交易、批次与分录原子写入）
 }
 
 // ==================== 构造函数 ====================
 
 // NewTransactionDomain 创建 Domain 实例
-func NewTransactionDomain(repo txRepo.Repo) txDomain.Domain {
+func NewTransactionDomain(
+	repo txRepo.Repo,
+	userRepo userRepo.Repo,
+	accountRepo accountRepo.Repo,
+	snapshotRepo snapshotRepo.Repo,
+	instrumentDomain instrumentDomain.Domain,
+	feeDomain feeDomain.Domain,
+	marketDomain marketDomain.Domain,
+	lotDomain lotDomain.Domain,
+	journalDomain journalDomain.Domain,
+	snapshotQueue portfolioDomain.SnapshotQueue,
+	transactor dao.Transactor,
+) txDomain.Domain {
+	return &usecase{
+		txRepo:           repo,
+		userRepo:         userRepo,
+		accountRepo:      accountRepo,
+		snapshotRepo:     snapshotRepo,
+		instrumentDomain: instrumentDomain,
+		feeDomain:        feeDomain,
+		marketDomain:     marketDomain,
+		lotDomain:        lotDomain,
+		journalDomain:    journalDomain,
+		snapshotQueue:    snapshotQueue,
+		transactor:       transactor,
+	}
+}
+
+// withTx 返回绑定到指定数据库事务的 usecase
+func (u *usecase) withTx(tx *gorm.DB) *usecase {
 	return &usecase{
-		txRepo: repo,
+		txRepo:           u.txRepo.WithTx(tx),
+		userRepo:         u.userRepo.WithTx(tx),
+		accountRepo:      u.accountRepo.WithTx(tx),
+		snapshotRepo:     u.snapshotRepo.WithTx(tx),
+		instrumentDomain: u.instrumentDomain.WithTx(tx),
+		feeDomain:        u.feeDomain.WithTx(tx),
+		marketDomain:     u.marketDomain,
+		lotDomain:        u.lotDomain.WithTx(tx),
+		journalDomain:    u.journalDomain.WithTx(tx),
+		snapshotQueue:    u.snapshotQueue,
+		transactor:       u.transactor,
 	}
 }
 
 // ==================== 业务方法实现 ====================
 
 // Create 创建交易记录
-// 核心业务逻辑都在这里：参数校验、金额计算
+// 交易写入、批次重建和复式记账过账在同一个数据库事务中完成，分录借贷不平衡时整体回滚
 func (u *usecase) Create(input *txDomain.CreateInput) (*entity.Transaction, error) {
+	var tx *entity.Transaction
+	err := u.transactor.Transaction(func(db *gorm.DB) error {
+		w := u.withTx(db)
 
-	// ========== 业务规则校验 ==========
+		// 1. 锁定用户行：串行化同一用户的账本写入，防止并发卖出绕过持仓校验
+		user, err := w.userRepo.GetByIDForUpdate(input.UserID)
+		if err != nil {
+			return err
+		}
 
-	// 1. 校验交易类型：只能是 BUY 或 SELL
-	if input.Type != entity.TransactionTypeBuy && input.Type != entity.TransactionTypeSell {
-		return nil, txDomain.ErrInvalidType
+		// 2. 校验并写入交易
+		tx, err = w.create(user, input)
+		if err != nil {
+			return err
+		}
+
+		// 3. 重建该股票的批次并重新过账
+		if err := w.rebuild(user.ID, tx.Symbol); err != nil {
+			return err
+		}
+
+		// 4. 交易日期及之后的估值快照失效（提交后由快照队列异步重新生成）
+		return w.snapshotRepo.DeleteFrom(user.ID, fxDomain.DateOf(tx.TradeTime))
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// 2. 校验数量：必须大于 0
-	if input.Quantity.LessThanOrEqual(decimal.Zero) {
-		return nil, txDomain.ErrInvalidQuantity
+	u.snapshotQueue.Enqueue(input.UserID)
+	return tx, nil
+}
+
+// Import 批量导入交易
+// 所有行在同一个数据库事务中逐行走与 Create 相同的业务规则，
+// 后面的行可以看到前面的行（如先买后卖）。任意一行失败或试运行时整体回滚
+// 带券商成交编号的行先去重，已导入过的跳过，因此同一份对账单可以安全地重复导入
+func (u *usecase) Import(input *txDomain.ImportInput) (*txDomain.ImportOutput, error) {
+	output := &txDomain.ImportOutput{
+		Total: len(input.Rows),
 	}
 
-	// 3. 校验单价：必须大于 0
-	if input.Price.LessThanOrEqual(decimal.Zero) {
-		return nil, txDomain.ErrInvalidPrice
+	err := u.transactor.Transaction(func(db *gorm.DB) error {
+		w := u.withTx(db)
+
+		// 1. 锁定用户行
+		user, err := w.userRepo.GetByIDForUpdate(input.UserID)
+		if err != nil {
+			return err
+		}
+
+		// 2. 目标账户必须属于当前用户（整个文件导入同一个账户）
+		if err := w.checkAccount(user.ID, input.AccountID); err != nil {
+			return err
+		}
+
+		// 3. 逐行校验并写入，收集每一行的错误
+		var symbols []string
+		var earliest time.Time
+		touched := make(map[string]bool)
+		for i, row := range input.Rows {
+			row.UserID = input.UserID
+			row.AccountID = input.AccountID
+			tx, err := w.create(user, row)
+			if errors.Is(err, txDomain.ErrDuplicateTrade) {
+				// 重复导入同一份对账单：已有的成交直接跳过
+				output.Skipped++
+				continue
+			}
+			if err != nil {
+				output.Errors = append(output.Errors, &txDomain.ImportRowError{Index: i, Err: err})
+				continue
+			}
+			output.Valid++
+			if earliest.IsZero() || tx.TradeTime.Before(earliest) {
+				earliest = tx.TradeTime
+			}
+			if !touched[tx.Symbol] {
+				touched[tx.Symbol] = true
+				symbols = append(symbols, tx.Symbol)
+			}
+		}
+		if len(output.Errors) > 0 {
+			return errImportRollback
+		}
+
+		// 4. 全部行写入后，按股票重建批次并重新过账
+		for _, symbol := range symbols {
+			if err := w.rebuild(user.ID, symbol); err != nil {
+				return err
+			}
+		}
+
+		// 5. 最早一笔交易日期及之后的估值快照失效
+		if !earliest.IsZero() {
+			if err := w.snapshotRepo.DeleteFrom(user.ID, fxDomain.DateOf(earliest)); err != nil {
+				return err
+			}
+		}
+
+		// 6. 试运行：校验全部通过也回滚
+		if input.DryRun {
+			return errImportRollback
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errImportRollback) {
+		return nil, err
 	}
 
-	// ========== 核心计算 ==========
+	output.Committed = err == nil
+	if output.Committed && output.Valid > 0 {
+		u.snapshotQueue.Enqueue(input.UserID)
+	}
+	return output, nil
+}
 
-	// 4. 计算成交总金额：数量 × 单价
-	//    使用 decimal 库保证精度，避免浮点数误差
-	amount := input.Quantity.Mul(input.Price)
+// create 在已锁定用户的事务中校验并写入一笔交易（不重建批次、不过账）
+// Create 和 Import 共用，保证单笔创建与批量导入的业务规则完全一致
+func (u *usecase) create(user *entity.User, input *txDomain.CreateInput) (*entity.Transaction, error) {
 
 	// ========== 创建实体 ==========
 
-	// 5. 组装 Transaction 实体
+	// 1. 组装 Transaction 实体
 	tx := &entity.Transaction{
 		UserID:    input.UserID,
+		AccountID: input.AccountID,
 		Symbol:    input.Symbol,
 		Name:      input.Name,
 		Type:      input.Type,
 		Quantity:  input.Quantity,
 		Price:     input.Price,
-		Amount:    amount, // 后端计算的总金额
-		Fee:       input.Fee,
+		Amount:    input.Amount,
+		Ratio:     input.Ratio,
 		TradeTime: input.TradeTime,
 		Notes:     input.Notes,
+		Source:    input.Source,
+	}
+	if input.Fee != nil {
+		tx.Fee = *input.Fee
+	}
+	if input.BrokerTradeID != "" {
+		tx.BrokerTradeID = &input.BrokerTradeID
+	}
+
+	// ========== 业务规则校验 & 核心计算 ==========
+
+	// 2~6. 按交易类型校验字段，并计算金额（买卖：数量 × 单价）
+	if err := applyTypeRules(tx); err != nil {
+		return nil, err
+	}
+
+	// 7. 账户必须属于当前用户
+	if err := u.checkAccount(user.ID, tx.AccountID); err != nil {
+		return nil, err
+	}
+
+	// 8. 规范化股票代码，按证券主数据补全名称和币种
+	instrument, err := u.applyInstrument(user, tx, input.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	// 9. 确定币种（默认为证券的交易币种或用户的基准货币），同一股票的买卖、转入转出币种必须一致
+	if err := u.applyCurrency(user, tx, input.Currency); err != nil {
+		return nil, err
+	}
+
+	// 10. 未填写手续费的买卖按费率表自动计算
+	if err := u.applyFee(tx, input.Fee); err != nil {
+		return nil, err
+	}
+
+	// 11. 交易所规则（用户开启时）：交易时段、买入整手，A 股 T+1、涨跌停
+	if err := u.checkMarketRules(user, tx, instrument, input.PrevClose); err != nil {
+		return nil, err
+	}
+
+	// 12. 确定卖出/转出的成本计算方法（账户或用户的默认方法，或指定批次）
+	method, err := u.defaultMethod(user, tx.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyCostBasis(method, tx, input.LotIDs); err != nil {
+		return nil, err
+	}
+
+	// ========== 去重 ==========
+
+	// 13. 同一来源的券商成交编号只能导入一次（同一批次内的重复行也能查到）
+	if tx.BrokerTradeID != nil {
+		exists, err := u.txRepo.ExistsBrokerTrade(tx.UserID, tx.Source, *tx.BrokerTradeID)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, txDomain.ErrDuplicateTrade
+		}
+	}
+
+	// ========== 持仓与现金校验 ==========
+
+	// 14. 卖出、转出、合股不能使交易时间点之后的持仓变为负数（含补录的历史交易）
+	if reducesPosition(tx.Type) {
+		if err := u.checkPosition(user, nil, tx); err != nil {
+			return nil, err
+		}
+	}
+
+	// 15. 买入、出金、费用不能使非融资账户的现金余额变为负数
+	if entity.CashFlow(tx).IsNegative() {
+		if err := u.checkCash(user.ID, nil, tx); err != nil {
+			return nil, err
+		}
 	}
 
 	// ========== 持久化 ==========
 
-	// 6. 调用 DAO 层存入数据库
+	// 16. 调用 DAO 层存入数据库
 	if err := u.txRepo.Create(tx); err != nil {
 		return nil, err
 	}
@@ -78,13 +323,206 @@ func (u *usecase) Create(input *txDomain.CreateInput) (*entity.Transaction, erro
 	return tx, nil
 }
 
+// Get 查询单条交易记录
+// 交易不属于当前用户时按"不存在"处理，避免泄露他人数据
+func (u *usecase) Get(userID, id uint) (*entity.Transaction, error) {
+	tx, err := u.txRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, txRepo.ErrTransactionNotFound) {
+			return nil, txDomain.ErrTransactionNotFound
+		}
+		return nil, err
+	}
+
+	// 归属校验：只能访问自己的交易
+	if tx.UserID != userID {
+		return nil, txDomain.ErrTransactionNotFound
+	}
+
+	return tx, nil
+}
+
+// Update 更新交易记录
+// 核心业务逻辑：归属校验、参数校验、重新计算金额、持仓与现金校验、批次重建
+func (u *usecase) Update(input *txDomain.UpdateInput) (*entity.Transaction, error) {
+	var tx *entity.Transaction
+	err := u.transactor.Transaction(func(db *gorm.DB) error {
+		w := u.withTx(db)
+
+		// 1. 锁定用户行
+		user, err := w.userRepo.GetByIDForUpdate(input.UserID)
+		if err != nil {
+			return err
+		}
+
+		// 2. 查询并校验归属
+		tx, err = w.Get(input.UserID, input.ID)
+		if err != nil {
+			return err
+		}
+
+		// 3. 覆盖可编辑字段（保留变更前的副本用于持仓校验）
+		before := *tx
+		tx.Symbol = input.Symbol
+		tx.Name = input.Name
+		tx.Type = input.Type
+		tx.Quantity = input.Quantity
+		tx.Price = input.Price
+		tx.Amount = input.Amount
+		tx.Fee = decimal.Zero
+		if input.Fee != nil {
+			tx.Fee = *input.Fee
+		}
+		tx.Ratio = input.Ratio
+		tx.TradeTime = input.TradeTime
+		tx.Notes = input.Notes
+		if input.AccountID != nil {
+			tx.AccountID = *input.AccountID
+		}
+
+		// 4. 按交易类型校验（与创建时一致），并重新计算金额
+		if err := applyTypeRules(tx); err != nil {
+			return err
+		}
+
+		// 5. 账户必须属于当前用户
+		if err := w.checkAccount(user.ID, tx.AccountID); err != nil {
+			return err
+		}
+
+		// 6. 规范化股票代码，按证券主数据补全名称
+		instrument, err := w.applyInstrument(user, tx, input.Currency)
+		if err != nil {
+			return err
+		}
+
+		// 7. 确定币种（不传则保持不变）
+		if err := w.applyCurrency(user, tx, input.Currency); err != nil {
+			return err
+		}
+
+		// 8. 未填写手续费的买卖按费率表重新计算（数量、单价、账户可能已变化）
+		if err := w.applyFee(tx, input.Fee); err != nil {
+			return err
+		}
+
+		// 9. 交易所规则（修改时没有昨收价，不校验涨跌停）
+		if err := w.checkMarketRules(user, tx, instrument, decimal.Zero); err != nil {
+			return err
+		}
+
+		// 10. 确定卖出/转出的成本计算方法
+		method, err := w.defaultMethod(user, tx.AccountID)
+		if err != nil {
+			return err
+		}
+		if err := applyCostBasis(method, tx, input.LotIDs); err != nil {
+			return err
+		}
+
+		// 11. 修改后不能造成超卖（如调小买入数量、把买入改成卖出、修改拆股比例、更换账户）
+		if err := w.checkPosition(user, &before, tx); err != nil {
+			return err
+		}
+
+		// 12. 修改后不能使非融资账户的现金余额变为负数（如调大买入金额、更换账户或币种）
+		if err := w.checkCash(user.ID, &before, tx); err != nil {
+			return err
+		}
+
+		// 13. 调用 DAO 层保存
+		if err := w.txRepo.Update(tx); err != nil {
+			return err
+		}
+
+		// 14. 重建批次并重新过账（修改了股票代码时，新旧两只股票都要重建）
+		if before.Symbol != tx.Symbol {
+			if err := w.rebuild(user.ID, before.Symbol); err != nil {
+				return err
+			}
+		}
+		if err := w.rebuild(user.ID, tx.Symbol); err != nil {
+			return err
+		}
+
+		// 15. 修改前后较早的交易日期及之后的估值快照失效
+		from := before.TradeTime
+		if tx.TradeTime.Before(from) {
+			from = tx.TradeTime
+		}
+		return w.snapshotRepo.DeleteFrom(user.ID, fxDomain.DateOf(from))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	u.snapshotQueue.Enqueue(input.UserID)
+	return tx, nil
+}
+
+// Delete 删除交易记录
+func (u *usecase) Delete(userID, id uint) error {
+	err := u.transactor.Transaction(func(db *gorm.DB) error {
+		w := u.withTx(db)
+
+		// 1. 锁定用户行
+		user, err := w.userRepo.GetByIDForUpdate(userID)
+		if err != nil {
+			return err
+		}
+
+		// 2. 查询并校验归属
+		tx, err := w.Get(userID, id)
+		if err != nil {
+			return err
+		}
+
+		// 3. 删除买入、转入、拆股不能导致其后的卖出超卖
+		if !entity.IsCashTransactionType(tx.Type) {
+			if err := w.checkPosition(user, tx, nil); err != nil {
+				return err
+			}
+		}
+
+		// 4. 删除卖出、入金、分红、利息不能导致其后的支出使现金余额为负
+		if entity.CashFlow(tx).IsPositive() {
+			if err := w.checkCash(user.ID, tx, nil); err != nil {
+				return err
+			}
+		}
+
+		// 5. 调用 DAO 层删除
+		if err := w.txRepo.Delete(tx.ID); err != nil {
+			if errors.Is(err, txRepo.ErrTransactionNotFound) {
+				return txDomain.ErrTransactionNotFound
+			}
+			return err
+		}
+
+		// 6. 重建批次并重新过账（被指定过的买入批次不能删除，回放会报错并回滚）
+		if err := w.rebuild(user.ID, tx.Symbol); err != nil {
+			return err
+		}
+
+		// 7. 交易日期及之后的估值快照失效
+		return w.snapshotRepo.DeleteFrom(user.ID, fxDomain.DateOf(tx.TradeTime))
+	})
+	if err != nil {
+		return err
+	}
+
+	u.snapshotQueue.Enqueue(userID)
+	return nil
+}
+
 // List 查询交易列表
 // 这里业务逻辑比较简单，主要是将 Domain 的 Input 转换为 DAO 的 Filter
 func (u *usecase) List(input *txDomain.ListInput) (*txDomain.ListOutput, error) {
 	// 构建 DAO 层的查询条件
 	filter := &txRepo.ListFilter{
 		UserID:    input.UserID,
-		Symbol:    input.Symbol,
+		AccountID: input.AccountID,
+		Symbol:    filterSymbol(input.Symbol),
 		Type:      input.Type,
 		StartTime: input.StartTime,
 		EndTime:   input.EndTime,
@@ -103,3 +541,291 @@ func (u *usecase) List(input *txDomain.ListInput) (*txDomain.ListOutput, error)
 		Total: total,
 	}, nil
 }
+
+// Export 按筛选条件逐条读取全部交易
+func (u *usecase) Export(input *txDomain.ListInput, fn func(tx *entity.Transaction) error) error {
+	return u.txRepo.Stream(&txRepo.ListFilter{
+		UserID:    input.UserID,
+		AccountID: input.AccountID,
+		Symbol:    filterSymbol(input.Symbol),
+		Type:      input.Type,
+		StartTime: input.StartTime,
+		EndTime:   input.EndTime,
+	}, fn)
+}
+
+// ==================== 私有辅助函数 ====================
+
+// rebuild 重建某只股票的批次并重新过账
+// 没有股票代码的现金交易不涉及批次，只过账
+func (u *usecase) rebuild(userID uint, symbol string) error {
+	if symbol != "" {
+		if err := u.lotDomain.Rebuild(userID, symbol); err != nil {
+			return err
+		}
+	}
+	return u.journalDomain.Post(userID, symbol)
+}
+
+// filterSymbol 按股票代码筛选时同样规范化（如 aapl → AAPL），无效的代码原样查询
+func filterSymbol(symbol string) string {
+	if symbol == "" {
+		return ""
+	}
+	normalized, err := instrumentDomain.NormalizeSymbol(symbol, "")
+	if err != nil {
+		return symbol
+	}
+	return normalized
+}
+
+// checkAccount 校验账户属于当前用户（0 表示未指定账户，不需要校验）
+func (u *usecase) checkAccount(userID, accountID uint) error {
+	if accountID == 0 {
+		return nil
+	}
+	account, err := u.accountRepo.GetByID(accountID)
+	if err != nil {
+		if errors.Is(err, accountRepo.ErrAccountNotFound) {
+			return accountDomain.ErrAccountNotFound
+		}
+		return err
+	}
+	if account.UserID != userID {
+		return accountDomain.ErrAccountNotFound
+	}
+	return nil
+}
+
+// applyTypeRules 按交易类型校验字段并计算金额
+// 创建和更新共用，保证两条路径的校验口径一致
+// - BUY / SELL：数量、单价大于 0，金额 = 数量 × 单价
+// - 现金类（DIVIDEND / INTEREST / FEE / DEPOSIT / WITHDRAWAL）：没有数量和单价，金额大于 0；分红必须关联股票
+// - SPLIT：比例大于 0 且不等于 1，没有数量、单价和金额
+// - TRANSFER_IN：数量大于 0，金额为转入成本（可以为 0，如赠股），单价 = 成本 / 数量
+// - TRANSFER_OUT：数量大于 0，没有单价和金额（结转成本由批次引擎按成本计算方法确定）
+func applyTypeRules(tx *entity.Transaction) error {
+	// 1. 校验交易类型
+	if !entity.IsValidTransactionType(tx.Type) {
+		return txDomain.ErrInvalidType
+	}
+
+	// 2. 校验手续费：不能为负数
+	if tx.Fee.IsNegative() {
+		return txDomain.ErrInvalidFee
+	}
+
+	// 3. 除现金类外都必须有股票代码（分红也必须有）
+	if tx.Symbol == "" && (!entity.IsCashTransactionType(tx.Type) || tx.Type == entity.TransactionTypeDividend) {
+		return txDomain.ErrSymbolRequired
+	}
+
+	// 4. 拆股比例只属于 SPLIT
+	if tx.Type != entity.TransactionTypeSplit && !tx.Ratio.IsZero() {
+		return txDomain.ErrFieldNotAllowed
+	}
+
+	// 5~6. 按类型校验数量/单价/金额
+	switch {
+	case tx.Type == entity.TransactionTypeBuy || tx.Type == entity.TransactionTypeSell:
+		if tx.Quantity.LessThanOrEqual(decimal.Zero) {
+			return txDomain.ErrInvalidQuantity
+		}
+		if tx.Price.LessThanOrEqual(decimal.Zero) {
+			return txDomain.ErrInvalidPrice
+		}
+		// 成交总金额：数量 × 单价（使用 decimal 库保证精度，避免浮点数误差）
+		tx.Amount = tx.Quantity.Mul(tx.Price)
+
+	case entity.IsCashTransactionType(tx.Type):
+		if !tx.Quantity.IsZero() || !tx.Price.IsZero() {
+			return txDomain.ErrQuantityNotAllowed
+		}
+		if tx.Amount.LessThanOrEqual(decimal.Zero) {
+			return txDomain.ErrInvalidAmount
+		}
+
+	case tx.Type == entity.TransactionTypeSplit:
+		if !tx.Quantity.IsZero() || !tx.Price.IsZero() || !tx.Amount.IsZero() {
+			return txDomain.ErrFieldNotAllowed
+		}
+		if tx.Ratio.LessThanOrEqual(decimal.Zero) || tx.Ratio.Equal(decimal.NewFromInt(1)) {
+			return txDomain.ErrInvalidRatio
+		}
+
+	case tx.Type == entity.TransactionTypeTransferIn:
+		if tx.Quantity.LessThanOrEqual(decimal.Zero) {
+			return txDomain.ErrInvalidQuantity
+		}
+		if tx.Amount.IsNegative() {
+			return txDomain.ErrInvalidCostBasis
+		}
+		// 单价仅供展示：转入成本 / 数量
+		tx.Price = tx.Amount.Div(tx.Quantity).Round(4)
+
+	case tx.Type == entity.TransactionTypeTransferOut:
+		if tx.Quantity.LessThanOrEqual(decimal.Zero) {
+			return txDomain.ErrInvalidQuantity
+		}
+		if !tx.Price.IsZero() || !tx.Amount.IsZero() {
+			return txDomain.ErrFieldNotAllowed
+		}
+	}
+
+	return nil
+}
+
+// applyInstrument 规范化股票代码，并按证券主数据补全名称和币种
+// - 股票代码统一写法（见 instrumentDomain.NormalizeSymbol），同一只股票的不同写法归为同一个持仓
+// - 主数据中有该证券时：未填写名称的使用主数据的名称；未传币种的新交易使用证券的交易币种
+// 没有股票代码的现金交易跳过，返回主数据中的证券（没有时为 nil）
+func (u *usecase) applyInstrument(user *entity.User, tx *entity.Transaction, currency string) (*entity.Instrument, error) {
+	if tx.Symbol == "" {
+		return nil, nil
+	}
+
+	// 1. 推断市场所用的币种：传了币种用传入的，否则修改时用原币种，新增时用基准货币
+	hint := strings.ToUpper(strings.TrimSpace(currency))
+	if hint == "" {
+		hint = tx.Currency
+	}
+	if hint == "" {
+		hint = fxDomain.BaseCurrencyOf(user)
+	}
+
+	// 2. 规范化股票代码并查找主数据
+	resolved, err := u.instrumentDomain.Resolve(tx.Symbol, hint)
+	if err != nil {
+		return nil, err
+	}
+	tx.Symbol = resolved.Symbol
+	instrument := resolved.Instrument
+	if instrument == nil {
+		return nil, nil
+	}
+
+	// 3. 补全名称和币种
+	if strings.TrimSpace(tx.Name) == "" {
+		tx.Name = instrument.Name
+	}
+	if currency == "" && tx.Currency == "" {
+		tx.Currency = instrument.Currency
+	}
+	return instrument, nil
+}
+
+// applyCurrency 确定交易币种并校验同一股票的币种一致
+// - 传了币种：校验并规范化（转大写）
+// - 未传币种：修改时保持原币种，新增时使用证券主数据的交易币种（已在 applyInstrument 填写），否则使用用户的基准货币
+// 买卖、转入转出的金额会进入批次成本，同一股票只能有一种币种；现金类交易（如以美元派发的港股分红）不受限制
+func (u *usecase) applyCurrency(user *entity.User, tx *entity.Transaction, currency string) error {
+	// 1. 确定币种
+	switch {
+	case currency != "":
+		code, err := fxDomain.NormalizeCurrency(currency)
+		if err != nil {
+			return err
+		}
+		tx.Currency = code
+	case tx.Currency == "":
+		tx.Currency = fxDomain.BaseCurrencyOf(user)
+	}
+
+	// 2. 校验同一股票的币种一致（排除交易自身，修改时可以整体更换唯一一笔交易的币种）
+	if !entity.AffectsCostBasis(tx.Type) {
+		return nil
+	}
+	currencies, err := u.txRepo.FindCurrencies(&txRepo.CurrencyFilter{
+		UserID:    tx.UserID,
+		Symbol:    tx.Symbol,
+		Types:     entity.CostBasisTransactionTypes,
+		ExcludeID: tx.ID,
+	})
+	if err != nil {
+		return err
+	}
+	for _, c := range currencies {
+		if c != tx.Currency {
+			return fmt.Errorf("%w：%s 已有 %s 计价的交易", txDomain.ErrCurrencyMismatch, tx.Symbol, c)
+		}
+	}
+	return nil
+}
+
+// applyFee 确定交易的手续费
+// - 填写了手续费：按填写的金额，清空费率表明细
+// - 未填写的买卖：匹配费率表计算手续费并记录明细；没有匹配的费率表时手续费为 0
+// - 未填写的其他类型：手续费为 0
+func (u *usecase) applyFee(tx *entity.Transaction, fee *decimal.Decimal) error {
+	tx.FeeScheduleID = 0
+	tx.FeeDetail = entity.FeeBreakdown{}
+	if fee != nil || (tx.Type != entity.TransactionTypeBuy && tx.Type != entity.TransactionTypeSell) {
+		return nil
+	}
+
+	quote, err := u.feeDomain.Quote(tx)
+	if err != nil {
+		return err
+	}
+	if quote == nil {
+		tx.Fee = decimal.Zero
+		return nil
+	}
+	tx.Fee = quote.Breakdown.Total()
+	tx.FeeScheduleID = quote.Schedule.ID
+	tx.FeeDetail = quote.Breakdown
+	return nil
+}
+
+// reducesPosition 交易是否可能减少持仓（新增时需要做持仓校验）
+func reducesPosition(txType string) bool {
+	return entity.ConsumesLots(txType) || txType == entity.TransactionTypeSplit
+}
+
+// defaultMethod 交易所属账户的默认成本计算方法（账户设置优先于用户设置，见 lotDomain.DefaultMethod）
+// 账户不存在时按未指定账户处理（归属校验另行报错）
+func (u *usecase) defaultMethod(user *entity.User, accountID uint) (string, error) {
+	if accountID == 0 {
+		return lotDomain.DefaultMethod(user, nil), nil
+	}
+	account, err := u.accountRepo.GetByID(accountID)
+	if err != nil {
+		if errors.Is(err, accountRepo.ErrAccountNotFound) {
+			return lotDomain.DefaultMethod(user, nil), nil
+		}
+		return "", err
+	}
+	return lotDomain.DefaultMethod(user, account), nil
+}
+
+// applyCostBasis 确定卖出/转出交易的成本计算方法
+// - 指定了批次：SPECIFIC，记录批次ID列表
+// - 未传批次（nil）：沿用交易上已有的方法和批次（修改时保持稳定，包括 SPECIFIC），新交易用默认方法
+// - 显式传空数组：取消指定批次，沿用已有的 FIFO/LIFO/HIFO，否则用默认方法
+// defaultMethod 为所属账户的默认方法（见 defaultMethod）；其他类型的交易不能指定批次
+func applyCostBasis(defaultMethod string, tx *entity.Transaction, lotIDs []uint) error {
+	if !entity.ConsumesLots(tx.Type) {
+		if len(lotIDs) > 0 {
+			return fmt.Errorf("%w：只有卖出、转出交易可以指定批次", lotDomain.ErrInvalidLotSelection)
+		}
+		tx.CostBasisMethod = ""
+		tx.LotIDs = ""
+		return nil
+	}
+
+	if len(lotIDs) > 0 {
+		tx.CostBasisMethod = entity.CostBasisSpecific
+		tx.LotIDs = lotDomain.EncodeLotIDs(lotIDs)
+		return nil
+	}
+	if lotIDs == nil && tx.CostBasisMethod == entity.CostBasisSpecific && tx.LotIDs != "" {
+		return nil
+	}
+
+	tx.LotIDs = ""
+	if lotDomain.IsValidMethod(tx.CostBasisMethod) {
+		return nil
+	}
+	tx.CostBasisMethod = defaultMethod
+	return nil
+}
@@ -14,9 +14,36 @@ import (
 
 var (
 	ErrTransactionNotFound = errors.New("交易记录不存在")
-	ErrInvalidType         = errors.New("交易类型无效，必须是 BUY 或 SELL")
+	ErrInvalidType         = errors.New("交易类型无效")
 	ErrInvalidQuantity     = errors.New("交易数量必须大于 0")
 	ErrInvalidPrice        = errors.New("交易单价必须大于 0")
+	ErrInvalidFee          = errors.New("手续费不能为负数")
+
+	// 按交易类型的字段校验
+	ErrSymbolRequired     = errors.New("该交易类型必须填写股票代码")
+	ErrQuantityNotAllowed = errors.New("现金类交易不能填写数量和单价")
+	ErrInvalidAmount      = errors.New("现金类交易的金额必须大于 0")
+	ErrInvalidRatio       = errors.New("拆股比例必须大于 0 且不等于 1")
+	ErrInvalidCostBasis   = errors.New("转入成本不能为负数")
+	ErrFieldNotAllowed    = errors.New("该交易类型不能填写单价、金额或拆股比例")
+
+	// ErrCurrencyMismatch 同一股票的买卖、转入转出使用了不同币种（批次成本无法累加）
+	ErrCurrencyMismatch = errors.New("同一股票的买卖、转入转出必须使用相同币种")
+
+	// ErrInsufficientPosition 卖出数量超过持仓（未开启卖空时）
+	ErrInsufficientPosition = errors.New("卖出数量超过当前持仓")
+
+	// ErrInsufficientCash 交易会使非融资账户的现金余额变为负数
+	ErrInsufficientCash = errors.New("账户现金余额不足")
+
+	// 交易所规则（用户开启 EnforceMarketRules 时校验）
+	ErrT1Violation      = errors.New("A 股实行 T+1：当日买入的股票当日不能卖出")
+	ErrBoardLot         = errors.New("买入数量必须为整手（每手股数的整数倍）")
+	ErrPriceLimit       = errors.New("成交价超出涨跌停价格范围")
+	ErrInvalidPrevClose = errors.New("昨收价必须大于 0")
+
+	// ErrDuplicateTrade 同一笔券商成交已导入过（按来源 + 券商成交编号判断）
+	ErrDuplicateTrade = errors.New("该券商成交已导入")
 )
 
 // ==================== Domain 输入结构体 ====================
@@ -25,19 +52,58 @@ var (
 // CreateInput 创建交易的输入参数
 type CreateInput struct {
 	UserID    uint
+	AccountID uint // 券商账户ID（可选，0 表示未指定账户）
 	Symbol    string
 	Name      string
 	Type      string
 	Quantity  decimal.Decimal
 	Price     decimal.Decimal
-	Fee       decimal.Decimal
+	Fee       *decimal.Decimal // 手续费（可选，nil 表示买卖按费率表自动计算，其他类型为 0）
 	TradeTime time.Time
 	Notes     string
+	LotIDs    []uint          // 卖出/转出时指定消耗的批次（可选，按顺序消耗；nil 表示保持不变，空数组表示取消指定）
+	Amount    decimal.Decimal // 现金类交易的金额；TRANSFER_IN 的转入成本
+	Ratio     decimal.Decimal // 拆股比例（仅 SPLIT）
+	Currency  string          // 交易币种（可选，默认为用户的基准货币）
+	PrevClose decimal.Decimal // 昨收价（可选，开启交易所规则时用于 A 股涨跌停校验，0 表示不校验）
+
+	// 导入来源（手工录入时为空）
+	Source        string // 数据来源：csv / ibkr_flex / ofx
+	BrokerTradeID string // 券商成交编号（可选，用于去重）
+}
+
+// UpdateInput 更新交易的输入参数
+// PUT 语义：整体替换可编辑字段，Amount 由后端重新计算
+type UpdateInput struct {
+	ID        uint // 交易ID
+	UserID    uint // 当前登录用户ID（用于归属校验）
+	Symbol    string
+	Name      string
+	Type      string
+	Quantity  decimal.Decimal
+	Price     decimal.Decimal
+	Fee       *decimal.Decimal // 手续费（可选，nil 表示买卖按费率表自动计算，其他类型为 0）
+	TradeTime time.Time
+	Notes     string
+	LotIDs    []uint          // 卖出/转出时指定消耗的批次（可选，按顺序消耗）
+	Amount    decimal.Decimal // 现金类交易的金额；TRANSFER_IN 的转入成本
+	Ratio     decimal.Decimal // 拆股比例（仅 SPLIT）
+	Currency  string          // 交易币种（可选，不传则保持不变）
+	AccountID *uint           // 券商账户ID（可选，nil 表示保持不变，0 表示取消指定）
+}
+
+// ImportInput 批量导入的输入参数
+type ImportInput struct {
+	UserID    uint           // 用户ID（必须）
+	AccountID uint           // 导入到的券商账户（可选，0 表示未指定账户）
+	Rows      []*CreateInput // 按文件顺序的待导入行（UserID、AccountID 由 Domain 层统一填充）
+	DryRun    bool           // 试运行：只校验不写入
 }
 
 // ListInput 查询交易列表的输入参数
 type ListInput struct {
 	UserID    uint       // 用户ID（必须）
+	AccountID *uint      // 账户ID（可选，nil 表示全部账户，0 表示未指定账户的交易）
 	Symbol    string     // 股票代码（可选）
 	Type      string     // 交易类型（可选）
 	StartTime *time.Time // 开始时间（可选）
@@ -52,15 +118,51 @@ type ListOutput struct {
 	Total int64                 // 总条数
 }
 
+// ImportRowError 单行导入错误
+type ImportRowError struct {
+	Index int   // 行下标（对应 ImportInput.Rows）
+	Err   error // 业务错误
+}
+
+// ImportOutput 批量导入的输出结果
+type ImportOutput struct {
+	Total     int               // 总行数
+	Valid     int               // 通过校验的行数
+	Skipped   int               // 已导入过而跳过的行数（券商成交编号重复）
+	Committed bool              // 是否已提交（无错误且非试运行）
+	Errors    []*ImportRowError // 错误行
+}
+
 // ==================== Domain 接口定义 ====================
 // Service 层会依赖这个接口
 
 type Domain interface {
 	// Create 创建交易记录
-	// 核心业务逻辑：校验参数、计算总金额、存入数据库
+	// 核心业务逻辑：按交易类型校验参数、计算金额、存入数据库
 	Create(input *CreateInput) (*entity.Transaction, error)
 
+	// Import 批量导入交易
+	// 逐行执行与 Create 相同的业务规则，在一个数据库事务中全部成功或全部回滚
+	// 券商成交编号已存在的行视为重复导入，跳过而不报错
+	Import(input *ImportInput) (*ImportOutput, error)
+
+	// Get 查询单条交易记录
+	// 只能查询属于当前用户的交易
+	Get(userID, id uint) (*entity.Transaction, error)
+
+	// Update 更新交易记录
+	// 校验归属和参数，并重新计算总金额
+	Update(input *UpdateInput) (*entity.Transaction, error)
+
+	// Delete 删除交易记录
+	// 只能删除属于当前用户的交易
+	Delete(userID, id uint) error
+
 	// List 查询交易列表
 	// 支持分页和筛选
 	List(input *ListInput) (*ListOutput, error)
+
+	// Export 按与 List 相同的筛选条件逐条读取全部交易（忽略分页）
+	// 不一次性加载到内存，fn 返回错误时停止
+	Export(input *ListInput, fn func(tx *entity.Transaction) error) error
 }
@@ -6,6 +6,8 @@ import (
 	"golang.org/x/crypto/bcrypt"
 
 	userRepo "github.com/florentyang/smartfin-go/internal/dao/user"
+	fxDomain "github.com/florentyang/smartfin-go/internal/domain/fx"
+	lotDomain "github.com/florentyang/smartfin-go/internal/domain/lot"
 	userDomain "github.com/florentyang/smartfin-go/internal/domain/user"
 	"github.com/florentyang/smartfin-go/internal/entity"
 )
@@ -101,7 +103,7 @@ func (u *usecase) GetProfile(userID uint) (*entity.User, error) {
 }
 
 // UpdateProfile 更新用户个人信息
-func (u *usecase) UpdateProfile(userID uint, username, email string) error {
+func (u *usecase) UpdateProfile(userID uint, input *userDomain.UpdateProfileInput) error {
 	// 1. 根据用户ID查找用户
 	user, err := u.userRepo.GetByID(userID)
 	if err != nil {
@@ -109,8 +111,28 @@ func (u *usecase) UpdateProfile(userID uint, username, email string) error {
 	}
 
 	// 2. 更新用户信息
-	user.Username = username
-	user.Email = email
+	user.Username = input.Username
+	user.Email = input.Email
+	if input.AllowShortSelling != nil {
+		user.AllowShortSelling = *input.AllowShortSelling
+	}
+	if input.EnforceMarketRules != nil {
+		user.EnforceMarketRules = *input.EnforceMarketRules
+	}
+	if input.CostBasisMethod != "" {
+		if !lotDomain.IsValidMethod(input.CostBasisMethod) {
+			return userDomain.ErrInvalidMethod
+		}
+		user.CostBasisMethod = input.CostBasisMethod
+	}
+	if input.BaseCurrency != "" {
+		// 只影响报表换算和之后新增交易的默认币种，已有交易的币种不变
+		currency, err := fxDomain.NormalizeCurrency(input.BaseCurrency)
+		if err != nil {
+			return err
+		}
+		user.BaseCurrency = currency
+	}
 	user.UpdatedAt = time.Now()
 
 	// 3. 调用 DAO 层更新用户信息
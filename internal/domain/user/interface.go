@@ -15,8 +15,21 @@ var (
 	ErrEmailExists      = errors.New("邮箱已存在")
 	ErrInvalidPassword  = errors.New("密码错误")
 	ErrPasswordTooShort = errors.New("密码至少6个字符")
+	ErrInvalidMethod    = errors.New("成本计算方法无效，必须是 FIFO、LIFO 或 HIFO")
 )
 
+// ==================== Domain 输入结构体 ====================
+
+// UpdateProfileInput 更新个人信息的输入参数
+type UpdateProfileInput struct {
+	Username           string
+	Email              string
+	AllowShortSelling  *bool  // 是否允许卖空（nil 表示不修改）
+	EnforceMarketRules *bool  // 是否校验交易所规则（nil 表示不修改）
+	CostBasisMethod    string // 默认成本计算方法（空表示不修改）
+	BaseCurrency       string // 基准货币（空表示不修改）
+}
+
 // ==================== Domain 接口定义 ====================
 // Service 层会依赖这个接口，而不是具体实现
 
@@ -30,8 +43,8 @@ type Domain interface {
 	// GetProfile 获取用户个人信息
 	GetProfile(userID uint) (*entity.User, error)
 
-	// UpdateProfile 更新用户个人信息
-	UpdateProfile(userID uint, username, email string) error
+	// UpdateProfile 更新用户个人信息（含交易偏好设置）
+	UpdateProfile(userID uint, input *UpdateProfileInput) error
 
 	// UpdatePassword 更新用户密码（需验证旧密码）
 	UpdatePassword(userID uint, oldPassword, newPassword string) error
@@ -0,0 +1,85 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ================== 请求 DTO ==================
+
+// CreateAccountRequest 创建账户请求
+type CreateAccountRequest struct {
+	Name   string `json:"name" binding:"required,max=50"` // 账户名称（同一用户下唯一），如 "IBKR 主账户"
+	Broker string `json:"broker" binding:"max=50"`        // 券商，如 IBKR、富途（可选）
+	Number string `json:"number" binding:"max=50"`        // 券商账号（可选，仅用于展示）
+	Notes  string `json:"notes" binding:"max=500"`        // 备注（可选）
+	Margin bool   `json:"margin"`                         // 是否融资账户（可选，默认 false：交易不能使现金余额为负）
+
+	AllowShortSelling *bool  `json:"allow_short_selling"`                                        // 是否允许卖空（可选，不传时取用户的卖空设置）
+	CostBasisMethod   string `json:"cost_basis_method" binding:"omitempty,oneof=FIFO LIFO HIFO"` // 默认成本计算方法（可选，不传时使用用户的默认方法）
+}
+
+// UpdateAccountRequest 更新账户请求
+// PUT 语义：所有可编辑字段整体替换
+type UpdateAccountRequest struct {
+	Name   string `json:"name" binding:"required,max=50"` // 账户名称（同一用户下唯一）
+	Broker string `json:"broker" binding:"max=50"`        // 券商（可选）
+	Number string `json:"number" binding:"max=50"`        // 券商账号（可选）
+	Notes  string `json:"notes" binding:"max=500"`        // 备注（可选）
+	Margin bool   `json:"margin"`                         // 是否融资账户
+
+	AllowShortSelling bool   `json:"allow_short_selling"`                                        // 是否允许卖空
+	CostBasisMethod   string `json:"cost_basis_method" binding:"omitempty,oneof=FIFO LIFO HIFO"` // 默认成本计算方法（空表示使用用户的默认方法）
+}
+
+// AccountCashRequest 账户现金流水请求
+// 使用 form 标签绑定 Query 参数
+type AccountCashRequest struct {
+	Currency  string `form:"currency"`   // 只看某个币种（可选）
+	StartDate string `form:"start_date"` // 开始日期：2024-01-01（可选，之前的流水计入期初余额）
+	EndDate   string `form:"end_date"`   // 结束日期：2024-12-31（可选）
+}
+
+// ================== 响应 DTO ==================
+
+// AccountResponse 账户响应
+type AccountResponse struct {
+	ID                uint      `json:"id"`
+	Name              string    `json:"name"`
+	Broker            string    `json:"broker"`
+	Number            string    `json:"number"`
+	Notes             string    `json:"notes"`
+	Margin            bool      `json:"margin"`
+	AllowShortSelling bool      `json:"allow_short_selling"`
+	CostBasisMethod   string    `json:"cost_basis_method"` // 空表示使用用户的默认方法
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// CashBalanceResponse 单一币种的现金余额汇总
+type CashBalanceResponse struct {
+	Currency string          `json:"currency"`
+	Opening  decimal.Decimal `json:"opening"` // 期初余额
+	Inflow   decimal.Decimal `json:"inflow"`  // 期间流入
+	Outflow  decimal.Decimal `json:"outflow"` // 期间流出
+	Closing  decimal.Decimal `json:"closing"` // 期末余额
+}
+
+// CashEntryResponse 现金流水的一行
+type CashEntryResponse struct {
+	TransactionID uint            `json:"transaction_id"`
+	TradeTime     time.Time       `json:"trade_time"`
+	Type          string          `json:"type"`
+	Symbol        string          `json:"symbol"`
+	Currency      string          `json:"currency"`
+	Amount        decimal.Decimal `json:"amount"`  // 现金变动（正数流入，负数流出）
+	Balance       decimal.Decimal `json:"balance"` // 该笔交易之后同币种的余额
+}
+
+// AccountCashResponse 账户现金流水响应
+type AccountCashResponse struct {
+	AccountID uint                   `json:"account_id"`
+	Margin    bool                   `json:"margin"`
+	Balances  []*CashBalanceResponse `json:"balances"` // 各币种余额汇总
+	Entries   []*CashEntryResponse   `json:"entries"`  // 期间流水（余额随时间的变化）
+}
@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ================== 请求 DTO ==================
+
+// FeeScheduleRequest 创建/更新费率表请求
+// PUT 语义：更新时所有可编辑字段整体替换
+// 费率均为小数形式，如万分之 2.5 填 0.00025
+type FeeScheduleRequest struct {
+	Name            string          `json:"name" binding:"required,max=50"` // 名称，如 "富途 A 股"
+	AccountID       uint            `json:"account_id"`                     // 适用账户（可选，不传表示不限）
+	Broker          string          `json:"broker" binding:"max=50"`        // 适用券商，与账户的券商匹配（可选，不传表示不限）
+	Currency        string          `json:"currency"`                       // 适用交易币种，如 CNY / HKD（可选，不传表示不限）
+	CommissionRate  decimal.Decimal `json:"commission_rate"`                // 佣金费率（按成交额）
+	PerShareFee     decimal.Decimal `json:"per_share_fee"`                  // 每股佣金，如美股 0.005
+	MinCommission   decimal.Decimal `json:"min_commission"`                 // 最低佣金（每笔），如 A 股 5
+	StampDutyRate   decimal.Decimal `json:"stamp_duty_rate"`                // 印花税率（仅卖出），如 A 股 0.0005
+	TransferFeeRate decimal.Decimal `json:"transfer_fee_rate"`              // 过户费率（买卖双边），如 A 股 0.00001
+	LevyRate        decimal.Decimal `json:"levy_rate"`                      // 交易征费等双边费率合计，如港股 0.00130565
+}
+
+// ================== 响应 DTO ==================
+
+// FeeScheduleResponse 费率表响应
+type FeeScheduleResponse struct {
+	ID              uint            `json:"id"`
+	Name            string          `json:"name"`
+	AccountID       uint            `json:"account_id"` // 0 表示不限
+	Broker          string          `json:"broker"`     // 空表示不限
+	Currency        string          `json:"currency"`   // 空表示不限
+	CommissionRate  decimal.Decimal `json:"commission_rate"`
+	PerShareFee     decimal.Decimal `json:"per_share_fee"`
+	MinCommission   decimal.Decimal `json:"min_commission"`
+	StampDutyRate   decimal.Decimal `json:"stamp_duty_rate"`
+	TransferFeeRate decimal.Decimal `json:"transfer_fee_rate"`
+	LevyRate        decimal.Decimal `json:"levy_rate"`
+	CreatedAt       time.Time       `json:"created_at"`
+}
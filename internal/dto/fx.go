@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// ================== 请求 DTO ==================
+
+// ListFXRatesRequest 查询汇率序列请求
+// 使用 form 标签绑定 Query 参数
+type ListFXRatesRequest struct {
+	Base      string `form:"base" binding:"required"`  // 基础货币，如 USD
+	Quote     string `form:"quote" binding:"required"` // 计价货币，如 CNY
+	StartDate string `form:"start_date"`               // 开始日期：2024-01-01（可选）
+	EndDate   string `form:"end_date"`                 // 结束日期：2024-12-31（可选）
+}
+
+// FXRateRequest 按日期查询汇率请求
+type FXRateRequest struct {
+	From string `form:"from" binding:"required"` // 源货币，如 USD
+	To   string `form:"to" binding:"required"`   // 目标货币，如 CNY
+	Date string `form:"date"`                    // 日期：2024-01-15（可选，默认今天），取当天或之前最近的汇率
+}
+
+// ================== 响应 DTO ==================
+
+// FXRateResponse 一条汇率
+type FXRateResponse struct {
+	Date string          `json:"date"` // 汇率日期：2024-01-15
+	Rate decimal.Decimal `json:"rate"` // 1 base = rate quote
+}
+
+// ListFXRatesResponse 汇率序列响应
+type ListFXRatesResponse struct {
+	Base  string            `json:"base"`
+	Quote string            `json:"quote"`
+	List  []*FXRateResponse `json:"list"`
+}
+
+// FXRateLookupResponse 按日期查询汇率响应
+type FXRateLookupResponse struct {
+	From     string          `json:"from"`
+	To       string          `json:"to"`
+	Date     string          `json:"date"`      // 查询日期
+	RateDate string          `json:"rate_date"` // 实际采用的汇率日期（当天或之前最近一天）
+	Rate     decimal.Decimal `json:"rate"`      // 1 from = rate to
+	Inverted bool            `json:"inverted"`  // 是否由反向货币对取倒数得到
+}
+
+// ImportFXRatesResponse 批量导入汇率响应
+type ImportFXRatesResponse struct {
+	Committed bool                      `json:"committed"` // 是否已写入
+	Total     int                       `json:"total"`     // 数据行数
+	Imported  int                       `json:"imported"`  // 写入（新增或覆盖）的行数
+	Errors    []*ImportRowErrorResponse `json:"errors"`    // 错误行（存在错误时整体不写入）
+}
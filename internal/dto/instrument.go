@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ================== 请求 DTO ==================
+
+// InstrumentRequest 创建/更新证券请求
+// PUT 语义：更新时所有可编辑字段整体替换
+type InstrumentRequest struct {
+	Symbol     string          `json:"symbol" binding:"required,max=20"` // 股票代码，如 AAPL、600519.SH、0700.HK（保存前规范化）
+	Exchange   string          `json:"exchange" binding:"max=10"`        // 交易所，如 NYSE / HKEX / SSE（可选，能从代码识别时自动填写）
+	Currency   string          `json:"currency"`                         // 交易币种（可选，能从交易所推断时自动填写）
+	AssetClass string          `json:"asset_class"`                      // 资产类别：STOCK/ETF/FUND/BOND/OPTION/FUTURE/CRYPTO/OTHER（可选，默认 STOCK）
+	LotSize    decimal.Decimal `json:"lot_size"`                         // 每手股数（可选，A 股默认 100，其他默认 1）
+	Name       string          `json:"name" binding:"required,max=100"`  // 证券名称
+	ISIN       string          `json:"isin"`                             // 国际证券识别码（可选），如 US0378331005
+}
+
+// SearchInstrumentRequest 搜索证券请求
+// 使用 form 标签绑定 Query 参数
+type SearchInstrumentRequest struct {
+	Page       int    `form:"page"`        // 页码，默认 1
+	PageSize   int    `form:"page_size"`   // 每页条数，默认 20
+	Query      string `form:"q"`           // 关键字（可选）：代码前缀、名称包含或 ISIN
+	Exchange   string `form:"exchange"`    // 按交易所筛选（可选）
+	AssetClass string `form:"asset_class"` // 按资产类别筛选（可选）
+}
+
+// ================== 响应 DTO ==================
+
+// InstrumentResponse 证券响应
+type InstrumentResponse struct {
+	ID         uint            `json:"id"`
+	Symbol     string          `json:"symbol"`
+	Exchange   string          `json:"exchange"`
+	Currency   string          `json:"currency"`
+	AssetClass string          `json:"asset_class"`
+	LotSize    decimal.Decimal `json:"lot_size"`
+	Name       string          `json:"name"`
+	ISIN       string          `json:"isin"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// SearchInstrumentResponse 证券分页列表响应
+type SearchInstrumentResponse struct {
+	Total    int64                 `json:"total"`     // 总条数
+	Page     int                   `json:"page"`      // 当前页码
+	PageSize int                   `json:"page_size"` // 每页条数
+	List     []*InstrumentResponse `json:"list"`      // 数据列表
+}
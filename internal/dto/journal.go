@@ -0,0 +1,80 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ================== 请求 DTO ==================
+
+// ListJournalRequest 查询分录请求
+// 使用 form 标签绑定 Query 参数
+type ListJournalRequest struct {
+	Page     int `form:"page"`      // 页码，默认 1
+	PageSize int `form:"page_size"` // 每页条数，默认 20
+
+	AccountID     *uint  `form:"account_id"`     // 按账户筛选（可选，0 表示未指定账户）
+	TransactionID uint   `form:"transaction_id"` // 按来源交易筛选（可选）
+	Ledger        string `form:"ledger"`         // 按会计科目筛选：CASH/SECURITIES/FEES/REALIZED_GAIN/INCOME/CAPITAL（可选）
+	StartDate     string `form:"start_date"`     // 开始日期：2024-01-01（可选）
+	EndDate       string `form:"end_date"`       // 结束日期：2024-12-31（可选）
+}
+
+// TrialBalanceRequest 试算平衡表请求
+type TrialBalanceRequest struct {
+	AccountID *uint  `form:"account_id"` // 只看某个账户（可选，0 表示未指定账户）
+	EndDate   string `form:"end_date"`   // 截止日期：2024-12-31（可选，默认包含全部分录）
+}
+
+// ================== 响应 DTO ==================
+
+// JournalLineResponse 分录行响应
+type JournalLineResponse struct {
+	ID            uint            `json:"id"`
+	TransactionID uint            `json:"transaction_id"` // 来源交易ID
+	AccountID     uint            `json:"account_id"`
+	Symbol        string          `json:"symbol"`
+	Ledger        string          `json:"ledger"` // 会计科目
+	Currency      string          `json:"currency"`
+	Debit         decimal.Decimal `json:"debit"`  // 借方金额
+	Credit        decimal.Decimal `json:"credit"` // 贷方金额
+	PostedAt      time.Time       `json:"posted_at"`
+}
+
+// ListJournalResponse 分录分页列表响应
+type ListJournalResponse struct {
+	Total    int64                  `json:"total"`     // 总条数
+	Page     int                    `json:"page"`      // 当前页码
+	PageSize int                    `json:"page_size"` // 每页条数
+	List     []*JournalLineResponse `json:"list"`      // 数据列表
+}
+
+// TrialBalanceRowResponse 试算平衡表的一行
+type TrialBalanceRowResponse struct {
+	Ledger   string          `json:"ledger"`
+	Currency string          `json:"currency"`
+	Debit    decimal.Decimal `json:"debit"`   // 借方发生额
+	Credit   decimal.Decimal `json:"credit"`  // 贷方发生额
+	Balance  decimal.Decimal `json:"balance"` // 余额 = 借方 - 贷方
+}
+
+// TrialBalanceTotalResponse 单一币种的借贷合计
+type TrialBalanceTotalResponse struct {
+	Currency string          `json:"currency"`
+	Debit    decimal.Decimal `json:"debit"`
+	Credit   decimal.Decimal `json:"credit"`
+	Balanced bool            `json:"balanced"`
+}
+
+// TrialBalanceResponse 试算平衡表响应
+type TrialBalanceResponse struct {
+	Rows     []*TrialBalanceRowResponse   `json:"rows"`     // 按币种、科目排列
+	Totals   []*TrialBalanceTotalResponse `json:"totals"`   // 各币种借贷合计
+	Balanced bool                         `json:"balanced"` // 全部币种均借贷平衡
+}
+
+// RebuildJournalResponse 重新过账响应
+type RebuildJournalResponse struct {
+	SymbolCount int `json:"symbol_count"` // 过账的股票数量（没有股票代码的现金交易计为一组）
+}
@@ -0,0 +1,72 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ================== 请求 DTO ==================
+
+// ListLotsRequest 查询批次请求
+// 使用 form 标签绑定 Query 参数
+type ListLotsRequest struct {
+	AccountID *uint  `form:"account_id"` // 按账户筛选（可选，0 表示未指定账户）
+	Symbol    string `form:"symbol"`     // 按股票代码筛选（可选）
+	OpenOnly  bool   `form:"open_only"`  // 只查询未平仓批次（可选，默认 false）
+}
+
+// RealizedLotsRequest 查询已实现盈亏明细请求
+type RealizedLotsRequest struct {
+	AccountID *uint  `form:"account_id"` // 按账户筛选（可选，0 表示未指定账户）
+	Symbol    string `form:"symbol"`     // 按股票代码筛选（可选）
+	SellID    uint   `form:"sell_id"`    // 按卖出交易ID筛选（可选）
+	StartDate string `form:"start_date"` // 卖出日期起：2024-01-01（可选）
+	EndDate   string `form:"end_date"`   // 卖出日期止：2024-12-31（可选）
+}
+
+// ================== 响应 DTO ==================
+
+// TaxLotResponse 批次响应
+type TaxLotResponse struct {
+	ID                uint            `json:"id"` // 批次ID = 开仓交易ID
+	Symbol            string          `json:"symbol"`
+	AccountID         uint            `json:"account_id"`
+	OpenTime          time.Time       `json:"open_time"`
+	Quantity          decimal.Decimal `json:"quantity"`           // 开仓数量
+	CostBasis         decimal.Decimal `json:"cost_basis"`         // 开仓总成本（含手续费）
+	UnitCost          decimal.Decimal `json:"unit_cost"`          // 单位成本
+	RemainingQuantity decimal.Decimal `json:"remaining_quantity"` // 剩余数量
+	RemainingCost     decimal.Decimal `json:"remaining_cost"`     // 剩余成本
+}
+
+// LotAssignmentResponse 批次分配响应
+type LotAssignmentResponse struct {
+	ID                uint            `json:"id"`
+	Symbol            string          `json:"symbol"`
+	AccountID         uint            `json:"account_id"`
+	SellTransactionID uint            `json:"sell_transaction_id"`
+	LotID             uint            `json:"lot_id"`
+	Method            string          `json:"method"` // FIFO/LIFO/HIFO/SPECIFIC
+	Quantity          decimal.Decimal `json:"quantity"`
+	CostBasis         decimal.Decimal `json:"cost_basis"`
+	Proceeds          decimal.Decimal `json:"proceeds"`
+	RealizedGain      decimal.Decimal `json:"realized_gain"`
+	OpenTime          time.Time       `json:"open_time"`
+	CloseTime         time.Time       `json:"close_time"`
+	HoldingDays       int             `json:"holding_days"` // 持有天数
+	LongTerm          bool            `json:"long_term"`    // 是否长期持有（超过 1 年）
+}
+
+// RealizedLotsResponse 已实现盈亏明细响应
+type RealizedLotsResponse struct {
+	TotalProceeds     decimal.Decimal          `json:"total_proceeds"`
+	TotalCostBasis    decimal.Decimal          `json:"total_cost_basis"`
+	TotalRealizedGain decimal.Decimal          `json:"total_realized_gain"`
+	List              []*LotAssignmentResponse `json:"list"`
+}
+
+// RebuildLotsResponse 重建批次响应
+type RebuildLotsResponse struct {
+	SymbolCount int `json:"symbol_count"` // 重建的股票数量
+}
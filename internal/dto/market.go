@@ -0,0 +1,90 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ================== 请求 DTO ==================
+
+// MarketCalendarRequest 查询交易日历请求
+// 使用 form 标签绑定 Query 参数
+type MarketCalendarRequest struct {
+	Exchange  string `form:"exchange" binding:"required"` // 交易所：NYSE / HKEX / SSE / SZSE
+	StartDate string `form:"start_date"`                  // 开始日期：2024-01-01（可选，默认今天）
+	EndDate   string `form:"end_date"`                    // 结束日期：2024-12-31（可选，默认开始日期后 30 天，最多 366 天）
+}
+
+// MarketQuotesRequest 批量查询行情请求
+type MarketQuotesRequest struct {
+	Symbols string `form:"symbols" binding:"required"` // 股票代码，逗号分隔：AAPL,0700.HK,600519.SH
+}
+
+// MarketStreamRequest 实时行情推送请求
+type MarketStreamRequest struct {
+	Symbols string `form:"symbols"` // 股票代码，逗号分隔；不传时订阅当前持仓
+}
+
+// ================== 响应 DTO ==================
+
+// MarketSessionResponse 一个交易时段
+type MarketSessionResponse struct {
+	Open  time.Time `json:"open"`  // 开始时间（带交易所时区偏移）
+	Close time.Time `json:"close"` // 结束时间
+}
+
+// TradingDayResponse 某一天的开市情况
+type TradingDayResponse struct {
+	Date       string                   `json:"date"`                  // 日期（交易所当地）
+	Open       bool                     `json:"open"`                  // 是否开市
+	Holiday    string                   `json:"holiday,omitempty"`     // 休市或提前收市的节日名称
+	EarlyClose bool                     `json:"early_close,omitempty"` // 是否提前收市
+	Sessions   []*MarketSessionResponse `json:"sessions"`              // 交易时段（休市时为空）
+}
+
+// MarketCalendarResponse 交易日历响应
+type MarketCalendarResponse struct {
+	Exchange           string                `json:"exchange"`
+	Name               string                `json:"name"`
+	Timezone           string                `json:"timezone"`             // IANA 时区
+	Sessions           []string              `json:"sessions"`             // 常规交易时段（当地时间）
+	PreviousTradingDay string                `json:"previous_trading_day"` // 开始日期之前的最后一个交易日
+	Days               []*TradingDayResponse `json:"days"`
+}
+
+// QuoteResponse 一只股票的最新行情
+type QuoteResponse struct {
+	Symbol        string           `json:"symbol"`                   // 规范化的股票代码
+	Price         decimal.Decimal  `json:"price"`                    // 最新价
+	PrevClose     decimal.Decimal  `json:"prev_close"`               // 昨收价（行情源不提供时为 0）
+	Change        *decimal.Decimal `json:"change,omitempty"`         // 涨跌额（有昨收价时返回）
+	ChangePercent *decimal.Decimal `json:"change_percent,omitempty"` // 涨跌幅 %（有昨收价时返回，保留 2 位小数）
+	Currency      string           `json:"currency,omitempty"`       // 计价币种
+	Time          time.Time        `json:"time"`                     // 行情时间
+	Source        string           `json:"source"`                   // 行情源
+}
+
+// QuoteErrorResponse 查询失败的股票
+type QuoteErrorResponse struct {
+	Symbol  string `json:"symbol"`
+	Message string `json:"message"` // 失败原因
+}
+
+// MarketQuotesResponse 批量查询行情响应
+// 部分股票失败时仍返回成功的行情，失败的股票列在 errors 中
+type MarketQuotesResponse struct {
+	Quotes []*QuoteResponse      `json:"quotes"`
+	Errors []*QuoteErrorResponse `json:"errors"`
+}
+
+// MarketStreamSubscribedResponse 推送连接建立后的第一条消息（subscribed 事件）
+type MarketStreamSubscribedResponse struct {
+	Symbols []string `json:"symbols"` // 实际订阅的股票代码（已规范化、去重）
+}
+
+// MarketStreamTicketResponse 推送票据（用于 GET /market/stream?ticket=...）
+type MarketStreamTicketResponse struct {
+	Ticket    string `json:"ticket"`     // 一次性票据
+	ExpiresAt int64  `json:"expires_at"` // 过期时间戳
+}
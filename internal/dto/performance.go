@@ -0,0 +1,75 @@
+package dto
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// ================== 请求 DTO ==================
+
+// ReturnsRequest 收益率请求
+// 使用 form 标签绑定 Query 参数
+type ReturnsRequest struct {
+	AccountID *uint  `form:"account_id"` // 只看某个账户（可选，不传为全部账户合并）
+	Symbol    string `form:"symbol"`     // 只看某只股票（可选，不传为整个组合）
+	Windows   string `form:"windows"`    // 区间，逗号分隔：mtd,ytd,1y,inception（可选，默认全部）
+}
+
+// RiskRequest 风险指标请求
+type RiskRequest struct {
+	AccountID    *uint  `form:"account_id"`     // 只看某个账户（可选，不传为全部账户合并）
+	Symbol       string `form:"symbol"`         // 只看某只股票（可选，不传为整个组合）
+	StartDate    string `form:"start_date"`     // 开始日期：2024-01-01（可选，默认首笔交易）
+	EndDate      string `form:"end_date"`       // 结束日期：2024-12-31（可选，默认最新快照）
+	Benchmark    string `form:"benchmark"`      // 基准股票代码：SPY、2800.HK（可选，取历史行情库的收盘价）
+	RiskFreeRate string `form:"risk_free_rate"` // 年化无风险利率：0.02 表示 2%（可选，默认取配置）
+}
+
+// ================== 响应 DTO ==================
+
+// WindowReturnResponse 单个区间的收益率
+type WindowReturnResponse struct {
+	Window        string           `json:"window"`         // 区间：mtd / ytd / 1y / inception
+	StartDate     string           `json:"start_date"`     // 期初日期（该日收盘后的市值为期初市值）：2024-12-31
+	EndDate       string           `json:"end_date"`       // 期末日期：2025-01-15
+	Days          int              `json:"days"`           // 区间自然日数
+	StartValue    decimal.Decimal  `json:"start_value"`    // 期初市值
+	EndValue      decimal.Decimal  `json:"end_value"`      // 期末市值
+	NetFlow       decimal.Decimal  `json:"net_flow"`       // 区间外部资金净流入
+	Profit        decimal.Decimal  `json:"profit"`         // 区间收益 = 期末市值 - 期初市值 - 净流入
+	TWR           decimal.Decimal  `json:"twr"`            // 时间加权收益率（累计）：0.0523 表示 5.23%
+	TWRAnnualized *decimal.Decimal `json:"twr_annualized"` // 年化时间加权收益率（区间不足一年时为 null）
+	XIRR          *decimal.Decimal `json:"xirr"`           // 资金加权收益率（年化内部收益率，无解时为 null）
+}
+
+// ReturnsResponse 收益率响应（金额为基准货币）
+type ReturnsResponse struct {
+	BaseCurrency string                  `json:"base_currency"`
+	Symbol       string                  `json:"symbol,omitempty"` // 只看某只股票时返回
+	AsOf         string                  `json:"as_of"`            // 截止日期（最新估值快照）：2025-01-15
+	Windows      []*WindowReturnResponse `json:"windows"`
+}
+
+// RiskResponse 风险指标响应（比率均为小数，样本不足时为 null）
+type RiskResponse struct {
+	BaseCurrency string          `json:"base_currency"`
+	Symbol       string          `json:"symbol,omitempty"` // 只看某只股票时返回
+	StartDate    string          `json:"start_date"`       // 第一天收益率的日期：2024-01-02
+	EndDate      string          `json:"end_date"`         // 最后一天收益率的日期：2024-12-31
+	Days         int             `json:"days"`             // 日收益率的天数（自然日）
+	RiskFreeRate decimal.Decimal `json:"risk_free_rate"`   // 使用的年化无风险利率
+	TotalReturn  decimal.Decimal `json:"total_return"`     // 区间时间加权收益率
+
+	Volatility *decimal.Decimal `json:"volatility"` // 年化波动率
+	Sharpe     *decimal.Decimal `json:"sharpe"`     // 夏普比率
+	Sortino    *decimal.Decimal `json:"sortino"`    // 索提诺比率
+
+	MaxDrawdown  decimal.Decimal `json:"max_drawdown"`  // 最大回撤：-0.2 表示回撤 20%
+	PeakDate     string          `json:"peak_date"`     // 最大回撤的峰值日期（没有回撤时为空）
+	TroughDate   string          `json:"trough_date"`   // 最大回撤的谷底日期
+	RecoveryDate string          `json:"recovery_date"` // 回到峰值的日期（尚未恢复时为空）
+
+	Benchmark       string           `json:"benchmark,omitempty"` // 基准股票代码（指定基准时返回）
+	BenchmarkReturn *decimal.Decimal `json:"benchmark_return"`    // 基准区间涨跌幅（基准自身币种）
+	Beta            *decimal.Decimal `json:"beta"`                // Beta
+	Correlation     *decimal.Decimal `json:"correlation"`         // 与基准的相关系数
+}
@@ -0,0 +1,89 @@
+package dto
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// ================== 请求 DTO ==================
+
+// HoldingsRequest 持仓汇总请求
+// 使用 form 标签绑定 Query 参数
+type HoldingsRequest struct {
+	AccountID     *uint  `form:"account_id"`                                    // 只看某个账户的持仓（可选，不传为全部账户合并视图，0 表示未指定账户）
+	IncludeClosed bool   `form:"include_closed"`                                // 是否包含已清仓的股票（可选，默认 false）
+	Currency      string `form:"currency" binding:"omitempty,oneof=base trade"` // 报表币种：base 换算为基准货币 / trade 保持交易币种（可选，默认 base）
+}
+
+// PortfolioHistoryRequest 估值历史请求
+type PortfolioHistoryRequest struct {
+	AccountID       *uint  `form:"account_id"`       // 只看某个账户（可选，不传为全部账户合并）
+	StartDate       string `form:"start_date"`       // 开始日期：2024-01-01（可选）
+	EndDate         string `form:"end_date"`         // 结束日期：2024-12-31（可选）
+	IncludeHoldings bool   `form:"include_holdings"` // 是否返回每天的持仓明细（可选，默认 false）
+}
+
+// ================== 响应 DTO ==================
+
+// HoldingResponse 单只股票持仓响应
+type HoldingResponse struct {
+	Symbol      string          `json:"symbol"`
+	Currency    string          `json:"currency"` // 金额的币种
+	Name        string          `json:"name"`
+	Quantity    decimal.Decimal `json:"quantity"`     // 当前持仓数量
+	TotalCost   decimal.Decimal `json:"total_cost"`   // 持仓总成本（含手续费）
+	AverageCost decimal.Decimal `json:"average_cost"` // 平均成本（含手续费）
+	RealizedPnL decimal.Decimal `json:"realized_pnl"` // 已实现盈亏
+	Income      decimal.Decimal `json:"income"`       // 分红、利息收入（税前）
+	TotalFee    decimal.Decimal `json:"total_fee"`    // 累计手续费（含费用类交易和预扣税）
+	TradeCount  int             `json:"trade_count"`  // 交易笔数
+
+	MarketPrice   *decimal.Decimal `json:"market_price"`   // 估值价格（没有价格时为 null）
+	MarketValue   *decimal.Decimal `json:"market_value"`   // 市值
+	UnrealizedPnL *decimal.Decimal `json:"unrealized_pnl"` // 未实现盈亏
+	PriceDate     string           `json:"price_date"`     // 估值价格的日期：2024-01-15
+	PriceSource   string           `json:"price_source"`   // 估值价格来源：bar 历史行情收盘价 / trade 最新成交价
+}
+
+// HoldingsResponse 持仓汇总响应
+// 合计始终按基准货币计算
+type HoldingsResponse struct {
+	BaseCurrency     string             `json:"base_currency"`      // 基准货币（合计的币种）
+	TotalCost        decimal.Decimal    `json:"total_cost"`         // 全部持仓总成本
+	TotalRealizedPnL decimal.Decimal    `json:"total_realized_pnl"` // 全部已实现盈亏
+	TotalIncome      decimal.Decimal    `json:"total_income"`       // 全部分红、利息收入
+	TotalMarketValue decimal.Decimal    `json:"total_market_value"` // 全部持仓市值（不含没有估值价格的持仓）
+	TotalUnrealized  decimal.Decimal    `json:"total_unrealized"`   // 全部未实现盈亏
+	Holdings         []*HoldingResponse `json:"holdings"`           // 持仓列表
+}
+
+// HistoryHoldingResponse 某天收盘后的一只持仓
+type HistoryHoldingResponse struct {
+	Symbol        string          `json:"symbol"`
+	Quantity      decimal.Decimal `json:"quantity"`
+	Price         decimal.Decimal `json:"price"`                    // 估值价格（没有价格时为 0，按成本计价）
+	PriceCurrency string          `json:"price_currency,omitempty"` // 估值价格的币种
+	MarketValue   decimal.Decimal `json:"market_value"`             // 市值（基准货币）
+	Cost          decimal.Decimal `json:"cost"`                     // 成本（基准货币）
+}
+
+// HistoryPointResponse 某天收盘后的估值（资产曲线上的一个点）
+type HistoryPointResponse struct {
+	Date        string                    `json:"date"`               // 日期：2024-01-15
+	MarketValue decimal.Decimal           `json:"market_value"`       // 持仓市值
+	Cost        decimal.Decimal           `json:"cost"`               // 持仓成本
+	Cash        decimal.Decimal           `json:"cash"`               // 现金余额
+	TotalValue  decimal.Decimal           `json:"total_value"`        // 总资产 = 市值 + 现金
+	NetDeposit  decimal.Decimal           `json:"net_deposit"`        // 当天外部资金净流入（入金 - 出金）
+	Holdings    []*HistoryHoldingResponse `json:"holdings,omitempty"` // 持仓明细（include_holdings 时返回）
+}
+
+// PortfolioHistoryResponse 估值历史响应（金额为基准货币）
+type PortfolioHistoryResponse struct {
+	BaseCurrency string                  `json:"base_currency"`
+	Points       []*HistoryPointResponse `json:"points"` // 按日期正序，每个自然日一条
+}
+
+// RebuildHistoryResponse 重新生成估值快照响应
+type RebuildHistoryResponse struct {
+	DayCount int `json:"day_count"` // 生成的天数
+}
@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// ================== 请求 DTO ==================
+
+// ListPriceBarsRequest 查询日线行情请求
+// 使用 form 标签绑定 Query 参数
+type ListPriceBarsRequest struct {
+	Symbol    string `form:"symbol" binding:"required"` // 股票代码，如 AAPL、600519.SH（也可传 ISIN）
+	StartDate string `form:"start_date"`                // 开始日期：2024-01-01（可选）
+	EndDate   string `form:"end_date"`                  // 结束日期：2024-12-31（可选）
+}
+
+// ================== 响应 DTO ==================
+
+// PriceBarResponse 一条日线行情
+type PriceBarResponse struct {
+	Date   string          `json:"date"` // 交易日期：2024-01-15
+	Open   decimal.Decimal `json:"open"`
+	High   decimal.Decimal `json:"high"`
+	Low    decimal.Decimal `json:"low"`
+	Close  decimal.Decimal `json:"close"`
+	Volume decimal.Decimal `json:"volume"`
+}
+
+// ListPriceBarsResponse 日线行情序列响应
+type ListPriceBarsResponse struct {
+	Symbol   string              `json:"symbol"`   // 规范化的股票代码
+	Name     string              `json:"name"`     // 证券名称
+	Currency string              `json:"currency"` // 价格的币种
+	List     []*PriceBarResponse `json:"list"`
+}
+
+// ImportPriceBarsResponse 批量导入日线行情响应
+type ImportPriceBarsResponse struct {
+	Committed bool                      `json:"committed"` // 是否已写入
+	Total     int                       `json:"total"`     // 数据行数
+	Imported  int                       `json:"imported"`  // 写入（新增或覆盖）的行数
+	Errors    []*ImportRowErrorResponse `json:"errors"`    // 错误行（存在错误时整体不写入）
+}
@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ================== 请求 DTO ==================
+
+// PnLReportRequest 盈亏报表请求
+// 使用 form 标签绑定 Query 参数
+type PnLReportRequest struct {
+	AccountID *uint  `form:"account_id"`                                      // 只统计某个账户（可选，不传为全部账户，0 表示未指定账户）
+	StartDate string `form:"start_date"`                                      // 开始日期：2024-01-01（可选）
+	EndDate   string `form:"end_date"`                                        // 结束日期：2024-12-31（可选，默认今天）
+	GroupBy   string `form:"group_by" binding:"omitempty,oneof=symbol month"` // 分组方式：symbol/month（可选，默认 symbol）
+	Currency  string `form:"currency" binding:"omitempty,oneof=base trade"`   // 报表币种：base 换算为基准货币 / trade 保持交易币种（可选，默认 base；按月分组只支持 base）
+}
+
+// ================== 响应 DTO ==================
+
+// PnLRowResponse 盈亏报表明细行
+type PnLRowResponse struct {
+	Key              string          `json:"key"`               // 股票代码或月份
+	Currency         string          `json:"currency"`          // 金额的币种
+	Realized         decimal.Decimal `json:"realized"`          // 已实现盈亏
+	Unrealized       decimal.Decimal `json:"unrealized"`        // 期末（月末）未实现盈亏
+	UnrealizedChange decimal.Decimal `json:"unrealized_change"` // 期间（当月）未实现盈亏变动，计入 pnl
+	Income           decimal.Decimal `json:"income"`            // 分红、利息净收入
+	Expenses         decimal.Decimal `json:"expenses"`          // 费用
+	PnL              decimal.Decimal `json:"pnl"`               // 合计盈亏
+	Quantity         decimal.Decimal `json:"quantity"`          // 期末持仓数量（按股票分组）
+	CostBasis        decimal.Decimal `json:"cost_basis"`        // 期末持仓成本（按股票分组）
+	MarketPrice      decimal.Decimal `json:"market_price"`      // 估值价格（按股票分组）
+	MarketValue      decimal.Decimal `json:"market_value"`      // 期末市值（按股票分组）
+}
+
+// PnLReportResponse 盈亏报表响应
+type PnLReportResponse struct {
+	GroupBy               string            `json:"group_by"`
+	BaseCurrency          string            `json:"base_currency"`           // 基准货币（合计的币种）
+	AsOf                  time.Time         `json:"as_of"`                   // 估值时点
+	TotalRealized         decimal.Decimal   `json:"total_realized"`          // 期间已实现盈亏
+	TotalUnrealized       decimal.Decimal   `json:"total_unrealized"`        // 期末未实现盈亏
+	TotalUnrealizedChange decimal.Decimal   `json:"total_unrealized_change"` // 期间未实现盈亏变动（期末 - 期初），计入 total_pnl
+	TotalIncome           decimal.Decimal   `json:"total_income"`            // 期间分红、利息净收入
+	TotalExpenses         decimal.Decimal   `json:"total_expenses"`          // 期间费用
+	TotalPnL              decimal.Decimal   `json:"total_pnl"`               // 合计 = 已实现 + 未实现变动 + 收入 - 费用
+	Rows                  []*PnLRowResponse `json:"rows"`
+}
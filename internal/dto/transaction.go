@@ -11,14 +11,38 @@ import (
 // CreateTransactionRequest 创建交易请求
 // 前端传来的 JSON 会自动映射到这个结构体
 type CreateTransactionRequest struct {
-	Symbol    string          `json:"symbol" binding:"required"`              // 股票代码，如 AAPL
-	Name      string          `json:"name"`                                   // 股票名称（可选）
-	Type      string          `json:"type" binding:"required,oneof=BUY SELL"` // 交易类型：必须是 BUY 或 SELL
-	Quantity  decimal.Decimal `json:"quantity" binding:"required"`            // 交易数量
-	Price     decimal.Decimal `json:"price" binding:"required"`               // 成交单价
-	Fee       decimal.Decimal `json:"fee"`                                    // 手续费（可选，默认0）
-	TradeTime string          `json:"trade_time" binding:"required"`          // 交易时间，ISO 8601 格式：2024-01-15T10:30:00Z
-	Notes     string          `json:"notes"`                                  // 备注（可选）
+	AccountID uint             `json:"account_id"`                                                                                                     // 券商账户ID（可选，不传表示未指定账户）
+	Symbol    string           `json:"symbol"`                                                                                                         // 股票代码，如 AAPL（利息、费用、出入金可不填）
+	Name      string           `json:"name"`                                                                                                           // 股票名称（可选）
+	Type      string           `json:"type" binding:"required,oneof=BUY SELL DIVIDEND INTEREST FEE DEPOSIT WITHDRAWAL SPLIT TRANSFER_IN TRANSFER_OUT"` // 交易类型，见 entity.TransactionType*
+	Quantity  decimal.Decimal  `json:"quantity"`                                                                                                       // 交易数量（买卖、转入转出必填；现金类、拆股不填）
+	Price     decimal.Decimal  `json:"price"`                                                                                                          // 成交单价（仅买卖）
+	Amount    decimal.Decimal  `json:"amount"`                                                                                                         // 现金类交易的金额；TRANSFER_IN 的转入成本（买卖由后端计算）
+	Fee       *decimal.Decimal `json:"fee"`                                                                                                            // 手续费（可选；买卖不传则按费率表自动计算，其他类型默认0；分红、利息为预扣税）
+	Ratio     decimal.Decimal  `json:"ratio"`                                                                                                          // 拆股比例（仅 SPLIT）：1 拆 2 填 2，10 合 1 填 0.1
+	Currency  string           `json:"currency"`                                                                                                       // 交易币种（ISO 4217），如 USD / HKD / CNY（可选，默认为用户的基准货币）
+	PrevClose decimal.Decimal  `json:"prev_close"`                                                                                                     // 昨收价（可选，开启交易所规则时校验 A 股涨跌停）
+	TradeTime string           `json:"trade_time" binding:"required"`                                                                                  // 交易时间，ISO 8601 格式：2024-01-15T10:30:00Z
+	Notes     string           `json:"notes"`                                                                                                          // 备注（可选）
+	LotIDs    []uint           `json:"lot_ids"`                                                                                                        // 卖出/转出时指定消耗的批次ID（可选，按顺序消耗）
+}
+
+// UpdateTransactionRequest 更新交易请求
+// PUT 语义：所有可编辑字段整体替换，总金额由后端重新计算
+type UpdateTransactionRequest struct {
+	AccountID *uint            `json:"account_id"`                                                                                                     // 券商账户ID（可选，不传则保持不变，传 0 表示取消指定）
+	Symbol    string           `json:"symbol"`                                                                                                         // 股票代码，如 AAPL（利息、费用、出入金可不填）
+	Name      string           `json:"name"`                                                                                                           // 股票名称（可选）
+	Type      string           `json:"type" binding:"required,oneof=BUY SELL DIVIDEND INTEREST FEE DEPOSIT WITHDRAWAL SPLIT TRANSFER_IN TRANSFER_OUT"` // 交易类型，见 entity.TransactionType*
+	Quantity  decimal.Decimal  `json:"quantity"`                                                                                                       // 交易数量（买卖、转入转出必填；现金类、拆股不填）
+	Price     decimal.Decimal  `json:"price"`                                                                                                          // 成交单价（仅买卖）
+	Amount    decimal.Decimal  `json:"amount"`                                                                                                         // 现金类交易的金额；TRANSFER_IN 的转入成本（买卖由后端计算）
+	Fee       *decimal.Decimal `json:"fee"`                                                                                                            // 手续费（可选；买卖不传则按费率表自动计算，其他类型默认0；分红、利息为预扣税）
+	Ratio     decimal.Decimal  `json:"ratio"`                                                                                                          // 拆股比例（仅 SPLIT）：1 拆 2 填 2，10 合 1 填 0.1
+	Currency  string           `json:"currency"`                                                                                                       // 交易币种（ISO 4217），如 USD / HKD / CNY（可选，不传则保持不变）
+	TradeTime string           `json:"trade_time" binding:"required"`                                                                                  // 交易时间，ISO 8601 格式：2024-01-15T10:30:00Z
+	Notes     string           `json:"notes"`                                                                                                          // 备注（可选）
+	LotIDs    []uint           `json:"lot_ids"`                                                                                                        // 卖出/转出时指定消耗的批次ID（可选，按顺序消耗；不传则保持不变，传 [] 取消指定、改用默认方法）
 }
 
 // ListTransactionRequest 查询交易列表请求
@@ -29,28 +53,66 @@ type ListTransactionRequest struct {
 	PageSize int `form:"page_size"` // 每页条数，默认 20
 
 	// ===== 筛选参数 =====
+	AccountID *uint  `form:"account_id"` // 按账户筛选（可选，0 表示未指定账户的交易）
 	Symbol    string `form:"symbol"`     // 按股票代码筛选（可选）
-	Type      string `form:"type"`       // 按交易类型筛选：BUY/SELL（可选）
+	Type      string `form:"type"`       // 按交易类型筛选：BUY/SELL/DIVIDEND 等（可选）
 	StartDate string `form:"start_date"` // 开始日期：2024-01-01（可选）
 	EndDate   string `form:"end_date"`   // 结束日期：2024-12-31（可选）
 }
 
+// ImportTransactionRequest 批量导入交易请求
+// 文件通过 multipart 表单的 file 字段上传，其余参数走 Query
+type ImportTransactionRequest struct {
+	Format    string `form:"format" binding:"omitempty,oneof=csv ibkr_flex ofx qfx"` // 文件格式：csv / ibkr_flex / ofx / qfx（可选，默认 csv）
+	Timezone  string `form:"timezone"`                                               // 文件中不带时区的时间按此时区解析，如 America/New_York（可选，默认 UTC）
+	DryRun    bool   `form:"dry_run"`                                                // 试运行：只校验不写入（可选，默认 false）
+	AccountID uint   `form:"account_id"`                                             // 导入到的券商账户ID（可选，整个文件导入同一账户）
+}
+
+// ExportTransactionRequest 导出交易请求
+// 筛选条件与 ListTransactionRequest 一致，不分页，导出全部匹配的交易
+type ExportTransactionRequest struct {
+	Format    string `form:"format" binding:"omitempty,oneof=csv jsonl xlsx"` // 导出格式：csv / jsonl / xlsx（可选，默认 csv）
+	AccountID *uint  `form:"account_id"`                                      // 按账户筛选（可选，0 表示未指定账户的交易）
+	Symbol    string `form:"symbol"`                                          // 按股票代码筛选（可选）
+	Type      string `form:"type"`                                            // 按交易类型筛选：BUY/SELL/DIVIDEND 等（可选）
+	StartDate string `form:"start_date"`                                      // 开始日期：2024-01-01（可选）
+	EndDate   string `form:"end_date"`                                        // 结束日期：2024-12-31（可选）
+}
+
 // ================== 响应 DTO ==================
 
 // TransactionResponse 交易响应
 // 返回给前端的数据结构
 type TransactionResponse struct {
-	ID        uint            `json:"id"`
-	Symbol    string          `json:"symbol"`
-	Name      string          `json:"name"`
-	Type      string          `json:"type"`
-	Quantity  decimal.Decimal `json:"quantity"`
-	Price     decimal.Decimal `json:"price"`
-	Amount    decimal.Decimal `json:"amount"` // 总金额（后端计算）
-	Fee       decimal.Decimal `json:"fee"`
-	TradeTime time.Time       `json:"trade_time"`
-	Notes     string          `json:"notes"`
-	CreatedAt time.Time       `json:"created_at"`
+	ID              uint            `json:"id"`
+	AccountID       uint            `json:"account_id"` // 券商账户ID（0 表示未指定账户）
+	Symbol          string          `json:"symbol"`
+	Name            string          `json:"name"`
+	Type            string          `json:"type"`
+	Quantity        decimal.Decimal `json:"quantity"`
+	Price           decimal.Decimal `json:"price"`
+	Amount          decimal.Decimal `json:"amount"` // 总金额（买卖由后端计算）
+	Fee             decimal.Decimal `json:"fee"`
+	FeeScheduleID   uint            `json:"fee_schedule_id"`         // 计算手续费所用的费率表ID（0 表示手工填写）
+	FeeBreakdown    *FeeBreakdown   `json:"fee_breakdown,omitempty"` // 手续费明细（按费率表计算时返回）
+	Ratio           decimal.Decimal `json:"ratio"`                   // 拆股比例（仅 SPLIT）
+	Currency        string          `json:"currency"`                // 交易币种
+	TradeTime       time.Time       `json:"trade_time"`
+	Notes           string          `json:"notes"`
+	CostBasisMethod string          `json:"cost_basis_method,omitempty"` // 卖出采用的成本计算方法
+	LotIDs          []uint          `json:"lot_ids,omitempty"`           // 卖出指定的批次
+	Source          string          `json:"source,omitempty"`            // 导入来源：csv / ibkr_flex / ofx
+	BrokerTradeID   string          `json:"broker_trade_id,omitempty"`   // 券商成交编号
+	CreatedAt       time.Time       `json:"created_at"`
+}
+
+// FeeBreakdown 手续费明细
+type FeeBreakdown struct {
+	Commission  decimal.Decimal `json:"commission"`   // 佣金
+	StampDuty   decimal.Decimal `json:"stamp_duty"`   // 印花税（仅卖出）
+	TransferFee decimal.Decimal `json:"transfer_fee"` // 过户费
+	Levy        decimal.Decimal `json:"levy"`         // 交易征费等
 }
 
 // ListTransactionResponse 分页列表响应
@@ -60,3 +122,44 @@ type ListTransactionResponse struct {
 	PageSize int                    `json:"page_size"` // 每页条数
 	List     []*TransactionResponse `json:"list"`      // 数据列表
 }
+
+// ImportRowErrorResponse 导入错误行
+type ImportRowErrorResponse struct {
+	Line    int    `json:"line"`    // 源文件行号（CSV 表头为第 1 行，XML/OFX 为交易元素所在行）
+	Message string `json:"message"` // 错误原因
+}
+
+// ImportTransactionResponse 批量导入响应
+type ImportTransactionResponse struct {
+	Format    string                    `json:"format"`    // 文件格式
+	DryRun    bool                      `json:"dry_run"`   // 是否试运行
+	Committed bool                      `json:"committed"` // 是否已写入
+	Total     int                       `json:"total"`     // 数据行数
+	Valid     int                       `json:"valid"`     // 通过校验的行数
+	Skipped   int                       `json:"skipped"`   // 已导入过而跳过的行数
+	Imported  int                       `json:"imported"`  // 实际写入的行数
+	Errors    []*ImportRowErrorResponse `json:"errors"`    // 错误行（存在错误时整体不写入）
+}
+
+// ExportTransactionRow 导出的一行交易（JSON Lines 每行一个对象）
+// 金额、数量为按 decimal(18,4) 精度输出的定点字符串，如 "100.0000"
+type ExportTransactionRow struct {
+	ID              uint   `json:"id"`
+	AccountID       uint   `json:"account_id"`
+	Symbol          string `json:"symbol"`
+	Name            string `json:"name"`
+	Type            string `json:"type"`
+	Quantity        string `json:"quantity"`
+	Price           string `json:"price"`
+	Amount          string `json:"amount"`
+	Fee             string `json:"fee"`
+	Ratio           string `json:"ratio"` // 拆股比例，decimal(18,8)
+	Currency        string `json:"currency"`
+	TradeTime       string `json:"trade_time"` // RFC3339
+	Notes           string `json:"notes"`
+	CostBasisMethod string `json:"cost_basis_method,omitempty"`
+	LotIDs          []uint `json:"lot_ids,omitempty"`
+	Source          string `json:"source,omitempty"`
+	BrokerTradeID   string `json:"broker_trade_id,omitempty"`
+	CreatedAt       string `json:"created_at"` // RFC3339
+}
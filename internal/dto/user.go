@@ -21,8 +21,12 @@ type LoginRequest struct {
 
 // 更新用户信息请求（基础）
 type UpdateUserRequest struct {
-	Username string `json:"username" binding:"required"`
-	Email    string `json:"email" binding:"required,email"`
+	Username           string `json:"username" binding:"required"`
+	Email              string `json:"email" binding:"required,email"`
+	AllowShortSelling  *bool  `json:"allow_short_selling"`                                        // 是否允许卖空（可选，不传则不修改）
+	EnforceMarketRules *bool  `json:"enforce_market_rules"`                                       // 是否校验 A 股 T+1、整手、涨跌停（可选，不传则不修改）
+	CostBasisMethod    string `json:"cost_basis_method" binding:"omitempty,oneof=FIFO LIFO HIFO"` // 默认成本计算方法（可选，不传则不修改）
+	BaseCurrency       string `json:"base_currency" binding:"omitempty,len=3"`                    // 基准货币，如 USD / CNY（可选，不传则不修改）
 }
 
 // 更新用户密码请求
@@ -35,10 +39,14 @@ type UpdatePasswordRequest struct {
 
 // 用户响应
 type UserResponse struct {
-	ID        uint      `json:"id"`
-	Username  string    `json:"username"`
-	Email     string    `json:"email"`
-	CreatedAt time.Time `json:"created_at"`
+	ID                 uint      `json:"id"`
+	Username           string    `json:"username"`
+	Email              string    `json:"email"`
+	AllowShortSelling  bool      `json:"allow_short_selling"`
+	EnforceMarketRules bool      `json:"enforce_market_rules"`
+	CostBasisMethod    string    `json:"cost_basis_method"`
+	BaseCurrency       string    `json:"base_currency"`
+	CreatedAt          time.Time `json:"created_at"`
 }
 
 // 登录响应（包含 Token）
@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+// Account 券商账户实体（对应数据库表 accounts）
+// 一个用户可以有多个券商账户，交易、持仓和税务批次都按账户隔离；
+// 交易的 AccountID 为 0 表示未指定账户（历史数据或不区分账户的用户），视为一个独立的默认账户
+// 账户现金余额由交易流水推导（见 CashFlow），不单独存储
+type Account struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_accounts_user_name,priority:1"`         // 用户ID
+	Name      string    `gorm:"not null;size:50;uniqueIndex:idx_accounts_user_name,priority:2"` // 账户名称（同一用户下唯一），如 "IBKR 主账户"
+	Broker    string    `gorm:"size:50"`                                                        // 券商，如 IBKR、富途
+	Number    string    `gorm:"size:50"`                                                        // 券商账号（可选，仅用于展示）
+	Notes     string    `gorm:"size:500"`                                                       // 备注
+	Margin    bool      `gorm:"not null;default:false"`                                         // 是否融资账户：非融资账户的交易不能使现金余额变为负数
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+
+	// AllowShortSelling 是否允许卖空：关闭时卖出（转出）数量不能超过该账户的持仓
+	// 创建时未指定则取用户的卖空设置（User.AllowShortSelling 只作为默认值和未指定账户的设置）
+	AllowShortSelling bool `gorm:"not null;default:false"`
+
+	// CostBasisMethod 账户的默认成本计算方法：FIFO/LIFO/HIFO，空表示使用用户的默认方法
+	CostBasisMethod string `gorm:"size:10"`
+}
@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// FeeSchedule 手续费费率表（对应数据库表 fee_schedules）
+// 创建买卖交易时未填写手续费，按交易的账户、券商和币种匹配最具体的费率表自动计算
+type FeeSchedule struct {
+	ID              uint            `gorm:"primaryKey"`
+	UserID          uint            `gorm:"not null;index"`              // 用户ID
+	Name            string          `gorm:"not null;size:50"`            // 费率表名称，如 "富途 A 股"
+	AccountID       uint            `gorm:"not null;default:0"`          // 适用账户（0 表示不限）
+	Broker          string          `gorm:"size:50"`                     // 适用券商，与账户的券商匹配（空表示不限）
+	Currency        string          `gorm:"size:3"`                      // 适用交易币种（空表示不限）
+	CommissionRate  decimal.Decimal `gorm:"type:decimal(18,8);not null"` // 佣金费率（按成交额），如 0.00025 表示万分之 2.5
+	PerShareFee     decimal.Decimal `gorm:"type:decimal(18,8);not null"` // 每股佣金，如 0.005
+	MinCommission   decimal.Decimal `gorm:"type:decimal(18,4);not null"` // 最低佣金（每笔）
+	StampDutyRate   decimal.Decimal `gorm:"type:decimal(18,8);not null"` // 印花税率（仅卖出），如 A 股 0.0005
+	TransferFeeRate decimal.Decimal `gorm:"type:decimal(18,8);not null"` // 过户费率（买卖双边），如 A 股 0.00001
+	LevyRate        decimal.Decimal `gorm:"type:decimal(18,8);not null"` // 交易征费等双边费率合计，如港股印花税、证监会及会财局征费、交易费
+	CreatedAt       time.Time       `gorm:"autoCreateTime"`
+	UpdatedAt       time.Time       `gorm:"autoUpdateTime"`
+}
+
+// FeeBreakdown 按费率表计算的手续费明细（嵌入 Transaction，列名前缀 fee_）
+type FeeBreakdown struct {
+	Commission  decimal.Decimal `gorm:"type:decimal(18,4);default:0"` // 佣金
+	StampDuty   decimal.Decimal `gorm:"type:decimal(18,4);default:0"` // 印花税
+	TransferFee decimal.Decimal `gorm:"type:decimal(18,4);default:0"` // 过户费
+	Levy        decimal.Decimal `gorm:"type:decimal(18,4);default:0"` // 交易征费等
+}
+
+// Total 手续费合计
+func (b FeeBreakdown) Total() decimal.Decimal {
+	return b.Commission.Add(b.StampDuty).Add(b.TransferFee).Add(b.Levy)
+}
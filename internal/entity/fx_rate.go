@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// DefaultCurrency 默认币种：未指定币种的交易和用户基准货币均为美元
+const DefaultCurrency = "USD"
+
+// FXRate 汇率实体（对应数据库表 fx_rates）
+// 表示 RateDate 当天 1 单位 Base 货币可兑换 Rate 单位 Quote 货币，如 USD/CNY = 7.1
+// 汇率是公共行情数据，不归属任何用户；反向汇率（CNY/USD）查询时自动取倒数，无需重复录入
+type FXRate struct {
+	ID        uint            `gorm:"primaryKey"`
+	Base      string          `gorm:"not null;size:3;uniqueIndex:idx_fx_rates_pair_date"`    // 基础货币，如 USD
+	Quote     string          `gorm:"not null;size:3;uniqueIndex:idx_fx_rates_pair_date"`    // 计价货币，如 CNY
+	RateDate  time.Time       `gorm:"not null;type:date;uniqueIndex:idx_fx_rates_pair_date"` // 汇率日期
+	Rate      decimal.Decimal `gorm:"type:decimal(18,8);not null"`                           // 1 Base = Rate Quote
+	CreatedAt time.Time       `gorm:"autoCreateTime"`
+	UpdatedAt time.Time       `gorm:"autoUpdateTime"`
+}
@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// IdempotencyKey 幂等键记录（对应数据库表 idempotency_keys）
+// 客户端通过 Idempotency-Key 请求头标识一次写操作，重试时直接回放首次的响应
+type IdempotencyKey struct {
+	ID          uint      `gorm:"primaryKey"`                                                        // 主键ID
+	UserID      uint      `gorm:"not null;uniqueIndex:idx_idempotency_user_key,priority:1"`          // 用户ID（幂等键按用户隔离）
+	Key         string    `gorm:"not null;size:255;uniqueIndex:idx_idempotency_user_key,priority:2"` // 客户端传入的幂等键
+	Method      string    `gorm:"not null;size:10"`                                                  // 请求方法
+	Path        string    `gorm:"not null;size:255"`                                                 // 请求路径
+	RequestHash string    `gorm:"not null;size:64"`                                                  // 请求方法 + 路径 + 请求体的 SHA-256
+	StatusCode  int       `gorm:"not null;default:0"`                                                // 首次响应的 HTTP 状态码（0 表示处理中）
+	ContentType string    `gorm:"size:100"`                                                          // 首次响应的 Content-Type
+	Response    []byte    `gorm:"type:mediumblob"`                                                   // 首次响应的响应体
+	CreatedAt   time.Time `gorm:"autoCreateTime;index"`                                              // 创建时间（用于过期判断）
+	UpdatedAt   time.Time `gorm:"autoUpdateTime"`                                                    // 更新时间
+}
+
+// Completed 首次请求是否已处理完成
+func (k *IdempotencyKey) Completed() bool {
+	return k.StatusCode != 0
+}
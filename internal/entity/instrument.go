@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Instrument 证券主数据（对应数据库表 instruments）
+// 证券主数据是公共数据，不归属任何用户；交易仍按股票代码文本关联，
+// 创建交易时股票代码先规范化（如 aapl、AAPL.US → AAPL），再按规范化后的代码查找主数据补全名称和币种
+type Instrument struct {
+	ID         uint            `gorm:"primaryKey"`
+	Symbol     string          `gorm:"not null;size:20;uniqueIndex"`          // 规范化的股票代码，如 AAPL、600519.SH、0700.HK
+	Exchange   string          `gorm:"not null;size:10;index"`                // 交易所代码，如 NYSE、HKEX、SSE
+	Currency   string          `gorm:"not null;size:3"`                       // 交易币种（ISO 4217）
+	AssetClass string          `gorm:"not null;size:10;default:STOCK"`        // 资产类别，见下方常量
+	LotSize    decimal.Decimal `gorm:"type:decimal(18,4);not null;default:1"` // 每手股数，如 A 股 100、港股按个股而定
+	Name       string          `gorm:"not null;size:100"`                     // 证券名称，如 Apple Inc.
+	ISIN       string          `gorm:"size:12;index"`                         // 国际证券识别码（可选），如 US0378331005
+	CreatedAt  time.Time       `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time       `gorm:"autoUpdateTime"`
+}
+
+// 资产类别常量
+const (
+	AssetClassStock  = "STOCK"  // 股票
+	AssetClassETF    = "ETF"    // 交易所交易基金
+	AssetClassFund   = "FUND"   // 场外基金
+	AssetClassBond   = "BOND"   // 债券
+	AssetClassOption = "OPTION" // 期权
+	AssetClassFuture = "FUTURE" // 期货
+	AssetClassCrypto = "CRYPTO" // 加密货币
+	AssetClassOther  = "OTHER"  // 其他
+)
+
+// IsValidAssetClass 判断资产类别是否有效
+func IsValidAssetClass(class string) bool {
+	switch class {
+	case AssetClassStock, AssetClassETF, AssetClassFund, AssetClassBond,
+		AssetClassOption, AssetClassFuture, AssetClassCrypto, AssetClassOther:
+		return true
+	}
+	return false
+}
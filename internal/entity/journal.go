@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// JournalLine 复式记账分录行（对应数据库表 journal_lines）
+// 每笔交易过账为一组借贷平衡的分录行（同一 TransactionID 下、同一币种借方合计 = 贷方合计），
+// 分录由交易流水和批次回放推导，交易增删改后与批次在同一数据库事务中按股票重新过账
+type JournalLine struct {
+	ID            uint            `gorm:"primaryKey"`
+	UserID        uint            `gorm:"not null;index:idx_journal_lines_user_symbol"`
+	Symbol        string          `gorm:"not null;size:20;index:idx_journal_lines_user_symbol"` // 股票代码（同交易，现金交易可为空）
+	AccountID     uint            `gorm:"not null;default:0"`                                   // 券商账户ID（同交易）
+	TransactionID uint            `gorm:"not null;index"`                                       // 来源交易ID
+	Ledger        string          `gorm:"not null;size:30"`                                     // 会计科目，见下方常量
+	Currency      string          `gorm:"not null;size:3"`                                      // 币种（同交易）
+	Debit         decimal.Decimal `gorm:"type:decimal(18,4);not null"`                          // 借方金额
+	Credit        decimal.Decimal `gorm:"type:decimal(18,4);not null"`                          // 贷方金额
+	PostedAt      time.Time       `gorm:"not null;index"`                                       // 记账时间（= 交易时间）
+	CreatedAt     time.Time       `gorm:"autoCreateTime"`
+}
+
+// 会计科目常量
+// 买入手续费计入证券成本、卖出手续费冲减卖出收入（与税务批次口径一致），
+// 因此证券科目余额等于未平仓批次的剩余成本，已实现损益科目等于批次的已实现盈亏合计
+const (
+	LedgerCash         = "CASH"          // 资产：现金（借方增加）
+	LedgerSecurities   = "SECURITIES"    // 资产：证券持仓成本（借方增加）
+	LedgerFees         = "FEES"          // 费用：账户费用、预扣税、出入金及转托管手续费（借方增加）
+	LedgerRealizedGain = "REALIZED_GAIN" // 损益：已实现盈亏（贷方为收益，借方为亏损）
+	LedgerIncome       = "INCOME"        // 收入：分红、利息（贷方增加）
+	LedgerCapital      = "CAPITAL"       // 权益：投入资本，出入金和证券转入转出（贷方增加）
+)
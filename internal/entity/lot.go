@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// TaxLot 税务批次实体（对应数据库表 tax_lots）
+// 每笔 BUY 交易开立一个批次，批次ID 与开仓交易ID 相同，
+// 这样批次在重新回放后 ID 保持不变，卖出时可以稳定地指定批次
+type TaxLot struct {
+	ID                uint            `gorm:"primaryKey;autoIncrement:false"`          // 批次ID = 开仓（BUY）交易ID
+	UserID            uint            `gorm:"not null;index:idx_tax_lots_user_symbol"` // 用户ID
+	Symbol            string          `gorm:"not null;size:20;index:idx_tax_lots_user_symbol"`
+	AccountID         uint            `gorm:"not null;default:0"`          // 券商账户ID（同开仓交易），卖出只消耗同一账户的批次
+	OpenTime          time.Time       `gorm:"not null"`                    // 开仓时间
+	Currency          string          `gorm:"not null;size:3;default:USD"` // 计价币种（同开仓交易）
+	Quantity          decimal.Decimal `gorm:"type:decimal(18,4);not null"` // 开仓数量
+	CostBasis         decimal.Decimal `gorm:"type:decimal(18,4);not null"` // 开仓总成本 = Amount + Fee
+	UnitCost          decimal.Decimal `gorm:"type:decimal(18,4);not null"` // 单位成本（含手续费）
+	RemainingQuantity decimal.Decimal `gorm:"type:decimal(18,4);not null"` // 剩余数量
+	RemainingCost     decimal.Decimal `gorm:"type:decimal(18,4);not null"` // 剩余成本
+	CreatedAt         time.Time       `gorm:"autoCreateTime"`
+	UpdatedAt         time.Time       `gorm:"autoUpdateTime"`
+}
+
+// LotAssignment 批次分配记录（对应数据库表 lot_assignments）
+// 记录每笔 SELL 交易消耗了哪些批次、各自的成本和已实现盈亏
+type LotAssignment struct {
+	ID                uint            `gorm:"primaryKey"`
+	UserID            uint            `gorm:"not null;index:idx_lot_assignments_user_symbol"`
+	Symbol            string          `gorm:"not null;size:20;index:idx_lot_assignments_user_symbol"`
+	AccountID         uint            `gorm:"not null;default:0"`          // 券商账户ID（同卖出交易）
+	SellTransactionID uint            `gorm:"not null;index"`              // 卖出交易ID
+	LotID             uint            `gorm:"not null;index"`              // 被消耗的批次ID
+	Method            string          `gorm:"not null;size:10"`            // 成本计算方法：FIFO/LIFO/HIFO/SPECIFIC
+	Currency          string          `gorm:"not null;size:3;default:USD"` // 计价币种（同卖出交易，与批次币种一致）
+	Quantity          decimal.Decimal `gorm:"type:decimal(18,4);not null"` // 消耗数量
+	CostBasis         decimal.Decimal `gorm:"type:decimal(18,4);not null"` // 结转成本
+	Proceeds          decimal.Decimal `gorm:"type:decimal(18,4);not null"` // 卖出净收入（手续费按数量分摊）
+	RealizedGain      decimal.Decimal `gorm:"type:decimal(18,4);not null"` // 已实现盈亏 = Proceeds - CostBasis
+	OpenTime          time.Time       `gorm:"not null"`                    // 批次开仓时间
+	CloseTime         time.Time       `gorm:"not null;index"`              // 卖出时间
+	CreatedAt         time.Time       `gorm:"autoCreateTime"`
+}
+
+// 成本计算方法常量
+const (
+	CostBasisFIFO     = "FIFO"     // 先进先出
+	CostBasisLIFO     = "LIFO"     // 后进先出
+	CostBasisHIFO     = "HIFO"     // 最高成本优先
+	CostBasisSpecific = "SPECIFIC" // 指定批次
+)
@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PriceBar 日线行情实体（对应数据库表 price_bars）
+// 每只证券每个交易日一条 OHLCV 记录，价格为证券主数据的交易币种；
+// 行情是公共数据，不归属任何用户。(instrument_id, bar_date) 联合唯一索引同时服务于
+// 「某日当天或之前最近的收盘价」查询：按证券定位后在日期上倒序取第一条
+type PriceBar struct {
+	ID           uint            `gorm:"primaryKey"`
+	InstrumentID uint            `gorm:"not null;uniqueIndex:idx_price_bars_instrument_date,priority:1"`           // 证券ID（关联 instruments）
+	BarDate      time.Time       `gorm:"not null;type:date;uniqueIndex:idx_price_bars_instrument_date,priority:2"` // 交易日期
+	Open         decimal.Decimal `gorm:"type:decimal(18,4);not null"`                                              // 开盘价
+	High         decimal.Decimal `gorm:"type:decimal(18,4);not null"`                                              // 最高价
+	Low          decimal.Decimal `gorm:"type:decimal(18,4);not null"`                                              // 最低价
+	Close        decimal.Decimal `gorm:"type:decimal(18,4);not null"`                                              // 收盘价（估值使用）
+	Volume       decimal.Decimal `gorm:"type:decimal(20,4);not null;default:0"`                                    // 成交量
+	CreatedAt    time.Time       `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time       `gorm:"autoUpdateTime"`
+}
@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PortfolioSnapshot 每日估值快照实体（对应数据库表 portfolio_snapshots）
+// 每个用户每个账户每天一条，记录当天收盘后的市值、成本和现金，金额均为快照生成时用户的基准货币；
+// 由后台任务按交易流水和历史行情生成，交易变更（含补录历史交易）后从交易日期起删除，再由快照补齐队列异步重新生成。
+// (user_id, snapshot_date, account_id) 联合唯一索引同时服务于按用户、日期范围的查询和删除
+type PortfolioSnapshot struct {
+	ID           uint              `gorm:"primaryKey"`
+	UserID       uint              `gorm:"not null;uniqueIndex:idx_portfolio_snapshots_user_date_account,priority:1"`           // 用户ID
+	SnapshotDate time.Time         `gorm:"not null;type:date;uniqueIndex:idx_portfolio_snapshots_user_date_account,priority:2"` // 快照日期（当天收盘后）
+	AccountID    uint              `gorm:"not null;uniqueIndex:idx_portfolio_snapshots_user_date_account,priority:3"`           // 券商账户ID
+	Currency     string            `gorm:"not null;size:3"`                                                                     // 金额币种（基准货币）
+	MarketValue  decimal.Decimal   `gorm:"type:decimal(18,4);not null"`                                                         // 持仓市值
+	Cost         decimal.Decimal   `gorm:"type:decimal(18,4);not null"`                                                         // 持仓成本（按交易日汇率）
+	Cash         decimal.Decimal   `gorm:"type:decimal(18,4);not null"`                                                         // 现金余额（按当天汇率）
+	NetDeposit   decimal.Decimal   `gorm:"type:decimal(18,4);not null;default:0"`                                               // 当天外部资金净流入：入金 - 出金（按交易日汇率）
+	Holdings     []SnapshotHolding `gorm:"foreignKey:SnapshotID"`                                                               // 当天的持仓明细
+	CreatedAt    time.Time         `gorm:"autoCreateTime"`
+}
+
+// SnapshotHolding 快照中的一只持仓（对应数据库表 snapshot_holdings）
+// 没有估值价格时按成本计价（Price 为 0）
+type SnapshotHolding struct {
+	ID            uint            `gorm:"primaryKey"`
+	SnapshotID    uint            `gorm:"not null;index"`              // 所属快照ID
+	Symbol        string          `gorm:"not null;size:20"`            // 股票代码
+	Quantity      decimal.Decimal `gorm:"type:decimal(18,4);not null"` // 持仓数量（带符号，负数为卖空）
+	Price         decimal.Decimal `gorm:"type:decimal(18,4);not null"` // 估值价格（PriceCurrency 计价）
+	PriceCurrency string          `gorm:"size:3"`                      // 估值价格的币种
+	MarketValue   decimal.Decimal `gorm:"type:decimal(18,4);not null"` // 市值（基准货币）
+	Cost          decimal.Decimal `gorm:"type:decimal(18,4);not null"` // 成本（基准货币）
+}
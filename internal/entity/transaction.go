@@ -7,25 +7,110 @@ import (
 )
 
 // Transaction 交易记录实体（对应数据库表 transactions）
-// 记录用户的每一笔买入/卖出操作
+// 记录用户的每一笔买卖、现金收支、拆股和转托管操作
 type Transaction struct {
-	ID        uint            `gorm:"primaryKey"`                   // 主键ID
-	UserID    uint            `gorm:"not null;index"`               // 用户ID（关联 users 表）
-	Symbol    string          `gorm:"not null;size:20;index"`       // 股票代码，如 AAPL、TSLA
-	Name      string          `gorm:"size:100"`                     // 股票名称，如 Apple Inc.
-	Type      string          `gorm:"not null;size:10"`             // 交易类型：BUY（买入）/ SELL（卖出）
-	Quantity  decimal.Decimal `gorm:"type:decimal(18,4);not null"`  // 交易数量（用 decimal 防止精度丢失）
-	Price     decimal.Decimal `gorm:"type:decimal(18,4);not null"`  // 成交单价
-	Amount    decimal.Decimal `gorm:"type:decimal(18,4);not null"`  // 成交总金额 = Quantity × Price
-	Fee       decimal.Decimal `gorm:"type:decimal(18,4);default:0"` // 手续费
-	TradeTime time.Time       `gorm:"not null;index"`               // 交易时间（用户输入的实际成交时间）
-	Notes     string          `gorm:"size:500"`                     // 备注
-	CreatedAt time.Time       `gorm:"autoCreateTime"`               // 记录创建时间（系统自动）
-	UpdatedAt time.Time       `gorm:"autoUpdateTime"`               // 记录更新时间（系统自动）
+	ID              uint            `gorm:"primaryKey"`                                                // 主键ID
+	UserID          uint            `gorm:"not null;index;uniqueIndex:idx_tx_broker_trade,priority:1"` // 用户ID（关联 users 表）
+	AccountID       uint            `gorm:"not null;default:0;index"`                                  // 券商账户ID（关联 accounts 表，0 表示未指定账户）
+	Symbol          string          `gorm:"not null;size:20;index"`                                    // 股票代码，如 AAPL、TSLA
+	Name            string          `gorm:"size:100"`                                                  // 股票名称，如 Apple Inc.
+	Type            string          `gorm:"not null;size:20"`                                          // 交易类型，见下方常量
+	Quantity        decimal.Decimal `gorm:"type:decimal(18,4);not null"`                               // 交易数量（用 decimal 防止精度丢失）
+	Price           decimal.Decimal `gorm:"type:decimal(18,4);not null"`                               // 成交单价
+	Amount          decimal.Decimal `gorm:"type:decimal(18,4);not null"`                               // 金额：买卖为 Quantity × Price；现金类为收支金额；TRANSFER_IN 为转入成本
+	Fee             decimal.Decimal `gorm:"type:decimal(18,4);default:0"`                              // 手续费（分红、利息为预扣税）
+	FeeScheduleID   uint            `gorm:"not null;default:0"`                                        // 计算手续费所用的费率表ID（0 表示手工填写）
+	FeeDetail       FeeBreakdown    `gorm:"embedded;embeddedPrefix:fee_"`                              // 手续费明细（按费率表计算时填写）
+	Ratio           decimal.Decimal `gorm:"type:decimal(18,8);default:0"`                              // 拆股比例（仅 SPLIT）：新股数 / 旧股数，如 2 表示 1 拆 2
+	Currency        string          `gorm:"not null;size:3;default:USD"`                               // 交易币种（ISO 4217），单价、金额、手续费均以该币种计价
+	TradeTime       time.Time       `gorm:"not null;index"`                                            // 交易时间（用户输入的实际成交时间）
+	Notes           string          `gorm:"size:500"`                                                  // 备注
+	CostBasisMethod string          `gorm:"size:10"`                                                   // 成本计算方法（仅 SELL / TRANSFER_OUT）：FIFO/LIFO/HIFO/SPECIFIC
+	LotIDs          string          `gorm:"size:500"`                                                  // 指定的批次ID列表（仅 SELL / TRANSFER_OUT），逗号分隔，按消耗顺序
+	Source          string          `gorm:"size:20;uniqueIndex:idx_tx_broker_trade,priority:2"`        // 数据来源：空（手工录入）/ csv / ibkr_flex / ofx
+	BrokerTradeID   *string         `gorm:"size:100;uniqueIndex:idx_tx_broker_trade,priority:3"`       // 券商成交编号（用于重复导入去重，手工录入为 NULL）
+	CreatedAt       time.Time       `gorm:"autoCreateTime"`                                            // 记录创建时间（系统自动）
+	UpdatedAt       time.Time       `gorm:"autoUpdateTime"`                                            // 记录更新时间（系统自动）
 }
 
 // 交易类型常量
 const (
+	// 证券买卖
 	TransactionTypeBuy  = "BUY"  // 买入
 	TransactionTypeSell = "SELL" // 卖出
+
+	// 现金收支（只有金额，没有数量和单价）
+	TransactionTypeDividend   = "DIVIDEND"   // 分红（需要股票代码）
+	TransactionTypeInterest   = "INTEREST"   // 利息
+	TransactionTypeFee        = "FEE"        // 费用（账户管理费等，可关联股票代码）
+	TransactionTypeDeposit    = "DEPOSIT"    // 入金
+	TransactionTypeWithdrawal = "WITHDRAWAL" // 出金
+
+	// 公司行动与转托管
+	TransactionTypeSplit       = "SPLIT"        // 拆股/合股（按比例调整持仓数量，成本不变）
+	TransactionTypeTransferIn  = "TRANSFER_IN"  // 转入（带转入成本，不涉及现金）
+	TransactionTypeTransferOut = "TRANSFER_OUT" // 转出（按批次结转成本，不产生已实现盈亏）
 )
+
+// IsValidTransactionType 判断交易类型是否有效
+func IsValidTransactionType(txType string) bool {
+	switch txType {
+	case TransactionTypeBuy, TransactionTypeSell,
+		TransactionTypeDividend, TransactionTypeInterest, TransactionTypeFee,
+		TransactionTypeDeposit, TransactionTypeWithdrawal,
+		TransactionTypeSplit, TransactionTypeTransferIn, TransactionTypeTransferOut:
+		return true
+	}
+	return false
+}
+
+// IsCashTransactionType 是否为现金类交易（不影响持仓数量）
+func IsCashTransactionType(txType string) bool {
+	switch txType {
+	case TransactionTypeDividend, TransactionTypeInterest, TransactionTypeFee,
+		TransactionTypeDeposit, TransactionTypeWithdrawal:
+		return true
+	}
+	return false
+}
+
+// ConsumesLots 是否按成本计算方法消耗批次（卖出、转出）
+func ConsumesLots(txType string) bool {
+	return txType == TransactionTypeSell || txType == TransactionTypeTransferOut
+}
+
+// CashFlow 交易对账户现金余额的影响（交易币种，正数为流入，负数为流出）
+// - BUY：流出成交金额 + 手续费
+// - SELL：流入成交金额 - 手续费
+// - DIVIDEND / INTEREST / DEPOSIT：流入金额 - 手续费（分红、利息的手续费为预扣税）
+// - FEE / WITHDRAWAL：流出金额 + 手续费
+// - TRANSFER_IN / TRANSFER_OUT：证券转托管不涉及现金，只流出手续费
+// - SPLIT：不影响现金
+func CashFlow(tx *Transaction) decimal.Decimal {
+	switch tx.Type {
+	case TransactionTypeBuy, TransactionTypeFee, TransactionTypeWithdrawal:
+		return tx.Amount.Add(tx.Fee).Neg()
+	case TransactionTypeSell, TransactionTypeDividend, TransactionTypeInterest, TransactionTypeDeposit:
+		return tx.Amount.Sub(tx.Fee)
+	case TransactionTypeTransferIn, TransactionTypeTransferOut:
+		return tx.Fee.Neg()
+	default:
+		return decimal.Zero
+	}
+}
+
+// CostBasisTransactionTypes 影响持仓成本的交易类型（买卖、转入转出）
+// 这些交易的金额进入批次成本或卖出收入，同一股票必须使用相同币种
+var CostBasisTransactionTypes = []string{
+	TransactionTypeBuy, TransactionTypeSell, TransactionTypeTransferIn, TransactionTypeTransferOut,
+}
+
+// AffectsCostBasis 是否影响持仓成本，见 CostBasisTransactionTypes
+func AffectsCostBasis(txType string) bool {
+	for _, t := range CostBasisTransactionTypes {
+		if t == txType {
+			return true
+		}
+	}
+	return false
+}
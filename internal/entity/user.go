@@ -11,4 +11,18 @@ type User struct {
 	Password  string    `gorm:"not null;size:255"`
 	CreatedAt time.Time `gorm:"autoCreateTime"`
 	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+
+	// AllowShortSelling 是否允许卖空（默认关闭，关闭时卖出数量不能超过持仓）
+	// 只用于未指定账户（account_id=0）的交易，以及新建账户时的默认值；券商账户以 Account.AllowShortSelling 为准
+	AllowShortSelling bool `gorm:"not null;default:false"`
+
+	// EnforceMarketRules 是否校验交易所规则（默认关闭）：A 股 T+1、买入整手、涨跌停价格
+	EnforceMarketRules bool `gorm:"not null;default:false"`
+
+	// CostBasisMethod 默认成本计算方法：FIFO/LIFO/HIFO（卖出时未指定批次则使用该方法）
+	// 账户设置了 Account.CostBasisMethod 时以账户为准
+	CostBasisMethod string `gorm:"not null;size:10;default:FIFO"`
+
+	// BaseCurrency 基准货币（ISO 4217）：持仓、报表默认换算到该币种；交易未指定币种时也使用该币种
+	BaseCurrency string `gorm:"not null;size:3;default:USD"`
 }
@@ -0,0 +1,189 @@
+package impl
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	lotDomain "github.com/florentyang/smartfin-go/internal/domain/lot"
+	"github.com/florentyang/smartfin-go/internal/entity"
+	"github.com/florentyang/smartfin-go/internal/importer"
+)
+
+// ==================== 通用 CSV ====================
+// 第一行为表头，列名与 CreateTransactionRequest 的 JSON 字段一致（不区分大小写）：
+//   symbol, name, type, quantity, price, amount, fee, ratio, currency, trade_time, notes, lot_ids, broker_trade_id
+// 必填列：type, trade_time；其余字段是否必填由交易类型决定，在 Domain 层校验
+// （如买卖需要 symbol/quantity/price，现金类需要 amount，拆股需要 ratio）
+// trade_time 支持 RFC3339（2024-01-15T10:30:00Z）、2024-01-15 10:30:00 或日期（2024-01-15），
+// 后两种按 Options.Location 解析
+// lot_ids 多个批次用分号分隔：12;15
+// broker_trade_id 可选，填写后重复导入会自动跳过
+
+// csvRequiredColumns 必填列
+var csvRequiredColumns = []string{"type", "trade_time"}
+
+type csvImporter struct{}
+
+// NewCSVImporter 创建通用 CSV 导入器
+func NewCSVImporter() importer.Importer {
+	return &csvImporter{}
+}
+
+// Format 格式名
+func (p *csvImporter) Format() string {
+	return importer.FormatCSV
+}
+
+// Parse 解析 CSV
+func (p *csvImporter) Parse(r io.Reader, opts *importer.Options) ([]*importer.Record, error) {
+	loc := opts.TimeLocation()
+
+	// 1. 读取表头
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1 // 允许行尾缺省的可选列
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("CSV 文件为空")
+		}
+		return nil, fmt.Errorf("CSV 表头解析失败: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		// 去掉 Excel 导出的 UTF-8 BOM
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		columns[name] = i
+	}
+	for _, name := range csvRequiredColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("CSV 缺少必填列: %s", name)
+		}
+	}
+
+	// 2. 逐行解析
+	var records []*importer.Record
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			// 单行格式错误（如引号不匹配）记为行错误，不影响后续行；其他读取错误直接返回
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, fmt.Errorf("CSV 读取失败: %w", err)
+			}
+			records = append(records, &importer.Record{Line: parseErr.StartLine, Err: parseErr.Err})
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		if isBlankRecord(record) {
+			continue
+		}
+
+		tx, err := parseCSVRecord(record, columns, loc)
+		records = append(records, &importer.Record{Line: line, Transaction: tx, Err: err})
+	}
+	return records, nil
+}
+
+// ==================== 私有辅助函数 ====================
+
+// parseCSVRecord 将一行 CSV 解析为交易实体
+// 这里只做格式解析，业务规则由 Domain 层校验
+func parseCSVRecord(record []string, columns map[string]int, loc *time.Location) (*entity.Transaction, error) {
+	field := func(name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	tx := &entity.Transaction{
+		Symbol:   field("symbol"),
+		Name:     field("name"),
+		Type:     strings.ToUpper(field("type")),
+		Notes:    field("notes"),
+		Currency: field("currency"), // 可选，为空时使用用户的基准货币
+		Source:   importer.FormatCSV,
+	}
+	if id := field("broker_trade_id"); id != "" {
+		tx.BrokerTradeID = &id
+	}
+
+	var err error
+	if tx.Quantity, err = parseDecimal("quantity", field("quantity"), false); err != nil {
+		return nil, err
+	}
+	if tx.Price, err = parseDecimal("price", field("price"), false); err != nil {
+		return nil, err
+	}
+	if tx.Amount, err = parseDecimal("amount", field("amount"), false); err != nil {
+		return nil, err
+	}
+	if tx.Fee, err = parseDecimal("fee", field("fee"), false); err != nil {
+		return nil, err
+	}
+	if tx.Ratio, err = parseDecimal("ratio", field("ratio"), false); err != nil {
+		return nil, err
+	}
+	if tx.TradeTime, err = parseCSVTime(field("trade_time"), loc); err != nil {
+		return nil, err
+	}
+	if tx.LotIDs, err = parseCSVLotIDs(field("lot_ids")); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// parseCSVTime 解析交易时间：RFC3339 自带时区，其余格式按 loc 解析
+func parseCSVTime(value string, loc *time.Location) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, errors.New("trade_time 不能为空")
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("trade_time 格式错误: %s", value)
+}
+
+// parseCSVLotIDs 解析指定批次：分号分隔，转为 Transaction.LotIDs 的存储格式
+func parseCSVLotIDs(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	parts := strings.Split(value, ";")
+	ids := make([]uint, 0, len(parts))
+	for _, part := range parts {
+		id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 64)
+		if err != nil || id == 0 {
+			return "", fmt.Errorf("lot_ids 格式错误: %s", value)
+		}
+		ids = append(ids, uint(id))
+	}
+	return lotDomain.EncodeLotIDs(ids), nil
+}
+
+// isBlankRecord 判断是否为空行
+func isBlankRecord(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
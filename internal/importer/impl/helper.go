@@ -0,0 +1,26 @@
+package impl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// ==================== 各格式共用的解析函数 ====================
+
+// parseDecimal 解析金额/数量字段（字符串直接转 decimal，不经过 float64）
+func parseDecimal(name, value string, required bool) (decimal.Decimal, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		if required {
+			return decimal.Zero, fmt.Errorf("%s 不能为空", name)
+		}
+		return decimal.Zero, nil
+	}
+	d, err := decimal.NewFromString(value)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("%s 格式错误: %s", name, value)
+	}
+	return d, nil
+}
@@ -0,0 +1,188 @@
+package impl
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/florentyang/smartfin-go/internal/entity"
+	"github.com/florentyang/smartfin-go/internal/importer"
+)
+
+// ==================== Interactive Brokers Flex Query XML ====================
+// 读取 FlexQueryResponse 中的 <Trade> 元素（属性格式），关键属性：
+//   accountId, tradeID, symbol, description, assetCategory, buySell, quantity,
+//   tradePrice, currency, ibCommission, ibCommissionCurrency, taxes, dateTime, tradeDate, levelOfDetail, notes
+// Flex 报表中的时间不带时区（为账户设置的时区，通常是美东），按 Options.Location 解析
+// 券商成交编号 = accountId:tradeID，同一笔成交重复导入会被跳过
+
+// flexDateTimeLayouts Flex 报表可配置的日期时间格式
+var flexDateTimeLayouts = []string{
+	"20060102;150405",
+	"2006-01-02;15:04:05",
+	"20060102 150405",
+	"2006-01-02 15:04:05",
+	"2006-01-02, 15:04:05",
+	"01/02/2006;15:04:05",
+	"20060102",
+	"2006-01-02",
+}
+
+type ibkrFlexImporter struct{}
+
+// NewIBKRFlexImporter 创建 IBKR Flex XML 导入器
+func NewIBKRFlexImporter() importer.Importer {
+	return &ibkrFlexImporter{}
+}
+
+// Format 格式名
+func (p *ibkrFlexImporter) Format() string {
+	return importer.FormatIBKRFlex
+}
+
+// Parse 解析 Flex XML
+// 以流方式读取，只处理 <Trade> 元素，其余节点（持仓、现金流水等）忽略
+func (p *ibkrFlexImporter) Parse(r io.Reader, opts *importer.Options) ([]*importer.Record, error) {
+	loc := opts.TimeLocation()
+	decoder := xml.NewDecoder(r)
+
+	var (
+		records []*importer.Record
+		root    bool
+	)
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Flex XML 解析失败: %w", err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if !root {
+			// 第一个元素必须是 FlexQueryResponse，防止误传其他 XML
+			if start.Name.Local != "FlexQueryResponse" {
+				return nil, errors.New("不是 IBKR Flex Query 报表（缺少 FlexQueryResponse 根节点）")
+			}
+			root = true
+			continue
+		}
+		if start.Name.Local != "Trade" {
+			continue
+		}
+
+		line, _ := decoder.InputPos()
+		attrs := make(map[string]string, len(start.Attr))
+		for _, attr := range start.Attr {
+			attrs[attr.Name.Local] = strings.TrimSpace(attr.Value)
+		}
+		// 只导入成交明细：ORDER / CLOSED_LOT 等汇总行会与 EXECUTION 重复
+		if level := attrs["levelOfDetail"]; level != "" && !strings.EqualFold(level, "EXECUTION") {
+			continue
+		}
+		// 外汇兑换（assetCategory=CASH）不是证券交易，不导入
+		if strings.EqualFold(attrs["assetCategory"], "CASH") {
+			continue
+		}
+
+		tx, err := parseFlexTrade(attrs, loc)
+		records = append(records, &importer.Record{Line: line, Transaction: tx, Err: err})
+	}
+	if !root {
+		return nil, errors.New("Flex XML 文件为空")
+	}
+	return records, nil
+}
+
+// ==================== 私有辅助函数 ====================
+
+// parseFlexTrade 将 <Trade> 属性解析为交易实体
+func parseFlexTrade(attrs map[string]string, loc *time.Location) (*entity.Transaction, error) {
+	tradeID := attrs["tradeID"]
+	if tradeID == "" {
+		tradeID = attrs["transactionID"]
+	}
+	if tradeID == "" {
+		return nil, errors.New("缺少 tradeID")
+	}
+	brokerTradeID := tradeID
+	if account := attrs["accountId"]; account != "" {
+		brokerTradeID = account + ":" + tradeID
+	}
+
+	tx := &entity.Transaction{
+		Symbol:        attrs["symbol"],
+		Name:          attrs["description"],
+		Notes:         attrs["notes"],
+		Currency:      attrs["currency"], // 成交币种，如 USD / HKD
+		Source:        importer.FormatIBKRFlex,
+		BrokerTradeID: &brokerTradeID,
+	}
+	if tx.Symbol == "" {
+		return nil, errors.New("symbol 不能为空")
+	}
+
+	// 买卖方向：buySell 为 BUY / SELL（撤销的成交为 "BUY (Ca.)" 等，不导入）
+	switch side := strings.ToUpper(attrs["buySell"]); side {
+	case entity.TransactionTypeBuy, entity.TransactionTypeSell:
+		tx.Type = side
+	default:
+		return nil, fmt.Errorf("不支持的 buySell: %s", attrs["buySell"])
+	}
+
+	// 数量：卖出为负数，统一取绝对值
+	quantity, err := parseDecimal("quantity", attrs["quantity"], true)
+	if err != nil {
+		return nil, err
+	}
+	tx.Quantity = quantity.Abs()
+
+	if tx.Price, err = parseDecimal("tradePrice", attrs["tradePrice"], true); err != nil {
+		return nil, err
+	}
+
+	// 手续费：ibCommission 与 taxes 在报表中为负数（支出），取绝对值相加
+	commission, err := parseDecimal("ibCommission", attrs["ibCommission"], false)
+	if err != nil {
+		return nil, err
+	}
+	taxes, err := parseDecimal("taxes", attrs["taxes"], false)
+	if err != nil {
+		return nil, err
+	}
+	tx.Fee = commission.Abs().Add(taxes.Abs())
+	// 佣金按成交币种计入手续费，币种不同时无法直接相加
+	if c := attrs["ibCommissionCurrency"]; c != "" && tx.Currency != "" && !commission.IsZero() && c != tx.Currency {
+		return nil, fmt.Errorf("佣金币种 %s 与成交币种 %s 不同", c, tx.Currency)
+	}
+
+	// 成交时间：优先 dateTime，没有时退回 tradeDate
+	value := attrs["dateTime"]
+	if value == "" {
+		value = attrs["tradeDate"]
+	}
+	if tx.TradeTime, err = parseFlexTime(value, loc); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// parseFlexTime 解析 Flex 时间（不带时区，按 loc 解析）
+func parseFlexTime(value string, loc *time.Location) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, errors.New("dateTime 不能为空")
+	}
+	for _, layout := range flexDateTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("dateTime 格式错误: %s", value)
+}
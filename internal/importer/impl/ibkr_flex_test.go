@@ -0,0 +1,190 @@
+package impl
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/florentyang/smartfin-go/internal/entity"
+	"github.com/florentyang/smartfin-go/internal/importer"
+)
+
+// wantTx 期望的交易字段（只比较导入器负责填充的字段）
+type wantTx struct {
+	symbol        string
+	name          string
+	txType        string
+	quantity      string
+	price         string
+	fee           string
+	currency      string
+	tradeTime     time.Time
+	brokerTradeID string
+	notes         string
+}
+
+// assertTx 比较解析出的交易与期望字段
+func assertTx(t *testing.T, source string, got *entity.Transaction, want wantTx) {
+	t.Helper()
+	if got == nil {
+		t.Fatalf("交易为 nil")
+	}
+	if got.Symbol != want.symbol || got.Name != want.name || got.Type != want.txType || got.Currency != want.currency || got.Notes != want.notes {
+		t.Errorf("symbol/name/type/currency/notes = %q/%q/%q/%q/%q, want %q/%q/%q/%q/%q",
+			got.Symbol, got.Name, got.Type, got.Currency, got.Notes, want.symbol, want.name, want.txType, want.currency, want.notes)
+	}
+	for _, c := range []struct {
+		field string
+		got   decimal.Decimal
+		want  string
+	}{
+		{"quantity", got.Quantity, want.quantity},
+		{"price", got.Price, want.price},
+		{"fee", got.Fee, want.fee},
+	} {
+		if !c.got.Equal(decimal.RequireFromString(c.want)) {
+			t.Errorf("%s = %s, want %s", c.field, c.got, c.want)
+		}
+	}
+	if !got.TradeTime.Equal(want.tradeTime) {
+		t.Errorf("trade_time = %s, want %s", got.TradeTime, want.tradeTime)
+	}
+	if got.BrokerTradeID == nil || *got.BrokerTradeID != want.brokerTradeID {
+		t.Errorf("broker_trade_id = %v, want %s", got.BrokerTradeID, want.brokerTradeID)
+	}
+	if got.Source != source {
+		t.Errorf("source = %s, want %s", got.Source, source)
+	}
+}
+
+func TestIBKRFlexImporterParse(t *testing.T) {
+	f, err := os.Open("testdata/ibkr_flex.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("缺少时区数据: %v", err)
+	}
+
+	records, err := NewIBKRFlexImporter().Parse(f, &importer.Options{Location: newYork})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	// ORDER 汇总行和外汇兑换（CASH）不导入
+	if len(records) != 3 {
+		t.Fatalf("len(records) = %d, want 3", len(records))
+	}
+
+	for i, want := range []wantTx{
+		{
+			symbol: "AAPL", name: "APPLE INC", txType: entity.TransactionTypeBuy,
+			quantity: "10", price: "185.5", fee: "1.05", currency: "USD",
+			tradeTime:     time.Date(2024, 1, 15, 10, 30, 0, 0, newYork),
+			brokerTradeID: "U1234567:1001",
+		},
+		{
+			symbol: "700", name: "TENCENT", txType: entity.TransactionTypeSell,
+			quantity: "200", price: "290.2", fee: "78.5", currency: "HKD",
+			tradeTime:     time.Date(2024, 1, 16, 9, 45, 0, 0, newYork),
+			brokerTradeID: "U1234567:1002", notes: "P",
+		},
+	} {
+		if records[i].Err != nil {
+			t.Fatalf("records[%d].Err = %v", i, records[i].Err)
+		}
+		assertTx(t, importer.FormatIBKRFlex, records[i].Transaction, want)
+	}
+
+	// 撤销的成交（BUY (Ca.)）作为行错误返回，带行号
+	if records[2].Err == nil || records[2].Transaction != nil {
+		t.Errorf("records[2] = %+v, want 行错误", records[2])
+	}
+	if records[2].Line == 0 {
+		t.Errorf("records[2].Line = 0, want 行号")
+	}
+}
+
+func TestIBKRFlexImporterParseRejectsOtherXML(t *testing.T) {
+	f, err := os.Open("testdata/xml.qfx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := NewIBKRFlexImporter().Parse(f, nil); err == nil {
+		t.Error("Parse() error = nil, want 缺少 FlexQueryResponse 根节点")
+	}
+}
+
+func TestParseFlexTrade(t *testing.T) {
+	base := func(overrides map[string]string) map[string]string {
+		attrs := map[string]string{
+			"accountId": "U1", "tradeID": "7", "symbol": "MSFT", "description": "MICROSOFT CORP",
+			"buySell": "SELL", "quantity": "-3", "tradePrice": "400.25", "currency": "USD",
+			"ibCommission": "-0.35", "ibCommissionCurrency": "USD", "dateTime": "20240301;153000",
+		}
+		for k, v := range overrides {
+			attrs[k] = v
+		}
+		return attrs
+	}
+
+	tests := []struct {
+		name    string
+		attrs   map[string]string
+		want    *wantTx
+		wantErr bool
+	}{
+		{
+			name:  "卖出数量取绝对值，佣金负数记为手续费",
+			attrs: base(nil),
+			want: &wantTx{
+				symbol: "MSFT", name: "MICROSOFT CORP", txType: entity.TransactionTypeSell,
+				quantity: "3", price: "400.25", fee: "0.35", currency: "USD",
+				tradeTime: time.Date(2024, 3, 1, 15, 30, 0, 0, time.UTC), brokerTradeID: "U1:7",
+			},
+		},
+		{
+			name:  "佣金为正数（返佣冲正）同样按绝对值计入，加上税费",
+			attrs: base(map[string]string{"ibCommission": "0.35", "taxes": "-0.02"}),
+			want: &wantTx{
+				symbol: "MSFT", name: "MICROSOFT CORP", txType: entity.TransactionTypeSell,
+				quantity: "3", price: "400.25", fee: "0.37", currency: "USD",
+				tradeTime: time.Date(2024, 3, 1, 15, 30, 0, 0, time.UTC), brokerTradeID: "U1:7",
+			},
+		},
+		{
+			name:  "没有 tradeID 时取 transactionID，没有账户时不加前缀，没有 dateTime 时取 tradeDate",
+			attrs: base(map[string]string{"tradeID": "", "transactionID": "T9", "accountId": "", "dateTime": "", "tradeDate": "2024-03-04", "ibCommission": ""}),
+			want: &wantTx{
+				symbol: "MSFT", name: "MICROSOFT CORP", txType: entity.TransactionTypeSell,
+				quantity: "3", price: "400.25", fee: "0", currency: "USD",
+				tradeTime: time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), brokerTradeID: "T9",
+			},
+		},
+		{name: "缺少成交编号", attrs: base(map[string]string{"tradeID": ""}), wantErr: true},
+		{name: "缺少股票代码", attrs: base(map[string]string{"symbol": ""}), wantErr: true},
+		{name: "撤销的成交", attrs: base(map[string]string{"buySell": "SELL (Ca.)"}), wantErr: true},
+		{name: "缺少成交价", attrs: base(map[string]string{"tradePrice": ""}), wantErr: true},
+		{name: "佣金币种与成交币种不同", attrs: base(map[string]string{"ibCommissionCurrency": "HKD"}), wantErr: true},
+		{name: "时间格式错误", attrs: base(map[string]string{"dateTime": "03/01/24"}), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := parseFlexTrade(tt.attrs, time.UTC)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseFlexTrade() = %+v, want error", tx)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseFlexTrade() error = %v", err)
+			}
+			assertTx(t, importer.FormatIBKRFlex, tx, *tt.want)
+		})
+	}
+}
@@ -0,0 +1,347 @@
+package impl
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/florentyang/smartfin-go/internal/entity"
+	"github.com/florentyang/smartfin-go/internal/importer"
+)
+
+// ==================== OFX / QFX ====================
+// 同时兼容 OFX 1.x（SGML，叶子元素没有结束标签）与 OFX 2.x（XML）
+// 读取投资对账单 INVSTMTRS/INVTRANLIST 中的买卖交易：
+//   BUYSTOCK / SELLSTOCK / BUYMF / SELLMF / BUYDEBT / SELLDEBT / BUYOPT / SELLOPT / BUYOTHER / SELLOTHER
+// 股票代码通过 SECLIST 中的 SECID → TICKER 映射，找不到时使用 UNIQUEID（如 CUSIP）
+// DTTRADE 自带时区（如 20240115103000.000[-5:EST]），未带时区时按 Options.Location 解析
+// 币种取交易中的 CURRENCY/CURSYM，没有时取对账单的 CURDEF
+// 券商成交编号 = ACCTID:FITID，同一笔成交重复导入会被跳过
+// 分红、转托管等其他交易类型暂不导入
+
+// ofxTradeTypes 交易聚合元素 → 买卖方向
+var ofxTradeTypes = map[string]string{
+	"BUYSTOCK":  entity.TransactionTypeBuy,
+	"BUYMF":     entity.TransactionTypeBuy,
+	"BUYDEBT":   entity.TransactionTypeBuy,
+	"BUYOPT":    entity.TransactionTypeBuy,
+	"BUYOTHER":  entity.TransactionTypeBuy,
+	"SELLSTOCK": entity.TransactionTypeSell,
+	"SELLMF":    entity.TransactionTypeSell,
+	"SELLDEBT":  entity.TransactionTypeSell,
+	"SELLOPT":   entity.TransactionTypeSell,
+	"SELLOTHER": entity.TransactionTypeSell,
+}
+
+// ofxEntities OFX 文本中的转义字符
+var ofxEntities = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&quot;", `"`, "&apos;", "'", "&nbsp;", " ", "&amp;", "&")
+
+type ofxImporter struct{}
+
+// NewOFXImporter 创建 OFX/QFX 导入器
+func NewOFXImporter() importer.Importer {
+	return &ofxImporter{}
+}
+
+// Format 格式名
+func (p *ofxImporter) Format() string {
+	return importer.FormatOFX
+}
+
+// Parse 解析 OFX/QFX
+func (p *ofxImporter) Parse(r io.Reader, opts *importer.Options) ([]*importer.Record, error) {
+	loc := opts.TimeLocation()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("OFX 读取失败: %w", err)
+	}
+	root, err := parseOFXTree(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. 证券信息：UNIQUEID → 代码/名称
+	securities := make(map[string]*ofxNode)
+	root.walk(func(n *ofxNode) {
+		if n.name == "SECINFO" {
+			if id := n.text("SECID", "UNIQUEID"); id != "" {
+				securities[id] = n
+			}
+		}
+	})
+
+	// 2. 逐个投资账户读取交易
+	var records []*importer.Record
+	root.walk(func(stmt *ofxNode) {
+		if stmt.name != "INVSTMTRS" {
+			return
+		}
+		account := stmt.text("INVACCTFROM", "ACCTID")
+		curdef := stmt.text("CURDEF") // 对账单默认币种
+		list := stmt.child("INVTRANLIST")
+		if list == nil {
+			return
+		}
+		for _, n := range list.children {
+			side, ok := ofxTradeTypes[n.name]
+			if !ok {
+				continue
+			}
+			tx, err := parseOFXTrade(n, side, account, curdef, securities, loc)
+			records = append(records, &importer.Record{Line: n.line, Transaction: tx, Err: err})
+		}
+	})
+	return records, nil
+}
+
+// ==================== 交易解析 ====================
+
+// parseOFXTrade 将一个买卖聚合元素解析为交易实体
+func parseOFXTrade(n *ofxNode, side, account, curdef string, securities map[string]*ofxNode, loc *time.Location) (*entity.Transaction, error) {
+	// BUY* 的明细在 INVBUY 中，SELL* 的明细在 INVSELL 中
+	detail := n.child("INVBUY")
+	if detail == nil {
+		detail = n.child("INVSELL")
+	}
+	if detail == nil {
+		return nil, fmt.Errorf("%s 缺少 INVBUY/INVSELL", n.name)
+	}
+
+	fitID := detail.text("INVTRAN", "FITID")
+	if fitID == "" {
+		return nil, errors.New("缺少 FITID")
+	}
+	brokerTradeID := fitID
+	if account != "" {
+		brokerTradeID = account + ":" + fitID
+	}
+
+	tx := &entity.Transaction{
+		Type:          side,
+		Notes:         detail.text("INVTRAN", "MEMO"),
+		Currency:      curdef,
+		Source:        importer.FormatOFX,
+		BrokerTradeID: &brokerTradeID,
+	}
+	// 带 CURRENCY 聚合时金额以该币种计价（ORIGCURRENCY 表示已折算为默认币种，仍按 CURDEF）
+	if cur := detail.text("CURRENCY", "CURSYM"); cur != "" {
+		tx.Currency = cur
+	}
+
+	// 股票代码：优先 SECLIST 中的 TICKER
+	secID := detail.text("SECID", "UNIQUEID")
+	if secID == "" {
+		return nil, errors.New("缺少 SECID")
+	}
+	tx.Symbol = secID
+	if sec, ok := securities[secID]; ok {
+		if ticker := sec.text("TICKER"); ticker != "" {
+			tx.Symbol = ticker
+		}
+		tx.Name = sec.text("SECNAME")
+	}
+
+	// 数量：卖出为负数，统一取绝对值
+	units, err := parseDecimal("UNITS", detail.text("UNITS"), true)
+	if err != nil {
+		return nil, err
+	}
+	tx.Quantity = units.Abs()
+	if tx.Price, err = parseDecimal("UNITPRICE", detail.text("UNITPRICE"), true); err != nil {
+		return nil, err
+	}
+
+	// 手续费 = 佣金 + 税费 + 其他费用 + 申购费
+	tx.Fee = decimal.Zero
+	for _, name := range []string{"COMMISSION", "TAXES", "FEES", "LOAD"} {
+		fee, err := parseDecimal(name, detail.text(name), false)
+		if err != nil {
+			return nil, err
+		}
+		tx.Fee = tx.Fee.Add(fee.Abs())
+	}
+
+	if tx.TradeTime, err = parseOFXTime(detail.text("INVTRAN", "DTTRADE"), loc); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// parseOFXTime 解析 OFX 时间：YYYYMMDD[HHMMSS[.XXX]][[偏移小时[:时区名]]]
+// 例：20240115103000.000[-5:EST]、20240115[+8]
+func parseOFXTime(value string, loc *time.Location) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, errors.New("DTTRADE 不能为空")
+	}
+
+	v := value
+	zone := loc
+	if i := strings.Index(v, "["); i >= 0 {
+		tz := strings.TrimSuffix(v[i+1:], "]")
+		v = v[:i]
+		offset, name := tz, ""
+		if j := strings.Index(tz, ":"); j >= 0 {
+			offset, name = tz[:j], tz[j+1:]
+		}
+		// 偏移为小时数，可带小数（如 +5.5）
+		hours, err := decimal.NewFromString(offset)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("DTTRADE 时区格式错误: %s", value)
+		}
+		if name == "" {
+			name = "UTC" + offset
+		}
+		zone = time.FixedZone(name, int(hours.Mul(decimal.NewFromInt(3600)).IntPart()))
+	}
+	// 毫秒部分舍去
+	if dot := strings.Index(v, "."); dot >= 0 {
+		v = v[:dot]
+	}
+
+	var layout string
+	switch len(v) {
+	case 8:
+		layout = "20060102"
+	case 12:
+		layout = "200601021504"
+	case 14:
+		layout = "20060102150405"
+	default:
+		return time.Time{}, fmt.Errorf("DTTRADE 格式错误: %s", value)
+	}
+	t, err := time.ParseInLocation(layout, v, zone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("DTTRADE 格式错误: %s", value)
+	}
+	return t, nil
+}
+
+// ==================== OFX 元素树 ====================
+
+// ofxNode OFX 元素树节点
+// 聚合元素有 children，叶子元素有 value
+type ofxNode struct {
+	name     string
+	value    string
+	line     int
+	children []*ofxNode
+}
+
+// child 查找第一个指定名称的直接子元素
+func (n *ofxNode) child(name string) *ofxNode {
+	for _, c := range n.children {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// text 按路径取叶子元素的值，不存在时返回空字符串
+func (n *ofxNode) text(path ...string) string {
+	cur := n
+	for _, name := range path {
+		if cur = cur.child(name); cur == nil {
+			return ""
+		}
+	}
+	return cur.value
+}
+
+// walk 深度优先遍历
+func (n *ofxNode) walk(fn func(*ofxNode)) {
+	fn(n)
+	for _, c := range n.children {
+		c.walk(fn)
+	}
+}
+
+// isLeaf 是否为已取得值的叶子元素
+func (n *ofxNode) isLeaf() bool {
+	return n.value != "" && len(n.children) == 0
+}
+
+// parseOFXTree 将 OFX 文本解析为元素树，返回 <OFX> 根节点
+// SGML 的叶子元素没有结束标签：遇到下一个标签时，已取得值的叶子元素自动闭合
+func parseOFXTree(data string) (*ofxNode, error) {
+	// 跳过文件头（OFX 1.x 的 KEY:VALUE 头或 OFX 2.x 的 XML 声明）
+	start := strings.Index(strings.ToUpper(data), "<OFX>")
+	if start < 0 {
+		return nil, errors.New("不是 OFX 文件（缺少 <OFX> 根节点）")
+	}
+	line := 1 + strings.Count(data[:start], "\n")
+	data = data[start:]
+
+	root := &ofxNode{}
+	stack := []*ofxNode{root}
+	top := func() *ofxNode { return stack[len(stack)-1] }
+
+	for pos := 0; pos < len(data); {
+		// 文本：作为当前元素的值
+		if data[pos] != '<' {
+			end := strings.IndexByte(data[pos:], '<')
+			if end < 0 {
+				end = len(data) - pos
+			}
+			text := data[pos : pos+end]
+			line += strings.Count(text, "\n")
+			if value := strings.TrimSpace(text); value != "" && len(stack) > 1 {
+				top().value = ofxEntities.Replace(value)
+			}
+			pos += end
+			continue
+		}
+
+		// 标签
+		end := strings.IndexByte(data[pos:], '>')
+		if end < 0 {
+			return nil, fmt.Errorf("OFX 第 %d 行：标签未闭合", line)
+		}
+		tag := strings.TrimSpace(data[pos+1 : pos+end])
+		tagLine := line
+		line += strings.Count(tag, "\n")
+		pos += end + 1
+
+		switch {
+		case tag == "" || tag[0] == '?' || tag[0] == '!':
+			// 处理指令、注释
+		case tag[0] == '/':
+			// 结束标签：先闭合未写结束标签的叶子元素，再弹出到同名元素
+			name := strings.ToUpper(strings.TrimSpace(tag[1:]))
+			if n := top(); n.isLeaf() && n.name != name && len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+			for i := len(stack) - 1; i > 0; i-- {
+				if stack[i].name == name {
+					stack = stack[:i]
+					break
+				}
+			}
+		default:
+			// 开始标签：上一个已取得值的叶子元素自动闭合
+			if n := top(); n.isLeaf() && len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+			name := strings.ToUpper(strings.Fields(tag)[0])
+			selfClosing := strings.HasSuffix(tag, "/")
+			name = strings.TrimSuffix(name, "/")
+			node := &ofxNode{name: name, line: tagLine}
+			parent := top()
+			parent.children = append(parent.children, node)
+			if !selfClosing {
+				stack = append(stack, node)
+			}
+		}
+	}
+
+	ofx := root.child("OFX")
+	if ofx == nil {
+		return nil, errors.New("不是 OFX 文件（缺少 <OFX> 根节点）")
+	}
+	return ofx, nil
+}
@@ -0,0 +1,163 @@
+package impl
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/florentyang/smartfin-go/internal/entity"
+	"github.com/florentyang/smartfin-go/internal/importer"
+)
+
+func TestOFXImporterParse(t *testing.T) {
+	est := time.FixedZone("EST", -5*3600)
+	tests := []struct {
+		name    string
+		file    string
+		loc     *time.Location
+		want    []*wantTx // nil 表示该记录应为行错误
+		wantErr bool
+	}{
+		{
+			// OFX 1.x：叶子元素没有结束标签，时间自带时区，SECLIST 映射代码，INCOME 不导入
+			name: "SGML",
+			file: "testdata/sgml.ofx",
+			loc:  time.UTC,
+			want: []*wantTx{
+				{
+					symbol: "T", name: "AT&T INC", txType: entity.TransactionTypeBuy,
+					quantity: "100", price: "16.85", fee: "5", currency: "USD",
+					tradeTime: time.Date(2024, 1, 15, 10, 30, 0, 0, est), brokerTradeID: "X-998:T-100", notes: "Buy AT&T",
+				},
+				{
+					symbol: "BMMV2K8", txType: entity.TransactionTypeSell,
+					quantity: "300", price: "290.2", fee: "78.5", currency: "HKD",
+					tradeTime: time.Date(2024, 1, 20, 0, 0, 0, 0, time.FixedZone("UTC+8", 8*3600)), brokerTradeID: "X-998:T-101",
+				},
+			},
+		},
+		{
+			// OFX 2.x：标准 XML，时间不带时区时按 Options.Location 解析
+			name: "XML",
+			file: "testdata/xml.qfx",
+			loc:  est,
+			want: []*wantTx{
+				{
+					symbol: "VFIAX", name: "Vanguard 500 Index Fund", txType: entity.TransactionTypeBuy,
+					quantity: "2.5", price: "460.12", fee: "1.25", currency: "USD",
+					tradeTime: time.Date(2024, 2, 5, 9, 30, 0, 0, est), brokerTradeID: "Z-42:MF-1", notes: "Monthly investment",
+				},
+				nil, // UNITPRICE 格式错误
+			},
+		},
+		{name: "不是 OFX", file: "testdata/ibkr_flex.xml", loc: time.UTC, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			records, err := NewOFXImporter().Parse(f, &importer.Options{Location: tt.loc})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Parse() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if len(records) != len(tt.want) {
+				t.Fatalf("len(records) = %d, want %d", len(records), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if records[i].Line == 0 {
+					t.Errorf("records[%d].Line = 0, want 行号", i)
+				}
+				if want == nil {
+					if records[i].Err == nil {
+						t.Errorf("records[%d].Err = nil, want 行错误", i)
+					}
+					continue
+				}
+				if records[i].Err != nil {
+					t.Fatalf("records[%d].Err = %v", i, records[i].Err)
+				}
+				assertTx(t, importer.FormatOFX, records[i].Transaction, *want)
+			}
+		})
+	}
+}
+
+func TestParseOFXTime(t *testing.T) {
+	shanghai := time.FixedZone("CST", 8*3600)
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "20240115103000.000[-5:EST]", want: time.Date(2024, 1, 15, 15, 30, 0, 0, time.UTC)},
+		{value: "20240115103000[-5]", want: time.Date(2024, 1, 15, 15, 30, 0, 0, time.UTC)},
+		{value: "20240115103000.123[+5.5:IST]", want: time.Date(2024, 1, 15, 5, 0, 0, 0, time.UTC)},
+		{value: "20240115[+8]", want: time.Date(2024, 1, 14, 16, 0, 0, 0, time.UTC)},
+		{value: "202401151030", want: time.Date(2024, 1, 15, 10, 30, 0, 0, shanghai)},
+		{value: "20240115", want: time.Date(2024, 1, 15, 0, 0, 0, 0, shanghai)},
+		{value: "", wantErr: true},
+		{value: "2024011", wantErr: true},
+		{value: "20241315", wantErr: true},
+		{value: "20240115[EST]", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseOFXTime(tt.value, shanghai)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseOFXTime() = %s, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseOFXTime() error = %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseOFXTime() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOFXTree(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		path []string
+		want string
+	}{
+		{name: "SGML 叶子元素自动闭合", data: "<OFX><A><B>1<C>2</A><D>x</OFX>", path: []string{"A", "C"}, want: "2"},
+		{name: "SGML 聚合元素闭合后的兄弟元素", data: "<OFX><A><B>1<C>2</A><D>x</OFX>", path: []string{"D"}, want: "x"},
+		{name: "XML 结束标签", data: "<?xml version=\"1.0\"?><OFX><A><B>1</B><C>2</C></A></OFX>", path: []string{"A", "B"}, want: "1"},
+		{name: "标签不区分大小写", data: "<ofx><a><b>1</a></ofx>", path: []string{"A", "B"}, want: "1"},
+		{name: "转义字符", data: "<OFX><MEMO>AT&amp;T &lt;NYSE&gt;</OFX>", path: []string{"MEMO"}, want: "AT&T <NYSE>"},
+		{name: "文件头之后的根节点", data: "OFXHEADER:100\r\nDATA:OFXSGML\r\n\r\n<OFX><A>1</OFX>", path: []string{"A"}, want: "1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := parseOFXTree(tt.data)
+			if err != nil {
+				t.Fatalf("parseOFXTree() error = %v", err)
+			}
+			if got := root.text(tt.path...); got != tt.want {
+				t.Errorf("text(%v) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+
+	for _, data := range []string{"<A>1</A>", "<OFX><A"} {
+		if _, err := parseOFXTree(data); err == nil {
+			t.Errorf("parseOFXTree(%q) error = nil, want error", data)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package importer
+
+import (
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 错误定义 ====================
+
+var (
+	ErrUnsupportedFormat = errors.New("不支持的导入格式")
+)
+
+// 导入格式常量（同时作为 Transaction.Source 的取值）
+const (
+	FormatCSV      = "csv"       // 通用 CSV
+	FormatIBKRFlex = "ibkr_flex" // Interactive Brokers Flex Query XML
+	FormatOFX      = "ofx"       // OFX / QFX 对账单
+)
+
+// ==================== 结构体定义 ====================
+
+// Options 解析选项
+type Options struct {
+	Location *time.Location // 文件中未带时区的时间按此时区解析（nil 表示 UTC）
+}
+
+// Record 解析出的一条交易记录
+// 解析失败的记录 Transaction 为 nil、Err 非空，由上层汇总为行错误
+type Record struct {
+	Line        int                 // 在源文件中的行号，用于错误报告
+	Transaction *entity.Transaction // 解析出的交易（UserID、Amount 由 Domain 层填充）
+	Err         error               // 解析错误
+}
+
+// ==================== 接口定义 ====================
+// 每种对账单格式实现一个 Importer，Service 层按格式名选择
+
+type Importer interface {
+	// Format 格式名
+	Format() string
+
+	// Parse 解析对账单
+	// 单条记录的格式问题放在 Record.Err 中；整个文件无法解析时返回 error
+	Parse(r io.Reader, opts *Options) ([]*Record, error)
+}
+
+// ==================== 注册表 ====================
+
+// Registry 按格式名查找 Importer
+type Registry map[string]Importer
+
+// NewRegistry 创建注册表
+func NewRegistry(importers ...Importer) Registry {
+	registry := make(Registry, len(importers))
+	for _, imp := range importers {
+		registry[imp.Format()] = imp
+	}
+	return registry
+}
+
+// Get 按格式名查找 Importer（不区分大小写，qfx 视为 ofx）
+func (r Registry) Get(format string) (Importer, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "qfx" {
+		format = FormatOFX
+	}
+	imp, ok := r[format]
+	if !ok {
+		return nil, ErrUnsupportedFormat
+	}
+	return imp, nil
+}
+
+// Formats 已注册的格式名（排序后）
+func (r Registry) Formats() []string {
+	formats := make([]string, 0, len(r))
+	for format := range r {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// TimeLocation 返回解析时区（未设置时为 UTC）
+func (o *Options) TimeLocation() *time.Location {
+	if o == nil || o.Location == nil {
+		return time.UTC
+	}
+	return o.Location
+}
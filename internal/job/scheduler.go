@@ -0,0 +1,102 @@
+package job
+
+import (
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+// ==================== 任务定义 ====================
+
+// Func 一次任务执行，返回错误时只记录日志，不影响下次执行
+type Func func() error
+
+// dailyJob 每天固定时间运行的任务
+type dailyJob struct {
+	name    string
+	hour    int
+	minute  int
+	run     Func
+	running atomic.Bool // 上一次执行尚未结束时跳过本次
+}
+
+// ==================== 调度器 ====================
+
+// Scheduler 进程内的定时任务调度器
+// 每个任务一个 goroutine，按本地时区计算下次运行时间；只适合单实例部署，
+// 多实例时同一任务会在每个实例上运行（任务本身须可重复执行）
+type Scheduler struct {
+	jobs []*dailyJob
+}
+
+// NewScheduler 创建调度器
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// Daily 注册每天 at（HH:MM，本地时区）运行的任务
+func (s *Scheduler) Daily(name, at string, run Func) error {
+	t, err := time.Parse("15:04", at)
+	if err != nil {
+		return fmt.Errorf("任务 %s 的运行时间无效（格式 HH:MM）: %w", name, err)
+	}
+	s.jobs = append(s.jobs, &dailyJob{
+		name:   name,
+		hour:   t.Hour(),
+		minute: t.Minute(),
+		run:    run,
+	})
+	return nil
+}
+
+// Start 启动全部任务；runNow 为 true 时每个任务先在后台立即运行一次
+func (s *Scheduler) Start(runNow bool) {
+	for _, j := range s.jobs {
+		go j.loop(runNow)
+	}
+}
+
+// ==================== 私有方法 ====================
+
+// loop 等到下次运行时间后执行，循环往复
+func (j *dailyJob) loop(runNow bool) {
+	if runNow {
+		j.execute()
+	}
+	for {
+		time.Sleep(time.Until(j.next(time.Now())))
+		j.execute()
+	}
+}
+
+// next 下次运行时间：今天的运行时间已过则为明天
+// 按日期重新计算（而不是固定间隔 24 小时），夏令时切换当天也在同一时刻运行
+func (j *dailyJob) next(now time.Time) time.Time {
+	t := time.Date(now.Year(), now.Month(), now.Day(), j.hour, j.minute, 0, 0, time.Local)
+	if !t.After(now) {
+		t = time.Date(now.Year(), now.Month(), now.Day()+1, j.hour, j.minute, 0, 0, time.Local)
+	}
+	return t
+}
+
+// execute 执行一次任务，记录耗时；panic 只影响本次执行
+func (j *dailyJob) execute() {
+	if !j.running.CompareAndSwap(false, true) {
+		log.Printf("⏭️  任务 %s 上一次执行尚未结束，跳过", j.name)
+		return
+	}
+	defer j.running.Store(false)
+
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("❌ 任务 %s panic: %v", j.name, r)
+		}
+	}()
+	if err := j.run(); err != nil {
+		log.Printf("❌ 任务 %s 失败（耗时 %v）: %v", j.name, time.Since(start), err)
+		return
+	}
+	log.Printf("✅ 任务 %s 完成（耗时 %v）", j.name, time.Since(start))
+}
@@ -0,0 +1,106 @@
+package job
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	fxDomain "github.com/florentyang/smartfin-go/internal/domain/fx"
+	portfolioDomain "github.com/florentyang/smartfin-go/internal/domain/portfolio"
+)
+
+// NewSnapshotJob 每日估值快照任务
+// 为每个有交易记录的用户补齐截止到昨天的快照（含交易变更后失效的天数），
+// 单个用户失败（如缺少汇率）只记录日志，继续处理其他用户
+func NewSnapshotJob(portfolioDomain portfolioDomain.Domain) Func {
+	return func() error {
+		// 1. 需要生成快照的用户
+		userIDs, err := portfolioDomain.SnapshotUserIDs()
+		if err != nil {
+			return err
+		}
+
+		// 2. 逐个用户补齐到昨天
+		through := fxDomain.DateOf(time.Now()).AddDate(0, 0, -1)
+		days, failed := 0, 0
+		for _, userID := range userIDs {
+			n, err := portfolioDomain.BuildSnapshots(userID, through)
+			days += n
+			if err != nil {
+				failed++
+				log.Printf("用户 %d 的估值快照生成失败: %v", userID, err)
+			}
+		}
+
+		log.Printf("估值快照：%d 个用户，新生成 %d 天", len(userIDs), days)
+		if failed > 0 {
+			return fmt.Errorf("%d 个用户的估值快照生成失败", failed)
+		}
+		return nil
+	}
+}
+
+// ==================== 异步补齐队列 ====================
+
+// snapshotQueueSize 队列容量；队列已满时丢弃，由每日任务补齐
+const snapshotQueueSize = 1024
+
+// SnapshotQueue 交易写入后异步补齐快照的队列
+// 同一用户排队期间重复入队只处理一次；单个 goroutine 串行处理，失败只记录日志（由每日任务兜底）
+type SnapshotQueue struct {
+	portfolioDomain portfolioDomain.Domain
+	mu              sync.Mutex
+	pending         map[uint]bool // 已入队、尚未开始处理的用户
+	queue           chan uint
+}
+
+// NewSnapshotQueue 创建队列，Start 之前入队的用户在 Start 后处理
+func NewSnapshotQueue(portfolioDomain portfolioDomain.Domain) *SnapshotQueue {
+	return &SnapshotQueue{
+		portfolioDomain: portfolioDomain,
+		pending:         make(map[uint]bool),
+		queue:           make(chan uint, snapshotQueueSize),
+	}
+}
+
+// Enqueue 用户的快照需要补齐（不阻塞）
+func (q *SnapshotQueue) Enqueue(userID uint) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.pending[userID] {
+		return
+	}
+	select {
+	case q.queue <- userID:
+		q.pending[userID] = true
+	default:
+		log.Printf("估值快照队列已满，用户 %d 由每日任务补齐", userID)
+	}
+}
+
+// Start 启动后台 goroutine 处理队列
+func (q *SnapshotQueue) Start() {
+	go func() {
+		for userID := range q.queue {
+			q.process(userID)
+		}
+	}()
+}
+
+// process 补齐一个用户到昨天；先移出 pending，处理期间的新写入会重新入队
+func (q *SnapshotQueue) process(userID uint) {
+	q.mu.Lock()
+	delete(q.pending, userID)
+	q.mu.Unlock()
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("用户 %d 的估值快照补齐 panic: %v", userID, r)
+		}
+	}()
+	through := fxDomain.DateOf(time.Now()).AddDate(0, 0, -1)
+	if _, err := q.portfolioDomain.BuildSnapshots(userID, through); err != nil {
+		log.Printf("用户 %d 的估值快照补齐失败: %v", userID, err)
+	}
+}
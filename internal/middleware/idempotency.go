@@ -0,0 +1,207 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/florentyang/smartfin-go/internal/config"
+	idempotencyDomain "github.com/florentyang/smartfin-go/internal/domain/idempotency"
+	"github.com/florentyang/smartfin-go/pkg/errcode"
+	"github.com/florentyang/smartfin-go/pkg/response"
+)
+
+// IdempotencyKeyHeader 幂等键请求头
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotencyReplayedHeader 回放响应时附加的响应头
+const IdempotencyReplayedHeader = "Idempotency-Replayed"
+
+// Idempotency 幂等中间件
+// 写操作（POST/PUT/PATCH/DELETE）带 Idempotency-Key 请求头时：
+// - 首次请求：正常处理，只保存成功的响应（HTTP 2xx 且业务码为 0）
+// - 失败响应（参数错误、余额不足、数据库死锁等）不保存，释放幂等键，客户端可用同一个键重试
+// - 相同请求重试：直接回放首次的响应，不再执行业务逻辑
+// - 同一个键换了请求内容：拒绝
+// 请求体不超过 cfg.MaxBodyBytes；multipart 上传按各字段和文件内容计算摘要（重试时 boundary 会变化）
+// 需要放在 JWTAuth 之后（幂等键按用户隔离）；不带请求头的请求不受影响
+func Idempotency(domain idempotencyDomain.Domain, cfg *config.IdempotencyConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1. 只处理带幂等键的写操作
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || !isMutatingMethod(c.Request.Method) {
+			c.Next()
+			return
+		}
+		userID, exists := c.Get("userID")
+		if !exists {
+			response.Unauthorized(c, "请先登录")
+			c.Abort()
+			return
+		}
+
+		// 2. 读取请求体（限制大小，读完后放回，后续 Handler 照常绑定）
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, cfg.MaxBodyBytes))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				response.Fail(c, http.StatusRequestEntityTooLarge, fmt.Sprintf("请求体超过 %d 字节", cfg.MaxBodyBytes))
+			} else {
+				response.BadRequest(c, "请求体读取失败: "+err.Error())
+			}
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		digest, err := requestDigest(c.GetHeader("Content-Type"), body)
+		if err != nil {
+			response.BadRequest(c, "请求体解析失败: "+err.Error())
+			c.Abort()
+			return
+		}
+
+		// 3. 占用幂等键
+		output, err := domain.Begin(&idempotencyDomain.BeginInput{
+			UserID: userID.(uint),
+			Key:    key,
+			Method: c.Request.Method,
+			Path:   c.Request.URL.RequestURI(),
+			Body:   digest,
+		})
+		if err != nil {
+			failIdempotency(c, err)
+			c.Abort()
+			return
+		}
+
+		// 4. 已处理过的相同请求：回放首次响应
+		if output.Replay {
+			record := output.Record
+			c.Header(IdempotencyReplayedHeader, "true")
+			c.Data(record.StatusCode, record.ContentType, record.Response)
+			c.Abort()
+			return
+		}
+
+		// 5. 首次请求：记录响应后保存
+		writer := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		status := writer.Status()
+		contentType := writer.Header().Get("Content-Type")
+		if !isSuccessResponse(status, contentType, writer.body.Bytes()) {
+			// 业务失败不产生副作用（写操作在事务中回滚），释放幂等键让客户端重试
+			if err := domain.Abandon(output.Record.ID); err != nil {
+				log.Printf("释放幂等键失败: %v", err)
+			}
+			return
+		}
+		err = domain.Complete(&idempotencyDomain.CompleteInput{
+			ID:          output.Record.ID,
+			StatusCode:  status,
+			ContentType: contentType,
+			Body:        writer.body.Bytes(),
+		})
+		if err != nil {
+			// 响应已发送，只能记录日志；幂等键会在处理中超时后失效
+			log.Printf("保存幂等响应失败: %v", err)
+		}
+	}
+}
+
+// ==================== 私有辅助函数 ====================
+
+// responseRecorder 在写出响应的同时保留一份响应体
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// requestDigest 参与幂等比较的请求内容
+// multipart 请求的原始请求体包含随机 boundary，同一个文件重试时也不相同，
+// 改为按顺序拼接各部分的字段名、文件名和内容摘要；其他请求使用原始请求体
+func requestDigest(contentType string, body []byte) ([]byte, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") || params["boundary"] == "" {
+		return body, nil
+	}
+
+	var digest bytes.Buffer
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return digest.Bytes(), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		h := sha256.New()
+		if _, err := io.Copy(h, part); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&digest, "%s\x00%s\x00%x\n", part.FormName(), part.FileName(), h.Sum(nil))
+	}
+}
+
+// isSuccessResponse 是否为成功响应
+// 统一响应的失败也以 HTTP 200 返回（response.Fail），需要再看业务码；非 JSON 响应只看状态码
+func isSuccessResponse(status int, contentType string, body []byte) bool {
+	if status < http.StatusOK || status >= http.StatusMultipleChoices {
+		return false
+	}
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType != "application/json" {
+		return true
+	}
+	var resp struct {
+		Code *int `json:"code"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil || resp.Code == nil {
+		return false
+	}
+	return *resp.Code == errcode.Success
+}
+
+// isMutatingMethod 是否为写操作
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// failIdempotency 根据幂等错误类型返回不同响应
+func failIdempotency(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, idempotencyDomain.ErrInvalidKey):
+		response.BadRequest(c, err.Error())
+	case errors.Is(err, idempotencyDomain.ErrKeyReused):
+		response.Fail(c, errcode.IdempotencyKeyReused, err.Error())
+	case errors.Is(err, idempotencyDomain.ErrInProgress):
+		response.Fail(c, errcode.IdempotencyInProgress, err.Error())
+	default:
+		response.ServerError(c, err.Error())
+	}
+}
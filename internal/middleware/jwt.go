@@ -2,6 +2,8 @@ package middleware
 
 import (
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -31,19 +33,89 @@ func JWTAuth() gin.HandlerFunc {
 		}
 		tokenString := parts[1]
 
-		// 3. 验证 Token（调用 jwt.go 的工具函数）
-		claims, err := jwt.ParseToken(tokenString)
-		if err != nil {
-			response.Unauthorized(c, "Token 无效或已过期")
+		// 3. 验证 Token 并存入用户信息
+		authenticate(c, tokenString)
+	}
+}
+
+// StreamJWTAuth 推送接口的鉴权中间件
+// 浏览器的 EventSource 不能设置请求头，除 Authorization 请求头外还接受 Query 参数 ticket：
+// 票据由 POST /market/stream/ticket 签发，有效期短、只能用于推送接口且只能使用一次。
+// 普通 Token 不接受放在 URL 中（会进入访问日志、浏览器历史和代理日志）
+func StreamJWTAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1. 有请求头时按普通 Token 验证
+		if authHeader := c.GetHeader("Authorization"); authHeader != "" {
+			parts := strings.SplitN(authHeader, " ", 2)
+			if len(parts) != 2 || parts[0] != "Bearer" {
+				response.Unauthorized(c, "Token 格式错误")
+				c.Abort()
+				return
+			}
+			authenticate(c, parts[1])
+			return
+		}
+
+		// 2. 否则取 Query 参数中的票据
+		ticket := c.Query("ticket")
+		if ticket == "" {
+			response.Unauthorized(c, "请先登录")
+			c.Abort()
+			return
+		}
+		claims, err := jwt.ParseStreamTicket(ticket)
+		if err != nil || !usedTickets.claim(claims.ID, claims.ExpiresAt.Time) {
+			response.Unauthorized(c, "票据无效、已过期或已使用")
 			c.Abort()
 			return
 		}
 
-		// 4. 将用户信息存入 Context，供后续 Controller 使用
 		c.Set("userID", claims.UserID)
 		c.Set("username", claims.Username)
-
-		// 5. 继续执行后续 Handler
 		c.Next()
 	}
 }
+
+// ticketStore 已使用的推送票据（按唯一 ID 记录，过期后清理）
+type ticketStore struct {
+	mu   sync.Mutex
+	used map[string]time.Time
+}
+
+var usedTickets = &ticketStore{used: make(map[string]time.Time)}
+
+// claim 标记票据已使用，票据此前已使用过时返回 false
+func (s *ticketStore) claim(id string, expiresAt time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for usedID, exp := range s.used {
+		if now.After(exp) {
+			delete(s.used, usedID)
+		}
+	}
+	if _, ok := s.used[id]; ok {
+		return false
+	}
+	s.used[id] = expiresAt
+	return true
+}
+
+// authenticate 验证 Token，通过后将用户信息存入 Context 并继续执行后续 Handler
+func authenticate(c *gin.Context, tokenString string) {
+	// 1. 验证 Token（调用 jwt.go 的工具函数）
+	claims, err := jwt.ParseToken(tokenString)
+	if err != nil {
+		response.Unauthorized(c, "Token 无效或已过期")
+		c.Abort()
+		return
+	}
+
+	// 2. 将用户信息存入 Context，供后续 Controller 使用
+	c.Set("userID", claims.UserID)
+	c.Set("username", claims.Username)
+
+	// 3. 继续执行后续 Handler
+	c.Next()
+}
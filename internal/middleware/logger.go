@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// redactedParams 访问日志中需要脱敏的 Query 参数
+var redactedParams = []string{"ticket", "access_token", "token"}
+
+// Logger 访问日志中间件
+// 格式与 gin 默认日志一致，但 URL 中的凭证参数替换为 REDACTED
+func Logger() gin.HandlerFunc {
+	return gin.LoggerWithConfig(gin.LoggerConfig{
+		Formatter: func(p gin.LogFormatterParams) string {
+			if p.Latency > time.Minute {
+				p.Latency = p.Latency.Truncate(time.Second)
+			}
+			return fmt.Sprintf("[GIN] %v |%s %3d %s| %13v | %15s |%s %-7s %s %#v\n%s",
+				p.TimeStamp.Format("2006/01/02 - 15:04:05"),
+				p.StatusCodeColor(), p.StatusCode, p.ResetColor(),
+				p.Latency,
+				p.ClientIP,
+				p.MethodColor(), p.Method, p.ResetColor(),
+				redactPath(p.Path),
+				p.ErrorMessage,
+			)
+		},
+	})
+}
+
+// redactPath 把路径中凭证类 Query 参数的值替换为 REDACTED
+func redactPath(path string) string {
+	i := strings.IndexByte(path, '?')
+	if i < 0 {
+		return path
+	}
+	query, err := url.ParseQuery(path[i+1:])
+	if err != nil {
+		// 无法解析时整段丢弃，宁可少记也不泄露
+		return path[:i] + "?REDACTED"
+	}
+	for _, name := range redactedParams {
+		if _, ok := query[name]; ok {
+			query.Set(name, "REDACTED")
+		}
+	}
+	return path[:i] + "?" + query.Encode()
+}
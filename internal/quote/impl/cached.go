@@ -0,0 +1,183 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/florentyang/smartfin-go/internal/cache"
+	"github.com/florentyang/smartfin-go/internal/config"
+	marketDomain "github.com/florentyang/smartfin-go/internal/domain/market"
+	"github.com/florentyang/smartfin-go/internal/quote"
+)
+
+// ==================== 带缓存的行情源 ====================
+// 包在任意行情源外面，对行情 Domain 透明：
+// - 缓存有效期（CacheTTL）内直接返回缓存
+// - 同一只股票的并发未命中只请求一次行情源（singleflight），其余请求共享结果
+// - 缓存过期但仍在保留期（StaleTTL）内，且股票所在市场休市时，先返回过期行情再在后台刷新
+//   （stale-while-revalidate）；开市期间过期的行情必须同步刷新
+// - 缓存读写失败时直接请求行情源，缓存故障不影响行情查询
+
+// CachedProvider 带缓存的行情源（并发安全）
+type CachedProvider struct {
+	provider     quote.Provider
+	cache        cache.Cache
+	marketDomain marketDomain.Domain // 判断股票所在市场是否开市
+	ttl          time.Duration       // 缓存有效期
+	staleTTL     time.Duration       // 过期后的保留期
+	timeout      time.Duration       // 共享请求和后台刷新的超时（不受单个调用方取消的影响）
+	group        singleflight.Group
+	now          func() time.Time
+}
+
+// cachedQuote 缓存中保存的行情
+type cachedQuote struct {
+	Quote      *quote.Quote `json:"quote"`
+	FreshUntil time.Time    `json:"fresh_until"` // 有效期截止时间，之后为过期行情
+}
+
+// NewCachedProvider 在行情源外包一层缓存
+func NewCachedProvider(provider quote.Provider, c cache.Cache, market marketDomain.Domain, cfg *config.QuoteConfig) *CachedProvider {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = config.DefaultQuoteConfig().Timeout
+	}
+	return &CachedProvider{
+		provider:     provider,
+		cache:        c,
+		marketDomain: market,
+		ttl:          cfg.CacheTTL,
+		staleTTL:     cfg.StaleTTL,
+		timeout:      timeout,
+		now:          time.Now,
+	}
+}
+
+// Name 行情源名称（与被包装的行情源相同）
+func (p *CachedProvider) Name() string {
+	return p.provider.Name()
+}
+
+// Fetch 查询一只股票的最新行情，优先读缓存
+func (p *CachedProvider) Fetch(ctx context.Context, symbol string) (*quote.Quote, error) {
+	// 1. 有效期内的缓存直接返回
+	now := p.now()
+	entry := p.load(ctx, symbol)
+	if entry != nil && now.Before(entry.FreshUntil) {
+		return entry.Quote, nil
+	}
+
+	// 2. 已过期：休市期间行情不会变化，先返回过期行情，后台刷新（结果通道带缓冲，无需读取）
+	if entry != nil && p.staleTTL > 0 && !p.isTrading(symbol, now) {
+		p.group.DoChan(symbol, func() (interface{}, error) {
+			return p.refresh(symbol)
+		})
+		return entry.Quote, nil
+	}
+
+	// 3. 未命中或开市期间已过期：同一只股票的并发请求合并为一次
+	//    每个调用方可以单独超时或取消，共享的请求继续完成并写入缓存
+	ch := p.group.DoChan(symbol, func() (interface{}, error) {
+		return p.refresh(symbol)
+	})
+	select {
+	case result := <-ch:
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		copied := *result.Val.(*quote.Quote)
+		return &copied, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// ==================== 私有辅助函数 ====================
+
+// cacheKey 行情的缓存键（按行情源区分，切换行情源后不会读到旧数据）
+func (p *CachedProvider) cacheKey(symbol string) string {
+	return "quote:" + p.provider.Name() + ":" + symbol
+}
+
+// load 读取缓存，未命中或读取失败时返回 nil
+func (p *CachedProvider) load(ctx context.Context, symbol string) *cachedQuote {
+	data, err := p.cache.Get(ctx, p.cacheKey(symbol))
+	if err != nil {
+		if !errors.Is(err, cache.ErrCacheMiss) {
+			log.Printf("读取行情缓存失败: %v", err)
+		}
+		return nil
+	}
+	var entry cachedQuote
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Quote == nil {
+		return nil
+	}
+	return &entry
+}
+
+// refresh 请求行情源并写入缓存
+// 使用独立的超时而不是调用方的 ctx：共享请求的结果还要给其他调用方和缓存使用；
+// 行情源 panic 时转换为错误（singleflight 会在其他 goroutine 中重新抛出 panic）
+func (p *CachedProvider) refresh(symbol string) (q *quote.Quote, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			q, err = nil, fmt.Errorf("行情源内部错误: %v", r)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+
+	q, err = p.provider.Fetch(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	if q == nil {
+		return nil, quote.ErrQuoteNotFound
+	}
+	q.Symbol = symbol
+	if q.Source == "" {
+		q.Source = p.provider.Name()
+	}
+	p.store(symbol, q)
+	return q, nil
+}
+
+// store 写入缓存，保留时长 = 有效期 + 过期后的保留期
+func (p *CachedProvider) store(symbol string, q *quote.Quote) {
+	data, err := json.Marshal(&cachedQuote{Quote: q, FreshUntil: p.now().Add(p.ttl)})
+	if err != nil {
+		log.Printf("写入行情缓存失败: %v", err)
+		return
+	}
+	ttl := p.ttl
+	if p.staleTTL > 0 {
+		ttl += p.staleTTL
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+	if err := p.cache.Set(ctx, p.cacheKey(symbol), data, ttl); err != nil {
+		log.Printf("写入行情缓存失败: %v", err)
+	}
+}
+
+// isTrading 股票所在市场当前是否开市；无法识别市场时按开市处理（不返回过期行情）
+// 规范化后不带交易所后缀的代码为美股，按美元识别
+func (p *CachedProvider) isTrading(symbol string, at time.Time) bool {
+	parsed, ok := marketDomain.ParseSymbol(symbol, "USD")
+	if !ok {
+		return true
+	}
+	open, err := p.marketDomain.IsOpen(parsed.Exchange, at)
+	if err != nil {
+		return true
+	}
+	return open
+}
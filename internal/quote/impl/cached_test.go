@@ -0,0 +1,365 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/florentyang/smartfin-go/internal/cache"
+	"github.com/florentyang/smartfin-go/internal/config"
+	marketDomain "github.com/florentyang/smartfin-go/internal/domain/market"
+	"github.com/florentyang/smartfin-go/internal/quote"
+)
+
+// ==================== 测试替身 ====================
+
+// countingProvider 记录调用次数的行情源：第 n 次调用返回价格 n
+// release 不为 nil 时每次调用先等待 release 关闭；err、panicValue 不为空时返回错误或 panic
+type countingProvider struct {
+	calls      atomic.Int64
+	started    chan struct{} // 每次调用开始时发送一个信号（带缓冲）
+	release    chan struct{}
+	err        error
+	panicValue any
+}
+
+func newCountingProvider() *countingProvider {
+	return &countingProvider{started: make(chan struct{}, 100)}
+}
+
+func (p *countingProvider) Name() string { return "counting" }
+
+func (p *countingProvider) Fetch(ctx context.Context, symbol string) (*quote.Quote, error) {
+	n := p.calls.Add(1)
+	p.started <- struct{}{}
+	if p.release != nil {
+		<-p.release
+	}
+	if p.panicValue != nil {
+		panic(p.panicValue)
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &quote.Quote{Price: decimal.NewFromInt(n)}, nil
+}
+
+// mapCache 不过期的内存缓存，记录最近一次写入的有效期
+type mapCache struct {
+	mu      sync.Mutex
+	data    map[string][]byte
+	lastTTL time.Duration
+}
+
+func newMapCache() *mapCache {
+	return &mapCache{data: make(map[string][]byte)}
+}
+
+func (c *mapCache) Get(_ context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	data, ok := c.data[key]
+	if !ok {
+		return nil, cache.ErrCacheMiss
+	}
+	return data, nil
+}
+
+func (c *mapCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.data[key] = value
+	c.lastTTL = ttl
+	return nil
+}
+
+func (c *mapCache) Delete(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.data, key)
+	return nil
+}
+
+func (c *mapCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.data)
+}
+
+// brokenCache 读写都失败的缓存
+type brokenCache struct{}
+
+func (brokenCache) Get(context.Context, string) ([]byte, error) {
+	return nil, errors.New("连接失败")
+}
+
+func (brokenCache) Set(context.Context, string, []byte, time.Duration) error {
+	return errors.New("连接失败")
+}
+
+func (brokenCache) Delete(context.Context, string) error {
+	return errors.New("连接失败")
+}
+
+// fakeMarket 只实现 IsOpen，所有交易所同样开市或休市
+type fakeMarket struct {
+	marketDomain.Domain
+	open bool
+}
+
+func (m *fakeMarket) IsOpen(string, time.Time) (bool, error) {
+	return m.open, nil
+}
+
+// fakeClock 可手动前进的时钟
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// ==================== 测试辅助 ====================
+
+const (
+	testTTL      = time.Minute
+	testStaleTTL = time.Hour
+)
+
+// newTestProvider 创建带缓存的行情源，时钟可手动前进
+func newTestProvider(provider quote.Provider, c cache.Cache, marketOpen bool, staleTTL time.Duration) (*CachedProvider, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2024, time.January, 2, 15, 0, 0, 0, time.UTC)}
+	p := NewCachedProvider(provider, c, &fakeMarket{open: marketOpen}, &config.QuoteConfig{
+		Timeout:  time.Second,
+		CacheTTL: testTTL,
+		StaleTTL: staleTTL,
+	})
+	p.now = clock.Now
+	return p, clock
+}
+
+// assertPrice 查询一次行情并比较价格
+func assertPrice(t *testing.T, p *CachedProvider, want int64) {
+	t.Helper()
+	q, err := p.Fetch(context.Background(), "AAPL")
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if !q.Price.Equal(decimal.NewFromInt(want)) {
+		t.Errorf("price = %s, want %d", q.Price, want)
+	}
+}
+
+// assertCalls 比较行情源的调用次数
+func assertCalls(t *testing.T, provider *countingProvider, want int64) {
+	t.Helper()
+	if got := provider.calls.Load(); got != want {
+		t.Errorf("provider calls = %d, want %d", got, want)
+	}
+}
+
+// waitFor 等待条件成立（后台刷新），超时失败
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("等待超时：%s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// ==================== 测试用例 ====================
+
+func TestCachedProviderConcurrentMissesShareOneCall(t *testing.T) {
+	provider := newCountingProvider()
+	provider.release = make(chan struct{})
+	p, _ := newTestProvider(provider, newMapCache(), true, testStaleTTL)
+
+	const callers = 20
+	results := make([]*quote.Quote, callers)
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = p.Fetch(context.Background(), "AAPL")
+		}(i)
+	}
+
+	// 第一个请求到达行情源后稍等，让其余调用方进入 singleflight 等待，再放行
+	<-provider.started
+	time.Sleep(20 * time.Millisecond)
+	close(provider.release)
+	wg.Wait()
+
+	assertCalls(t, provider, 1)
+	for i := range results {
+		if errs[i] != nil {
+			t.Fatalf("Fetch() error = %v", errs[i])
+		}
+		if !results[i].Price.Equal(decimal.NewFromInt(1)) || results[i].Symbol != "AAPL" || results[i].Source != "counting" {
+			t.Errorf("results[%d] = %+v", i, results[i])
+		}
+	}
+	// 每个调用方拿到各自的副本
+	results[0].Price = decimal.NewFromInt(99)
+	if results[1].Price.Equal(results[0].Price) {
+		t.Errorf("调用方之间共享了同一个行情对象")
+	}
+}
+
+func TestCachedProviderFreshness(t *testing.T) {
+	tests := []struct {
+		name       string
+		marketOpen bool
+		staleTTL   time.Duration
+		advance    time.Duration
+		wantPrice  int64 // 过期后第二次查询立即返回的价格
+		wantCalls  int64 // 第二次查询返回时行情源的调用次数
+	}{
+		{name: "有效期内直接返回缓存", marketOpen: true, staleTTL: testStaleTTL, advance: testTTL - time.Second, wantPrice: 1, wantCalls: 1},
+		{name: "开市期间过期同步刷新", marketOpen: true, staleTTL: testStaleTTL, advance: testTTL, wantPrice: 2, wantCalls: 2},
+		{name: "休市但没有保留期时同步刷新", marketOpen: false, staleTTL: 0, advance: testTTL, wantPrice: 2, wantCalls: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newCountingProvider()
+			p, clock := newTestProvider(provider, newMapCache(), tt.marketOpen, tt.staleTTL)
+
+			assertPrice(t, p, 1)
+			clock.Advance(tt.advance)
+			assertPrice(t, p, tt.wantPrice)
+			assertCalls(t, provider, tt.wantCalls)
+		})
+	}
+}
+
+func TestCachedProviderStaleWhileRevalidate(t *testing.T) {
+	provider := newCountingProvider()
+	p, clock := newTestProvider(provider, newMapCache(), false, testStaleTTL)
+
+	assertPrice(t, p, 1)
+	<-provider.started
+
+	// 休市期间过期：先返回过期行情，后台刷新时阻塞行情源，确认返回不依赖刷新完成
+	provider.release = make(chan struct{})
+	clock.Advance(testTTL)
+	assertPrice(t, p, 1)
+	<-provider.started
+	assertCalls(t, provider, 2)
+
+	// 刷新进行中再次查询：仍返回过期行情，不重复请求
+	assertPrice(t, p, 1)
+	close(provider.release)
+
+	// 刷新完成后返回新行情
+	waitFor(t, "后台刷新写入缓存", func() bool {
+		q, err := p.Fetch(context.Background(), "AAPL")
+		return err == nil && q.Price.Equal(decimal.NewFromInt(2))
+	})
+	assertCalls(t, provider, 2)
+}
+
+func TestCachedProviderCallerCancel(t *testing.T) {
+	provider := newCountingProvider()
+	provider.release = make(chan struct{})
+	c := newMapCache()
+	p, _ := newTestProvider(provider, c, true, testStaleTTL)
+
+	// 调用方取消后立即返回，共享请求继续完成并写入缓存
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := p.Fetch(ctx, "AAPL")
+		done <- err
+	}()
+	<-provider.started
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Fetch() error = %v, want %v", err, context.Canceled)
+	}
+
+	close(provider.release)
+	waitFor(t, "共享请求写入缓存", func() bool { return c.len() == 1 })
+	assertPrice(t, p, 1)
+	assertCalls(t, provider, 1)
+}
+
+func TestCachedProviderErrors(t *testing.T) {
+	t.Run("行情源错误不写入缓存", func(t *testing.T) {
+		provider := newCountingProvider()
+		provider.err = quote.ErrQuoteNotFound
+		c := newMapCache()
+		p, _ := newTestProvider(provider, c, true, testStaleTTL)
+
+		for i := 0; i < 2; i++ {
+			if _, err := p.Fetch(context.Background(), "AAPL"); !errors.Is(err, quote.ErrQuoteNotFound) {
+				t.Fatalf("Fetch() error = %v, want %v", err, quote.ErrQuoteNotFound)
+			}
+		}
+		assertCalls(t, provider, 2)
+		if c.len() != 0 {
+			t.Errorf("cache len = %d, want 0", c.len())
+		}
+	})
+
+	t.Run("行情源 panic 转换为错误", func(t *testing.T) {
+		provider := newCountingProvider()
+		provider.panicValue = "boom"
+		p, _ := newTestProvider(provider, newMapCache(), true, testStaleTTL)
+
+		if _, err := p.Fetch(context.Background(), "AAPL"); err == nil {
+			t.Fatalf("Fetch() error = nil, want error")
+		}
+	})
+
+	t.Run("缓存故障时直接请求行情源", func(t *testing.T) {
+		provider := newCountingProvider()
+		p, _ := newTestProvider(provider, brokenCache{}, true, testStaleTTL)
+
+		assertPrice(t, p, 1)
+		assertPrice(t, p, 2)
+		assertCalls(t, provider, 2)
+	})
+}
+
+func TestCachedProviderStoreTTL(t *testing.T) {
+	tests := []struct {
+		name     string
+		staleTTL time.Duration
+		want     time.Duration
+	}{
+		{name: "保留时长 = 有效期 + 保留期", staleTTL: testStaleTTL, want: testTTL + testStaleTTL},
+		{name: "没有保留期时只保留有效期", staleTTL: 0, want: testTTL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newMapCache()
+			p, _ := newTestProvider(newCountingProvider(), c, true, tt.staleTTL)
+			assertPrice(t, p, 1)
+			if c.lastTTL != tt.want {
+				t.Errorf("cache ttl = %s, want %s", c.lastTTL, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package impl
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	instrumentDomain "github.com/florentyang/smartfin-go/internal/domain/instrument"
+	"github.com/florentyang/smartfin-go/internal/quote"
+)
+
+// ==================== 文件行情源 ====================
+// CSV 文件，第一行为表头（不区分大小写）：symbol,price,prev_close,currency,time
+// - 必填列：symbol, price；prev_close、currency、time 可选
+// - symbol 按与交易相同的规则规范化（如 700.HK → 0700.HK），带 currency 时可推断不带后缀的代码
+// - time 为 RFC3339，空或缺省时取文件的修改时间
+// # 开头的行为注释。每次查询前检查文件修改时间，文件更新后自动重新加载，无需重启
+
+// FileProvider 文件行情源（并发安全）
+type FileProvider struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time               // 已加载文件的修改时间
+	quotes  map[string]*quote.Quote // 股票代码 → 行情
+}
+
+// NewFileProvider 创建文件行情源
+// 启动时加载一次，文件不存在或格式错误时返回错误
+func NewFileProvider(path string) (*FileProvider, error) {
+	p := &FileProvider{path: path}
+	if err := p.reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Name 行情源名称
+func (p *FileProvider) Name() string {
+	return quote.ProviderFile
+}
+
+// Fetch 查询一只股票的最新行情
+func (p *FileProvider) Fetch(ctx context.Context, symbol string) (*quote.Quote, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// 1. 文件有更新时重新加载（加载失败时返回错误，不使用旧数据）
+	if err := p.reload(); err != nil {
+		return nil, err
+	}
+
+	// 2. 查找行情
+	q, ok := p.quotes[symbol]
+	if !ok {
+		return nil, quote.ErrQuoteNotFound
+	}
+	copied := *q
+	return &copied, nil
+}
+
+// reload 文件修改时间变化时重新解析（调用方持有锁，或在构造时调用）
+func (p *FileProvider) reload() error {
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return fmt.Errorf("读取行情文件失败: %w", err)
+	}
+	if p.quotes != nil && info.ModTime().Equal(p.modTime) {
+		return nil
+	}
+
+	f, err := os.Open(p.path)
+	if err != nil {
+		return fmt.Errorf("读取行情文件失败: %w", err)
+	}
+	defer f.Close()
+
+	quotes, err := parseQuoteFile(f, info.ModTime())
+	if err != nil {
+		return fmt.Errorf("行情文件 %s 格式错误: %w", p.path, err)
+	}
+	p.quotes = quotes
+	p.modTime = info.ModTime()
+	return nil
+}
+
+// parseQuoteFile 解析行情 CSV，defaultTime 为未填写 time 时的行情时间
+func parseQuoteFile(r io.Reader, defaultTime time.Time) (map[string]*quote.Quote, error) {
+	// 1. 读取表头
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("文件为空")
+		}
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		// 去掉 Excel 导出的 UTF-8 BOM
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, name := range []string{"symbol", "price"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("缺少必填列: %s", name)
+		}
+	}
+
+	// 2. 逐行解析（任意一行有误整个文件不生效，避免部分行情悄悄缺失）
+	quotes := make(map[string]*quote.Quote)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		currency := strings.ToUpper(field("currency"))
+		symbol, err := instrumentDomain.NormalizeSymbol(field("symbol"), currency)
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 行：%w", line, err)
+		}
+		price, err := decimal.NewFromString(field("price"))
+		if err != nil || !price.IsPositive() {
+			return nil, fmt.Errorf("第 %d 行：价格必须大于 0", line)
+		}
+		q := &quote.Quote{
+			Symbol:   symbol,
+			Price:    price,
+			Currency: currency,
+			Time:     defaultTime,
+			Source:   quote.ProviderFile,
+		}
+		if s := field("prev_close"); s != "" {
+			q.PrevClose, err = decimal.NewFromString(s)
+			if err != nil || q.PrevClose.IsNegative() {
+				return nil, fmt.Errorf("第 %d 行：昨收价格式错误 %q", line, s)
+			}
+		}
+		if s := field("time"); s != "" {
+			q.Time, err = time.Parse(time.RFC3339, s)
+			if err != nil {
+				return nil, fmt.Errorf("第 %d 行：时间格式错误 %q，应为 RFC3339", line, s)
+			}
+		}
+		quotes[symbol] = q
+	}
+
+	return quotes, nil
+}
@@ -0,0 +1,58 @@
+package impl
+
+import (
+	"context"
+	"sync"
+
+	"github.com/florentyang/smartfin-go/internal/quote"
+)
+
+// ==================== 进程内行情源 ====================
+// 行情保存在内存中，由 Set 设置，不访问网络
+// 用于离线开发和测试：不依赖外部行情服务即可走通行情查询的完整链路
+
+// StaticProvider 进程内行情源（并发安全）
+type StaticProvider struct {
+	mu     sync.RWMutex
+	quotes map[string]*quote.Quote // 股票代码 → 行情
+}
+
+// NewStaticProvider 创建进程内行情源，可传入初始行情
+func NewStaticProvider(quotes ...*quote.Quote) *StaticProvider {
+	p := &StaticProvider{quotes: make(map[string]*quote.Quote, len(quotes))}
+	for _, q := range quotes {
+		p.Set(q)
+	}
+	return p
+}
+
+// Name 行情源名称
+func (p *StaticProvider) Name() string {
+	return quote.ProviderStatic
+}
+
+// Set 设置一只股票的行情（覆盖已有行情）
+func (p *StaticProvider) Set(q *quote.Quote) {
+	copied := *q
+	copied.Source = quote.ProviderStatic
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.quotes[q.Symbol] = &copied
+}
+
+// Fetch 查询一只股票的最新行情（返回副本，调用方修改不影响内存中的行情）
+func (p *StaticProvider) Fetch(ctx context.Context, symbol string) (*quote.Quote, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	q, ok := p.quotes[symbol]
+	if !ok {
+		return nil, quote.ErrQuoteNotFound
+	}
+	copied := *q
+	return &copied, nil
+}
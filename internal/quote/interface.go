@@ -0,0 +1,46 @@
+package quote
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ==================== 错误定义 ====================
+
+var (
+	// ErrQuoteNotFound 行情源没有该股票的行情
+	ErrQuoteNotFound = errors.New("没有该股票的行情")
+)
+
+// 行情源名称
+const (
+	ProviderStatic = "static" // 进程内行情（手工设置，用于离线开发和测试）
+	ProviderFile   = "file"   // 文件行情（CSV，修改文件后自动重新加载）
+)
+
+// ==================== 结构体定义 ====================
+
+// Quote 一只股票的最新行情
+type Quote struct {
+	Symbol    string          // 规范化的股票代码，如 AAPL、0700.HK
+	Price     decimal.Decimal // 最新价
+	PrevClose decimal.Decimal // 昨收价（行情源不提供时为 0）
+	Currency  string          // 计价币种（行情源不提供时为空）
+	Time      time.Time       // 行情时间
+	Source    string          // 行情源名称
+}
+
+// ==================== 接口定义 ====================
+// 每个行情源实现一个 Provider，bootstrap 按配置选择
+
+type Provider interface {
+	// Name 行情源名称
+	Name() string
+
+	// Fetch 查询一只股票的最新行情
+	// 没有该股票的行情时返回 ErrQuoteNotFound；实现应在 ctx 取消或超时后尽快返回
+	Fetch(ctx context.Context, symbol string) (*Quote, error)
+}
@@ -10,12 +10,28 @@ import (
 )
 
 // SetupRouter 初始化并配置所有路由
-// 参数：从 bootstrap 传入各个 Controller
+// 参数：从 bootstrap 传入各个 Controller 和需要依赖注入的中间件
+// 需要登录的写接口（POST/PUT/DELETE）挂载幂等中间件：可带 Idempotency-Key 请求头安全重试
 func SetupRouter(
+	idempotency gin.HandlerFunc,
 	userController controller.UserController,
 	txController controller.TransactionController,
+	portfolioController controller.PortfolioController,
+	lotController controller.LotController,
+	reportController controller.ReportController,
+	fxController controller.FXController,
+	accountController controller.AccountController,
+	journalController controller.JournalController,
+	feeController controller.FeeController,
+	marketController controller.MarketController,
+	instrumentController controller.InstrumentController,
+	priceController controller.PriceController,
+	streamController controller.StreamController,
+	performanceController controller.PerformanceController,
 ) *gin.Engine {
-	r := gin.Default()
+	// 不用 gin.Default：默认访问日志会原样记录 URL 中的票据
+	r := gin.New()
+	r.Use(middleware.Logger(), gin.Recovery())
 
 	// 健康检查接口
 	r.GET("/health", func(c *gin.Context) {
@@ -36,17 +52,133 @@ func SetupRouter(
 	userAuthGroup := r.Group("/api/v1/user")
 	userAuthGroup.Use(middleware.JWTAuth())
 	{
-		userAuthGroup.GET("/profile", userController.GetProfile)       // 获取个人信息
-		userAuthGroup.PUT("/profile", userController.UpdateProfile)    // 更新个人信息
-		userAuthGroup.POST("/password", userController.UpdatePassword) // 更新密码
+		userAuthGroup.GET("/profile", userController.GetProfile)                    // 获取个人信息
+		userAuthGroup.PUT("/profile", idempotency, userController.UpdateProfile)    // 更新个人信息
+		userAuthGroup.POST("/password", idempotency, userController.UpdatePassword) // 更新密码
+	}
+
+	// ==================== 券商账户模块 - 私有接口 ====================
+	accountGroup := r.Group("/api/v1/accounts")
+	accountGroup.Use(middleware.JWTAuth())
+	{
+		accountGroup.POST("/create", idempotency, accountController.Create) // 创建账户：POST /api/v1/accounts/create
+		accountGroup.GET("/list", accountController.List)                   // 查询账户列表：GET /api/v1/accounts/list
+		accountGroup.GET("/:id", accountController.Get)                     // 查询单个账户：GET /api/v1/accounts/:id
+		accountGroup.PUT("/:id", idempotency, accountController.Update)     // 更新账户：PUT /api/v1/accounts/:id
+		accountGroup.DELETE("/:id", idempotency, accountController.Delete)  // 删除账户：DELETE /api/v1/accounts/:id
+		accountGroup.GET("/:id/cash", accountController.Cash)               // 现金流水及余额：GET /api/v1/accounts/:id/cash
+	}
+
+	// ==================== 手续费费率表模块 - 私有接口 ====================
+	feeGroup := r.Group("/api/v1/fee-schedules")
+	feeGroup.Use(middleware.JWTAuth())
+	{
+		feeGroup.POST("/create", idempotency, feeController.Create) // 创建费率表：POST /api/v1/fee-schedules/create
+		feeGroup.GET("/list", feeController.List)                   // 查询费率表列表：GET /api/v1/fee-schedules/list
+		feeGroup.GET("/:id", feeController.Get)                     // 查询单个费率表：GET /api/v1/fee-schedules/:id
+		feeGroup.PUT("/:id", idempotency, feeController.Update)     // 更新费率表：PUT /api/v1/fee-schedules/:id
+		feeGroup.DELETE("/:id", idempotency, feeController.Delete)  // 删除费率表：DELETE /api/v1/fee-schedules/:id
+	}
+
+	// ==================== 证券主数据模块 - 私有接口 ====================
+	instrumentGroup := r.Group("/api/v1/instruments")
+	instrumentGroup.Use(middleware.JWTAuth())
+	{
+		instrumentGroup.POST("/create", idempotency, instrumentController.Create) // 创建证券：POST /api/v1/instruments/create
+		instrumentGroup.GET("/search", instrumentController.Search)               // 搜索证券：GET /api/v1/instruments/search
+		instrumentGroup.GET("/:id", instrumentController.Get)                     // 查询单个证券：GET /api/v1/instruments/:id
+		instrumentGroup.PUT("/:id", idempotency, instrumentController.Update)     // 更新证券：PUT /api/v1/instruments/:id
+		instrumentGroup.DELETE("/:id", idempotency, instrumentController.Delete)  // 删除证券：DELETE /api/v1/instruments/:id
 	}
 
 	// ==================== 交易模块 - 私有接口 ====================
 	txGroup := r.Group("/api/v1/transactions")
 	txGroup.Use(middleware.JWTAuth())
 	{
-		txGroup.POST("/create", txController.Create) // 创建交易：POST /api/v1/transactions/create
-		txGroup.GET("/list", txController.List)      // 查询交易列表：GET /api/v1/transactions/list
+		txGroup.POST("/create", idempotency, txController.Create) // 创建交易：POST /api/v1/transactions/create
+		txGroup.GET("/list", txController.List)                   // 查询交易列表：GET /api/v1/transactions/list
+		txGroup.GET("/export", txController.Export)               // 导出（CSV / JSONL / XLSX）：GET /api/v1/transactions/export
+		txGroup.POST("/import", idempotency, txController.Import) // 批量导入（CSV / IBKR Flex / OFX）：POST /api/v1/transactions/import
+		txGroup.GET("/:id", txController.Get)                     // 查询单条交易：GET /api/v1/transactions/:id
+		txGroup.PUT("/:id", idempotency, txController.Update)     // 更新交易：PUT /api/v1/transactions/:id
+		txGroup.DELETE("/:id", idempotency, txController.Delete)  // 删除交易：DELETE /api/v1/transactions/:id
+	}
+
+	// ==================== 持仓模块 - 私有接口 ====================
+	portfolioGroup := r.Group("/api/v1/portfolio")
+	portfolioGroup.Use(middleware.JWTAuth())
+	{
+		portfolioGroup.GET("/holdings", portfolioController.Holdings)                            // 持仓汇总：GET /api/v1/portfolio/holdings
+		portfolioGroup.GET("/history", portfolioController.History)                              // 估值历史：GET /api/v1/portfolio/history
+		portfolioGroup.POST("/history/rebuild", idempotency, portfolioController.RebuildHistory) // 重新生成估值快照：POST /api/v1/portfolio/history/rebuild
+	}
+
+	// ==================== 业绩分析模块 - 私有接口 ====================
+	performanceGroup := r.Group("/api/v1/performance")
+	performanceGroup.Use(middleware.JWTAuth())
+	{
+		performanceGroup.GET("/returns", performanceController.Returns) // 收益率（TWR / XIRR）：GET /api/v1/performance/returns
+		performanceGroup.GET("/risk", performanceController.Risk)       // 风险指标：GET /api/v1/performance/risk
+	}
+
+	// ==================== 税务批次模块 - 私有接口 ====================
+	lotGroup := r.Group("/api/v1/lots")
+	lotGroup.Use(middleware.JWTAuth())
+	{
+		lotGroup.GET("/list", lotController.List)                     // 查询批次：GET /api/v1/lots/list
+		lotGroup.GET("/realized", lotController.Realized)             // 已实现盈亏明细：GET /api/v1/lots/realized
+		lotGroup.POST("/rebuild", idempotency, lotController.Rebuild) // 重建全部批次：POST /api/v1/lots/rebuild
+	}
+
+	// ==================== 复式记账模块 - 私有接口 ====================
+	journalGroup := r.Group("/api/v1/journal")
+	journalGroup.Use(middleware.JWTAuth())
+	{
+		journalGroup.GET("/entries", journalController.List)                  // 查询分录：GET /api/v1/journal/entries
+		journalGroup.GET("/trial-balance", journalController.TrialBalance)    // 试算平衡表：GET /api/v1/journal/trial-balance
+		journalGroup.POST("/rebuild", idempotency, journalController.Rebuild) // 重新过账全部交易：POST /api/v1/journal/rebuild
+	}
+
+	// ==================== 报表模块 - 私有接口 ====================
+	reportGroup := r.Group("/api/v1/reports")
+	reportGroup.Use(middleware.JWTAuth())
+	{
+		reportGroup.GET("/pnl", reportController.PnL) // 盈亏报表：GET /api/v1/reports/pnl
+	}
+
+	// ==================== 交易日历与行情模块 - 私有接口 ====================
+	marketGroup := r.Group("/api/v1/market")
+	marketGroup.Use(middleware.JWTAuth())
+	{
+		marketGroup.GET("/calendar", marketController.Calendar) // 交易日历：GET /api/v1/market/calendar
+		marketGroup.GET("/quotes", marketController.Quotes)     // 批量查询行情：GET /api/v1/market/quotes
+		// 签发推送票据：POST /api/v1/market/stream/ticket（票据一次性，不做幂等缓存）
+		marketGroup.POST("/stream/ticket", streamController.Ticket)
+	}
+
+	// ==================== 实时行情推送 - 私有接口 ====================
+	// 长连接（SSE），浏览器 EventSource 不能设置请求头，鉴权额外接受 Query 参数 ticket（一次性推送票据）
+	streamGroup := r.Group("/api/v1/market")
+	streamGroup.Use(middleware.StreamJWTAuth())
+	{
+		streamGroup.GET("/stream", streamController.Quotes) // 实时行情推送：GET /api/v1/market/stream
+	}
+
+	// ==================== 汇率模块 - 私有接口 ====================
+	fxGroup := r.Group("/api/v1/fx")
+	fxGroup.Use(middleware.JWTAuth())
+	{
+		fxGroup.POST("/import", idempotency, fxController.Import) // 批量导入汇率（CSV）：POST /api/v1/fx/import
+		fxGroup.GET("/rates", fxController.List)                  // 查询汇率序列：GET /api/v1/fx/rates
+		fxGroup.GET("/rate", fxController.Rate)                   // 按日期查询汇率：GET /api/v1/fx/rate
+	}
+
+	// ==================== 历史行情模块 - 私有接口 ====================
+	priceGroup := r.Group("/api/v1/prices")
+	priceGroup.Use(middleware.JWTAuth())
+	{
+		priceGroup.POST("/import", idempotency, priceController.Import) // 批量导入日线行情（CSV）：POST /api/v1/prices/import
+		priceGroup.GET("/bars", priceController.List)                   // 查询日线行情：GET /api/v1/prices/bars
 	}
 
 	return r
@@ -0,0 +1,167 @@
+package service
+
+import (
+	accountDomain "github.com/florentyang/smartfin-go/internal/domain/account"
+	"github.com/florentyang/smartfin-go/internal/dto"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 接口定义 ====================
+// Controller 层会使用这个接口
+
+type AccountService interface {
+	Create(userID uint, req *dto.CreateAccountRequest) (*dto.AccountResponse, error)
+	Get(userID, id uint) (*dto.AccountResponse, error)
+	List(userID uint) ([]*dto.AccountResponse, error)
+	Update(userID, id uint, req *dto.UpdateAccountRequest) (*dto.AccountResponse, error)
+	Cash(userID, id uint, req *dto.AccountCashRequest) (*dto.AccountCashResponse, error)
+	Delete(userID, id uint) error
+}
+
+// ==================== 接口实现 ====================
+
+type accountService struct {
+	accountDomain accountDomain.Domain // 依赖 Domain 层接口
+}
+
+// NewAccountService 创建 Service 实例
+func NewAccountService(accountDomain accountDomain.Domain) AccountService {
+	return &accountService{
+		accountDomain: accountDomain,
+	}
+}
+
+// Create 创建账户
+// Service 层职责：DTO → Domain 输入 + 调用 Domain 层 + Entity → DTO 转换
+func (s *accountService) Create(userID uint, req *dto.CreateAccountRequest) (*dto.AccountResponse, error) {
+	account, err := s.accountDomain.Create(&accountDomain.CreateInput{
+		UserID:            userID,
+		Name:              req.Name,
+		Broker:            req.Broker,
+		Number:            req.Number,
+		Notes:             req.Notes,
+		Margin:            req.Margin,
+		AllowShortSelling: req.AllowShortSelling,
+		CostBasisMethod:   req.CostBasisMethod,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return accountEntityToDTO(account), nil
+}
+
+// Get 查询单个账户
+func (s *accountService) Get(userID, id uint) (*dto.AccountResponse, error) {
+	account, err := s.accountDomain.Get(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return accountEntityToDTO(account), nil
+}
+
+// List 查询用户的全部账户
+func (s *accountService) List(userID uint) ([]*dto.AccountResponse, error) {
+	accounts, err := s.accountDomain.List(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*dto.AccountResponse, len(accounts))
+	for i, account := range accounts {
+		list[i] = accountEntityToDTO(account)
+	}
+	return list, nil
+}
+
+// Update 更新账户
+func (s *accountService) Update(userID, id uint, req *dto.UpdateAccountRequest) (*dto.AccountResponse, error) {
+	account, err := s.accountDomain.Update(&accountDomain.UpdateInput{
+		ID:                id,
+		UserID:            userID,
+		Name:              req.Name,
+		Broker:            req.Broker,
+		Number:            req.Number,
+		Notes:             req.Notes,
+		Margin:            req.Margin,
+		AllowShortSelling: req.AllowShortSelling,
+		CostBasisMethod:   req.CostBasisMethod,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return accountEntityToDTO(account), nil
+}
+
+// Cash 账户现金流水及余额变化
+func (s *accountService) Cash(userID, id uint, req *dto.AccountCashRequest) (*dto.AccountCashResponse, error) {
+	// 1. 解析日期字符串（与交易列表相同的约定）
+	startTime, endTime, err := parseDateRange(req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 调用 Domain 层回放现金流水
+	output, err := s.accountDomain.Cash(&accountDomain.CashInput{
+		UserID:    userID,
+		AccountID: id,
+		Currency:  req.Currency,
+		StartTime: startTime,
+		EndTime:   endTime,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Domain 结构 → DTO 转换
+	balances := make([]*dto.CashBalanceResponse, len(output.Balances))
+	for i, b := range output.Balances {
+		balances[i] = &dto.CashBalanceResponse{
+			Currency: b.Currency,
+			Opening:  b.Opening,
+			Inflow:   b.Inflow,
+			Outflow:  b.Outflow,
+			Closing:  b.Closing,
+		}
+	}
+	entries := make([]*dto.CashEntryResponse, len(output.Entries))
+	for i, e := range output.Entries {
+		entries[i] = &dto.CashEntryResponse{
+			TransactionID: e.Transaction.ID,
+			TradeTime:     e.Transaction.TradeTime,
+			Type:          e.Transaction.Type,
+			Symbol:        e.Transaction.Symbol,
+			Currency:      e.Transaction.Currency,
+			Amount:        e.Amount,
+			Balance:       e.Balance,
+		}
+	}
+
+	return &dto.AccountCashResponse{
+		AccountID: output.Account.ID,
+		Margin:    output.Account.Margin,
+		Balances:  balances,
+		Entries:   entries,
+	}, nil
+}
+
+// Delete 删除账户
+func (s *accountService) Delete(userID, id uint) error {
+	return s.accountDomain.Delete(userID, id)
+}
+
+// ==================== 私有辅助函数 ====================
+
+// accountEntityToDTO 将 Account Entity 转换为 DTO
+func accountEntityToDTO(account *entity.Account) *dto.AccountResponse {
+	return &dto.AccountResponse{
+		ID:                account.ID,
+		Name:              account.Name,
+		Broker:            account.Broker,
+		Number:            account.Number,
+		Notes:             account.Notes,
+		Margin:            account.Margin,
+		AllowShortSelling: account.AllowShortSelling,
+		CostBasisMethod:   account.CostBasisMethod,
+		CreatedAt:         account.CreatedAt,
+	}
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	feeDomain "github.com/florentyang/smartfin-go/internal/domain/fee"
+	"github.com/florentyang/smartfin-go/internal/dto"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 接口定义 ====================
+// Controller 层会使用这个接口
+
+type FeeService interface {
+	Create(userID uint, req *dto.FeeScheduleRequest) (*dto.FeeScheduleResponse, error)
+	Get(userID, id uint) (*dto.FeeScheduleResponse, error)
+	List(userID uint) ([]*dto.FeeScheduleResponse, error)
+	Update(userID, id uint, req *dto.FeeScheduleRequest) (*dto.FeeScheduleResponse, error)
+	Delete(userID, id uint) error
+}
+
+// ==================== 接口实现 ====================
+
+type feeService struct {
+	feeDomain feeDomain.Domain // 依赖 Domain 层接口
+}
+
+// NewFeeService 创建 Service 实例
+func NewFeeService(feeDomain feeDomain.Domain) FeeService {
+	return &feeService{
+		feeDomain: feeDomain,
+	}
+}
+
+// Create 创建费率表
+// Service 层职责：DTO → Domain 输入 + 调用 Domain 层 + Entity → DTO 转换
+func (s *feeService) Create(userID uint, req *dto.FeeScheduleRequest) (*dto.FeeScheduleResponse, error) {
+	schedule, err := s.feeDomain.Create(&feeDomain.CreateInput{
+		UserID:        userID,
+		ScheduleInput: feeRequestToInput(req),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return feeEntityToDTO(schedule), nil
+}
+
+// Get 查询单个费率表
+func (s *feeService) Get(userID, id uint) (*dto.FeeScheduleResponse, error) {
+	schedule, err := s.feeDomain.Get(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return feeEntityToDTO(schedule), nil
+}
+
+// List 查询用户的全部费率表
+func (s *feeService) List(userID uint) ([]*dto.FeeScheduleResponse, error) {
+	schedules, err := s.feeDomain.List(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*dto.FeeScheduleResponse, len(schedules))
+	for i, schedule := range schedules {
+		list[i] = feeEntityToDTO(schedule)
+	}
+	return list, nil
+}
+
+// Update 更新费率表
+func (s *feeService) Update(userID, id uint, req *dto.FeeScheduleRequest) (*dto.FeeScheduleResponse, error) {
+	schedule, err := s.feeDomain.Update(&feeDomain.UpdateInput{
+		ID:            id,
+		UserID:        userID,
+		ScheduleInput: feeRequestToInput(req),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return feeEntityToDTO(schedule), nil
+}
+
+// Delete 删除费率表
+func (s *feeService) Delete(userID, id uint) error {
+	return s.feeDomain.Delete(userID, id)
+}
+
+// ==================== 私有辅助函数 ====================
+
+// feeRequestToInput 将请求 DTO 转换为 Domain 输入
+func feeRequestToInput(req *dto.FeeScheduleRequest) feeDomain.ScheduleInput {
+	return feeDomain.ScheduleInput{
+		Name:            req.Name,
+		AccountID:       req.AccountID,
+		Broker:          req.Broker,
+		Currency:        req.Currency,
+		CommissionRate:  req.CommissionRate,
+		PerShareFee:     req.PerShareFee,
+		MinCommission:   req.MinCommission,
+		StampDutyRate:   req.StampDutyRate,
+		TransferFeeRate: req.TransferFeeRate,
+		LevyRate:        req.LevyRate,
+	}
+}
+
+// feeEntityToDTO 将 FeeSchedule Entity 转换为 DTO
+func feeEntityToDTO(schedule *entity.FeeSchedule) *dto.FeeScheduleResponse {
+	return &dto.FeeScheduleResponse{
+		ID:              schedule.ID,
+		Name:            schedule.Name,
+		AccountID:       schedule.AccountID,
+		Broker:          schedule.Broker,
+		Currency:        schedule.Currency,
+		CommissionRate:  schedule.CommissionRate,
+		PerShareFee:     schedule.PerShareFee,
+		MinCommission:   schedule.MinCommission,
+		StampDutyRate:   schedule.StampDutyRate,
+		TransferFeeRate: schedule.TransferFeeRate,
+		LevyRate:        schedule.LevyRate,
+		CreatedAt:       schedule.CreatedAt,
+	}
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	fxDomain "github.com/florentyang/smartfin-go/internal/domain/fx"
+	"github.com/florentyang/smartfin-go/internal/dto"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 接口定义 ====================
+// Controller 层会使用这个接口
+
+type FXService interface {
+	Import(file io.Reader) (*dto.ImportFXRatesResponse, error)
+	List(req *dto.ListFXRatesRequest) (*dto.ListFXRatesResponse, error)
+	Rate(req *dto.FXRateRequest) (*dto.FXRateLookupResponse, error)
+}
+
+// ==================== 接口实现 ====================
+
+type fxService struct {
+	fxDomain fxDomain.Domain // 依赖 Domain 层接口
+}
+
+// NewFXService 创建 Service 实例
+func NewFXService(fxDomain fxDomain.Domain) FXService {
+	return &fxService{
+		fxDomain: fxDomain,
+	}
+}
+
+// maxFXImportRows 单次导入汇率的最大行数（多个货币对多年的日汇率）
+const maxFXImportRows = 100000
+
+// fxCSVColumns 汇率 CSV 的必填列：date（2024-01-15）, base, quote, rate（1 base = rate quote）
+var fxCSVColumns = []string{"date", "base", "quote", "rate"}
+
+// Import 从 CSV 批量导入汇率
+// Service 层职责：
+// 1. 解析 CSV（格式错误记为行错误）
+// 2. 调用 Domain 层校验并写入，全部成功才写入
+// 3. 合并格式错误和业务错误，按行号返回
+func (s *fxService) Import(file io.Reader) (*dto.ImportFXRatesResponse, error) {
+	// 1. 解析 CSV
+	rates, rateLines, rowErrors, err := parseFXCSV(file)
+	if err != nil {
+		return nil, err
+	}
+	total := len(rates) + len(rowErrors)
+	if total > maxFXImportRows {
+		return nil, fmt.Errorf("单次最多导入 %d 行", maxFXImportRows)
+	}
+
+	// 2. 调用 Domain 层校验并写入
+	//    存在格式错误时只校验其余行，不写入
+	output, err := s.fxDomain.Import(&fxDomain.ImportInput{
+		Rates:  rates,
+		DryRun: len(rowErrors) > 0,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 合并错误，按行号排序
+	for _, e := range output.Errors {
+		rowErrors = append(rowErrors, &dto.ImportRowErrorResponse{
+			Line:    rateLines[e.Index],
+			Message: e.Err.Error(),
+		})
+	}
+	sort.SliceStable(rowErrors, func(i, j int) bool {
+		return rowErrors[i].Line < rowErrors[j].Line
+	})
+
+	result := &dto.ImportFXRatesResponse{
+		Committed: output.Committed,
+		Total:     total,
+		Errors:    rowErrors,
+	}
+	if output.Committed {
+		result.Imported = len(rates)
+	}
+	return result, nil
+}
+
+// List 查询某个货币对的汇率序列
+func (s *fxService) List(req *dto.ListFXRatesRequest) (*dto.ListFXRatesResponse, error) {
+	// 1. 解析日期范围（与交易列表相同的约定）
+	startTime, endTime, err := parseDateRange(req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 调用 Domain 层查询
+	rates, err := s.fxDomain.List(&fxDomain.ListInput{
+		Base:      req.Base,
+		Quote:     req.Quote,
+		StartDate: startTime,
+		EndDate:   endTime,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Entity → DTO 转换
+	resp := &dto.ListFXRatesResponse{
+		Base:  strings.ToUpper(req.Base),
+		Quote: strings.ToUpper(req.Quote),
+		List:  make([]*dto.FXRateResponse, len(rates)),
+	}
+	for i, rate := range rates {
+		resp.List[i] = &dto.FXRateResponse{
+			Date: rate.RateDate.Format("2006-01-02"),
+			Rate: rate.Rate,
+		}
+	}
+	return resp, nil
+}
+
+// Rate 按日期查询汇率
+func (s *fxService) Rate(req *dto.FXRateRequest) (*dto.FXRateLookupResponse, error) {
+	// 1. 解析日期（默认今天）
+	date := time.Now()
+	if req.Date != "" {
+		t, err := time.ParseInLocation("2006-01-02", req.Date, time.Local)
+		if err != nil {
+			return nil, err
+		}
+		date = t
+	}
+
+	// 2. 调用 Domain 层查询
+	output, err := s.fxDomain.Rate(req.From, req.To, date)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 转换为 DTO
+	return &dto.FXRateLookupResponse{
+		From:     output.From,
+		To:       output.To,
+		Date:     output.Date.Format("2006-01-02"),
+		RateDate: output.RateDate.Format("2006-01-02"),
+		Rate:     output.Rate,
+		Inverted: output.Inverted,
+	}, nil
+}
+
+// ==================== 私有辅助函数 ====================
+
+// parseFXCSV 解析汇率 CSV
+// 返回解析成功的汇率及其行号，以及格式错误的行
+func parseFXCSV(file io.Reader) ([]*entity.FXRate, []int, []*dto.ImportRowErrorResponse, error) {
+	// 1. 读取表头
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, nil, errors.New("CSV 文件为空")
+		}
+		return nil, nil, nil, fmt.Errorf("CSV 表头解析失败: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		// 去掉 Excel 导出的 UTF-8 BOM
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		columns[name] = i
+	}
+	for _, name := range fxCSVColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, nil, nil, fmt.Errorf("CSV 缺少必填列: %s", name)
+		}
+	}
+
+	// 2. 逐行解析
+	var (
+		rates     []*entity.FXRate
+		rateLines []int
+		rowErrors []*dto.ImportRowErrorResponse
+	)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, nil, nil, fmt.Errorf("CSV 读取失败: %w", err)
+			}
+			rowErrors = append(rowErrors, &dto.ImportRowErrorResponse{Line: parseErr.StartLine, Message: parseErr.Err.Error()})
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		field := func(name string) string {
+			if i := columns[name]; i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		if field("date") == "" && field("base") == "" && field("quote") == "" && field("rate") == "" {
+			continue // 空行
+		}
+
+		rate, err := parseFXRecord(field("date"), field("base"), field("quote"), field("rate"))
+		if err != nil {
+			rowErrors = append(rowErrors, &dto.ImportRowErrorResponse{Line: line, Message: err.Error()})
+			continue
+		}
+		rates = append(rates, rate)
+		rateLines = append(rateLines, line)
+	}
+	return rates, rateLines, rowErrors, nil
+}
+
+// parseFXRecord 解析一行汇率（只做格式解析，业务规则由 Domain 层校验）
+func parseFXRecord(date, base, quote, rate string) (*entity.FXRate, error) {
+	rateDate, err := time.ParseInLocation("2006-01-02", date, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("date 格式错误: %s", date)
+	}
+	value, err := decimal.NewFromString(rate)
+	if err != nil {
+		return nil, fmt.Errorf("rate 格式错误: %s", rate)
+	}
+	return &entity.FXRate{Base: base, Quote: quote, RateDate: rateDate, Rate: value}, nil
+}
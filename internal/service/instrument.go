@@ -0,0 +1,134 @@
+package service
+
+import (
+	instrumentDomain "github.com/florentyang/smartfin-go/internal/domain/instrument"
+	"github.com/florentyang/smartfin-go/internal/dto"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 接口定义 ====================
+// Controller 层会使用这个接口
+
+type InstrumentService interface {
+	Create(req *dto.InstrumentRequest) (*dto.InstrumentResponse, error)
+	Get(id uint) (*dto.InstrumentResponse, error)
+	Search(req *dto.SearchInstrumentRequest) (*dto.SearchInstrumentResponse, error)
+	Update(id uint, req *dto.InstrumentRequest) (*dto.InstrumentResponse, error)
+	Delete(id uint) error
+}
+
+// ==================== 接口实现 ====================
+
+type instrumentService struct {
+	instrumentDomain instrumentDomain.Domain // 依赖 Domain 层接口
+}
+
+// NewInstrumentService 创建 Service 实例
+func NewInstrumentService(instrumentDomain instrumentDomain.Domain) InstrumentService {
+	return &instrumentService{
+		instrumentDomain: instrumentDomain,
+	}
+}
+
+// Create 创建证券
+// Service 层职责：DTO → Domain 输入 + 调用 Domain 层 + Entity → DTO 转换
+func (s *instrumentService) Create(req *dto.InstrumentRequest) (*dto.InstrumentResponse, error) {
+	instrument, err := s.instrumentDomain.Create(instrumentRequestToInput(req))
+	if err != nil {
+		return nil, err
+	}
+	return instrumentEntityToDTO(instrument), nil
+}
+
+// Get 查询单个证券
+func (s *instrumentService) Get(id uint) (*dto.InstrumentResponse, error) {
+	instrument, err := s.instrumentDomain.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	return instrumentEntityToDTO(instrument), nil
+}
+
+// Search 搜索证券
+// Service 层职责：设置分页默认值 + 调用 Domain 层 + Entity 列表 → DTO 列表转换
+func (s *instrumentService) Search(req *dto.SearchInstrumentRequest) (*dto.SearchInstrumentResponse, error) {
+	// 1. 设置分页默认值
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+
+	// 2. 调用 Domain 层搜索
+	output, err := s.instrumentDomain.Search(&instrumentDomain.SearchInput{
+		Query:      req.Query,
+		Exchange:   req.Exchange,
+		AssetClass: req.AssetClass,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Entity 列表 → DTO 列表转换
+	list := make([]*dto.InstrumentResponse, len(output.List))
+	for i, instrument := range output.List {
+		list[i] = instrumentEntityToDTO(instrument)
+	}
+
+	return &dto.SearchInstrumentResponse{
+		Total:    output.Total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		List:     list,
+	}, nil
+}
+
+// Update 更新证券
+func (s *instrumentService) Update(id uint, req *dto.InstrumentRequest) (*dto.InstrumentResponse, error) {
+	instrument, err := s.instrumentDomain.Update(&instrumentDomain.UpdateInput{
+		ID:              id,
+		InstrumentInput: *instrumentRequestToInput(req),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return instrumentEntityToDTO(instrument), nil
+}
+
+// Delete 删除证券
+func (s *instrumentService) Delete(id uint) error {
+	return s.instrumentDomain.Delete(id)
+}
+
+// ==================== 私有辅助函数 ====================
+
+// instrumentRequestToInput 将请求 DTO 转换为 Domain 输入
+func instrumentRequestToInput(req *dto.InstrumentRequest) *instrumentDomain.InstrumentInput {
+	return &instrumentDomain.InstrumentInput{
+		Symbol:     req.Symbol,
+		Exchange:   req.Exchange,
+		Currency:   req.Currency,
+		AssetClass: req.AssetClass,
+		LotSize:    req.LotSize,
+		Name:       req.Name,
+		ISIN:       req.ISIN,
+	}
+}
+
+// instrumentEntityToDTO 将 Instrument Entity 转换为 DTO
+func instrumentEntityToDTO(instrument *entity.Instrument) *dto.InstrumentResponse {
+	return &dto.InstrumentResponse{
+		ID:         instrument.ID,
+		Symbol:     instrument.Symbol,
+		Exchange:   instrument.Exchange,
+		Currency:   instrument.Currency,
+		AssetClass: instrument.AssetClass,
+		LotSize:    instrument.LotSize,
+		Name:       instrument.Name,
+		ISIN:       instrument.ISIN,
+		CreatedAt:  instrument.CreatedAt,
+	}
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	journalDomain "github.com/florentyang/smartfin-go/internal/domain/journal"
+	"github.com/florentyang/smartfin-go/internal/dto"
+)
+
+// ==================== 接口定义 ====================
+// Controller 层会使用这个接口
+
+type JournalService interface {
+	List(userID uint, req *dto.ListJournalRequest) (*dto.ListJournalResponse, error)
+	TrialBalance(userID uint, req *dto.TrialBalanceRequest) (*dto.TrialBalanceResponse, error)
+	Rebuild(userID uint) (*dto.RebuildJournalResponse, error)
+}
+
+// ==================== 接口实现 ====================
+
+type journalService struct {
+	journalDomain journalDomain.Domain // 依赖 Domain 层接口
+}
+
+// NewJournalService 创建 Service 实例
+func NewJournalService(journalDomain journalDomain.Domain) JournalService {
+	return &journalService{
+		journalDomain: journalDomain,
+	}
+}
+
+// List 分页查询分录
+// Service 层职责：设置分页默认值、解析日期 + 调用 Domain 层 + Entity → DTO 转换
+func (s *journalService) List(userID uint, req *dto.ListJournalRequest) (*dto.ListJournalResponse, error) {
+	// 1. 设置分页默认值
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+
+	// 2. 解析日期字符串（可选参数）
+	startTime, endTime, err := parseDateRange(req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 调用 Domain 层查询
+	output, err := s.journalDomain.Entries(&journalDomain.EntriesInput{
+		UserID:        userID,
+		AccountID:     req.AccountID,
+		TransactionID: req.TransactionID,
+		Ledger:        req.Ledger,
+		StartTime:     startTime,
+		EndTime:       endTime,
+		Page:          req.Page,
+		PageSize:      req.PageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Entity 列表 → DTO 列表转换
+	list := make([]*dto.JournalLineResponse, len(output.Lines))
+	for i, line := range output.Lines {
+		list[i] = &dto.JournalLineResponse{
+			ID:            line.ID,
+			TransactionID: line.TransactionID,
+			AccountID:     line.AccountID,
+			Symbol:        line.Symbol,
+			Ledger:        line.Ledger,
+			Currency:      line.Currency,
+			Debit:         line.Debit,
+			Credit:        line.Credit,
+			PostedAt:      line.PostedAt,
+		}
+	}
+
+	return &dto.ListJournalResponse{
+		Total:    output.Total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		List:     list,
+	}, nil
+}
+
+// TrialBalance 试算平衡表
+func (s *journalService) TrialBalance(userID uint, req *dto.TrialBalanceRequest) (*dto.TrialBalanceResponse, error) {
+	// 1. 解析截止日期（可选参数，包含当天）
+	_, endTime, err := parseDateRange("", req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 调用 Domain 层汇总
+	output, err := s.journalDomain.TrialBalance(&journalDomain.TrialBalanceInput{
+		UserID:    userID,
+		AccountID: req.AccountID,
+		EndTime:   endTime,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Domain 结构 → DTO 转换
+	rows := make([]*dto.TrialBalanceRowResponse, len(output.Rows))
+	for i, row := range output.Rows {
+		rows[i] = &dto.TrialBalanceRowResponse{
+			Ledger:   row.Ledger,
+			Currency: row.Currency,
+			Debit:    row.Debit,
+			Credit:   row.Credit,
+			Balance:  row.Balance,
+		}
+	}
+	totals := make([]*dto.TrialBalanceTotalResponse, len(output.Totals))
+	for i, total := range output.Totals {
+		totals[i] = &dto.TrialBalanceTotalResponse{
+			Currency: total.Currency,
+			Debit:    total.Debit,
+			Credit:   total.Credit,
+			Balanced: total.Balanced,
+		}
+	}
+
+	return &dto.TrialBalanceResponse{
+		Rows:     rows,
+		Totals:   totals,
+		Balanced: output.Balanced,
+	}, nil
+}
+
+// Rebuild 重新过账用户的全部交易
+func (s *journalService) Rebuild(userID uint) (*dto.RebuildJournalResponse, error) {
+	count, err := s.journalDomain.PostAll(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.RebuildJournalResponse{SymbolCount: count}, nil
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	lotDomain "github.com/florentyang/smartfin-go/internal/domain/lot"
+	"github.com/florentyang/smartfin-go/internal/dto"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 接口定义 ====================
+// Controller 层会使用这个接口
+
+type LotService interface {
+	List(userID uint, req *dto.ListLotsRequest) ([]*dto.TaxLotResponse, error)
+	Realized(userID uint, req *dto.RealizedLotsRequest) (*dto.RealizedLotsResponse, error)
+	Rebuild(userID uint) (*dto.RebuildLotsResponse, error)
+}
+
+// ==================== 接口实现 ====================
+
+type lotService struct {
+	lotDomain lotDomain.Domain // 依赖 Domain 层接口
+}
+
+// NewLotService 创建 Service 实例
+func NewLotService(lotDomain lotDomain.Domain) LotService {
+	return &lotService{
+		lotDomain: lotDomain,
+	}
+}
+
+// List 查询批次
+// Service 层职责：调用 Domain 层 + Entity → DTO 转换
+func (s *lotService) List(userID uint, req *dto.ListLotsRequest) ([]*dto.TaxLotResponse, error) {
+	lots, err := s.lotDomain.ListLots(&lotDomain.ListLotsInput{
+		UserID:    userID,
+		AccountID: req.AccountID,
+		Symbol:    req.Symbol,
+		OpenOnly:  req.OpenOnly,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*dto.TaxLotResponse, len(lots))
+	for i, lot := range lots {
+		list[i] = lotEntityToDTO(lot)
+	}
+	return list, nil
+}
+
+// Realized 查询已实现盈亏明细
+// Service 层职责：
+// 1. 解析日期字符串
+// 2. 调用 Domain 层
+// 3. Entity → DTO 转换（计算持有天数、长短期）
+func (s *lotService) Realized(userID uint, req *dto.RealizedLotsRequest) (*dto.RealizedLotsResponse, error) {
+	// 1. 解析日期字符串（可选参数）
+	startTime, endTime, err := parseDateRange(req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 调用 Domain 层查询
+	output, err := s.lotDomain.Realized(&lotDomain.RealizedInput{
+		UserID:            userID,
+		AccountID:         req.AccountID,
+		Symbol:            req.Symbol,
+		SellTransactionID: req.SellID,
+		StartTime:         startTime,
+		EndTime:           endTime,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Entity 列表 → DTO 列表转换
+	list := make([]*dto.LotAssignmentResponse, len(output.Assignments))
+	for i, a := range output.Assignments {
+		list[i] = assignmentEntityToDTO(a)
+	}
+
+	return &dto.RealizedLotsResponse{
+		TotalProceeds:     output.TotalProceeds,
+		TotalCostBasis:    output.TotalCostBasis,
+		TotalRealizedGain: output.TotalRealizedGain,
+		List:              list,
+	}, nil
+}
+
+// Rebuild 重建全部批次
+// Service 层职责：调用 Domain 层 + 组装响应
+func (s *lotService) Rebuild(userID uint) (*dto.RebuildLotsResponse, error) {
+	count, err := s.lotDomain.RebuildAll(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.RebuildLotsResponse{SymbolCount: count}, nil
+}
+
+// ==================== 私有辅助函数 ====================
+
+// lotEntityToDTO 将 TaxLot Entity 转换为 DTO
+func lotEntityToDTO(lot *entity.TaxLot) *dto.TaxLotResponse {
+	return &dto.TaxLotResponse{
+		ID:                lot.ID,
+		Symbol:            lot.Symbol,
+		AccountID:         lot.AccountID,
+		OpenTime:          lot.OpenTime,
+		Quantity:          lot.Quantity,
+		CostBasis:         lot.CostBasis,
+		UnitCost:          lot.UnitCost,
+		RemainingQuantity: lot.RemainingQuantity,
+		RemainingCost:     lot.RemainingCost,
+	}
+}
+
+// assignmentEntityToDTO 将 LotAssignment Entity 转换为 DTO
+// 长期持有：卖出时间晚于开仓时间满 1 年
+func assignmentEntityToDTO(a *entity.LotAssignment) *dto.LotAssignmentResponse {
+	return &dto.LotAssignmentResponse{
+		ID:                a.ID,
+		Symbol:            a.Symbol,
+		AccountID:         a.AccountID,
+		SellTransactionID: a.SellTransactionID,
+		LotID:             a.LotID,
+		Method:            a.Method,
+		Quantity:          a.Quantity,
+		CostBasis:         a.CostBasis,
+		Proceeds:          a.Proceeds,
+		RealizedGain:      a.RealizedGain,
+		OpenTime:          a.OpenTime,
+		CloseTime:         a.CloseTime,
+		HoldingDays:       int(a.CloseTime.Sub(a.OpenTime).Hours() / 24),
+		LongTerm:          a.CloseTime.After(a.OpenTime.AddDate(1, 0, 0)),
+	}
+}
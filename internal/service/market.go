@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	marketDomain "github.com/florentyang/smartfin-go/internal/domain/market"
+	quoteDomain "github.com/florentyang/smartfin-go/internal/domain/quote"
+	"github.com/florentyang/smartfin-go/internal/dto"
+	"github.com/florentyang/smartfin-go/internal/quote"
+)
+
+// defaultCalendarDays 未指定结束日期时查询的天数
+const defaultCalendarDays = 30
+
+// ==================== 接口定义 ====================
+// Controller 层会使用这个接口
+
+type MarketService interface {
+	Calendar(req *dto.MarketCalendarRequest) (*dto.MarketCalendarResponse, error)
+	Quotes(ctx context.Context, req *dto.MarketQuotesRequest) (*dto.MarketQuotesResponse, error)
+}
+
+// ==================== 接口实现 ====================
+
+type marketService struct {
+	marketDomain marketDomain.Domain // 依赖 Domain 层接口
+	quoteDomain  quoteDomain.Domain  // 行情 Domain
+}
+
+// NewMarketService 创建 Service 实例
+func NewMarketService(marketDomain marketDomain.Domain, quoteDomain quoteDomain.Domain) MarketService {
+	return &marketService{
+		marketDomain: marketDomain,
+		quoteDomain:  quoteDomain,
+	}
+}
+
+// Calendar 查询交易日历
+// Service 层职责：解析日期、设置默认范围 + 调用 Domain 层 + Domain 结构 → DTO 转换
+func (s *marketService) Calendar(req *dto.MarketCalendarRequest) (*dto.MarketCalendarResponse, error) {
+	// 1. 解析日期（开始日期默认今天，结束日期默认开始日期后 30 天）
+	start := time.Now().UTC()
+	if req.StartDate != "" {
+		t, err := time.Parse("2006-01-02", req.StartDate)
+		if err != nil {
+			return nil, err
+		}
+		start = t
+	}
+	end := start.AddDate(0, 0, defaultCalendarDays)
+	if req.EndDate != "" {
+		t, err := time.Parse("2006-01-02", req.EndDate)
+		if err != nil {
+			return nil, err
+		}
+		end = t
+	}
+
+	// 2. 调用 Domain 层生成日历
+	output, err := s.marketDomain.Calendar(&marketDomain.CalendarInput{
+		Exchange:  strings.ToUpper(strings.TrimSpace(req.Exchange)),
+		StartDate: start,
+		EndDate:   end,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Domain 结构 → DTO 转换
+	days := make([]*dto.TradingDayResponse, len(output.Days))
+	for i, day := range output.Days {
+		sessions := make([]*dto.MarketSessionResponse, len(day.Sessions))
+		for j, session := range day.Sessions {
+			sessions[j] = &dto.MarketSessionResponse{
+				Open:  session.Open,
+				Close: session.Close,
+			}
+		}
+		days[i] = &dto.TradingDayResponse{
+			Date:       day.Date.Format("2006-01-02"),
+			Open:       day.Open,
+			Holiday:    day.Holiday,
+			EarlyClose: day.EarlyClose,
+			Sessions:   sessions,
+		}
+	}
+
+	resp := &dto.MarketCalendarResponse{
+		Exchange: output.Exchange.Code,
+		Name:     output.Exchange.Name,
+		Timezone: output.Exchange.Timezone,
+		Sessions: output.Exchange.Sessions,
+		Days:     days,
+	}
+	if !output.PreviousTradingDay.IsZero() {
+		resp.PreviousTradingDay = output.PreviousTradingDay.Format("2006-01-02")
+	}
+	return resp, nil
+}
+
+// Quotes 批量查询最新行情
+// Service 层职责：拆分股票代码 + 调用 Domain 层 + Domain 结构 → DTO 转换（含涨跌额、涨跌幅）
+// ctx 为请求上下文：客户端断开时尚未完成的查询立即取消
+func (s *marketService) Quotes(ctx context.Context, req *dto.MarketQuotesRequest) (*dto.MarketQuotesResponse, error) {
+	// 1. 调用 Domain 层并发查询
+	output, err := s.quoteDomain.Fetch(ctx, splitSymbols(req.Symbols))
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Domain 结构 → DTO 转换
+	resp := &dto.MarketQuotesResponse{
+		Quotes: make([]*dto.QuoteResponse, len(output.Quotes)),
+		Errors: make([]*dto.QuoteErrorResponse, len(output.Errors)),
+	}
+	for i, q := range output.Quotes {
+		resp.Quotes[i] = toQuoteResponse(q)
+	}
+	for i, e := range output.Errors {
+		resp.Errors[i] = &dto.QuoteErrorResponse{
+			Symbol:  e.Symbol,
+			Message: e.Err.Error(),
+		}
+	}
+	return resp, nil
+}
+
+// ==================== 私有辅助函数 ====================
+
+// splitSymbols 拆分逗号分隔的股票代码，忽略空项
+func splitSymbols(raw string) []string {
+	var symbols []string
+	for _, symbol := range strings.Split(raw, ",") {
+		if symbol = strings.TrimSpace(symbol); symbol != "" {
+			symbols = append(symbols, symbol)
+		}
+	}
+	return symbols
+}
+
+// toQuoteResponse 行情 → DTO，有昨收价时计算涨跌额和涨跌幅
+func toQuoteResponse(q *quote.Quote) *dto.QuoteResponse {
+	item := &dto.QuoteResponse{
+		Symbol:    q.Symbol,
+		Price:     q.Price,
+		PrevClose: q.PrevClose,
+		Currency:  q.Currency,
+		Time:      q.Time,
+		Source:    q.Source,
+	}
+	if q.PrevClose.IsPositive() {
+		change := q.Price.Sub(q.PrevClose)
+		percent := change.Div(q.PrevClose).Mul(decimal.NewFromInt(100)).Round(2)
+		item.Change = &change
+		item.ChangePercent = &percent
+	}
+	return item
+}
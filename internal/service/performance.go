@@ -0,0 +1,138 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	performanceDomain "github.com/florentyang/smartfin-go/internal/domain/performance"
+	"github.com/florentyang/smartfin-go/internal/dto"
+)
+
+// ==================== 接口定义 ====================
+// Controller 层会使用这个接口
+
+type PerformanceService interface {
+	Returns(userID uint, req *dto.ReturnsRequest) (*dto.ReturnsResponse, error)
+	Risk(userID uint, req *dto.RiskRequest) (*dto.RiskResponse, error)
+}
+
+// ==================== 接口实现 ====================
+
+type performanceService struct {
+	performanceDomain performanceDomain.Domain // 依赖 Domain 层接口
+}
+
+// NewPerformanceService 创建 Service 实例
+func NewPerformanceService(performanceDomain performanceDomain.Domain) PerformanceService {
+	return &performanceService{
+		performanceDomain: performanceDomain,
+	}
+}
+
+// Returns 收益率
+// Service 层职责：解析区间列表 + 调用 Domain 层 + Domain 结构 → DTO 转换
+func (s *performanceService) Returns(userID uint, req *dto.ReturnsRequest) (*dto.ReturnsResponse, error) {
+	// 1. 解析区间列表（不区分大小写）
+	windows := splitSymbols(strings.ToLower(req.Windows))
+
+	// 2. 调用 Domain 层计算
+	output, err := s.performanceDomain.Returns(&performanceDomain.ReturnsInput{
+		UserID:    userID,
+		AccountID: req.AccountID,
+		Symbol:    req.Symbol,
+		Windows:   windows,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Domain 结构 → DTO 转换
+	resp := &dto.ReturnsResponse{
+		BaseCurrency: output.BaseCurrency,
+		Symbol:       output.Symbol,
+		AsOf:         output.AsOf.Format("2006-01-02"),
+		Windows:      make([]*dto.WindowReturnResponse, len(output.Windows)),
+	}
+	for i, w := range output.Windows {
+		resp.Windows[i] = &dto.WindowReturnResponse{
+			Window:        w.Window,
+			StartDate:     w.StartDate.Format("2006-01-02"),
+			EndDate:       w.EndDate.Format("2006-01-02"),
+			Days:          w.Days,
+			StartValue:    w.StartValue,
+			EndValue:      w.EndValue,
+			NetFlow:       w.NetFlow,
+			Profit:        w.Profit,
+			TWR:           w.TWR,
+			TWRAnnualized: w.TWRAnnualized,
+			XIRR:          w.XIRR,
+		}
+	}
+	return resp, nil
+}
+
+// Risk 风险指标
+// Service 层职责：解析日期范围和无风险利率 + 调用 Domain 层 + Domain 结构 → DTO 转换
+func (s *performanceService) Risk(userID uint, req *dto.RiskRequest) (*dto.RiskResponse, error) {
+	// 1. 解析日期范围（与交易列表相同的约定）和无风险利率
+	startTime, endTime, err := parseDateRange(req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	var riskFreeRate *decimal.Decimal
+	if req.RiskFreeRate != "" {
+		value, err := decimal.NewFromString(req.RiskFreeRate)
+		if err != nil {
+			return nil, performanceDomain.ErrInvalidRiskFreeRate
+		}
+		riskFreeRate = &value
+	}
+
+	// 2. 调用 Domain 层计算
+	output, err := s.performanceDomain.Risk(&performanceDomain.RiskInput{
+		UserID:       userID,
+		AccountID:    req.AccountID,
+		Symbol:       req.Symbol,
+		StartDate:    startTime,
+		EndDate:      endTime,
+		Benchmark:    req.Benchmark,
+		RiskFreeRate: riskFreeRate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Domain 结构 → DTO 转换
+	return &dto.RiskResponse{
+		BaseCurrency:    output.BaseCurrency,
+		Symbol:          output.Symbol,
+		StartDate:       output.StartDate.Format("2006-01-02"),
+		EndDate:         output.EndDate.Format("2006-01-02"),
+		Days:            output.Days,
+		RiskFreeRate:    output.RiskFreeRate,
+		TotalReturn:     output.TotalReturn,
+		Volatility:      output.Volatility,
+		Sharpe:          output.Sharpe,
+		Sortino:         output.Sortino,
+		MaxDrawdown:     output.MaxDrawdown,
+		PeakDate:        formatOptionalDate(output.PeakDate),
+		TroughDate:      formatOptionalDate(output.TroughDate),
+		RecoveryDate:    formatOptionalDate(output.RecoveryDate),
+		Benchmark:       output.Benchmark,
+		BenchmarkReturn: output.BenchmarkReturn,
+		Beta:            output.Beta,
+		Correlation:     output.Correlation,
+	}, nil
+}
+
+// ==================== 私有辅助函数 ====================
+
+// formatOptionalDate 可选日期 → 字符串，nil 时为空字符串
+func formatOptionalDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
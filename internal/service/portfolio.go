@@ -0,0 +1,149 @@
+package service
+
+import (
+	"time"
+
+	fxDomain "github.com/florentyang/smartfin-go/internal/domain/fx"
+	portfolioDomain "github.com/florentyang/smartfin-go/internal/domain/portfolio"
+	"github.com/florentyang/smartfin-go/internal/dto"
+)
+
+// ==================== 接口定义 ====================
+// Controller 层会使用这个接口
+
+type PortfolioService interface {
+	Holdings(userID uint, req *dto.HoldingsRequest) (*dto.HoldingsResponse, error)
+	History(userID uint, req *dto.PortfolioHistoryRequest) (*dto.PortfolioHistoryResponse, error)
+	RebuildHistory(userID uint) (*dto.RebuildHistoryResponse, error)
+}
+
+// ==================== 接口实现 ====================
+
+type portfolioService struct {
+	portfolioDomain portfolioDomain.Domain // 依赖 Domain 层接口
+}
+
+// NewPortfolioService 创建 Service 实例
+func NewPortfolioService(portfolioDomain portfolioDomain.Domain) PortfolioService {
+	return &portfolioService{
+		portfolioDomain: portfolioDomain,
+	}
+}
+
+// Holdings 持仓汇总
+// Service 层职责：调用 Domain 层 + Domain 结构 → DTO 转换
+func (s *portfolioService) Holdings(userID uint, req *dto.HoldingsRequest) (*dto.HoldingsResponse, error) {
+	// 1. 调用 Domain 层计算持仓
+	output, err := s.portfolioDomain.Holdings(&portfolioDomain.HoldingsInput{
+		UserID:        userID,
+		AccountID:     req.AccountID,
+		IncludeClosed: req.IncludeClosed,
+		Currency:      req.Currency,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Domain 结构 → DTO 转换
+	holdings := make([]*dto.HoldingResponse, len(output.Holdings))
+	for i, h := range output.Holdings {
+		holdings[i] = holdingToDTO(h)
+	}
+
+	return &dto.HoldingsResponse{
+		BaseCurrency:     output.BaseCurrency,
+		TotalCost:        output.TotalCost,
+		TotalRealizedPnL: output.TotalRealizedPnL,
+		TotalIncome:      output.TotalIncome,
+		TotalMarketValue: output.TotalMarketValue,
+		TotalUnrealized:  output.TotalUnrealized,
+		Holdings:         holdings,
+	}, nil
+}
+
+// History 估值历史
+// Service 层职责：解析日期范围 + 调用 Domain 层 + Domain 结构 → DTO 转换
+func (s *portfolioService) History(userID uint, req *dto.PortfolioHistoryRequest) (*dto.PortfolioHistoryResponse, error) {
+	// 1. 解析日期范围（与交易列表相同的约定）
+	startTime, endTime, err := parseDateRange(req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 调用 Domain 层查询
+	output, err := s.portfolioDomain.History(&portfolioDomain.HistoryInput{
+		UserID:          userID,
+		AccountID:       req.AccountID,
+		StartDate:       startTime,
+		EndDate:         endTime,
+		IncludeHoldings: req.IncludeHoldings,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Domain 结构 → DTO 转换
+	resp := &dto.PortfolioHistoryResponse{
+		BaseCurrency: output.BaseCurrency,
+		Points:       make([]*dto.HistoryPointResponse, len(output.Points)),
+	}
+	for i, p := range output.Points {
+		point := &dto.HistoryPointResponse{
+			Date:        p.Date.Format("2006-01-02"),
+			MarketValue: p.MarketValue,
+			Cost:        p.Cost,
+			Cash:        p.Cash,
+			TotalValue:  p.MarketValue.Add(p.Cash),
+			NetDeposit:  p.NetDeposit,
+		}
+		for _, h := range p.Holdings {
+			point.Holdings = append(point.Holdings, &dto.HistoryHoldingResponse{
+				Symbol:        h.Symbol,
+				Quantity:      h.Quantity,
+				Price:         h.Price,
+				PriceCurrency: h.PriceCurrency,
+				MarketValue:   h.MarketValue,
+				Cost:          h.Cost,
+			})
+		}
+		resp.Points[i] = point
+	}
+	return resp, nil
+}
+
+// RebuildHistory 重新生成估值快照（截止到昨天）
+// Service 层职责：调用 Domain 层 + 组装响应
+func (s *portfolioService) RebuildHistory(userID uint) (*dto.RebuildHistoryResponse, error) {
+	yesterday := fxDomain.DateOf(time.Now()).AddDate(0, 0, -1)
+	count, err := s.portfolioDomain.RebuildSnapshots(userID, yesterday)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.RebuildHistoryResponse{DayCount: count}, nil
+}
+
+// ==================== 私有辅助函数 ====================
+
+// holdingToDTO 将 Holding 转换为 DTO
+func holdingToDTO(h *portfolioDomain.Holding) *dto.HoldingResponse {
+	resp := &dto.HoldingResponse{
+		Symbol:        h.Symbol,
+		Currency:      h.Currency,
+		Name:          h.Name,
+		Quantity:      h.Quantity,
+		TotalCost:     h.TotalCost,
+		AverageCost:   h.AverageCost,
+		RealizedPnL:   h.RealizedPnL,
+		Income:        h.Income,
+		TotalFee:      h.TotalFee,
+		TradeCount:    h.TradeCount,
+		MarketPrice:   h.MarketPrice,
+		MarketValue:   h.MarketValue,
+		UnrealizedPnL: h.UnrealizedPnL,
+		PriceSource:   h.PriceSource,
+	}
+	if h.PriceDate != nil {
+		resp.PriceDate = h.PriceDate.Format("2006-01-02")
+	}
+	return resp
+}
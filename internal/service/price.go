@@ -0,0 +1,234 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	priceDomain "github.com/florentyang/smartfin-go/internal/domain/price"
+	"github.com/florentyang/smartfin-go/internal/dto"
+)
+
+// ==================== 接口定义 ====================
+// Controller 层会使用这个接口
+
+type PriceService interface {
+	Import(file io.Reader) (*dto.ImportPriceBarsResponse, error)
+	List(req *dto.ListPriceBarsRequest) (*dto.ListPriceBarsResponse, error)
+}
+
+// ==================== 接口实现 ====================
+
+type priceService struct {
+	priceDomain priceDomain.Domain // 依赖 Domain 层接口
+}
+
+// NewPriceService 创建 Service 实例
+func NewPriceService(priceDomain priceDomain.Domain) PriceService {
+	return &priceService{
+		priceDomain: priceDomain,
+	}
+}
+
+// maxPriceImportRows 单次导入日线行情的最大行数（数百只股票多年的日线）
+const maxPriceImportRows = 200000
+
+// priceCSVColumns 日线行情 CSV 的必填列：date（2024-01-15）, symbol, close
+// 可选列：open, high, low, volume
+var priceCSVColumns = []string{"date", "symbol", "close"}
+
+// Import 从 CSV 批量导入日线行情
+// Service 层职责：
+// 1. 解析 CSV（格式错误记为行错误）
+// 2. 调用 Domain 层校验并写入，全部成功才写入
+// 3. 合并格式错误和业务错误，按行号返回
+func (s *priceService) Import(file io.Reader) (*dto.ImportPriceBarsResponse, error) {
+	// 1. 解析 CSV
+	bars, barLines, rowErrors, err := parsePriceCSV(file)
+	if err != nil {
+		return nil, err
+	}
+	total := len(bars) + len(rowErrors)
+	if total > maxPriceImportRows {
+		return nil, fmt.Errorf("单次最多导入 %d 行", maxPriceImportRows)
+	}
+
+	// 2. 调用 Domain 层校验并写入
+	//    存在格式错误时只校验其余行，不写入
+	output, err := s.priceDomain.Import(&priceDomain.ImportInput{
+		Bars:   bars,
+		DryRun: len(rowErrors) > 0,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 合并错误，按行号排序
+	for _, e := range output.Errors {
+		rowErrors = append(rowErrors, &dto.ImportRowErrorResponse{
+			Line:    barLines[e.Index],
+			Message: e.Err.Error(),
+		})
+	}
+	sort.SliceStable(rowErrors, func(i, j int) bool {
+		return rowErrors[i].Line < rowErrors[j].Line
+	})
+
+	result := &dto.ImportPriceBarsResponse{
+		Committed: output.Committed,
+		Total:     total,
+		Errors:    rowErrors,
+	}
+	if output.Committed {
+		result.Imported = len(bars)
+	}
+	return result, nil
+}
+
+// List 查询某只证券的日线行情
+func (s *priceService) List(req *dto.ListPriceBarsRequest) (*dto.ListPriceBarsResponse, error) {
+	// 1. 解析日期范围（与交易列表相同的约定）
+	startTime, endTime, err := parseDateRange(req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 调用 Domain 层查询
+	output, err := s.priceDomain.List(&priceDomain.ListInput{
+		Symbol:    req.Symbol,
+		StartDate: startTime,
+		EndDate:   endTime,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Entity → DTO 转换
+	resp := &dto.ListPriceBarsResponse{
+		Symbol:   output.Instrument.Symbol,
+		Name:     output.Instrument.Name,
+		Currency: output.Instrument.Currency,
+		List:     make([]*dto.PriceBarResponse, len(output.Bars)),
+	}
+	for i, bar := range output.Bars {
+		resp.List[i] = &dto.PriceBarResponse{
+			Date:   bar.BarDate.Format("2006-01-02"),
+			Open:   bar.Open,
+			High:   bar.High,
+			Low:    bar.Low,
+			Close:  bar.Close,
+			Volume: bar.Volume,
+		}
+	}
+	return resp, nil
+}
+
+// ==================== 私有辅助函数 ====================
+
+// parsePriceCSV 解析日线行情 CSV
+// 返回解析成功的行情及其行号，以及格式错误的行
+func parsePriceCSV(file io.Reader) ([]*priceDomain.BarInput, []int, []*dto.ImportRowErrorResponse, error) {
+	// 1. 读取表头
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, nil, errors.New("CSV 文件为空")
+		}
+		return nil, nil, nil, fmt.Errorf("CSV 表头解析失败: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		// 去掉 Excel 导出的 UTF-8 BOM
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		columns[name] = i
+	}
+	for _, name := range priceCSVColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, nil, nil, fmt.Errorf("CSV 缺少必填列: %s", name)
+		}
+	}
+
+	// 2. 逐行解析
+	var (
+		bars      []*priceDomain.BarInput
+		barLines  []int
+		rowErrors []*dto.ImportRowErrorResponse
+	)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, nil, nil, fmt.Errorf("CSV 读取失败: %w", err)
+			}
+			rowErrors = append(rowErrors, &dto.ImportRowErrorResponse{Line: parseErr.StartLine, Message: parseErr.Err.Error()})
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue // 空行
+		}
+
+		bar, err := parsePriceRecord(field)
+		if err != nil {
+			rowErrors = append(rowErrors, &dto.ImportRowErrorResponse{Line: line, Message: err.Error()})
+			continue
+		}
+		bars = append(bars, bar)
+		barLines = append(barLines, line)
+	}
+	return bars, barLines, rowErrors, nil
+}
+
+// parsePriceRecord 解析一行日线行情（只做格式解析，业务规则由 Domain 层校验）
+// 可选列为空时记为 0，由 Domain 层按收盘价补全
+func parsePriceRecord(field func(name string) string) (*priceDomain.BarInput, error) {
+	date, err := time.ParseInLocation("2006-01-02", field("date"), time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("date 格式错误: %s", field("date"))
+	}
+	bar := &priceDomain.BarInput{Symbol: field("symbol"), Date: date}
+
+	values := []struct {
+		name     string
+		target   *decimal.Decimal
+		required bool
+	}{
+		{"open", &bar.Open, false},
+		{"high", &bar.High, false},
+		{"low", &bar.Low, false},
+		{"close", &bar.Close, true},
+		{"volume", &bar.Volume, false},
+	}
+	for _, v := range values {
+		raw := field(v.name)
+		if raw == "" && !v.required {
+			continue
+		}
+		value, err := decimal.NewFromString(strings.ReplaceAll(raw, ",", ""))
+		if err != nil {
+			return nil, fmt.Errorf("%s 格式错误: %s", v.name, raw)
+		}
+		*v.target = value
+	}
+	return bar, nil
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	reportDomain "github.com/florentyang/smartfin-go/internal/domain/report"
+	"github.com/florentyang/smartfin-go/internal/dto"
+)
+
+// ==================== 接口定义 ====================
+// Controller 层会使用这个接口
+
+type ReportService interface {
+	PnL(userID uint, req *dto.PnLReportRequest) (*dto.PnLReportResponse, error)
+}
+
+// ==================== 接口实现 ====================
+
+type reportService struct {
+	reportDomain reportDomain.Domain // 依赖 Domain 层接口
+}
+
+// NewReportService 创建 Service 实例
+func NewReportService(reportDomain reportDomain.Domain) ReportService {
+	return &reportService{
+		reportDomain: reportDomain,
+	}
+}
+
+// PnL 盈亏报表
+// Service 层职责：
+// 1. 设置默认值、解析日期字符串（与交易列表相同的约定）
+// 2. 调用 Domain 层
+// 3. Domain 结构 → DTO 转换
+func (s *reportService) PnL(userID uint, req *dto.PnLReportRequest) (*dto.PnLReportResponse, error) {
+	// 1. 设置默认分组方式
+	if req.GroupBy == "" {
+		req.GroupBy = reportDomain.GroupBySymbol
+	}
+
+	// 2. 解析日期字符串（可选参数）
+	startTime, endTime, err := parseDateRange(req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 调用 Domain 层计算
+	output, err := s.reportDomain.PnL(&reportDomain.PnLInput{
+		UserID:    userID,
+		AccountID: req.AccountID,
+		StartTime: startTime,
+		EndTime:   endTime,
+		GroupBy:   req.GroupBy,
+		Currency:  req.Currency,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Domain 结构 → DTO 转换
+	rows := make([]*dto.PnLRowResponse, len(output.Rows))
+	for i, row := range output.Rows {
+		rows[i] = &dto.PnLRowResponse{
+			Key:              row.Key,
+			Currency:         row.Currency,
+			Realized:         row.Realized,
+			Unrealized:       row.Unrealized,
+			UnrealizedChange: row.UnrealizedChange,
+			Income:           row.Income,
+			Expenses:         row.Expenses,
+			PnL:              row.PnL,
+			Quantity:         row.Quantity,
+			CostBasis:        row.CostBasis,
+			MarketPrice:      row.MarketPrice,
+			MarketValue:      row.MarketValue,
+		}
+	}
+
+	return &dto.PnLReportResponse{
+		GroupBy:               output.GroupBy,
+		BaseCurrency:          output.BaseCurrency,
+		AsOf:                  output.AsOf,
+		TotalRealized:         output.TotalRealized,
+		TotalUnrealized:       output.TotalUnrealized,
+		TotalUnrealizedChange: output.TotalUnrealizedChange,
+		TotalIncome:           output.TotalIncome,
+		TotalExpenses:         output.TotalExpenses,
+		TotalPnL:              output.TotalPnL,
+		Rows:                  rows,
+	}, nil
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	portfolioDomain "github.com/florentyang/smartfin-go/internal/domain/portfolio"
+	streamDomain "github.com/florentyang/smartfin-go/internal/domain/stream"
+	"github.com/florentyang/smartfin-go/internal/dto"
+	"github.com/florentyang/smartfin-go/pkg/jwt"
+)
+
+// ==================== 接口定义 ====================
+// Controller 层会使用这个接口
+
+type StreamService interface {
+	Subscribe(userID uint, req *dto.MarketStreamRequest) (*QuoteStream, error)
+	Ticket(userID uint, username string) (*dto.MarketStreamTicketResponse, error)
+}
+
+// ==================== 接口实现 ====================
+
+type streamService struct {
+	streamDomain    streamDomain.Domain    // 依赖 Domain 层接口
+	portfolioDomain portfolioDomain.Domain // 持仓 Domain（默认订阅当前持仓）
+}
+
+// NewStreamService 创建 Service 实例
+func NewStreamService(streamDomain streamDomain.Domain, portfolioDomain portfolioDomain.Domain) StreamService {
+	return &streamService{
+		streamDomain:    streamDomain,
+		portfolioDomain: portfolioDomain,
+	}
+}
+
+// Subscribe 订阅实时行情
+// Service 层职责：确定订阅的股票（未指定时取当前持仓）+ 调用 Domain 层 + 包装为 DTO 形式的行情流
+func (s *streamService) Subscribe(userID uint, req *dto.MarketStreamRequest) (*QuoteStream, error) {
+	// 1. 未指定股票代码时订阅当前持仓
+	symbols := splitSymbols(req.Symbols)
+	if len(symbols) == 0 {
+		held, err := s.portfolioDomain.HeldSymbols(userID)
+		if err != nil {
+			return nil, err
+		}
+		symbols = held
+	}
+
+	// 2. 调用 Domain 层订阅
+	sub, err := s.streamDomain.Subscribe(symbols)
+	if err != nil {
+		return nil, err
+	}
+	return &QuoteStream{sub: sub}, nil
+}
+
+// Ticket 签发推送票据
+// EventSource 不能设置请求头，用短期、一次性的票据代替放在 URL 中的普通 Token
+func (s *streamService) Ticket(userID uint, username string) (*dto.MarketStreamTicketResponse, error) {
+	ticket, _, expiresAt, err := jwt.GenerateStreamTicket(userID, username)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.MarketStreamTicketResponse{Ticket: ticket, ExpiresAt: expiresAt}, nil
+}
+
+// ==================== 行情流 ====================
+
+// QuoteStream 一个连接的行情流（Controller 持有，连接结束时必须 Close）
+type QuoteStream struct {
+	sub streamDomain.Subscription
+}
+
+// Subscribed 连接建立后的第一条消息
+func (qs *QuoteStream) Subscribed() *dto.MarketStreamSubscribedResponse {
+	return &dto.MarketStreamSubscribedResponse{Symbols: qs.sub.Symbols()}
+}
+
+// Ready 有新行情待取时可读
+func (qs *QuoteStream) Ready() <-chan struct{} {
+	return qs.sub.Ready()
+}
+
+// Dropped 客户端接收过慢被断开时关闭
+func (qs *QuoteStream) Dropped() <-chan struct{} {
+	return qs.sub.Done()
+}
+
+// Next 取出积压的行情（每只股票最多一条）
+func (qs *QuoteStream) Next() []*dto.QuoteResponse {
+	quotes := qs.sub.Drain()
+	items := make([]*dto.QuoteResponse, len(quotes))
+	for i, q := range quotes {
+		items[i] = toQuoteResponse(q)
+	}
+	return items
+}
+
+// Close 取消订阅
+func (qs *QuoteStream) Close() {
+	qs.sub.Close()
+}
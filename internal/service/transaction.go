@@ -1,11 +1,14 @@
 package service
 
 import (
+	"io"
 	"time"
 
+	lotDomain "github.com/florentyang/smartfin-go/internal/domain/lot"
 	txDomain "github.com/florentyang/smartfin-go/internal/domain/transaction"
 	"github.com/florentyang/smartfin-go/internal/dto"
 	"github.com/florentyang/smartfin-go/internal/entity"
+	"github.com/florentyang/smartfin-go/internal/importer"
 )
 
 // ==================== 接口定义 ====================
@@ -13,19 +16,26 @@ import (
 
 type TransactionService interface {
 	Create(userID uint, req *dto.CreateTransactionRequest) (*dto.TransactionResponse, error)
+	Get(userID, id uint) (*dto.TransactionResponse, error)
+	Update(userID, id uint, req *dto.UpdateTransactionRequest) (*dto.TransactionResponse, error)
+	Delete(userID, id uint) error
+	Import(userID uint, req *dto.ImportTransactionRequest, file io.Reader) (*dto.ImportTransactionResponse, error)
 	List(userID uint, req *dto.ListTransactionRequest) (*dto.ListTransactionResponse, error)
+	Export(userID uint, req *dto.ExportTransactionRequest, w io.Writer) error
 }
 
 // ==================== 接口实现 ====================
 
 type transactionService struct {
-	txDomain txDomain.Domain // 依赖 Domain 层接口
+	txDomain  txDomain.Domain   // 依赖 Domain 层接口
+	importers importer.Registry // 对账单导入器（按格式选择）
 }
 
 // NewTransactionService 创建 Service 实例
-func NewTransactionService(txDomain txDomain.Domain) TransactionService {
+func NewTransactionService(txDomain txDomain.Domain, importers importer.Registry) TransactionService {
 	return &transactionService{
-		txDomain: txDomain,
+		txDomain:  txDomain,
+		importers: importers,
 	}
 }
 
@@ -45,14 +55,20 @@ func (s *transactionService) Create(userID uint, req *dto.CreateTransactionReque
 	// 2. 调用 Domain 层处理核心业务
 	tx, err := s.txDomain.Create(&txDomain.CreateInput{
 		UserID:    userID,
+		AccountID: req.AccountID,
 		Symbol:    req.Symbol,
 		Name:      req.Name,
 		Type:      req.Type,
 		Quantity:  req.Quantity,
 		Price:     req.Price,
+		Amount:    req.Amount,
 		Fee:       req.Fee,
+		Ratio:     req.Ratio,
+		Currency:  req.Currency,
+		PrevClose: req.PrevClose,
 		TradeTime: tradeTime,
 		Notes:     req.Notes,
+		LotIDs:    req.LotIDs,
 	})
 	if err != nil {
 		return nil, err
@@ -62,6 +78,60 @@ func (s *transactionService) Create(userID uint, req *dto.CreateTransactionReque
 	return txEntityToDTO(tx), nil
 }
 
+// Get 查询单条交易记录
+// Service 层职责：调用 Domain 层 + Entity → DTO 转换
+func (s *transactionService) Get(userID, id uint) (*dto.TransactionResponse, error) {
+	tx, err := s.txDomain.Get(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return txEntityToDTO(tx), nil
+}
+
+// Update 更新交易记录
+// Service 层职责：
+// 1. 解析时间字符串
+// 2. 调用 Domain 层（归属校验 + 重新计算金额）
+// 3. Entity → DTO 转换
+func (s *transactionService) Update(userID, id uint, req *dto.UpdateTransactionRequest) (*dto.TransactionResponse, error) {
+	// 1. 解析交易时间（字符串 → time.Time）
+	tradeTime, err := time.Parse(time.RFC3339, req.TradeTime)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 调用 Domain 层处理核心业务
+	tx, err := s.txDomain.Update(&txDomain.UpdateInput{
+		ID:        id,
+		UserID:    userID,
+		Symbol:    req.Symbol,
+		Name:      req.Name,
+		Type:      req.Type,
+		Quantity:  req.Quantity,
+		Price:     req.Price,
+		Amount:    req.Amount,
+		Fee:       req.Fee,
+		Ratio:     req.Ratio,
+		Currency:  req.Currency,
+		AccountID: req.AccountID,
+		TradeTime: tradeTime,
+		Notes:     req.Notes,
+		LotIDs:    req.LotIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Entity → DTO 转换
+	return txEntityToDTO(tx), nil
+}
+
+// Delete 删除交易记录
+// Service 层职责：调用 Domain 层删除
+func (s *transactionService) Delete(userID, id uint) error {
+	return s.txDomain.Delete(userID, id)
+}
+
 // List 查询交易列表
 // Service 层职责：
 // 1. 设置默认值
@@ -78,29 +148,15 @@ func (s *transactionService) List(userID uint, req *dto.ListTransactionRequest)
 	}
 
 	// 2. 解析日期字符串（可选参数）
-	var startTime, endTime *time.Time
-
-	if req.StartDate != "" {
-		t, err := time.Parse("2006-01-02", req.StartDate)
-		if err != nil {
-			return nil, err
-		}
-		startTime = &t
-	}
-
-	if req.EndDate != "" {
-		t, err := time.Parse("2006-01-02", req.EndDate)
-		if err != nil {
-			return nil, err
-		}
-		// 结束日期加一天，以包含当天的数据
-		t = t.AddDate(0, 0, 1)
-		endTime = &t
+	startTime, endTime, err := parseDateRange(req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, err
 	}
 
 	// 3. 调用 Domain 层查询
 	output, err := s.txDomain.List(&txDomain.ListInput{
 		UserID:    userID,
+		AccountID: req.AccountID,
 		Symbol:    req.Symbol,
 		Type:      req.Type,
 		StartTime: startTime,
@@ -128,19 +184,66 @@ func (s *transactionService) List(userID uint, req *dto.ListTransactionRequest)
 
 // ==================== 私有辅助函数 ====================
 
+// parseDateRange 解析日期范围参数（格式：2024-01-01，均可选）
+// 返回 [startTime, endTime) 左闭右开区间：结束日期加一天，以包含当天的数据
+func parseDateRange(startDate, endDate string) (*time.Time, *time.Time, error) {
+	var startTime, endTime *time.Time
+
+	if startDate != "" {
+		t, err := time.Parse("2006-01-02", startDate)
+		if err != nil {
+			return nil, nil, err
+		}
+		startTime = &t
+	}
+
+	if endDate != "" {
+		t, err := time.Parse("2006-01-02", endDate)
+		if err != nil {
+			return nil, nil, err
+		}
+		t = t.AddDate(0, 0, 1)
+		endTime = &t
+	}
+
+	return startTime, endTime, nil
+}
+
 // txEntityToDTO 将 Transaction Entity 转换为 DTO
 func txEntityToDTO(tx *entity.Transaction) *dto.TransactionResponse {
-	return &dto.TransactionResponse{
-		ID:        tx.ID,
-		Symbol:    tx.Symbol,
-		Name:      tx.Name,
-		Type:      tx.Type,
-		Quantity:  tx.Quantity,
-		Price:     tx.Price,
-		Amount:    tx.Amount,
-		Fee:       tx.Fee,
-		TradeTime: tx.TradeTime,
-		Notes:     tx.Notes,
-		CreatedAt: tx.CreatedAt,
+	// LotIDs 由 Domain 层写入，格式固定，解析失败时忽略
+	lotIDs, _ := lotDomain.DecodeLotIDs(tx.LotIDs)
+
+	resp := &dto.TransactionResponse{
+		ID:              tx.ID,
+		AccountID:       tx.AccountID,
+		Symbol:          tx.Symbol,
+		Name:            tx.Name,
+		Type:            tx.Type,
+		Quantity:        tx.Quantity,
+		Price:           tx.Price,
+		Amount:          tx.Amount,
+		Fee:             tx.Fee,
+		Ratio:           tx.Ratio,
+		Currency:        tx.Currency,
+		TradeTime:       tx.TradeTime,
+		Notes:           tx.Notes,
+		CostBasisMethod: tx.CostBasisMethod,
+		LotIDs:          lotIDs,
+		Source:          tx.Source,
+		CreatedAt:       tx.CreatedAt,
+	}
+	if tx.FeeScheduleID != 0 {
+		resp.FeeScheduleID = tx.FeeScheduleID
+		resp.FeeBreakdown = &dto.FeeBreakdown{
+			Commission:  tx.FeeDetail.Commission,
+			StampDuty:   tx.FeeDetail.StampDuty,
+			TransferFee: tx.FeeDetail.TransferFee,
+			Levy:        tx.FeeDetail.Levy,
+		}
+	}
+	if tx.BrokerTradeID != nil {
+		resp.BrokerTradeID = *tx.BrokerTradeID
 	}
+	return resp
 }
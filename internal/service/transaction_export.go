@@ -0,0 +1,325 @@
+package service
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/xuri/excelize/v2"
+
+	lotDomain "github.com/florentyang/smartfin-go/internal/domain/lot"
+	txDomain "github.com/florentyang/smartfin-go/internal/domain/transaction"
+	"github.com/florentyang/smartfin-go/internal/dto"
+	"github.com/florentyang/smartfin-go/internal/entity"
+)
+
+// ==================== 导出 ====================
+// 支持 csv / jsonl / xlsx 三种格式，筛选条件与 List 一致
+// 金额、数量统一按数据库精度 decimal(18,4) 输出为定点字符串，不经过 float64
+// CSV 的列名与导入格式一致，导出的文件可以直接重新导入
+
+// 导出格式常量
+const (
+	ExportFormatCSV   = "csv"
+	ExportFormatJSONL = "jsonl"
+	ExportFormatXLSX  = "xlsx"
+)
+
+// exportDecimalPlaces 导出金额/数量的小数位数（与数据库字段精度一致）
+const exportDecimalPlaces = 4
+
+// exportRatioPlaces 导出拆股比例的小数位数（decimal(18,8)）
+const exportRatioPlaces = 8
+
+// exportColumns 导出列（CSV 表头 / XLSX 首行）
+var exportColumns = []string{
+	"id", "account_id", "symbol", "name", "type", "quantity", "price", "amount", "fee", "ratio", "currency", "trade_time",
+	"notes", "cost_basis_method", "lot_ids", "source", "broker_trade_id", "created_at",
+}
+
+// ErrInvalidExportFormat 导出格式无效
+var ErrInvalidExportFormat = errors.New("导出格式无效，必须是 csv、jsonl 或 xlsx")
+
+// exportAbortedMessage 导出中途失败时写在文件末尾的错误标记（不含内部错误详情）
+const exportAbortedMessage = "导出中断：读取交易失败，文件不完整，请重新导出"
+
+// Export 导出交易
+// Service 层职责：
+// 1. 解析日期筛选条件
+// 2. 选择格式写出器
+// 3. 调用 Domain 层逐条读取并写出（不整体加载到内存）
+func (s *transactionService) Export(userID uint, req *dto.ExportTransactionRequest, w io.Writer) error {
+	// 1. 解析日期字符串（与 List 一致）
+	startTime, endTime, err := parseDateRange(req.StartDate, req.EndDate)
+	if err != nil {
+		return err
+	}
+
+	// 2. 选择格式写出器
+	writer, err := newExportWriter(req.Format, w)
+	if err != nil {
+		return err
+	}
+
+	// 3. 逐条读取并写出；失败时不结束文件：尚未写出数据则丢弃，已经写出则追加错误标记
+	err = s.txDomain.Export(&txDomain.ListInput{
+		UserID:    userID,
+		AccountID: req.AccountID,
+		Symbol:    req.Symbol,
+		Type:      req.Type,
+		StartTime: startTime,
+		EndTime:   endTime,
+	}, writer.WriteRow)
+	if err != nil {
+		writer.Abort()
+		return err
+	}
+	return writer.Close()
+}
+
+// ==================== 格式写出器 ====================
+
+// exportWriter 按格式写出交易
+// 全部写出成功才调用 Close；中途失败调用 Abort
+type exportWriter interface {
+	WriteRow(tx *entity.Transaction) error
+	Close() error // 刷新缓冲区并结束文件
+	Abort()       // 中途失败：尚未写出任何数据时全部丢弃，否则追加错误标记，不生成完整的文件
+}
+
+// trackingWriter 记录是否已经向下游写出过数据
+// 写出器自带缓冲，数据量小时失败前可能一个字节都没有发送，此时可以改为返回 JSON 错误
+type trackingWriter struct {
+	w       io.Writer
+	written bool
+}
+
+func (t *trackingWriter) Write(p []byte) (int, error) {
+	if len(p) > 0 {
+		t.written = true
+	}
+	return t.w.Write(p)
+}
+
+// newExportWriter 按格式创建写出器（默认 csv）
+func newExportWriter(format string, w io.Writer) (exportWriter, error) {
+	switch format {
+	case "", ExportFormatCSV:
+		return newCSVExportWriter(w)
+	case ExportFormatJSONL:
+		return newJSONLExportWriter(w), nil
+	case ExportFormatXLSX:
+		return newXLSXExportWriter(w)
+	default:
+		return nil, ErrInvalidExportFormat
+	}
+}
+
+// ----- CSV -----
+// 中途失败时末尾追加一行错误标记（首列为 #error），重新导入时会因类型无效而报错，不会被当作完整文件
+
+type csvExportWriter struct {
+	out    *trackingWriter
+	w      *csv.Writer
+	header bool // 是否已写表头
+}
+
+func newCSVExportWriter(w io.Writer) (*csvExportWriter, error) {
+	out := &trackingWriter{w: w}
+	return &csvExportWriter{out: out, w: csv.NewWriter(out)}, nil
+}
+
+func (e *csvExportWriter) WriteRow(tx *entity.Transaction) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	return e.w.Write(exportRecord(tx))
+}
+
+// writeHeader 表头随第一行数据写入缓冲区（没有数据时在 Close 中写入）
+func (e *csvExportWriter) writeHeader() error {
+	if e.header {
+		return nil
+	}
+	e.header = true
+	return e.w.Write(exportColumns)
+}
+
+func (e *csvExportWriter) Close() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvExportWriter) Abort() {
+	if !e.out.written {
+		return
+	}
+	_ = e.w.Write([]string{"#error", exportAbortedMessage})
+	e.w.Flush()
+}
+
+// ----- JSON Lines -----
+// 中途失败时末尾追加一行 {"error": "..."}
+
+type jsonlExportWriter struct {
+	out *trackingWriter
+	buf *bufio.Writer
+	enc *json.Encoder
+}
+
+func newJSONLExportWriter(w io.Writer) *jsonlExportWriter {
+	out := &trackingWriter{w: w}
+	buf := bufio.NewWriter(out)
+	return &jsonlExportWriter{out: out, buf: buf, enc: json.NewEncoder(buf)}
+}
+
+func (e *jsonlExportWriter) WriteRow(tx *entity.Transaction) error {
+	// Encoder 每条记录后自动换行
+	return e.enc.Encode(exportRow(tx))
+}
+
+func (e *jsonlExportWriter) Close() error {
+	return e.buf.Flush()
+}
+
+func (e *jsonlExportWriter) Abort() {
+	if !e.out.written {
+		return
+	}
+	_ = e.enc.Encode(map[string]string{"error": exportAbortedMessage})
+	_ = e.buf.Flush()
+}
+
+// ----- XLSX -----
+// 使用 excelize 的流式写入：行数据超过内存阈值后落到临时文件，全部成功后一次性写出
+// 中途失败时不写出任何数据，由 Controller 返回 JSON 错误
+// 数值列以文本写入，避免 Excel 的双精度浮点数丢失精度
+
+type xlsxExportWriter struct {
+	w    io.Writer
+	file *excelize.File
+	sw   *excelize.StreamWriter
+	row  int
+}
+
+func newXLSXExportWriter(w io.Writer) (*xlsxExportWriter, error) {
+	file := excelize.NewFile()
+	sw, err := file.NewStreamWriter("Sheet1")
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	e := &xlsxExportWriter{w: w, file: file, sw: sw}
+	if err := e.writeCells(exportColumns); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *xlsxExportWriter) WriteRow(tx *entity.Transaction) error {
+	return e.writeCells(exportRecord(tx))
+}
+
+func (e *xlsxExportWriter) writeCells(values []string) error {
+	e.row++
+	cell, err := excelize.CoordinatesToCellName(1, e.row)
+	if err != nil {
+		return err
+	}
+	row := make([]interface{}, len(values))
+	for i, v := range values {
+		row[i] = v
+	}
+	return e.sw.SetRow(cell, row)
+}
+
+func (e *xlsxExportWriter) Close() error {
+	// 无论成功与否都要释放临时文件
+	defer e.file.Close()
+
+	if err := e.sw.Flush(); err != nil {
+		return err
+	}
+	_, err := e.file.WriteTo(e.w)
+	return err
+}
+
+func (e *xlsxExportWriter) Abort() {
+	_ = e.file.Close()
+}
+
+// ==================== 私有辅助函数 ====================
+
+// exportRecord 将交易转换为一行导出数据（列顺序与 exportColumns 一致）
+func exportRecord(tx *entity.Transaction) []string {
+	row := exportRow(tx)
+	lotIDs := make([]string, len(row.LotIDs))
+	for i, id := range row.LotIDs {
+		lotIDs[i] = strconv.FormatUint(uint64(id), 10)
+	}
+	return []string{
+		strconv.FormatUint(uint64(row.ID), 10),
+		strconv.FormatUint(uint64(row.AccountID), 10),
+		row.Symbol,
+		row.Name,
+		row.Type,
+		row.Quantity,
+		row.Price,
+		row.Amount,
+		row.Fee,
+		row.Ratio,
+		row.Currency,
+		row.TradeTime,
+		row.Notes,
+		row.CostBasisMethod,
+		strings.Join(lotIDs, ";"), // 与 CSV 导入格式一致：分号分隔
+		row.Source,
+		row.BrokerTradeID,
+		row.CreatedAt,
+	}
+}
+
+// exportRow 将交易转换为导出 DTO
+func exportRow(tx *entity.Transaction) *dto.ExportTransactionRow {
+	// LotIDs 由 Domain 层写入，格式固定，解析失败时忽略
+	lotIDs, _ := lotDomain.DecodeLotIDs(tx.LotIDs)
+
+	row := &dto.ExportTransactionRow{
+		ID:              tx.ID,
+		AccountID:       tx.AccountID,
+		Symbol:          tx.Symbol,
+		Name:            tx.Name,
+		Type:            tx.Type,
+		Quantity:        exportDecimal(tx.Quantity),
+		Price:           exportDecimal(tx.Price),
+		Amount:          exportDecimal(tx.Amount),
+		Fee:             exportDecimal(tx.Fee),
+		Ratio:           tx.Ratio.StringFixed(exportRatioPlaces),
+		Currency:        tx.Currency,
+		TradeTime:       tx.TradeTime.Format(time.RFC3339),
+		Notes:           tx.Notes,
+		CostBasisMethod: tx.CostBasisMethod,
+		LotIDs:          lotIDs,
+		Source:          tx.Source,
+		CreatedAt:       tx.CreatedAt.Format(time.RFC3339),
+	}
+	if tx.BrokerTradeID != nil {
+		row.BrokerTradeID = *tx.BrokerTradeID
+	}
+	return row
+}
+
+// exportDecimal 按数据库精度输出定点字符串（如 100 → "100.0000"）
+func exportDecimal(d decimal.Decimal) string {
+	return d.StringFixed(exportDecimalPlaces)
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	lotDomain "github.com/florentyang/smartfin-go/internal/domain/lot"
+	txDomain "github.com/florentyang/smartfin-go/internal/domain/transaction"
+	"github.com/florentyang/smartfin-go/internal/dto"
+	"github.com/florentyang/smartfin-go/internal/importer"
+)
+
+// ==================== 批量导入 ====================
+// 文件格式由 format 参数选择（csv / ibkr_flex / ofx，默认 csv），
+// 各格式的解析规则见 internal/importer/impl
+
+// maxImportRows 单次导入的最大数据行数
+const maxImportRows = 10000
+
+// Import 从对账单文件批量导入交易
+// Service 层职责：
+// 1. 选择导入器解析文件（格式错误记为行错误）
+// 2. 调用 Domain 层逐行校验，全部成功才提交
+// 3. 合并格式错误和业务错误，按行号返回
+func (s *transactionService) Import(userID uint, req *dto.ImportTransactionRequest, file io.Reader) (*dto.ImportTransactionResponse, error) {
+	// 1. 选择导入器，确定无时区时间的解析时区
+	format := req.Format
+	if format == "" {
+		format = importer.FormatCSV
+	}
+	imp, err := s.importers.Get(format)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s（支持 %v）", err, format, s.importers.Formats())
+	}
+	opts := &importer.Options{}
+	if req.Timezone != "" {
+		if opts.Location, err = time.LoadLocation(req.Timezone); err != nil {
+			return nil, fmt.Errorf("timezone 无效: %s", req.Timezone)
+		}
+	}
+
+	// 2. 解析文件
+	records, err := imp.Parse(file, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) > maxImportRows {
+		return nil, fmt.Errorf("单次最多导入 %d 行", maxImportRows)
+	}
+
+	var (
+		rows      []*txDomain.CreateInput
+		rowLines  []int // rows[i] 对应的源文件行号
+		rowErrors []*dto.ImportRowErrorResponse
+	)
+	for _, record := range records {
+		if record.Err != nil {
+			rowErrors = append(rowErrors, &dto.ImportRowErrorResponse{Line: record.Line, Message: record.Err.Error()})
+			continue
+		}
+		tx := record.Transaction
+		lotIDs, err := lotDomain.DecodeLotIDs(tx.LotIDs)
+		if err != nil {
+			rowErrors = append(rowErrors, &dto.ImportRowErrorResponse{Line: record.Line, Message: err.Error()})
+			continue
+		}
+		input := &txDomain.CreateInput{
+			Symbol:    tx.Symbol,
+			Name:      tx.Name,
+			Type:      tx.Type,
+			Quantity:  tx.Quantity,
+			Price:     tx.Price,
+			Amount:    tx.Amount,
+			Fee:       &tx.Fee, // 对账单上的手续费按原样导入，不按费率表重算
+			Ratio:     tx.Ratio,
+			Currency:  tx.Currency,
+			TradeTime: tx.TradeTime,
+			Notes:     tx.Notes,
+			LotIDs:    lotIDs,
+			Source:    tx.Source,
+		}
+		if tx.BrokerTradeID != nil {
+			input.BrokerTradeID = *tx.BrokerTradeID
+		}
+		rows = append(rows, input)
+		rowLines = append(rowLines, record.Line)
+	}
+
+	// 3. 调用 Domain 层校验并写入
+	//    存在格式错误时强制试运行：仍然校验其余行，但不提交
+	output, err := s.txDomain.Import(&txDomain.ImportInput{
+		UserID:    userID,
+		AccountID: req.AccountID,
+		Rows:      rows,
+		DryRun:    req.DryRun || len(rowErrors) > 0,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. 合并错误，按行号排序
+	for _, e := range output.Errors {
+		rowErrors = append(rowErrors, &dto.ImportRowErrorResponse{
+			Line:    rowLines[e.Index],
+			Message: e.Err.Error(),
+		})
+	}
+	sort.SliceStable(rowErrors, func(i, j int) bool {
+		return rowErrors[i].Line < rowErrors[j].Line
+	})
+
+	result := &dto.ImportTransactionResponse{
+		Format:    imp.Format(),
+		DryRun:    req.DryRun,
+		Committed: output.Committed,
+		Total:     len(records),
+		Valid:     output.Valid,
+		Skipped:   output.Skipped,
+		Errors:    rowErrors,
+	}
+	if output.Committed {
+		result.Imported = output.Valid
+	}
+	return result, nil
+}
@@ -49,10 +49,14 @@ func (s *userService) Register(req *dto.RegisterRequest) (*dto.UserResponse, err
 // entityToDTO 将 Entity 转换为 DTO（隐藏敏感字段如密码）
 func entityToDTO(user *entity.User) *dto.UserResponse {
 	return &dto.UserResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt,
+		ID:                 user.ID,
+		Username:           user.Username,
+		Email:              user.Email,
+		AllowShortSelling:  user.AllowShortSelling,
+		EnforceMarketRules: user.EnforceMarketRules,
+		CostBasisMethod:    user.CostBasisMethod,
+		BaseCurrency:       user.BaseCurrency,
+		CreatedAt:          user.CreatedAt,
 	}
 }
 
@@ -94,7 +98,14 @@ func (s *userService) GetProfile(userID uint) (*dto.UserResponse, error) {
 // Service 层职责：调用 Domain 层更新用户信息
 func (s *userService) UpdateProfile(userID uint, req *dto.UpdateUserRequest) error {
 	// 1. 调用 Domain 层更新用户信息
-	err := s.userDomain.UpdateProfile(userID, req.Username, req.Email)
+	err := s.userDomain.UpdateProfile(userID, &userDomain.UpdateProfileInput{
+		Username:           req.Username,
+		Email:              req.Email,
+		AllowShortSelling:  req.AllowShortSelling,
+		EnforceMarketRules: req.EnforceMarketRules,
+		CostBasisMethod:    req.CostBasisMethod,
+		BaseCurrency:       req.BaseCurrency,
+	})
 	if err != nil {
 		return err
 	}
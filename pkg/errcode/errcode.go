@@ -2,11 +2,13 @@ package errcode
 
 // 通用错误码 (1000-1999)
 const (
-	Success         = 0
-	ServerError     = 1001
-	InvalidParams   = 1002
-	NotFound        = 1003
-	TooManyRequests = 1004
+	Success               = 0
+	ServerError           = 1001
+	InvalidParams         = 1002
+	NotFound              = 1003
+	TooManyRequests       = 1004
+	IdempotencyKeyReused  = 1005
+	IdempotencyInProgress = 1006
 )
 
 // 用户模块错误码 (2000-2999)
@@ -49,11 +51,13 @@ const (
 
 // ErrMsg 错误码对应的消息
 var ErrMsg = map[int]string{
-	Success:         "操作成功",
-	ServerError:     "服务器内部错误",
-	InvalidParams:   "参数错误",
-	NotFound:        "资源不存在",
-	TooManyRequests: "请求过于频繁",
+	Success:               "操作成功",
+	ServerError:           "服务器内部错误",
+	InvalidParams:         "参数错误",
+	NotFound:              "资源不存在",
+	TooManyRequests:       "请求过于频繁",
+	IdempotencyKeyReused:  "幂等键已用于不同的请求",
+	IdempotencyInProgress: "相同幂等键的请求正在处理中",
 
 	UserNotFound:      "用户不存在",
 	UserAlreadyExists: "用户已存在",
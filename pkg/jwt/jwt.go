@@ -1,6 +1,8 @@
 package jwt
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"time"
 
@@ -11,8 +13,13 @@ import (
 var (
 	SecretKey   = []byte("smartfin-secret-key-2026") // JWT 签名密钥
 	TokenExpiry = 30 * time.Minute                   // Token 有效期：30分钟
+
+	StreamTicketExpiry = 60 * time.Second // 推送票据有效期：60秒（只用于建立连接）
 )
 
+// PurposeStream 推送票据的用途（普通 Token 的用途为空）
+const PurposeStream = "stream"
+
 // 错误定义
 var (
 	ErrInvalidToken = errors.New("无效的 Token")
@@ -23,6 +30,7 @@ var (
 type Claims struct {
 	UserID   uint   `json:"user_id"`
 	Username string `json:"username"`
+	Purpose  string `json:"purpose,omitempty"` // 用途：普通 Token 为空，推送票据为 stream
 	jwt.RegisteredClaims
 }
 
@@ -56,10 +64,64 @@ func GenerateToken(userID uint, username string) (string, int64, error) {
 	return tokenString, expiresAt.Unix(), nil
 }
 
+// GenerateStreamTicket 生成推送票据
+// 浏览器 EventSource 不能设置请求头，票据放在 URL 中：有效期短、只能用于推送接口、带唯一 ID（一次性使用由调用方校验）
+// 返回：票据字符串、唯一 ID、过期时间戳、错误
+func GenerateStreamTicket(userID uint, username string) (string, string, int64, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", "", 0, err
+	}
+	ticketID := hex.EncodeToString(id)
+	expiresAt := time.Now().Add(StreamTicketExpiry)
+
+	claims := &Claims{
+		UserID:   userID,
+		Username: username,
+		Purpose:  PurposeStream,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        ticketID,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    "smartfin-go",
+		},
+	}
+	ticket, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(SecretKey)
+	if err != nil {
+		return "", "", 0, err
+	}
+	return ticket, ticketID, expiresAt.Unix(), nil
+}
+
 // ParseToken 解析 JWT Token
 // 参数：token 字符串
 // 返回：Claims（包含 UserID）、错误
+// 推送票据不能当作普通 Token 使用
 func ParseToken(tokenString string) (*Claims, error) {
+	claims, err := parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != "" {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// ParseStreamTicket 解析推送票据（普通 Token 不能当作票据使用）
+func ParseStreamTicket(ticket string) (*Claims, error) {
+	claims, err := parse(ticket)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != PurposeStream || claims.ID == "" {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// parse 验证签名和有效期，提取 Claims
+func parse(tokenString string) (*Claims, error) {
 	// 解析 Token
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		return SecretKey, nil